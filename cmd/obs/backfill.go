@@ -8,53 +8,66 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"regexp"
 	"strings"
-)
-
-var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
-var whitespaceRe = regexp.MustCompile(`\s+`)
 
-func sanitizeForEmbedding(s string, maxChars int) string {
-	s = htmlTagRe.ReplaceAllString(s, " ")
-	s = whitespaceRe.ReplaceAllString(s, " ")
-	s = strings.TrimSpace(s)
-	if len(s) > maxChars {
-		s = s[:maxChars]
-	}
-	return s
-}
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/resilience"
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/usage"
+)
 
 func runBackfill() {
 	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
 	clear := fs.Bool("clear", false, "Clear all existing embeddings before backfilling")
+	prune := fs.Bool("prune", false, "Delete vectors from every other model (fallback backends included) before backfilling")
 	batchSize := fs.Int("batch-size", 50, "Items per batch")
 	dryRun := fs.Bool("dry-run", false, "Show counts without embedding")
+	all := fs.Bool("all", false, "Ignore the source selection (embed disabled and muted sources too)")
+	ignoreBudget := fs.Bool("ignore-budget", false, "Keep going after the daily/monthly token budget is spent")
 	fs.Parse(os.Args[1:])
 
 	apiKey := requireJinaKey()
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
+	ctx = usage.WithPurpose(ctx, usage.PurposeBackfill)
 
 	st := openDB()
 	defer st.Close()
+	cfg := loadConfig()
+
+	// Honour the source selection: disabled and muted sources are not embedded.
+	var excluded []string
+	if !*all {
+		excluded = excludedSources(cfg, st)
+	}
+
+	// Create Jina embedder
+	embedder := newJinaEmbedder(apiKey, st, true)
+	model := embed.ModelName(embedder)
 
 	// Count existing embeddings and total items
 	totalItems, err := st.CountAllItems()
 	if err != nil {
 		log.Fatalf("failed to count items: %v", err)
 	}
-	needingEmbedding, err := st.CountItemsNeedingEmbedding()
+	allNeeding, err := st.CountItemsNeedingEmbedding(model)
+	if err != nil {
+		log.Fatalf("failed to count items needing embedding: %v", err)
+	}
+	needingEmbedding, err := st.CountItemsNeedingEmbeddingExcept(model, excluded)
 	if err != nil {
 		log.Fatalf("failed to count items needing embedding: %v", err)
 	}
-	existingEmbeddings := totalItems - needingEmbedding
+	existingEmbeddings := totalItems - allNeeding
 
 	fmt.Printf("Database: %s\n", dbPath())
 	fmt.Printf("Total items: %d\n", totalItems)
 	fmt.Printf("Existing embeddings: %d\n", existingEmbeddings)
 	fmt.Printf("Needing embedding: %d\n", needingEmbedding)
+	if skipped := allNeeding - needingEmbedding; skipped > 0 {
+		fmt.Printf("Skipped (%d disabled/muted sources): %d  (--all to include)\n", len(excluded), skipped)
+	}
 	fmt.Println()
 
 	if *dryRun {
@@ -77,16 +90,30 @@ func runBackfill() {
 		if err != nil {
 			log.Fatalf("failed to clear embeddings: %v", err)
 		}
-		fmt.Printf("Cleared %d embeddings.\n\n", cleared)
+		fmt.Printf("Cleared %d embeddings (cached vectors are kept, so unchanged texts cost nothing).\n\n", cleared)
+	} else if *prune {
+		fmt.Printf("Delete vectors from every model other than %s? Search falls back to them while it is down. [y/N] ", model)
+		reader := bufio.NewReader(os.Stdin)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Aborted.")
+			return
+		}
+
+		pruned, err := st.ClearEmbeddingsExcept(model)
+		if err != nil {
+			log.Fatalf("failed to prune embeddings: %v", err)
+		}
+		fmt.Printf("Deleted %d vectors from models other than %s.\n\n", pruned, model)
 	}
 
-	// Create Jina embedder
-	embedder := newJinaEmbedder(apiKey)
 	fmt.Printf("Using model: %s\n", envOrDefault("JINA_EMBED_MODEL", "jina-embeddings-v3"))
 	fmt.Println("Starting backfill... (Ctrl+C to stop, re-run to resume)")
 	fmt.Println()
 
 	embedded := 0
+	fromCache := 0
 
 	for {
 		if ctx.Err() != nil {
@@ -94,7 +121,14 @@ func runBackfill() {
 			return
 		}
 
-		items, err := st.GetItemsNeedingEmbedding(*batchSize)
+		if !*ignoreBudget {
+			if err := checkBudget(cfg, st); err != nil {
+				fmt.Printf("\nStopping: %v. Embedded %d items. (--ignore-budget to continue anyway)\n", err, embedded)
+				return
+			}
+		}
+
+		items, err := st.GetItemsNeedingEmbeddingExcept(model, *batchSize, excluded)
 		if err != nil {
 			log.Fatalf("failed to get items: %v", err)
 		}
@@ -102,33 +136,38 @@ func runBackfill() {
 			break
 		}
 
-		// Build texts (strip HTML, cap length)
+		// Build texts (strip HTML, cap length), reusing cached vectors
 		texts := make([]string, len(items))
 		for i, item := range items {
-			texts[i] = item.Title
-			if item.Summary != "" {
-				clean := sanitizeForEmbedding(item.Summary, 2000-len(item.Title))
-				if clean != "" {
-					texts[i] += " " + clean
-				}
-			}
+			texts[i] = embed.DocumentText(item.Title, item.Summary)
+		}
+		batch := items
+		var hits int
+		items, texts, hits = saveCached(st, model, items, texts)
+		embedded += hits
+		fromCache += hits
+		if len(items) == 0 {
+			embedChunks(ctx, st, embedder, model, batch)
+			continue
 		}
 
-		// Embed batch with retries
-		var embeddings [][]float32
-		for attempt := 0; attempt < 3; attempt++ {
-			if ctx.Err() != nil {
-				fmt.Printf("\nInterrupted. Embedded %d items. Re-run to continue.\n", embedded)
-				return
-			}
-			embeddings, err = embedder.EmbedBatch(ctx, texts)
-			if err == nil {
-				break
-			}
-			log.Printf("Batch attempt %d failed: %v", attempt+1, err)
+		// Embed batch (the embedder retries transient failures itself)
+		embeddings, err := embedder.EmbedBatch(ctx, texts)
+		if ctx.Err() != nil {
+			fmt.Printf("\nInterrupted. Embedded %d items. Re-run to continue.\n", embedded)
+			return
 		}
 		if err != nil {
-			log.Printf("Skipping batch of %d items after 3 failures: %v", len(items), err)
+			// Find the items at fault one by one; failures back off in the
+			// embed queue so the next batch moves on instead of looping.
+			log.Printf("Batch of %d items failed (%v); embedding individually", len(items), err)
+			n := embedIndividually(ctx, st, embedder, model, items, texts)
+			if n == 0 {
+				fmt.Printf("\nNothing in the batch embedded; the backend looks down. Embedded %d items. Re-run to continue.\n", embedded)
+				return
+			}
+			embedded += n
+			embedChunks(ctx, st, embedder, model, batch)
 			continue
 		}
 
@@ -139,18 +178,143 @@ func runBackfill() {
 				break
 			}
 			if i < len(items) {
-				if err := st.SaveEmbedding(items[i].ID, emb); err != nil {
-					log.Printf("Warning: failed to save embedding for %s: %v", items[i].ID, err)
-				} else {
+				if saveEmbedding(st, model, items[i], texts[i], emb) {
 					saved++
 				}
 			}
 		}
 
 		embedded += saved
-		remaining, _ := st.CountItemsNeedingEmbedding()
-		fmt.Printf("Embedded %d items (%d remaining)\n", embedded, remaining)
+		embedChunks(ctx, st, embedder, model, batch)
+		remaining, _ := st.CountItemsNeedingEmbeddingExcept(model, excluded)
+		fmt.Printf("Embedded %d items, %d from cache (%d remaining)\n", embedded, fromCache, remaining)
+	}
+
+	fmt.Printf("\nDone! Embedded %d items total (%d from cache).\n", embedded, fromCache)
+}
+
+// saveCached saves cached vectors for items whose text was embedded before
+// and returns the items (and texts) that still need a backend call.
+func saveCached(st *store.Store, model string, items []store.Item, texts []string) ([]store.Item, []string, int) {
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = embed.CacheKey(model, embed.TaskDocument, text)
 	}
+	cached, err := st.GetCachedEmbeddings(keys)
+	if err != nil {
+		log.Printf("Warning: embedding cache lookup failed: %v", err)
+		return items, texts, 0
+	}
+
+	hits := 0
+	var restItems []store.Item
+	var restTexts []string
+	for i, item := range items {
+		emb, ok := cached[keys[i]]
+		if !ok {
+			restItems = append(restItems, item)
+			restTexts = append(restTexts, texts[i])
+			continue
+		}
+		if err := st.SaveModelEmbedding(item.ID, model, emb); err != nil {
+			log.Printf("Warning: failed to save embedding for %s: %v", item.ID, err)
+			continue
+		}
+		hits++
+	}
+	return restItems, restTexts, hits
+}
+
+// saveEmbedding stores a freshly computed vector for item and caches it
+// under its text. Returns false if the item's vector could not be saved.
+func saveEmbedding(st *store.Store, model string, item store.Item, text string, emb []float32) bool {
+	if err := st.SaveModelEmbedding(item.ID, model, emb); err != nil {
+		log.Printf("Warning: failed to save embedding for %s: %v", item.ID, err)
+		return false
+	}
+	if err := st.CacheEmbedding(embed.CacheKey(model, embed.TaskDocument, text), model, emb); err != nil {
+		log.Printf("Warning: failed to cache embedding for %s: %v", item.ID, err)
+	}
+	return true
+}
 
-	fmt.Printf("\nDone! Embedded %d items total.\n", embedded)
+// embedIndividually embeds items one at a time, recording each failure the
+// item is to blame for (resilience.InputFault) in the embed job queue.
+// Returns how many items were embedded.
+func embedIndividually(ctx context.Context, st *store.Store, e embed.Embedder, model string, items []store.Item, texts []string) int {
+	saved := 0
+	for i, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		emb, err := e.Embed(ctx, texts[i])
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			if !resilience.InputFault(err) {
+				log.Printf("Warning: failed to embed %s: %v", item.ID, err)
+				continue
+			}
+			job, rErr := st.RecordEmbedFailure(item.ID, err, store.DefaultEmbedRetryPolicy)
+			if rErr != nil {
+				log.Printf("Warning: failed to record failure for %s: %v", item.ID, rErr)
+			} else if job.State == store.EmbedJobDead {
+				log.Printf("Dead-lettered %s after %d attempts: %v", item.ID, job.Attempts, err)
+			}
+			continue
+		}
+		if saveEmbedding(st, model, item, texts[i], emb) {
+			saved++
+		}
+	}
+	return saved
+}
+
+// embedChunks embeds the passages of the long items among items that now
+// have a vector (see embed.DocumentChunks) and stores them as their chunks.
+// Failures are logged; the item keeps its document vector.
+func embedChunks(ctx context.Context, st *store.Store, e embed.BatchEmbedder, model string, items []store.Item) {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	have, err := st.GetModelEmbeddings(ids, model)
+	if err != nil {
+		log.Printf("Warning: failed to load embeddings: %v", err)
+		return
+	}
+	for _, item := range items {
+		passages := embed.DocumentChunks(item.Summary)
+		if len(passages) == 0 || have[item.ID] == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		texts := make([]string, len(passages))
+		for i, p := range passages {
+			texts[i] = embed.ChunkText(item.Title, p)
+		}
+		embs, err := e.EmbedBatch(ctx, texts)
+		if err == nil && len(embs) != len(texts) {
+			err = fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embs))
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Warning: failed to embed chunks for %s: %v", item.ID, err)
+			}
+			continue
+		}
+		chunks := make([]store.Chunk, len(passages))
+		for i, p := range passages {
+			chunks[i] = store.Chunk{Index: i, Text: p, Embedding: embs[i]}
+			if err := st.CacheEmbedding(embed.CacheKey(model, embed.TaskDocument, texts[i]), model, embs[i]); err != nil {
+				log.Printf("Warning: failed to cache chunk embedding for %s: %v", item.ID, err)
+			}
+		}
+		if err := st.SaveChunks(item.ID, model, chunks); err != nil {
+			log.Printf("Warning: failed to save chunks for %s: %v", item.ID, err)
+		}
+	}
 }
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/rerank"
+)
+
+// labelledScores are one backend's raw scores over every query-item pair
+// of the labelled set, with the labels.
+type labelledScores struct {
+	name     string
+	rerank   bool // a reranker (cached scores are cleared on refit), not cosine
+	scores   []float32
+	relevant []bool
+	err      error
+}
+
+func runCalibrate() {
+	fs := flag.NewFlagSet("calibrate", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the fitted calibrations without saving them")
+	model := fs.String("model", os.Getenv("OLLAMA_RERANK_MODEL"), "Ollama reranker model (auto-detects if empty)")
+	endpoint := fs.String("endpoint", os.Getenv("OLLAMA_HOST"), "Ollama endpoint (default: http://localhost:11434)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: obs calibrate [--dry-run] [--model M] [--endpoint URL]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Scores the labelled search-quality set with every configured reranker and")
+		fmt.Fprintln(os.Stderr, "embedding model, fits a calibration mapping each backend's raw scores to")
+		fmt.Fprintln(os.Stderr, "probabilities of relevance, and saves them to ~/.observer/calibration.json.")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[1:])
+
+	set := filter.NewQualitySet()
+	var pairs, relevant int
+	for _, q := range set.Queries {
+		pairs += len(set.Items)
+		relevant += len(q.Relevant)
+	}
+
+	rerankers, embedders, skipped := calibrationBackends(*endpoint, *model)
+
+	fmt.Println("=== Score Calibration ===")
+	fmt.Println()
+	fmt.Printf("Labelled set: %d queries x %d items (%d pairs, %d relevant)\n", len(set.Queries), len(set.Items), pairs, relevant)
+	for _, s := range skipped {
+		fmt.Printf("Skipped: %s\n", s)
+	}
+	fmt.Println()
+
+	var results []labelledScores
+	for _, r := range rerankers {
+		results = append(results, scoreWithReranker(set, r))
+	}
+	for _, e := range embedders {
+		results = append(results, scoreWithEmbedder(set, e))
+	}
+
+	path := rerank.CalibrationPath(dataDir())
+	cals, err := rerank.LoadCalibrations(path)
+	if err != nil {
+		log.Fatalf("load calibrations: %v", err)
+	}
+
+	fmt.Printf("%-36s %8s %14s %16s %9s %9s\n", "Backend", "Raw@0.5", "Acc raw→cal", "LogLoss raw→cal", "A", "B")
+	var fitted []labelledScores
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Printf("%-36s ERROR: %v\n", truncate(r.name, 36), r.err)
+			continue
+		}
+		cal, err := rerank.FitCalibration(r.scores, r.relevant)
+		if err != nil {
+			fmt.Printf("%-36s ERROR: %v\n", truncate(r.name, 36), err)
+			continue
+		}
+		calibrated := make([]float32, len(r.scores))
+		for i, s := range r.scores {
+			calibrated[i] = cal.Apply(s)
+		}
+		fmt.Printf("%-36s %8.3f %14s %16s %9.2f %9.2f\n",
+			truncate(r.name, 36), cal.Midpoint(),
+			fmt.Sprintf("%.0f%% → %.0f%%", 100*accuracy(r.scores, r.relevant), 100*accuracy(calibrated, r.relevant)),
+			fmt.Sprintf("%.3f → %.3f", logLoss(r.scores, r.relevant), logLoss(calibrated, r.relevant)),
+			cal.A, cal.B)
+		cals[r.name] = cal
+		fitted = append(fitted, r)
+	}
+	fmt.Println()
+	fmt.Println("Raw@0.5 is the raw score calibrated to 0.5; accuracy counts pairs on the right side of 0.5.")
+
+	if *dryRun || len(fitted) == 0 {
+		return
+	}
+	if err := cals.Save(path); err != nil {
+		log.Fatalf("save calibrations: %v", err)
+	}
+	fmt.Printf("Saved %d calibrations to %s\n", len(fitted), path)
+
+	// Cached rerank scores were calibrated with the old fit.
+	st := openDB()
+	defer st.Close()
+	for _, r := range fitted {
+		if !r.rerank {
+			continue
+		}
+		if n, err := st.ClearRerankScores(r.name); err != nil {
+			fmt.Fprintf(os.Stderr, "clear cached scores for %s: %v\n", r.name, err)
+		} else if n > 0 {
+			fmt.Printf("Cleared %d cached scores from %s\n", n, r.name)
+		}
+	}
+}
+
+// calibrationBackends returns every reranker and embedder the TUI could
+// use here, named as the TUI names them: the built-in ones always, Jina
+// with JINA_API_KEY, RERANK_URL, OPENAI_EMBED_URL, and Ollama if it is
+// running. skipped describes the ones that are not configured.
+func calibrationBackends(endpoint, model string) (rerankers []rerank.Reranker, embedders []embed.Embedder, skipped []string) {
+	rerankers = append(rerankers, rerank.NewLocalReranker())
+	embedders = append(embedders, embed.NewLocalEmbedder())
+
+	if apiKey := strings.TrimSpace(os.Getenv("JINA_API_KEY")); apiKey != "" {
+		rerankers = append(rerankers, newJinaReranker(apiKey, nil))
+		embedders = append(embedders, newJinaEmbedder(apiKey, nil, false))
+	} else {
+		skipped = append(skipped, "jina (JINA_API_KEY not set)")
+	}
+	if os.Getenv("RERANK_URL") != "" {
+		rerankers = append(rerankers, newTEIReranker())
+	} else {
+		skipped = append(skipped, "tei (RERANK_URL not set)")
+	}
+	if url := os.Getenv("OPENAI_EMBED_URL"); url != "" {
+		dims, _ := strconv.Atoi(os.Getenv("OPENAI_EMBED_DIMENSIONS"))
+		embedders = append(embedders, embed.NewOpenAICompatEmbedder(embed.OpenAICompatConfig{
+			BaseURL:     url,
+			Model:       os.Getenv("OPENAI_EMBED_MODEL"),
+			Dimensions:  dims,
+			QueryPrefix: os.Getenv("OPENAI_EMBED_QUERY_PREFIX"),
+			DocPrefix:   os.Getenv("OPENAI_EMBED_DOC_PREFIX"),
+			APIKey:      strings.TrimSpace(os.Getenv("OPENAI_API_KEY")),
+			AuthHeader:  os.Getenv("OPENAI_AUTH_HEADER"),
+		}))
+	}
+	if ollama, _ := newOllamaTestReranker(endpoint, model); ollama.Available() {
+		rerankers = append(rerankers, ollama)
+	} else {
+		skipped = append(skipped, "ollama reranker (no reranker model)")
+	}
+	if endpoint != "" {
+		embedders = append(embedders, embed.NewOllamaEmbedder(endpoint, envOrDefault("OLLAMA_EMBED_MODEL", "mxbai-embed-large")))
+	}
+	return rerankers, embedders, skipped
+}
+
+// scoreWithReranker scores every labelled pair with r, one call per query.
+func scoreWithReranker(set filter.QualitySet, r rerank.Reranker) labelledScores {
+	out := labelledScores{name: r.Name(), rerank: true}
+	docs := make([]rerank.Document, len(set.Items))
+	for i, item := range set.Items {
+		// No publish date: the local reranker's recency bonus would
+		// depend on when calibration ran.
+		docs[i] = rerank.Document{Title: item.Title, Summary: item.Summary, Author: item.Author}
+	}
+	for _, q := range set.Queries {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+		scores, err := rerank.RerankDocuments(ctx, r, q.Query, nil, docs)
+		cancel()
+		if err != nil {
+			out.err = err
+			return out
+		}
+		for i, item := range set.Items {
+			out.scores = append(out.scores, scores[i].Score)
+			out.relevant = append(out.relevant, q.IsRelevant(item.ID))
+		}
+	}
+	return out
+}
+
+// scoreWithEmbedder scores every labelled pair by cosine similarity
+// between e's query and document vectors.
+func scoreWithEmbedder(set filter.QualitySet, e embed.Embedder) labelledScores {
+	out := labelledScores{name: rerank.CosineKey(embed.ModelName(e))}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	docs := make([][]float32, len(set.Items))
+	for i, item := range set.Items {
+		v, err := e.Embed(ctx, embed.DocumentText(item.Title, item.Summary))
+		if err != nil {
+			out.err = err
+			return out
+		}
+		docs[i] = v
+	}
+	for _, q := range set.Queries {
+		qv, err := embed.EmbedQuery(ctx, e, q.Query)
+		if err != nil {
+			out.err = err
+			return out
+		}
+		for i, item := range set.Items {
+			out.scores = append(out.scores, embed.CosineSimilarity(qv, docs[i]))
+			out.relevant = append(out.relevant, q.IsRelevant(item.ID))
+		}
+	}
+	return out
+}
+
+// accuracy returns the share of scores on the labelled side of 0.5.
+func accuracy(scores []float32, relevant []bool) float64 {
+	right := 0
+	for i, s := range scores {
+		if (s >= 0.5) == relevant[i] {
+			right++
+		}
+	}
+	return float64(right) / float64(len(scores))
+}
+
+// logLoss returns the mean cross-entropy of scores read as probabilities
+// (clamped into (0, 1), so raw cosines and logits are penalized, not
+// undefined).
+func logLoss(scores []float32, relevant []bool) float64 {
+	var sum float64
+	for i, s := range scores {
+		p := math.Min(math.Max(float64(s), 1e-6), 1-1e-6)
+		if relevant[i] {
+			sum -= math.Log(p)
+		} else {
+			sum -= math.Log(1 - p)
+		}
+	}
+	return sum / float64(len(scores))
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+const embedQueueUsage = `Usage:
+  obs embed-queue [--state pending|dead] [--limit N]   Show queue counts and failed jobs
+  obs embed-queue requeue [item-id ...]                Retry the given items (all dead jobs if none)
+`
+
+func runEmbedQueue() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "requeue":
+			embedQueueRequeue(os.Args[2:])
+			return
+		case "help":
+			fmt.Print(embedQueueUsage)
+			return
+		}
+	}
+
+	fs := flag.NewFlagSet("embed-queue", flag.ExitOnError)
+	state := fs.String("state", "", "Only show jobs in this state (pending, dead)")
+	limit := fs.Int("limit", 50, "Max jobs to list")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, embedQueueUsage); fs.PrintDefaults() }
+	fs.Parse(os.Args[1:])
+
+	if *state != "" && *state != store.EmbedJobPending && *state != store.EmbedJobDead {
+		log.Fatalf("unknown state %q (want pending or dead)", *state)
+	}
+
+	st := openDB()
+	defer st.Close()
+
+	stats, err := st.EmbedQueueStats(dominantModel(st))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Printf("Ready:    %d\n", stats.Ready)
+	fmt.Printf("Backoff:  %d\n", stats.Backoff)
+	fmt.Printf("Dead:     %d\n", stats.Dead)
+
+	jobs, err := st.ListEmbedJobs(*state, *limit)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if len(jobs) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%-16s %-7s %3s  %-10s %-40s %s\n", "ITEM", "STATE", "TRY", "NEXT", "TITLE", "LAST ERROR")
+	for _, j := range jobs {
+		next := "-"
+		if j.State == store.EmbedJobPending {
+			if d := time.Until(j.NextAttempt); d > 0 {
+				next = "in " + d.Round(time.Second).String()
+			} else {
+				next = "due"
+			}
+		}
+		fmt.Printf("%-16s %-7s %3d  %-10s %-40s %s\n",
+			truncate(j.ItemID, 16), j.State, j.Attempts, next, truncate(j.Title, 40), truncate(j.LastError, 60))
+	}
+	if stats.Dead > 0 {
+		fmt.Println("\nRetry dead jobs with: obs embed-queue requeue")
+	}
+}
+
+func embedQueueRequeue(ids []string) {
+	st := openDB()
+	defer st.Close()
+
+	n, err := st.RequeueEmbedJobs(ids...)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Printf("Requeued %d jobs\n", n)
+}
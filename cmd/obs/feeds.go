@@ -0,0 +1,194 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+
+	"github.com/abelbrown/observer/internal/fetch"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+const feedsUsage = `Usage:
+  obs feeds list                          List subscribed feeds
+  obs feeds add [flags] <url>             Subscribe to an RSS, Atom or JSON feed
+  obs feeds remove <url>                  Unsubscribe (fetched items are kept)
+  obs feeds import <file.opml>            Import subscriptions from OPML
+  obs feeds export [file.opml]            Export subscriptions as OPML (stdout if no file)
+`
+
+func runFeeds() {
+	if len(os.Args) < 2 {
+		fmt.Print(feedsUsage)
+		os.Exit(1)
+	}
+	sub := os.Args[1]
+	args := os.Args[2:]
+
+	switch sub {
+	case "list":
+		feedsList()
+	case "add":
+		feedsAdd(args)
+	case "remove", "rm":
+		feedsRemove(args)
+	case "import":
+		feedsImport(args)
+	case "export":
+		feedsExport(args)
+	case "-h", "--help", "help":
+		fmt.Print(feedsUsage)
+	default:
+		fmt.Fprintf(os.Stderr, "obs feeds: unknown subcommand %q\n\n", sub)
+		fmt.Print(feedsUsage)
+		os.Exit(1)
+	}
+}
+
+func feedsList() {
+	st := openDB()
+	defer st.Close()
+
+	feeds, err := st.ListFeeds()
+	if err != nil {
+		log.Fatalf("list feeds: %v", err)
+	}
+	if len(feeds) == 0 {
+		fmt.Println("No feeds. Add one with: obs feeds add <url>")
+		return
+	}
+	fmt.Printf("%-15s %-30s %s\n", "CATEGORY", "TITLE", "URL")
+	for _, f := range feeds {
+		fmt.Printf("%-15s %-30s %s\n", truncate(f.Category, 15), truncate(f.Title, 30), f.URL)
+	}
+	fmt.Printf("\n%d feeds\n", len(feeds))
+}
+
+func feedsAdd(args []string) {
+	fs := flag.NewFlagSet("feeds add", flag.ExitOnError)
+	title := fs.String("title", "", "Display name (default: the feed's own title)")
+	category := fs.String("category", "", "Category")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: obs feeds add [--title T] [--category C] <url>")
+		os.Exit(1)
+	}
+	feedURL := fs.Arg(0)
+	if err := validateFeedURL(feedURL); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	st := openDB()
+	defer st.Close()
+
+	added, err := st.AddFeed(store.Feed{URL: feedURL, Title: *title, Category: *category})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if added {
+		fmt.Printf("Added %s\n", feedURL)
+	} else {
+		fmt.Printf("Updated %s\n", feedURL)
+	}
+}
+
+func feedsRemove(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: obs feeds remove <url>")
+		os.Exit(1)
+	}
+
+	st := openDB()
+	defer st.Close()
+
+	removed, err := st.RemoveFeed(args[0])
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if !removed {
+		fmt.Fprintf(os.Stderr, "no such feed: %s\n", args[0])
+		os.Exit(1)
+	}
+	fmt.Printf("Removed %s\n", args[0])
+}
+
+func feedsImport(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: obs feeds import <file.opml>")
+		os.Exit(1)
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		log.Fatalf("open OPML: %v", err)
+	}
+	defer f.Close()
+
+	feeds, err := fetch.ParseOPML(f)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	st := openDB()
+	defer st.Close()
+
+	var added, updated, skipped int
+	for _, feed := range feeds {
+		if err := validateFeedURL(feed.URL); err != nil {
+			fmt.Fprintf(os.Stderr, "skipping %s: %v\n", feed.URL, err)
+			skipped++
+			continue
+		}
+		isNew, err := st.AddFeed(feed)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if isNew {
+			added++
+		} else {
+			updated++
+		}
+	}
+	fmt.Printf("Imported %d feeds (%d new, %d updated, %d skipped)\n", added+updated, added, updated, skipped)
+}
+
+func feedsExport(args []string) {
+	st := openDB()
+	defer st.Close()
+
+	feeds, err := st.ListFeeds()
+	if err != nil {
+		log.Fatalf("list feeds: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if len(args) > 0 {
+		f, err := os.Create(args[0])
+		if err != nil {
+			log.Fatalf("create %s: %v", args[0], err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := fetch.WriteOPML(w, "Observer feeds", feeds); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Exported %d feeds to %s\n", len(feeds), args[0])
+	}
+}
+
+// validateFeedURL rejects anything that is not an absolute http(s) URL.
+func validateFeedURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid feed URL %q: %w", raw, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid feed URL %q: must be an absolute http(s) URL", raw)
+	}
+	return nil
+}
@@ -6,10 +6,16 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/abelbrown/observer/internal/config"
 	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/fetch"
+	"github.com/abelbrown/observer/internal/filter"
 	"github.com/abelbrown/observer/internal/rerank"
+	"github.com/abelbrown/observer/internal/resilience"
 	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
 // dataDir returns ~/.observer/, creating it if needed.
@@ -44,6 +50,107 @@ func openDB() *store.Store {
 	return st
 }
 
+// loadConfig loads ~/.observer/config.json (defaults if missing) or fatals.
+func loadConfig() config.Config {
+	cfg, err := config.Load(config.Path(dataDir()))
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	return cfg
+}
+
+// feedPipeline builds the configured feed filter pipeline — the one the
+// TUI runs — or fatals.
+func feedPipeline(cfg config.Config) filter.Pipeline {
+	p, err := filter.NewRegistry().Build(cfg.Pipeline)
+	if err != nil {
+		log.Fatalf("failed to build pipeline: %v", err)
+	}
+	return p
+}
+
+// modelEmbeddings returns a loader for st's vectors from model, for a
+// filter.Env. Errors are reported and give no vectors, so semantic dedup
+// falls back to URL dedup.
+func modelEmbeddings(st *store.Store, model string) func([]store.Item) map[string][]float32 {
+	return func(items []store.Item) map[string][]float32 {
+		ids := make([]string, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		embeddings, err := st.GetModelEmbeddings(ids, model)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "get embeddings: %v\n", err)
+		}
+		return embeddings
+	}
+}
+
+// catalogSelection returns every Clarion catalog source under the configured
+// selection and mutes, exactly as the fetch loop will see them.
+// Returns nil if no clarion provider is enabled.
+func catalogSelection(cfg config.Config, st *store.Store) []fetch.SourceState {
+	for _, pc := range cfg.Providers {
+		if pc.Type != "clarion" || !pc.IsEnabled() {
+			continue
+		}
+		p, err := fetch.NewClarionProviderFromConfig(pc, cfg.Sources, st, nil)
+		if err != nil {
+			log.Fatalf("provider %q: %v", pc.Name, err)
+		}
+		return p.Sources()
+	}
+	return nil
+}
+
+// excludedSources returns source names that the selection skips: disabled
+// catalog sources plus everything muted from the TUI (including user feeds).
+func excludedSources(cfg config.Config, st *store.Store) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range fetch.DisabledSourceNames(catalogSelection(cfg, st)) {
+		add(name)
+	}
+	muted, err := st.ListMutedSources()
+	if err != nil {
+		log.Fatalf("failed to list muted sources: %v", err)
+	}
+	for _, name := range muted {
+		add(name)
+	}
+	return names
+}
+
+// budget returns the configured token budget.
+func budget(cfg config.Config) usage.Budget {
+	return usage.Budget{DailyTokens: cfg.Budgets.DailyTokens, MonthlyTokens: cfg.Budgets.MonthlyTokens}
+}
+
+// checkBudget returns an error if today's or this month's usage has
+// reached the configured budget.
+func checkBudget(cfg config.Config, st *store.Store) error {
+	b := budget(cfg)
+	if !b.Enabled() {
+		return nil
+	}
+	now := time.Now()
+	day, err := st.UsageTotals(usage.StartOfDay(now))
+	if err != nil {
+		return err
+	}
+	month, err := st.UsageTotals(usage.StartOfMonth(now))
+	if err != nil {
+		return err
+	}
+	return b.Check(day, month)
+}
+
 // requireJinaKey returns the JINA_API_KEY or fatals.
 func requireJinaKey() string {
 	key := strings.TrimSpace(os.Getenv("JINA_API_KEY"))
@@ -63,16 +170,28 @@ func envOrDefault(key, fallback string) string {
 	return fallback
 }
 
-// newJinaEmbedder creates a Jina embedder with the configured model.
-func newJinaEmbedder(apiKey string) *embed.JinaEmbedder {
+// newJinaEmbedder creates a Jina embedder with the configured model,
+// recording usage to rec. Calls are retried on transient failures and,
+// if throttle is set, spaced to stay under the free-tier rate limit.
+func newJinaEmbedder(apiKey string, rec usage.Recorder, throttle bool) embed.BatchEmbedder {
 	model := envOrDefault("JINA_EMBED_MODEL", "jina-embeddings-v3")
-	return embed.NewJinaEmbedder(apiKey, model)
+	raw := embed.NewJinaEmbedder(apiKey, model)
+	raw.SetUsageRecorder(rec)
+	var e embed.Embedder = raw
+	if throttle {
+		e = embed.WithRateLimit(e, embed.JinaRateInterval)
+	}
+	// The decorators keep EmbedBatch, so the assertion always holds.
+	return embed.WithRetry(e, resilience.DefaultRetryPolicy).(embed.BatchEmbedder)
 }
 
-// newJinaReranker creates a Jina reranker with the configured model.
-func newJinaReranker(apiKey string) *rerank.JinaReranker {
+// newJinaReranker creates a Jina reranker with the configured model,
+// recording usage to rec and retrying transient failures.
+func newJinaReranker(apiKey string, rec usage.Recorder) rerank.Reranker {
 	model := envOrDefault("JINA_RERANK_MODEL", "jina-reranker-v3")
-	return rerank.NewJinaReranker(apiKey, model)
+	raw := rerank.NewJinaReranker(apiKey, model)
+	raw.SetUsageRecorder(rec)
+	return rerank.WithRetry(raw, resilience.DefaultRetryPolicy)
 }
 
 // truncate shortens a string to max runes, appending "..." if truncated.
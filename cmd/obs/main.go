@@ -6,9 +6,16 @@
 //	obs backfill            Batch embed items missing embeddings
 //	obs stats               Pipeline statistics
 //	obs stats --db          Pipeline statistics + DB health
+//	obs pipeline explain    Per-stage counts through the configured feed pipeline
 //	obs search <query>      Two-stage search pipeline debug
-//	obs rerank              Reranker validation (Ollama)
+//	obs rerank              Reranker validation (Ollama, Jina, TEI, local; --compare)
+//	obs calibrate           Fit per-backend score calibrations on the labelled set
 //	obs events              JSONL event log viewer
+//	obs feeds <subcommand>  Manage user feeds (list, add, remove, import, export)
+//	obs sources             Clarion catalog selection (enabled/disabled, mute)
+//	obs embed-queue         Inspect failed embedding jobs; requeue dead ones
+//	obs usage               AI backend usage by model and purpose vs budgets
+//	obs trending            Stories many sources just took up (--window 1h)
 package main
 
 import (
@@ -16,7 +23,7 @@ import (
 	"os"
 )
 
-const usage = `obs — Observer debug & maintenance CLI
+const usageText = `obs — Observer debug & maintenance CLI
 
 Usage:
   obs <command> [flags]
@@ -24,21 +31,31 @@ Usage:
 Commands:
   backfill    Batch embed items missing embeddings (requires JINA_API_KEY)
   stats       Pipeline statistics and source distribution
+  pipeline    Explain the configured feed filter pipeline with per-stage counts
   search      Two-stage search pipeline debug (requires JINA_API_KEY)
-  rerank      Reranker validation with test headlines (Ollama)
+  rerank      Reranker validation with test headlines (--backend ollama|jina|tei|local, --compare)
+  calibrate   Fit per-backend score calibrations on the labelled set (--dry-run)
   events      JSONL event log viewer
+  feeds       Manage user feeds: list, add, remove, OPML import/export
+  sources     Show the Clarion source selection; mute/unmute sources
+  embed-queue Show embedding retries and dead-lettered items; requeue them
+  usage       Show API usage (tokens, requests, latency) against budgets
+  trending    Stories many sources took up at once (--window 1h)
 
 Environment:
   JINA_API_KEY       Jina AI API key (required for backfill, search)
   JINA_EMBED_MODEL   Embedding model (default: jina-embeddings-v3)
   JINA_RERANK_MODEL  Reranking model (default: jina-reranker-v3)
+  RERANK_URL         Self-hosted /rerank server (TEI, Infinity, llama.cpp) for rerank
+  RERANK_MODEL       Model sent to RERANK_URL (optional for single-model servers)
+  RERANK_API_KEY     Bearer token for RERANK_URL (optional)
 
 Run 'obs <command> -h' for command-specific help.
 `
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Print(usage)
+		fmt.Print(usageText)
 		os.Exit(0)
 	}
 
@@ -51,17 +68,31 @@ func main() {
 		runBackfill()
 	case "stats":
 		runStats()
+	case "pipeline":
+		runPipeline()
 	case "search":
 		runSearch()
 	case "rerank":
 		runRerank()
+	case "calibrate":
+		runCalibrate()
 	case "events":
 		runEvents()
+	case "feeds":
+		runFeeds()
+	case "sources":
+		runSources()
+	case "embed-queue":
+		runEmbedQueue()
+	case "usage":
+		runUsage()
+	case "trending":
+		runTrending()
 	case "-h", "--help", "help":
-		fmt.Print(usage)
+		fmt.Print(usageText)
 	default:
 		fmt.Fprintf(os.Stderr, "obs: unknown command %q\n\n", cmd)
-		fmt.Print(usage)
+		fmt.Print(usageText)
 		os.Exit(1)
 	}
 }
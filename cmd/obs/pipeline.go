@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+const pipelineUsage = `Usage:
+  obs pipeline explain [flags]            Run the feed pipeline and print per-stage counts
+
+The pipeline is the "pipeline" list in ~/.observer/config.json (stage,
+name, enabled, options); without one, the default max_age → rules →
+semantic_dedup → limit_per_source. --drops lists what each stage dropped
+and why.
+`
+
+func runPipeline() {
+	if len(os.Args) < 2 {
+		fmt.Print(pipelineUsage)
+		os.Exit(1)
+	}
+	sub := os.Args[1]
+	args := os.Args[2:]
+
+	switch sub {
+	case "explain":
+		pipelineExplain(args)
+	case "-h", "--help", "help":
+		fmt.Print(pipelineUsage)
+	default:
+		fmt.Fprintf(os.Stderr, "obs pipeline: unknown subcommand %q\n\n", sub)
+		fmt.Print(pipelineUsage)
+		os.Exit(1)
+	}
+}
+
+func pipelineExplain(args []string) {
+	fs := flag.NewFlagSet("pipeline explain", flag.ExitOnError)
+	includeRead := fs.Bool("read", false, "Include read items (the TUI feed shows unread only)")
+	limit := fs.Int("limit", 10000, "Items to load, newest first")
+	model := fs.String("model", "", "Embedding model for semantic stages (default: the model most items have)")
+	drops := fs.Int("drops", 0, "List up to this many dropped items per stage, with the reason")
+	fs.Parse(args)
+
+	cfg := loadConfig()
+	st := openDB()
+	defer st.Close()
+
+	items, err := st.GetItems(*limit, *includeRead)
+	if err != nil {
+		log.Fatalf("get items: %v", err)
+	}
+	explainPipeline(st, cfg, items, *model, *drops)
+}
+
+// explainPipeline runs the configured pipeline over items as the TUI
+// does — muted sources first — printing a row per stage and, if drops
+// > 0, up to drops of the items each stage dropped. Returns the survivors.
+func explainPipeline(st *store.Store, cfg config.Config, items []store.Item, model string, drops int) []store.Item {
+	p := feedPipeline(cfg)
+	if model == "" {
+		model = dominantModel(st)
+	}
+
+	rules, err := st.ListRules()
+	if err != nil {
+		log.Fatalf("list rules: %v", err)
+	}
+	rs, err := filter.NewRuleSet(rules, model)
+	if err != nil {
+		log.Fatalf("compile rules: %v", err)
+	}
+	env := &filter.Env{LoadEmbeddings: modelEmbeddings(st, model), Rules: rs, Trace: &filter.Trace{}}
+
+	muted, err := st.ListMutedSources()
+	if err != nil {
+		log.Fatalf("list muted sources: %v", err)
+	}
+	in := len(items)
+	items = filter.ExcludeMuted(items, muted, env)
+	unmuted := len(items)
+	items, counts := p.Explain(items, env)
+
+	if model == "" {
+		model = "(none)"
+	}
+	fmt.Printf("Embedding model: %s (%d vectors loaded)\n\n", model, len(env.Embeddings))
+	fmt.Printf("%-22s %-32s %7s %7s %8s %9s\n", "Stage", "Options", "In", "Out", "Dropped", "Time")
+	fmt.Printf("%-22s %-32s %7d %7d %8d %9s\n", "(muted sources)", truncate(strings.Join(muted, ","), 32), in, unmuted, in-unmuted, "")
+	for _, c := range counts {
+		name := c.Stage.Name
+		if name != c.Stage.Type {
+			name += " (" + c.Stage.Type + ")"
+		}
+		fmt.Printf("%-22s %-32s %7d %7d %8d %9s\n", truncate(name, 22), truncate(compactJSON(c.Stage.Options), 32),
+			c.In, c.Out, c.In-c.Out, c.Elapsed.Round(time.Microsecond))
+	}
+	fmt.Printf("\n%d of %d items reach the feed\n", len(items), in)
+
+	if drops > 0 {
+		printDrops(env.Trace, "muted sources", drops)
+		for _, c := range counts {
+			printDrops(env.Trace, c.Stage.Name, drops)
+		}
+		fmt.Println()
+	}
+	return items
+}
+
+// printDrops lists up to max of the items stage dropped, with why.
+func printDrops(trace *filter.Trace, stage string, max int) {
+	dropped := trace.DroppedBy(stage)
+	if len(dropped) == 0 {
+		return
+	}
+	fmt.Printf("\nDropped by %s (%d):\n", stage, len(dropped))
+	for i, d := range dropped {
+		if i == max {
+			fmt.Printf("  ... and %d more\n", len(dropped)-max)
+			break
+		}
+		fmt.Printf("  %-16s %-48s %s\n", truncate(d.Item.SourceName, 16), truncate(d.Item.Title, 48), d.Reason)
+	}
+}
+
+// dominantModel returns the model most stored vectors come from, or ""
+// if there are none.
+func dominantModel(st *store.Store) string {
+	counts, err := st.EmbeddingModelCounts()
+	if err != nil {
+		log.Fatalf("count embedding models: %v", err)
+	}
+	best := ""
+	for model, n := range counts {
+		if n > counts[best] || n == counts[best] && model < best {
+			best = model
+		}
+	}
+	return best
+}
+
+// compactJSON returns raw on one line, as written if it doesn't parse.
+func compactJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
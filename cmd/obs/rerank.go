@@ -5,9 +5,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/abelbrown/observer/internal/rerank"
+	"github.com/abelbrown/observer/internal/resilience"
 )
 
 // testHeadlines is a fixed set of headlines for reranker validation.
@@ -39,51 +41,59 @@ var testHeadlines = []string{
 	"Scientists discover high-speed winds deep below Jupiter's clouds",
 }
 
+// relevantHeadlines is how many of testHeadlines, from the start, are
+// about the default query.
+const relevantHeadlines = 6
+
+// defaultRerankQuery is the query testHeadlines are labelled for.
+const defaultRerankQuery = "super bowl"
+
 func runRerank() {
 	fs := flag.NewFlagSet("rerank", flag.ExitOnError)
-	query := fs.String("query", "super bowl", "Query to test")
+	query := fs.String("query", defaultRerankQuery, "Query to test")
+	backend := fs.String("backend", "ollama", "Reranker to validate: ollama, jina, tei (RERANK_URL) or local")
+	compare := fs.Bool("compare", false, "Compare the local reranker with Jina (JINA_API_KEY), RERANK_URL and Ollama")
 	model := fs.String("model", "", "Ollama model name (auto-detects if empty)")
 	endpoint := fs.String("endpoint", "", "Ollama endpoint (default: http://localhost:11434)")
 	fs.Parse(os.Args[1:])
 
-	fmt.Println("=== Reranker Validation ===")
-	fmt.Println()
-
-	// Create reranker
-	modelName := *model
-	if modelName == "" {
-		modelName = "dengcao/Qwen3-Reranker-4B:Q5_K_M"
+	if *compare {
+		runRerankCompare(*query, *endpoint, *model)
+		return
 	}
 
-	reranker := rerank.NewOllamaReranker(*endpoint, modelName)
-
-	fmt.Printf("Endpoint: %s\n", func() string {
-		if *endpoint == "" {
-			return "http://localhost:11434 (default)"
-		}
-		return *endpoint
-	}())
-	fmt.Printf("Model: %s\n", reranker.Name())
-	fmt.Printf("Available: %v\n", reranker.Available())
+	fmt.Println("=== Reranker Validation ===")
 	fmt.Println()
 
-	// Auto-detect if specified model unavailable
-	if !reranker.Available() && *model == "" {
-		fmt.Println("Specified model not available, trying auto-detection...")
-		reranker = rerank.NewOllamaReranker(*endpoint, "")
-		fmt.Printf("Auto-detected model: %s\n", reranker.Name())
-		fmt.Printf("Available: %v\n", reranker.Available())
+	var reranker rerank.Reranker
+	switch *backend {
+	case "ollama":
+		reranker = ollamaValidationReranker(*endpoint, *model)
+	case "jina":
+		apiKey := os.Getenv("JINA_API_KEY")
+		if apiKey == "" {
+			fmt.Fprintln(os.Stderr, "JINA_API_KEY not set")
+			os.Exit(1)
+		}
+		reranker = newJinaReranker(apiKey, nil)
+		fmt.Printf("Model: %s\n", reranker.Name())
 		fmt.Println()
-	}
-
-	if !reranker.Available() {
-		fmt.Println("ERROR: No reranker model available!")
+	case "tei":
+		if os.Getenv("RERANK_URL") == "" {
+			fmt.Fprintln(os.Stderr, "RERANK_URL not set")
+			os.Exit(1)
+		}
+		reranker = newTEIReranker()
+		fmt.Printf("Endpoint: %s\n", os.Getenv("RERANK_URL"))
+		fmt.Printf("Model: %s\n", reranker.Name())
 		fmt.Println()
-		fmt.Println("To fix this:")
-		fmt.Println("  1. Make sure Ollama is running: ollama serve")
-		fmt.Println("  2. Pull a reranker model:")
-		fmt.Println("     ollama pull dengcao/Qwen3-Reranker-4B:Q5_K_M")
-		os.Exit(1)
+	case "local":
+		reranker = rerank.NewLocalReranker()
+		fmt.Printf("Model: %s (built-in)\n", reranker.Name())
+		fmt.Println()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown backend %q (want ollama, jina, tei or local)\n", *backend)
+		os.Exit(2)
 	}
 
 	fmt.Printf("Query: %q\n", *query)
@@ -150,3 +160,150 @@ func runRerank() {
 		fmt.Println("NOTE: Fewer than expected relevant headlines found.")
 	}
 }
+
+// ollamaValidationReranker creates the Ollama reranker to validate,
+// auto-detecting a model if none was given and the default isn't pulled.
+// Exits with setup instructions if Ollama has no reranker model.
+func ollamaValidationReranker(endpoint, model string) rerank.Reranker {
+	reranker, detected := newOllamaTestReranker(endpoint, model)
+
+	fmt.Printf("Endpoint: %s\n", func() string {
+		if endpoint == "" {
+			return "http://localhost:11434 (default)"
+		}
+		return endpoint
+	}())
+	if detected {
+		fmt.Println("Specified model not available, using auto-detection")
+	}
+	fmt.Printf("Model: %s\n", reranker.Name())
+	fmt.Printf("Available: %v\n", reranker.Available())
+	fmt.Println()
+
+	if !reranker.Available() {
+		fmt.Println("ERROR: No reranker model available!")
+		fmt.Println()
+		fmt.Println("To fix this:")
+		fmt.Println("  1. Make sure Ollama is running: ollama serve")
+		fmt.Println("  2. Pull a reranker model:")
+		fmt.Println("     ollama pull dengcao/Qwen3-Reranker-4B:Q5_K_M")
+		os.Exit(1)
+	}
+	return reranker
+}
+
+// newOllamaTestReranker creates an Ollama reranker for model (the Qwen3
+// reranker if empty), falling back to auto-detection when no model was
+// given and the default isn't available. detected reports the fallback.
+func newOllamaTestReranker(endpoint, model string) (r *rerank.OllamaReranker, detected bool) {
+	modelName := model
+	if modelName == "" {
+		modelName = "dengcao/Qwen3-Reranker-4B:Q5_K_M"
+	}
+	r = rerank.NewOllamaReranker(endpoint, modelName)
+	if !r.Available() && model == "" {
+		return rerank.NewOllamaReranker(endpoint, ""), true
+	}
+	return r, false
+}
+
+// runRerankCompare scores testHeadlines with every reranker that can run
+// here — the built-in one always, Jina with JINA_API_KEY, a /rerank server
+// with RERANK_URL, Ollama if it has a reranker model — and prints latency,
+// precision and each top list.
+func runRerankCompare(query, endpoint, model string) {
+	rerankers := []rerank.Reranker{rerank.NewLocalReranker()}
+	var skipped []string
+	if apiKey := os.Getenv("JINA_API_KEY"); apiKey != "" {
+		rerankers = append(rerankers, newJinaReranker(apiKey, nil))
+	} else {
+		skipped = append(skipped, "jina (JINA_API_KEY not set)")
+	}
+	if os.Getenv("RERANK_URL") != "" {
+		rerankers = append(rerankers, newTEIReranker())
+	} else {
+		skipped = append(skipped, "tei (RERANK_URL not set)")
+	}
+	if ollama, _ := newOllamaTestReranker(endpoint, model); ollama.Available() {
+		rerankers = append(rerankers, ollama)
+	} else {
+		skipped = append(skipped, "ollama (no reranker model)")
+	}
+
+	fmt.Println("=== Reranker Comparison ===")
+	fmt.Println()
+	fmt.Printf("Query: %q\n", query)
+	fmt.Printf("Documents: %d headlines", len(testHeadlines))
+	labelled := query == defaultRerankQuery
+	if labelled {
+		fmt.Printf(" (first %d relevant)", relevantHeadlines)
+	}
+	fmt.Println()
+	for _, s := range skipped {
+		fmt.Printf("Skipped: %s\n", s)
+	}
+	fmt.Println()
+
+	type result struct {
+		name    string
+		elapsed time.Duration
+		sorted  []rerank.Score
+		err     error
+	}
+	results := make([]result, len(rerankers))
+	for i, r := range rerankers {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+		start := time.Now()
+		scores, err := r.Rerank(ctx, query, testHeadlines)
+		cancel()
+		results[i] = result{name: r.Name(), elapsed: time.Since(start), sorted: rerank.SortByScore(scores), err: err}
+	}
+
+	fmt.Printf("%-40s %12s  %s\n", "Backend", "Latency", fmt.Sprintf("P@%d", relevantHeadlines))
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Printf("%-40s %12s  ERROR: %v\n", truncate(r.name, 40), r.elapsed.Round(time.Microsecond), r.err)
+			continue
+		}
+		precision := "-"
+		if labelled {
+			hits := 0
+			for _, s := range r.sorted[:min(relevantHeadlines, len(r.sorted))] {
+				if s.Index < relevantHeadlines {
+					hits++
+				}
+			}
+			precision = fmt.Sprintf("%d/%d", hits, relevantHeadlines)
+		}
+		fmt.Printf("%-40s %12s  %s\n", truncate(r.name, 40), r.elapsed.Round(time.Microsecond), precision)
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			continue
+		}
+		fmt.Println()
+		fmt.Printf("--- %s ---\n", r.name)
+		for i, s := range r.sorted[:min(relevantHeadlines+3, len(r.sorted))] {
+			marker := "  "
+			if labelled && s.Index < relevantHeadlines {
+				marker = "**"
+			}
+			fmt.Printf("%s %2d. [%.3f] %s\n", marker, i+1, s.Score, truncate(testHeadlines[s.Index], 65))
+		}
+	}
+	if labelled {
+		fmt.Println()
+		fmt.Println("Legend: ** = labelled relevant")
+	}
+}
+
+// newTEIReranker creates the /rerank server reranker from RERANK_URL,
+// RERANK_MODEL and RERANK_API_KEY.
+func newTEIReranker() rerank.Reranker {
+	return rerank.WithRetry(rerank.NewTEIReranker(rerank.TEIConfig{
+		BaseURL: os.Getenv("RERANK_URL"),
+		Model:   os.Getenv("RERANK_MODEL"),
+		APIKey:  strings.TrimSpace(os.Getenv("RERANK_API_KEY")),
+	}), resilience.DefaultRetryPolicy)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/abelbrown/observer/internal/embed"
 	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/rerank"
 	"github.com/abelbrown/observer/internal/store"
 )
 
@@ -37,40 +38,37 @@ func runSearch() {
 	if err != nil {
 		log.Fatalf("get items: %v", err)
 	}
-	allItems = filter.ByAge(allItems, 24*time.Hour)
+	embedder := newJinaEmbedder(apiKey, st, false)
+	env := &filter.Env{LoadEmbeddings: modelEmbeddings(st, embed.ModelName(embedder))}
+	allItems = feedPipeline(loadConfig()).Run(allItems, env)
 
+	// Rebuild embeddings for filtered items
+	allEmbeddings := env.EmbeddingsFor(allItems)
+	filteredEmb := make(map[string][]float32)
 	ids := make([]string, len(allItems))
 	for i, item := range allItems {
 		ids[i] = item.ID
-	}
-	allEmbeddings, err := st.GetItemsWithEmbeddings(ids)
-	if err != nil {
-		log.Fatalf("get embeddings: %v", err)
-	}
-
-	allItems = filter.SemanticDedup(allItems, allEmbeddings, 0.85)
-	allItems = filter.LimitPerSource(allItems, 50)
-
-	// Rebuild embeddings for filtered items
-	filteredEmb := make(map[string][]float32)
-	for _, item := range allItems {
 		if emb, ok := allEmbeddings[item.ID]; ok {
 			filteredEmb[item.ID] = emb
 		}
 	}
 
+	chunks, err := st.GetModelChunks(ids, embed.ModelName(embedder))
+	if err != nil {
+		log.Fatalf("get chunks: %v", err)
+	}
+
 	withEmb := 0
 	for _, item := range allItems {
 		if _, ok := filteredEmb[item.ID]; ok {
 			withEmb++
 		}
 	}
-	fmt.Printf("Items: %d total, %d with embeddings\n", len(allItems), withEmb)
+	fmt.Printf("Items: %d total, %d with embeddings, %d chunked\n", len(allItems), withEmb, len(chunks))
 	fmt.Println(strings.Repeat("=", 80))
 
 	ctx := context.Background()
-	embedder := newJinaEmbedder(apiKey)
-	reranker := newJinaReranker(apiKey)
+	reranker := newJinaReranker(apiKey, st)
 
 	for _, query := range queries {
 		fmt.Printf("\n\n>>> QUERY: %q\n", query)
@@ -78,7 +76,7 @@ func runSearch() {
 
 		// Embed query
 		t0 := time.Now()
-		queryEmb, err := embedder.EmbedQuery(ctx, query)
+		queryEmb, err := embed.EmbedQuery(ctx, embedder, query)
 		embedDur := time.Since(t0)
 		if err != nil {
 			fmt.Printf("  ERROR embedding query: %v\n", err)
@@ -87,7 +85,8 @@ func runSearch() {
 		fmt.Printf("  Query embedded in %v\n", embedDur.Round(time.Millisecond))
 
 		// Stage 1: cosine similarity
-		reranked := filter.RerankByQuery(allItems, filteredEmb, queryEmb)
+		reranked := filter.RerankByQuery(allItems, filteredEmb, chunks, queryEmb)
+		passages := filter.MatchingPassages(reranked, chunks, queryEmb)
 
 		fmt.Println("\n  STAGE 1 — Cosine Similarity (Top 10):")
 		for i := 0; i < 10 && i < len(reranked); i++ {
@@ -95,7 +94,7 @@ func runSearch() {
 			sim := float32(0)
 			hasEmb := false
 			if emb, ok := filteredEmb[item.ID]; ok {
-				sim = embed.CosineSimilarity(emb, queryEmb)
+				sim, _ = filter.MaxSim([][]float32{queryEmb}, emb, chunks[item.ID])
 				hasEmb = true
 			}
 			embTag := "NO-EMB"
@@ -103,6 +102,9 @@ func runSearch() {
 				embTag = fmt.Sprintf("%.4f", sim)
 			}
 			fmt.Printf("  %2d. [%s] %s — %s\n", i+1, embTag, item.SourceName, truncate(item.Title, 70))
+			if p, ok := passages[item.ID]; ok {
+				fmt.Printf("        ↳ %q\n", truncate(p, 90))
+			}
 		}
 
 		if *cosineOnly {
@@ -116,21 +118,53 @@ func runSearch() {
 		}
 		candidates := reranked[:n]
 
-		docs := make([]string, n)
+		// Scores cached by earlier searches (here or in the TUI) are
+		// reused; only the rest are sent to the reranker.
+		name := reranker.Name()
+		keys := make([]store.RerankKey, n)
+		for i, item := range candidates {
+			keys[i] = store.NewRerankKey(item)
+		}
+		cached, err := st.GetRerankScores(name, query, keys)
+		if err != nil {
+			log.Fatalf("get rerank cache: %v", err)
+		}
+		var docs []string
+		var sent []int
 		for i, item := range candidates {
-			docs[i] = item.Title
+			if _, ok := cached[item.ID]; ok {
+				continue
+			}
+			doc := item.Title
 			if item.Summary != "" {
-				docs[i] += " - " + item.Summary
+				doc += " - " + item.Summary
 			}
+			docs = append(docs, doc)
+			sent = append(sent, i)
 		}
 
 		t1 := time.Now()
-		scores, err := reranker.Rerank(ctx, query, docs)
+		var fresh []rerank.Score
+		if len(docs) > 0 {
+			fresh, err = reranker.Rerank(ctx, query, docs)
+		}
 		rerankDur := time.Since(t1)
 		if err != nil {
 			fmt.Printf("  ERROR reranking: %v\n", err)
 			continue
 		}
+		scores := make([]float32, n)
+		for i, item := range candidates {
+			scores[i] = cached[item.ID]
+		}
+		entries := make([]store.RerankScore, len(fresh))
+		for i, sc := range fresh {
+			scores[sent[sc.Index]] = sc.Score
+			entries[i] = store.RerankScore{RerankKey: keys[sent[sc.Index]], Score: sc.Score}
+		}
+		if err := st.SaveRerankScores(name, query, entries); err != nil {
+			log.Fatalf("save rerank cache: %v", err)
+		}
 
 		type scored struct {
 			index int
@@ -139,13 +173,13 @@ func runSearch() {
 		}
 		scoredItems := make([]scored, len(candidates))
 		for i, item := range candidates {
-			scoredItems[i] = scored{index: i, score: scores[i].Score, item: item}
+			scoredItems[i] = scored{index: i, score: scores[i], item: item}
 		}
 		sort.SliceStable(scoredItems, func(i, j int) bool {
 			return scoredItems[i].score > scoredItems[j].score
 		})
 
-		fmt.Printf("\n  STAGE 2 — Cross-Encoder Reranking (Top 10) [%v]:\n", rerankDur.Round(time.Millisecond))
+		fmt.Printf("\n  STAGE 2 — Cross-Encoder Reranking (Top 10) [%v, cache: %d/%d hits]:\n", rerankDur.Round(time.Millisecond), len(cached), n)
 		for i := 0; i < 10 && i < len(scoredItems); i++ {
 			s := scoredItems[i]
 			fmt.Printf("  %2d. [%.4f] %s — %s\n", i+1, s.score, s.item.SourceName, truncate(s.item.Title, 70))
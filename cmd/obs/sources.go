@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+const sourcesUsage = `Usage:
+  obs sources [--enabled|--disabled]      Show the Clarion catalog under the current selection
+  obs sources mute <name>                 Mute a source (same as S in the TUI)
+  obs sources unmute <name>               Unmute a source
+`
+
+func runSources() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "mute":
+			sourcesMute(os.Args[2:], true)
+			return
+		case "unmute":
+			sourcesMute(os.Args[2:], false)
+			return
+		case "help":
+			fmt.Print(sourcesUsage)
+			return
+		}
+	}
+
+	fs := flag.NewFlagSet("sources", flag.ExitOnError)
+	onlyEnabled := fs.Bool("enabled", false, "Only show sources that will be fetched")
+	onlyDisabled := fs.Bool("disabled", false, "Only show sources that are skipped")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, sourcesUsage); fs.PrintDefaults() }
+	fs.Parse(os.Args[1:])
+
+	cfg := loadConfig()
+	st := openDB()
+	defer st.Close()
+
+	states := catalogSelection(cfg, st)
+	if states == nil {
+		fmt.Println("No clarion provider is enabled in config.json; the catalog is not fetched.")
+		return
+	}
+
+	fmt.Printf("%-30s %-8s %-12s %-18s %6s %8s\n", "NAME", "TYPE", "CATEGORY", "STATUS", "ITEMS", "TIMEOUT")
+	var enabled int
+	for _, s := range states {
+		if s.Enabled {
+			enabled++
+		}
+		if (*onlyEnabled && !s.Enabled) || (*onlyDisabled && s.Enabled) {
+			continue
+		}
+		status := "enabled"
+		if !s.Enabled {
+			status = s.Reason
+		}
+		fmt.Printf("%-30s %-8s %-12s %-18s %6d %8s\n",
+			truncate(s.Source.Name, 30), truncate(string(s.Source.Type), 8), truncate(s.Source.Category, 12),
+			status, s.Options.MaxItems, s.Options.Timeout.Round(time.Second))
+	}
+	fmt.Printf("\n%d of %d sources enabled\n", enabled, len(states))
+
+	// Mutes can also cover user feeds, which are not in the catalog.
+	muted, err := st.ListMutedSources()
+	if err != nil {
+		log.Fatalf("failed to list muted sources: %v", err)
+	}
+	catalog := make(map[string]bool, len(states))
+	for _, s := range states {
+		catalog[s.Source.Name] = true
+	}
+	for _, name := range muted {
+		if !catalog[name] {
+			fmt.Printf("Also muted (not in catalog): %s\n", name)
+		}
+	}
+}
+
+func sourcesMute(args []string, mute bool) {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, sourcesUsage)
+		os.Exit(1)
+	}
+	name := args[0]
+
+	st := openDB()
+	defer st.Close()
+
+	if mute {
+		if err := st.MuteSource(name); err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("Muted %s\n", name)
+		return
+	}
+	ok, err := st.UnmuteSource(name)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s was not muted\n", name)
+		os.Exit(1)
+	}
+	fmt.Printf("Unmuted %s\n", name)
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/abelbrown/observer/internal/filter"
@@ -31,25 +32,15 @@ func runStats() {
 	recentUnread := filter.ByAge(unread, 24*time.Hour)
 	fmt.Printf("Last 24h (unread):     %d\n", len(recentUnread))
 
-	// Simulate the actual pipeline from main.go
-	items, _ := st.GetItems(500, false)
-	fmt.Printf("\nGetItems(500, false):   %d\n", len(items))
-
-	items = filter.ByAge(items, 24*time.Hour)
-	fmt.Printf("After ByAge(24h):      %d\n", len(items))
-
-	ids := make([]string, len(items))
-	for i, item := range items {
-		ids[i] = item.ID
+	// Run the feed pipeline the TUI runs
+	fmt.Println()
+	items := explainPipeline(st, loadConfig(), unread, "", 10)
+	if c, err := st.EmbedCacheStats(); err == nil {
+		fmt.Printf("Embedding cache:       %d entries, %d hits (%.1f%% hit rate)\n", c.Entries, c.Hits, c.HitRate()*100)
+	}
+	if c, err := st.RerankCacheStats(); err == nil && c.Entries > 0 {
+		fmt.Printf("Rerank cache:          %d scores, %d hits (%.1f%% hit rate)\n", c.Entries, c.Hits, c.HitRate()*100)
 	}
-	embeddings, _ := st.GetItemsWithEmbeddings(ids)
-	fmt.Printf("With embeddings:       %d\n", len(embeddings))
-
-	items = filter.SemanticDedup(items, embeddings, 0.85)
-	fmt.Printf("After SemanticDedup:   %d\n", len(items))
-
-	items = filter.LimitPerSource(items, 50)
-	fmt.Printf("After LimitPerSource:  %d\n", len(items))
 
 	// Count sources
 	sources := map[string]int{}
@@ -69,16 +60,42 @@ func runStats() {
 	fmt.Println()
 	fmt.Println("=== DB Health ===")
 
+	// Coverage is counted for the model most vectors come from.
+	model := dominantModel(st)
 	totalItems, _ := st.CountAllItems()
-	needingEmbedding, _ := st.CountItemsNeedingEmbedding()
+	needingEmbedding, _ := st.CountItemsNeedingEmbedding(model)
 	existingEmbeddings := totalItems - needingEmbedding
 
 	fmt.Printf("Total items:           %d\n", totalItems)
+	if model != "" {
+		fmt.Printf("Embedding model:       %s\n", model)
+	}
 	fmt.Printf("With embeddings:       %d\n", existingEmbeddings)
 	if totalItems > 0 {
 		fmt.Printf("Embedding coverage:    %.1f%%\n", float64(existingEmbeddings)/float64(totalItems)*100)
 	}
 	fmt.Printf("Needing embedding:     %d\n", needingEmbedding)
+	if chunks, chunked, err := st.CountChunks(); err == nil && chunks > 0 {
+		fmt.Printf("Chunked (long) items:  %d  (%d passage vectors)\n", chunked, chunks)
+	}
+	if models, err := st.EmbeddingModelCounts(); err == nil && len(models) > 1 {
+		names := make([]string, 0, len(models))
+		for name := range models {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			label := name
+			if label == "" {
+				label = "(unrecorded)"
+			}
+			fmt.Printf("  %-28s %d\n", label, models[name])
+		}
+	}
+	if q, err := st.EmbedQueueStats(model); err == nil && (q.Backoff > 0 || q.Dead > 0) {
+		fmt.Printf("  backing off:         %d\n", q.Backoff)
+		fmt.Printf("  dead-lettered:       %d  (obs embed-queue)\n", q.Dead)
+	}
 
 	// Timestamp analysis
 	sample, _ := st.GetItems(5000, true)
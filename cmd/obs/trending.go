@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abelbrown/observer/internal/thread"
+)
+
+const trendingUsage = `Usage:
+  obs trending [--window 1h] [--baseline 24h] [--min-sources 3] [--burst 3]
+
+Lists story threads many sources took up within the window: at least
+--min-sources distinct sources, at --burst times the thread's usual
+reports per window over the baseline before it. Threads are linked by
+the embedding worker (TUI or daemon) as items are embedded.
+`
+
+func runTrending() {
+	fs := flag.NewFlagSet("trending", flag.ExitOnError)
+	d := thread.DefaultDetector
+	fs.DurationVar(&d.Window, "window", d.Window, "How recent a burst is")
+	fs.DurationVar(&d.Baseline, "baseline", d.Baseline, "History before the window the burst is compared against")
+	fs.IntVar(&d.MinSources, "min-sources", d.MinSources, "Distinct sources that must report within the window")
+	fs.Float64Var(&d.Burst, "burst", d.Burst, "Sources in the window over the usual reports per window, one added to each")
+	limit := fs.Int("limit", 20, "Max stories to list")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, trendingUsage); fs.PrintDefaults() }
+	fs.Parse(os.Args[1:])
+
+	if d.Window <= 0 || d.Baseline <= 0 {
+		log.Fatalf("--window and --baseline must be positive")
+	}
+
+	st := openDB()
+	defer st.Close()
+
+	now := time.Now()
+	threads, reports, err := st.ThreadItemsSince(d.Since(now))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	trends := d.Detect(threads, reports, now)
+
+	fmt.Printf("Trending in the last %s (%d threads active; ≥%d sources, ≥%.1f× the rate over the %s before)\n",
+		shortDuration(d.Window), len(threads), d.MinSources, d.Burst, shortDuration(d.Baseline))
+	if len(trends) == 0 {
+		fmt.Println("\nNothing trending.")
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%8s %7s %7s %8s  %s\n", "VELOCITY", "SOURCES", "REPORTS", "BASELINE", "STORY")
+	for i, tr := range trends {
+		if i == *limit {
+			fmt.Printf("... and %d more (--limit)\n", len(trends)-*limit)
+			break
+		}
+		first, latest := tr.Reports[0], tr.Reports[len(tr.Reports)-1]
+		fmt.Printf("%7.1f× %7d %7d %8.2f  %s\n", tr.Velocity, len(tr.Sources), tr.Recent, tr.Baseline, truncate(tr.Thread.Title, 60))
+		fmt.Printf("%34s %s\n", "", truncate(strings.Join(tr.Sources, ", "), 60))
+		fmt.Printf("%34s first %s ago, latest %s ago (thread #%d)\n", "",
+			shortDuration(now.Sub(first.Published)), shortDuration(now.Sub(latest.Published)), tr.Thread.ID)
+	}
+}
+
+// shortDuration formats d to the minute without zero units: "1h",
+// "1h30m", "45m".
+func shortDuration(d time.Duration) string {
+	s := strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	switch {
+	case s == "":
+		return "0m"
+	case strings.HasSuffix(s, "h0m"):
+		return strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+func runUsage() {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	days := fs.Int("days", 0, "Report the last N days instead of this month")
+	fs.Parse(os.Args[1:])
+
+	st := openDB()
+	defer st.Close()
+	b := budget(loadConfig())
+
+	now := time.Now()
+	day, err := st.UsageTotals(usage.StartOfDay(now))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	month, err := st.UsageTotals(usage.StartOfMonth(now))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	fmt.Printf("Today:       %s\n", budgetLine(day, b.DailyTokens))
+	fmt.Printf("This month:  %s\n", budgetLine(month, b.MonthlyTokens))
+	if err := b.Check(day, month); err != nil {
+		fmt.Printf("\nBackground embedding is paused: %v\n", err)
+	}
+
+	since := usage.StartOfMonth(now)
+	label := "this month"
+	if *days > 0 {
+		since = now.AddDate(0, 0, -*days)
+		label = fmt.Sprintf("last %d days", *days)
+	}
+	rows, err := st.UsageReport(since)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if len(rows) == 0 {
+		fmt.Printf("\nNo backend calls recorded %s.\n", label)
+		return
+	}
+
+	fmt.Printf("\nBy model and purpose (%s):\n", label)
+	fmt.Printf("%-8s %-26s %-7s %-12s %8s %11s %12s %8s %6s\n",
+		"BACKEND", "MODEL", "KIND", "PURPOSE", "REQUESTS", "TOKENS", "CHARS", "AVG MS", "FAILED")
+	for _, r := range rows {
+		avg := int64(0)
+		if r.Requests > 0 {
+			avg = r.Latency.Milliseconds() / r.Requests
+		}
+		fmt.Printf("%-8s %-26s %-7s %-12s %8d %11d %12d %8d %6d\n",
+			r.Backend, truncate(r.Model, 26), r.Kind, r.Purpose, r.Requests, r.Tokens, r.Chars, avg, r.Failures)
+	}
+}
+
+// budgetLine formats totals against an optional token cap.
+func budgetLine(t usage.Totals, capTokens int64) string {
+	line := fmt.Sprintf("%d tokens, %d requests", t.Tokens, t.Requests)
+	if capTokens > 0 {
+		line += fmt.Sprintf("  (%.0f%% of %d budget)", float64(t.Tokens)/float64(capTokens)*100, capTokens)
+	}
+	return line
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/rerank"
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/ui"
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+// backend holds the AI backends selected from the environment.
+type backend struct {
+	embedder embed.Embedder
+	reranker rerank.Reranker // nil only in e2e mode
+	// queryEmbedder embeds interactive search queries: unthrottled
+	// instances of rate-limited APIs, so searches never queue behind the
+	// background embedding worker.
+	queryEmbedder embed.Embedder
+
+	// The fallback chains behind the fields above (nil in e2e mode).
+	docs    *embed.FallbackEmbedder
+	queries *embed.FallbackEmbedder
+	rerank  *rerank.FallbackReranker
+
+	// cals are the fitted score calibrations (`obs calibrate`): each
+	// reranker in the chain is wrapped with its own, and cosine
+	// similarities are calibrated per embedding model.
+	cals rerank.Calibrations
+
+	// changed receives a value (without blocking) whenever a chain
+	// switches backends, for the TUI to refresh its status.
+	changed chan struct{}
+}
+
+// selectBackend builds fallback chains from every backend configured in
+// the environment, most preferred first: Jina (JINA_API_KEY), an
+// OpenAI-compatible embeddings server (OPENAI_EMBED_URL: llama.cpp, vLLM,
+// LM Studio, ...), Ollama (OLLAMA_HOST), and finally the built-in offline
+// embedder, which is always available. Rerankers chain the same way
+// (Jina, a self-hosted /rerank server at RERANK_URL, then Ollama with
+// OLLAMA_RERANK_MODEL), ending with the built-in BM25F reranker.
+//
+// Every backend call is recorded to rec, and every backend is wrapped in
+// the retry/circuit-breaker/timeout/metrics middleware. Rerankers with a
+// calibration in cals return calibrated scores. st (nil when
+// attached to a daemon) is where vectors are re-embedded once the
+// document chain returns to its primary.
+func selectBackend(logger *otel.Logger, rec usage.Recorder, st *store.Store, cals rerank.Calibrations) *backend {
+	b := &backend{changed: make(chan struct{}, 1), cals: cals}
+	if os.Getenv("OBSERVER_E2E") != "" {
+		b.embedder = e2eEmbedder{}
+		b.queryEmbedder = b.embedder
+		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "using e2e mock embedder"})
+		return b
+	}
+
+	var docs, queries []embed.Embedder
+	var rerankers []rerank.Reranker
+
+	if jinaKey := strings.TrimSpace(os.Getenv("JINA_API_KEY")); jinaKey != "" {
+		embedModel := envOrDefault("JINA_EMBED_MODEL", "jina-embeddings-v3")
+		jinaEmbedder := embed.NewJinaEmbedder(jinaKey, embedModel)
+		jinaEmbedder.SetUsageRecorder(rec)
+		queryEmbedder := embed.NewJinaEmbedder(jinaKey, embedModel)
+		queryEmbedder.SetUsageRecorder(rec)
+		jinaReranker := rerank.NewJinaReranker(jinaKey, envOrDefault("JINA_RERANK_MODEL", "jina-reranker-v3"))
+		jinaReranker.SetUsageRecorder(rec)
+		breaker := newBreaker(jinaEmbedder.ModelName(), logger)
+		docs = append(docs, resilientEmbedder(jinaEmbedder, embed.JinaRateInterval, breaker, logger))
+		queries = append(queries, resilientEmbedder(queryEmbedder, 0, breaker, logger))
+		rerankers = append(rerankers, cals.Wrap(resilientReranker(jinaReranker, logger)))
+	}
+	if url := os.Getenv("OPENAI_EMBED_URL"); url != "" {
+		dims, _ := strconv.Atoi(os.Getenv("OPENAI_EMBED_DIMENSIONS"))
+		openaiEmbedder := embed.NewOpenAICompatEmbedder(embed.OpenAICompatConfig{
+			BaseURL:     url,
+			Model:       os.Getenv("OPENAI_EMBED_MODEL"),
+			Dimensions:  dims,
+			QueryPrefix: os.Getenv("OPENAI_EMBED_QUERY_PREFIX"),
+			DocPrefix:   os.Getenv("OPENAI_EMBED_DOC_PREFIX"),
+			APIKey:      strings.TrimSpace(os.Getenv("OPENAI_API_KEY")),
+			AuthHeader:  os.Getenv("OPENAI_AUTH_HEADER"),
+		})
+		openaiEmbedder.SetUsageRecorder(rec)
+		e := resilientEmbedder(openaiEmbedder, 0, newBreaker(openaiEmbedder.ModelName(), logger), logger)
+		docs, queries = append(docs, e), append(queries, e)
+	}
+	if url := os.Getenv("RERANK_URL"); url != "" {
+		teiReranker := rerank.NewTEIReranker(rerank.TEIConfig{
+			BaseURL: url,
+			Model:   os.Getenv("RERANK_MODEL"),
+			APIKey:  strings.TrimSpace(os.Getenv("RERANK_API_KEY")),
+		})
+		teiReranker.SetUsageRecorder(rec)
+		rerankers = append(rerankers, cals.Wrap(resilientReranker(teiReranker, logger)))
+	}
+	if host := os.Getenv("OLLAMA_HOST"); host != "" {
+		ollamaEmbedder := embed.NewOllamaEmbedder(host, envOrDefault("OLLAMA_EMBED_MODEL", "mxbai-embed-large"))
+		ollamaEmbedder.SetUsageRecorder(rec)
+		e := resilientEmbedder(ollamaEmbedder, 0, newBreaker(ollamaEmbedder.ModelName(), logger), logger)
+		docs, queries = append(docs, e), append(queries, e)
+		if model := os.Getenv("OLLAMA_RERANK_MODEL"); model != "" {
+			ollamaReranker := rerank.NewOllamaReranker(host, model)
+			ollamaReranker.SetUsageRecorder(rec)
+			rerankers = append(rerankers, cals.Wrap(resilientReranker(ollamaReranker, logger)))
+		}
+	}
+	// Offline last resort: weaker vectors, but dedup, MLT and cosine
+	// search keep working without any service.
+	local := embed.NewLocalEmbedder()
+	docs, queries = append(docs, local), append(queries, local)
+	rerankers = append(rerankers, cals.Wrap(rerank.NewLocalReranker()))
+
+	b.docs = embed.NewFallbackEmbedder(b.switchHook(logger, "embed", nil), docs...)
+	b.queries = embed.NewFallbackEmbedder(b.switchHook(logger, "query", nil), queries...)
+	b.embedder, b.queryEmbedder = b.docs, b.queries
+	b.rerank = rerank.NewFallbackReranker(b.switchHook(logger, "rerank", nil), rerankers...)
+	b.reranker = b.rerank
+
+	names := make([]string, len(docs))
+	for i, e := range docs {
+		names[i] = embed.ModelName(e)
+	}
+	level, msg := otel.LevelInfo, "embedding backends"
+	if len(docs) == 1 {
+		level, msg = otel.LevelWarn, "no AI backend (set JINA_API_KEY, OPENAI_EMBED_URL or OLLAMA_HOST); using built-in offline embedder"
+	}
+	logger.Emit(otel.Event{Kind: otel.KindStartup, Level: level, Comp: "main", Msg: msg, Extra: map[string]any{"chain": names}})
+
+	if st != nil {
+		adoptEmbeddings(st, b.docs.Primary(), logger)
+	}
+	return b
+}
+
+// loadCalibrations reads the calibrations `obs calibrate` saved in
+// dataDir. Without the file, or if it can't be read, scores are used raw.
+func loadCalibrations(dataDir string, logger *otel.Logger) rerank.Calibrations {
+	cals, err := rerank.LoadCalibrations(rerank.CalibrationPath(dataDir))
+	if err != nil {
+		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelWarn, Comp: "main", Msg: "failed to load score calibrations", Err: err.Error()})
+		return rerank.Calibrations{}
+	}
+	if len(cals) > 0 {
+		names := make([]string, 0, len(cals))
+		for name := range cals {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "score calibrations", Extra: map[string]any{"backends": names}})
+	}
+	return cals
+}
+
+// switchHook returns a fallback chain's onSwitch callback: it logs the
+// switch, signals b.changed, and calls then (if set) with the new backend.
+func (b *backend) switchHook(logger *otel.Logger, comp string, then func(to string)) func(from, to string, cause error) {
+	return func(from, to string, cause error) {
+		ev := otel.Event{Kind: otel.KindBackendSwitch, Level: otel.LevelInfo, Comp: comp, Source: to, Msg: "switched from " + from + " to " + to}
+		if cause != nil {
+			ev.Level, ev.Err = otel.LevelWarn, cause.Error()
+		}
+		logger.Emit(ev)
+		select {
+		case b.changed <- struct{}{}:
+		default:
+		}
+		if then != nil {
+			then(to)
+		}
+	}
+}
+
+// adoptEmbeddings attributes vectors stored before models were recorded
+// to the primary model, if they have the dimension of the vectors it has
+// stored since. Vectors of any other length stay unattributed: they came
+// from a backend configured back then, not the primary now. Until the
+// primary has stored a vector, nothing is adopted.
+func adoptEmbeddings(st *store.Store, primary string, logger *otel.Logger) {
+	dims, err := st.EmbeddingDims(primary)
+	if err != nil || dims == 0 {
+		return
+	}
+	if n, err := st.AdoptEmbeddings(primary, dims); err != nil {
+		logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to adopt embeddings", Err: err.Error()})
+	} else if n > 0 {
+		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Source: primary, Msg: "attributed stored embeddings to primary model", Count: int(n)})
+	}
+}
+
+// indexModel returns the model whose vectors the TUI should load: the
+// one the next search query will be embedded with.
+func (b *backend) indexModel() string {
+	return embed.ModelName(b.queryEmbedder)
+}
+
+// status describes the backends for the TUI's status bar and debug overlay.
+func (b *backend) status() ui.BackendStatus {
+	if b.queries == nil {
+		return ui.BackendStatus{Embedder: embed.ModelName(b.queryEmbedder)}
+	}
+	s := ui.BackendStatus{Embedder: b.queries.Active()}
+	s.Degraded = s.Embedder != b.queries.Primary() || b.docs.Active() != b.docs.Primary()
+	for _, st := range b.docs.Status() {
+		s.Chain = append(s.Chain, backendHealth(st.Name, st.Healthy, st.Active, st.LastErr))
+	}
+	s.Reranker = b.rerank.Active()
+	s.Degraded = s.Degraded || s.Reranker != b.rerank.Primary()
+	return s
+}
+
+// backendHealth converts a chain member's status for the UI.
+func backendHealth(name string, healthy, active bool, lastErr error) ui.BackendHealth {
+	h := ui.BackendHealth{Name: name, Healthy: healthy, Active: active}
+	if lastErr != nil {
+		h.Err = lastErr.Error()
+	}
+	return h
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/abelbrown/observer/internal/daemon"
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// runDaemon runs the fetch and embed pipeline headless and serves the
+// local socket API until SIGINT/SIGTERM. TUIs started while it runs
+// attach to it instead of running their own pipeline.
+func runDaemon() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dataDir := setupDataDir()
+
+	lock, err := daemon.AcquireLock(dataDir)
+	if errors.Is(err, daemon.ErrLocked) {
+		log.Fatalf("Observer is already running: %v", err)
+	}
+	if err != nil {
+		log.Fatalf("Failed to lock data directory: %v", err)
+	}
+	defer lock.Release()
+
+	eventFile, logger := openEventLog(dataDir)
+	defer eventFile.Close()
+	defer logger.Close()
+
+	st, err := store.Open(filepath.Join(dataDir, "observer.db"))
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer st.Close()
+
+	b := selectBackend(logger, st, st, loadCalibrations(dataDir, logger))
+	server := daemon.NewServer(st, b.embedder, logger)
+
+	// We hold the lock, so any socket file left behind is stale.
+	socketPath := daemon.SocketPath(dataDir)
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		log.Fatalf("Failed to remove stale socket: %v", err)
+	}
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", socketPath, err)
+	}
+	defer os.Remove(socketPath)
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		log.Fatalf("Failed to restrict socket permissions: %v", err)
+	}
+
+	logger.Emit(otel.Event{Kind: otel.KindDaemonStart, Level: otel.LevelInfo, Comp: "daemon", Msg: "daemon listening", Extra: map[string]any{"socket": socketPath}})
+	log.Printf("observer daemon listening on %s", socketPath)
+
+	coordinator := newCoordinator(dataDir, st, b.embedder, logger)
+	coordinator.Start(ctx, server)
+	coordinator.StartEmbeddingWorker(ctx)
+
+	if err := server.Serve(ctx, ln); err != nil {
+		logger.Emit(otel.Event{Kind: otel.KindError, Level: otel.LevelError, Comp: "daemon", Msg: "serve failed", Err: err.Error()})
+		stop()
+	}
+
+	logger.Emit(otel.Event{Kind: otel.KindShutdown, Level: otel.LevelInfo, Comp: "daemon", Msg: "daemon stopping"})
+	coordinator.Wait()
+}
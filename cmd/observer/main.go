@@ -2,23 +2,26 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/infblueocean/clarion"
 
 	"github.com/abelbrown/observer/internal/coord"
+	"github.com/abelbrown/observer/internal/daemon"
 	"github.com/abelbrown/observer/internal/embed"
-	"github.com/abelbrown/observer/internal/fetch"
 	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/interest"
 	"github.com/abelbrown/observer/internal/otel"
 	"github.com/abelbrown/observer/internal/rerank"
 	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/thread"
 	"github.com/abelbrown/observer/internal/ui"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
 func envOrDefault(key, fallback string) string {
@@ -55,11 +58,44 @@ func (n noopProvider) Fetch(ctx context.Context) ([]store.Item, error) {
 	return nil, nil
 }
 
+// itemSource is the read/write surface the TUI needs. Satisfied by
+// *store.Store (standalone) and *daemon.Client (attached to a daemon).
+type itemSource interface {
+	GetItems(limit int, includeRead bool) ([]store.Item, error)
+	GetItemsSince(since time.Time) ([]store.Item, error)
+	GetModelEmbeddings(ids []string, model string) (map[string][]float32, error)
+	GetModelChunks(ids []string, model string) (map[string][]store.Chunk, error)
+	MarkRead(id string) error
+	SearchFTS(query string, limit int) ([]store.Item, error)
+	ListMutedSources() ([]string, error)
+	MuteSource(name string) error
+	SetEmbedPriority(priority int, ids []string) error
+	RecordUsage(r usage.Record) error
+	GetRerankScores(reranker, query string, keys []store.RerankKey) (map[string]float32, error)
+	SaveRerankScores(reranker, query string, scores []store.RerankScore) error
+	ListRules() ([]store.Rule, error)
+	AddRule(r store.Rule) (store.Rule, error)
+	DeleteRule(id int64) (bool, error)
+	RecordRuleHits(hits map[int64][]string) error
+	ItemThread(itemID string) (*store.Thread, []store.Item, error)
+	ThreadItemsSince(since time.Time) ([]store.Thread, map[int64][]store.Item, error)
+	MarkSaved(id string, saved bool) error
+	Signals(since time.Time) ([]store.Signal, error)
+	RecordSignal(itemID, kind string) error
+	ForgetSignal(itemID, kind string) error
+	ResetInterest() error
+}
+
 func main() {
-	// Setup context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
+	if len(os.Args) > 1 && os.Args[1] == "daemon" {
+		runDaemon()
+		return
+	}
+	runTUI()
+}
 
-	// Data directory: ~/.observer/
+// setupDataDir returns ~/.observer/, creating it if needed.
+func setupDataDir() string {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		log.Fatalf("Failed to get home directory: %v", err)
@@ -68,24 +104,31 @@ func main() {
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		log.Fatalf("Failed to create data directory: %v", err)
 	}
-	dbPath := filepath.Join(dataDir, "observer.db")
-
-	// Open store
-	st, err := store.Open(dbPath)
-	if err != nil {
-		log.Fatalf("Failed to open database: %v", err)
-	}
-	defer st.Close()
+	return dataDir
+}
 
-	// Structured event log (JSONL) — separate from Bubble Tea's log output
+// openEventLog opens the structured event log (JSONL) — separate from
+// Bubble Tea's log output. The caller closes the returned file.
+func openEventLog(dataDir string) (*os.File, *otel.Logger) {
 	eventLogPath := filepath.Join(dataDir, "observer.events.jsonl")
 	eventFile, err := os.OpenFile(eventLogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
 	if err != nil {
 		log.Fatalf("Failed to open event log: %v", err)
 	}
-	defer eventFile.Close()
+	return eventFile, otel.NewLogger(eventFile)
+}
 
-	logger := otel.NewLogger(eventFile)
+// runTUI runs the interactive UI. If a daemon is serving the data
+// directory, the TUI attaches to it as a thin client; otherwise it takes
+// the single-instance lock and runs the fetch/embed pipeline itself.
+func runTUI() {
+	// Setup context for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+
+	dataDir := setupDataDir()
+
+	eventFile, logger := openEventLog(dataDir)
+	defer eventFile.Close()
 	defer logger.Close()
 
 	ring := otel.NewRingBuffer(otel.DefaultRingSize)
@@ -93,41 +136,47 @@ func main() {
 
 	logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "observer starting"})
 
-	// Backend selection: Jina when JINA_API_KEY is set, otherwise no AI backend.
-	// Ollama can be enabled explicitly via OLLAMA_HOST.
-	e2eMode := os.Getenv("OBSERVER_E2E") != ""
-	jinaKey := strings.TrimSpace(os.Getenv("JINA_API_KEY"))
-	embedModel := envOrDefault("JINA_EMBED_MODEL", "jina-embeddings-v3")
-	rerankModel := envOrDefault("JINA_RERANK_MODEL", "jina-reranker-v3")
-
-	var embedder embed.Embedder
-	var reranker rerank.Reranker
-
-	switch {
-	case e2eMode:
-		embedder = e2eEmbedder{}
-		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "using e2e mock embedder"})
-	case jinaKey != "":
-		embedder = embed.NewJinaEmbedder(jinaKey, embedModel)
-		reranker = rerank.NewJinaReranker(jinaKey, rerankModel)
-		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "using Jina API backend"})
-	case os.Getenv("OLLAMA_HOST") != "":
-		ollamaEndpoint := os.Getenv("OLLAMA_HOST")
-		ollamaEmbedModel := envOrDefault("OLLAMA_EMBED_MODEL", "mxbai-embed-large")
-		ollamaRerankModel := os.Getenv("OLLAMA_RERANK_MODEL")
-		embedder = embed.NewOllamaEmbedder(ollamaEndpoint, ollamaEmbedModel)
-		reranker = rerank.NewOllamaReranker(ollamaEndpoint, ollamaRerankModel)
-		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "using Ollama backend"})
-	default:
-		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelWarn, Comp: "main", Msg: "no AI backend (set JINA_API_KEY or OLLAMA_HOST)"})
+	// Attach to a running daemon if there is one.
+	var st itemSource
+	var localStore *store.Store // nil when attached
+	var client *daemon.Client
+	var coordinator *coord.Coordinator
+	if c, err := daemon.Dial(daemon.SocketPath(dataDir)); err == nil {
+		client = c
+		defer client.Close()
+		st = client
+		logger.Emit(otel.Event{Kind: otel.KindDaemonAttach, Level: otel.LevelInfo, Comp: "main", Msg: "attached to daemon"})
+	} else {
+		lock, err := daemon.AcquireLock(dataDir)
+		if err != nil {
+			log.Fatalf("Failed to lock data directory (is a daemon starting up?): %v", err)
+		}
+		defer lock.Release()
+
+		s, err := store.Open(filepath.Join(dataDir, "observer.db"))
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		defer s.Close()
+		st, localStore = s, s
+	}
+
+	b := selectBackend(logger, st, localStore, loadCalibrations(dataDir, logger))
+	embedder, queryEmbedder, reranker := b.embedder, b.queryEmbedder, b.reranker
+
+	// mutedSources returns sources muted from the TUI (hidden everywhere).
+	mutedSources := func() []string {
+		muted, err := st.ListMutedSources()
+		if err != nil {
+			logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to list muted sources", Err: err.Error()})
+		}
+		return muted
 	}
 
-	// Create provider using Clarion
-	provider := fetch.NewClarionProvider(nil, clarion.FetchOptions{
-		MaxConcurrency: 10,
-		Timeout:        30 * time.Second,
-		MaxItems:       50,
-	}, logger)
+	// The feed filter pipeline from the config. A TUI attached to a
+	// daemon still filters locally, so it reads the config either way.
+	pipeline := feedPipeline(dataDir)
+	searchDedup := pipeline.Only("semantic_dedup") // search only folds near-duplicates
 
 	// Create UI app with dependency injection
 	cfg := ui.AppConfig{
@@ -147,30 +196,16 @@ func main() {
 						unread = append(unread, item)
 					}
 				}
-				items = unread
 
 				// Same filter pipeline as LoadItems
-				ids := make([]string, len(items))
-				for i, item := range items {
-					ids[i] = item.ID
-				}
-				embeddings, err := st.GetItemsWithEmbeddings(ids)
-				if err != nil {
-					logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to get embeddings (recent)", Err: err.Error()})
-					embeddings = make(map[string][]float32)
-				}
-
-				items = filter.SemanticDedup(items, embeddings, 0.85)
-				items = filter.LimitPerSource(items, 50)
-
-				filteredEmbeddings := make(map[string][]float32)
-				for _, item := range items {
-					if emb, ok := embeddings[item.ID]; ok {
-						filteredEmbeddings[item.ID] = emb
-					}
-				}
-
-				return ui.ItemsLoaded{Items: items, Embeddings: filteredEmbeddings}
+				model := b.indexModel()
+				env := &filter.Env{LoadEmbeddings: modelEmbeddings(st, model, logger), Rules: feedRules(st, model, logger), Trace: &filter.Trace{}}
+				items = filter.ExcludeMuted(unread, mutedSources(), env)
+				items = pipeline.Run(items, env)
+				filteredEmbeddings := embeddingsOf(items, env.EmbeddingsFor(items))
+				recordRuleHits(st, env, logger)
+
+				return ui.ItemsLoaded{Items: items, Embeddings: filteredEmbeddings, EmbeddingModel: model, Boosted: env.RuleBoosted, Hidden: env.Trace.Dropped(), Similar: env.Clusters, Trends: feedTrends(st, logger), Interest: feedInterest(st, model, filteredEmbeddings, logger)}
 			}
 		},
 		// LoadItems: Stage 2 — full 24h corpus (also used by refresh/fetch)
@@ -180,34 +215,20 @@ func main() {
 				if err != nil {
 					return ui.ItemsLoaded{Err: err}
 				}
-				items = filter.ByAge(items, 24*time.Hour)
-
-				// Get embeddings for semantic dedup and search
-				ids := make([]string, len(items))
-				for i, item := range items {
-					ids[i] = item.ID
-				}
-				embeddings, err := st.GetItemsWithEmbeddings(ids)
-				if err != nil {
-					// Log but continue - semantic dedup will fall back to URL dedup
-					logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to get embeddings (full)", Err: err.Error()})
-					embeddings = make(map[string][]float32)
-				}
 
-				// Use semantic dedup (falls back to URL dedup if no embeddings)
-				// Threshold 0.85 means items with >85% cosine similarity are considered duplicates
-				items = filter.SemanticDedup(items, embeddings, 0.85)
-				items = filter.LimitPerSource(items, 50)
-
-				// Rebuild embeddings map for filtered items only
-				filteredEmbeddings := make(map[string][]float32)
-				for _, item := range items {
-					if emb, ok := embeddings[item.ID]; ok {
-						filteredEmbeddings[item.ID] = emb
-					}
-				}
-
-				return ui.ItemsLoaded{Items: items, Embeddings: filteredEmbeddings}
+				// Configured pipeline (max_age → rules → semantic_dedup →
+				// limit_per_source by default) after muted sources; vectors
+				// are read only for items that survive the stages before
+				// they're needed. The trace says why the rest were dropped.
+				model := b.indexModel()
+				env := &filter.Env{LoadEmbeddings: modelEmbeddings(st, model, logger), Rules: feedRules(st, model, logger), Trace: &filter.Trace{}}
+				items = filter.ExcludeMuted(items, mutedSources(), env)
+				items = pipeline.Run(items, env)
+				filteredEmbeddings := embeddingsOf(items, env.EmbeddingsFor(items))
+				chunks := loadChunks(st, items, model, logger)
+				recordRuleHits(st, env, logger)
+
+				return ui.ItemsLoaded{Items: items, Embeddings: filteredEmbeddings, EmbeddingModel: model, Chunks: chunks, Boosted: env.RuleBoosted, Hidden: env.Trace.Dropped(), Similar: env.Clusters, Trends: feedTrends(st, logger), Interest: feedInterest(st, model, filteredEmbeddings, logger)}
 			}
 		},
 		// LoadSearchPool: load all items for full-history search
@@ -220,19 +241,26 @@ func main() {
 				if err != nil {
 					return ui.SearchPoolLoaded{Err: err, QueryID: queryID}
 				}
+				items = filter.ExcludeSources(items, mutedSources())
 				// No age filter, no LimitPerSource — search needs everything
 				ids := make([]string, len(items))
 				for i, item := range items {
 					ids[i] = item.ID
 				}
-				embeddings, err := st.GetItemsWithEmbeddings(ids)
+				model := b.indexModel()
+				embeddings, err := st.GetModelEmbeddings(ids, model)
 				if err != nil {
 					logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to get embeddings (search pool)", Err: err.Error()})
 					embeddings = make(map[string][]float32)
 				}
-				// No dedup for search: SemanticDedup is O(n^2) and dominates latency
-				// for large pools. The reranker handles relevance; dupes cluster naturally.
-				return ui.SearchPoolLoaded{Items: items, Embeddings: embeddings, QueryID: queryID}
+				// Near-duplicates fold into the newest version as in the feed
+				// (the configured semantic_dedup stage, if any): results show
+				// "+4 similar" and e expands them. Every version keeps its
+				// vector for when a cluster is expanded.
+				env := &filter.Env{Embeddings: embeddings}
+				items = searchDedup.Run(items, env)
+				chunks := loadChunks(st, items, model, logger)
+				return ui.SearchPoolLoaded{Items: items, Embeddings: embeddings, EmbeddingModel: model, Chunks: chunks, Similar: env.Clusters, QueryID: queryID}
 			}
 		},
 		// markRead
@@ -252,7 +280,109 @@ func main() {
 		},
 		// SearchFTS: instant lexical search (synchronous)
 		SearchFTS: func(query string, limit int) ([]store.Item, error) {
-			return st.SearchFTS(query, limit)
+			items, err := st.SearchFTS(query, limit)
+			if err != nil {
+				return nil, err
+			}
+			return filter.ExcludeSources(items, mutedSources()), nil
+		},
+		// MuteSource: hide a source and stop fetching it
+		MuteSource: func(source string) tea.Cmd {
+			return func() tea.Msg {
+				err := st.MuteSource(source)
+				if err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return ui.SourceMuted{Source: source, Err: err}
+			}
+		},
+		// Rules: mute/boost/allow, applied by the pipeline's rules stage
+		LoadRules: func() tea.Cmd {
+			return func() tea.Msg {
+				rules, err := st.ListRules()
+				return ui.RulesLoaded{Rules: rules, Err: err}
+			}
+		},
+		AddRule: func(rule store.Rule) tea.Cmd {
+			return func() tea.Msg {
+				added, err := st.AddRule(rule)
+				if err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return ui.RuleAdded{Rule: added, Err: err}
+			}
+		},
+		DeleteRule: func(id int64) tea.Cmd {
+			return func() tea.Msg {
+				_, err := st.DeleteRule(id)
+				if err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return ui.RuleDeleted{ID: id, Err: err}
+			}
+		},
+		// LoadThread: the story thread the coordinator linked an item into
+		LoadThread: func(itemID string) tea.Cmd {
+			return func() tea.Msg {
+				thread, items, err := st.ItemThread(itemID)
+				if err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return ui.ThreadLoaded{ItemID: itemID, Thread: thread, Items: items, Err: err}
+			}
+		},
+		// Interest profile: learned locally from reads, saves, more-like-this
+		// seeds and exclusions
+		LoadInterest: func() tea.Cmd {
+			return func() tea.Msg {
+				p, err := interestProfile(st, b.indexModel())
+				return ui.InterestLoaded{Profile: p, Err: err}
+			}
+		},
+		RecordSignal: func(itemID, kind string) tea.Cmd {
+			return func() tea.Msg {
+				err := st.RecordSignal(itemID, kind)
+				if err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return ui.InterestChanged{Err: err}
+			}
+		},
+		ForgetSignal: func(itemID, kind string) tea.Cmd {
+			return func() tea.Msg {
+				err := st.ForgetSignal(itemID, kind)
+				if err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return ui.InterestChanged{Err: err}
+			}
+		},
+		ResetInterest: func() tea.Cmd {
+			return func() tea.Msg {
+				err := st.ResetInterest()
+				if err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return ui.InterestChanged{Err: err}
+			}
+		},
+		MarkSaved: func(id string, saved bool) tea.Cmd {
+			return func() tea.Msg {
+				err := st.MarkSaved(id, saved)
+				if err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return ui.InterestChanged{Err: err}
+			}
+		},
+		// PrioritizeEmbedding: embed what the user is looking at first
+		PrioritizeEmbedding: func(priority int, ids []string) tea.Cmd {
+			return func() tea.Msg {
+				if err := st.SetEmbedPriority(priority, ids); err != nil {
+					logger.Error(otel.KindStoreError, "main", err)
+				}
+				return nil
+			}
 		},
 		Obs: ui.ObsConfig{
 			Logger: logger,
@@ -262,33 +392,24 @@ func main() {
 			MLT:  true,
 			FTS5: true,
 		},
+		Backend: b.status(),
 	}
 
 	// Wire embedding closures only when an AI backend is available.
-	// Interactive search gets its own embedder — no rate limiter,
-	// no contention with the background embedding worker.
-	if embedder != nil {
-		if e2eMode || jinaKey == "" {
-			cfg.EmbedQuery = func(ctx context.Context, query string, queryID string) tea.Cmd {
-				return func() tea.Msg {
-					emb, err := embed.EmbedQuery(ctx, embedder, query)
-					return ui.QueryEmbedded{Query: query, Embedding: emb, Err: err, QueryID: queryID}
-				}
-			}
-		} else {
-			queryEmbedder := embed.NewJinaEmbedder(jinaKey, embedModel)
-			queryEmbedder.SetRateLimit(0) // no throttle for interactive queries
-			cfg.EmbedQuery = func(ctx context.Context, query string, queryID string) tea.Cmd {
-				return func() tea.Msg {
-					emb, err := embed.EmbedQuery(ctx, queryEmbedder, query)
-					return ui.QueryEmbedded{Query: query, Embedding: emb, Err: err, QueryID: queryID}
-				}
+	// Interactive search gets its own embedder where the background one
+	// is rate limited, so queries never wait behind the embedding worker.
+	if queryEmbedder != nil {
+		cfg.EmbedQuery = func(ctx context.Context, query string, queryID string) tea.Cmd {
+			return func() tea.Msg {
+				emb, model, err := embed.EmbedQueryWithModel(ctx, queryEmbedder, query)
+				return ui.QueryEmbedded{Query: query, Embedding: emb, Model: model, Err: err, QueryID: queryID}
 			}
 		}
 	}
 
 	// Wire reranker based on backend type.
-	// Jina: fast batch API → auto-rerank after every search.
+	// Jina and the local BM25F reranker: fast batch → auto-rerank after
+	// every search.
 	// Ollama: slow per-item scoring → user presses R to opt in.
 	autoReranks := false
 	if ar, ok := reranker.(rerank.AutoReranker); ok {
@@ -296,24 +417,34 @@ func main() {
 	}
 	cfg.AutoReranks = autoReranks
 
-	if autoReranks {
+	if _, ok := reranker.(rerank.DocumentReranker); ok && autoReranks {
+		// Item path: the chain gets titles, summaries, authors, dates and
+		// vectors, which the local BM25F fallback scores field by field.
+		cfg.RerankItems = func(ctx context.Context, query string, queryEmb []float32, items []store.Item, embs [][]float32, queryID string) tea.Cmd {
+			return func() tea.Msg {
+				rerankCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+				defer cancel()
+				docs := make([]rerank.Document, len(items))
+				for i, item := range items {
+					docs[i] = rerank.Document{Title: item.Title, Summary: item.Summary, Author: item.Author, Published: item.Published}
+					if embs != nil {
+						docs[i].Embedding = embs[i]
+					}
+				}
+				scores, name, err := rerank.RerankDocumentsWithName(rerankCtx, reranker, query, queryEmb, docs)
+				return rerankComplete(query, queryID, name, len(docs), scores, err)
+			}
+		}
+		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "reranker: auto (batch)", Extra: map[string]any{"name": reranker.Name()}})
+	} else if autoReranks {
 		// Batch path: single API call for all docs (Jina).
 		// 15s timeout prevents indefinite hangs from retries/rate limiting.
 		cfg.BatchRerank = func(ctx context.Context, query string, docs []string, queryID string) tea.Cmd {
 			return func() tea.Msg {
 				rerankCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 				defer cancel()
-				scores, err := reranker.Rerank(rerankCtx, query, docs)
-				if err != nil {
-					return ui.RerankComplete{Query: query, Err: err, QueryID: queryID}
-				}
-				result := make([]float32, len(docs))
-				for _, s := range scores {
-					if s.Index < len(result) {
-						result[s.Index] = s.Score
-					}
-				}
-				return ui.RerankComplete{Query: query, Scores: result, QueryID: queryID}
+				scores, name, err := rerank.RerankWithName(rerankCtx, reranker, query, docs)
+				return rerankComplete(query, queryID, name, len(docs), scores, err)
 			}
 		}
 		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "reranker: auto (batch)", Extra: map[string]any{"name": reranker.Name()}})
@@ -324,11 +455,11 @@ func main() {
 				if err := ctx.Err(); err != nil {
 					return ui.EntryReranked{ItemID: itemID, Score: 0, QueryID: queryID, Err: err}
 				}
-				score, err := reranker.Rerank(ctx, query, []string{doc})
+				score, name, err := rerank.RerankWithName(ctx, reranker, query, []string{doc})
 				if err != nil || len(score) == 0 {
 					return ui.EntryReranked{ItemID: itemID, Score: 0.5, QueryID: queryID, Err: err}
 				}
-				return ui.EntryReranked{ItemID: itemID, Score: score[0].Score, QueryID: queryID}
+				return ui.EntryReranked{ItemID: itemID, Score: score[0].Score, QueryID: queryID, Reranker: name}
 			}
 		}
 		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "reranker: manual (per-entry)", Extra: map[string]any{"name": reranker.Name()}})
@@ -336,6 +467,47 @@ func main() {
 		logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelWarn, Comp: "main", Msg: "no reranker available — cosine only"})
 	}
 
+	if reranker != nil {
+		// Cross-encoder scores are cached per (query, item text, reranker),
+		// so repeated searches only send items not scored before.
+		cfg.CachedRerankScores = func(query string, items []store.Item) map[string]float32 {
+			name := reranker.Name()
+			if !rerank.Cacheable(name) {
+				return nil
+			}
+			keys := make([]store.RerankKey, len(items))
+			for i, item := range items {
+				keys[i] = store.NewRerankKey(item)
+			}
+			cached, err := st.GetRerankScores(name, query, keys)
+			if err != nil {
+				logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to read rerank cache", Err: err.Error()})
+				return nil
+			}
+			return cached
+		}
+		cfg.SaveRerankScores = func(query, name string, items []store.Item, scores []float32) tea.Cmd {
+			if !rerank.Cacheable(name) {
+				return nil
+			}
+			return func() tea.Msg {
+				entries := make([]store.RerankScore, len(items))
+				for i, item := range items {
+					entries[i] = store.RerankScore{RerankKey: store.NewRerankKey(item), Score: scores[i]}
+				}
+				if err := st.SaveRerankScores(name, query, entries); err != nil {
+					logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to save rerank scores", Err: err.Error()})
+				}
+				return nil
+			}
+		}
+	}
+	if len(b.cals) > 0 {
+		// Calibrated cosine lets the relevance cut-off hide results
+		// that were never reranked.
+		cfg.CalibrateCosine = b.cals.Cosine
+	}
+
 	app := ui.NewAppWithConfig(cfg)
 
 	// Redirect log output to file so it doesn't corrupt the TUI
@@ -347,12 +519,48 @@ func main() {
 	// Create program
 	program := tea.NewProgram(app, tea.WithAltScreen())
 
-	// Create and start coordinator
-	coordinator := coord.NewCoordinator(st, provider, embedder, logger)
-	coordinator.Start(ctx, program)
+	// Tell the UI when a fallback chain switches backends.
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.changed:
+				program.Send(ui.BackendChanged{Status: b.status()})
+			}
+		}
+	}()
+
+	if client != nil {
+		// The daemon owns fetching and embedding; relay its completion
+		// notifications so the UI reloads as it would standalone.
+		go func() {
+			err := client.Subscribe(ctx, func(ev daemon.Event) {
+				switch ev.Kind {
+				case daemon.EventFetchComplete:
+					msg := ui.FetchComplete{Source: ev.Source, NewItems: ev.NewItems}
+					if ev.Err != "" {
+						msg.Err = errors.New(ev.Err)
+					}
+					program.Send(msg)
+				case daemon.EventSourceMuted, daemon.EventRulesChanged:
+					// Muted or rules changed from another client: reload so
+					// its items disappear here too.
+					program.Send(ui.RefreshTick{})
+				}
+			})
+			if err != nil {
+				logger.Emit(otel.Event{Kind: otel.KindError, Level: otel.LevelWarn, Comp: "main", Msg: "daemon subscription ended", Err: err.Error()})
+			}
+		}()
+	} else {
+		// Create and start coordinator
+		coordinator = newCoordinator(dataDir, localStore, embedder, logger)
+		coordinator.Start(ctx, program)
 
-	// Start background embedding worker (continuously embeds items without embeddings)
-	coordinator.StartEmbeddingWorker(ctx)
+		// Start background embedding worker (continuously embeds items without embeddings)
+		coordinator.StartEmbeddingWorker(ctx)
+	}
 
 	// Run UI (blocks until quit)
 	if _, err := program.Run(); err != nil {
@@ -363,5 +571,131 @@ func main() {
 
 	// Graceful shutdown
 	cancel()
-	coordinator.Wait()
-}
\ No newline at end of file
+	if coordinator != nil {
+		coordinator.Wait()
+	}
+}
+
+// modelEmbeddings returns a filter.Env loader for vectors from model. If
+// they can't be read there are none, and semantic dedup falls back to URL
+// dedup.
+func modelEmbeddings(st itemSource, model string, logger *otel.Logger) func([]store.Item) map[string][]float32 {
+	return func(items []store.Item) map[string][]float32 {
+		ids := make([]string, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		embeddings, err := st.GetModelEmbeddings(ids, model)
+		if err != nil {
+			logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to get embeddings", Err: err.Error()})
+			return nil
+		}
+		return embeddings
+	}
+}
+
+// feedRules returns the user's rules compiled for vectors from model, or
+// nil (no rules applied) if they can't be read.
+func feedRules(st itemSource, model string, logger *otel.Logger) *filter.RuleSet {
+	rules, err := st.ListRules()
+	if err == nil {
+		var rs *filter.RuleSet
+		if rs, err = filter.NewRuleSet(rules, model); err == nil {
+			return rs
+		}
+	}
+	logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to load rules", Err: err.Error()})
+	return nil
+}
+
+// feedTrends returns the story threads trending now, or nil if their
+// reports can't be read.
+func feedTrends(st itemSource, logger *otel.Logger) []thread.Trend {
+	now := time.Now()
+	threads, reports, err := st.ThreadItemsSince(thread.DefaultDetector.Since(now))
+	if err != nil {
+		logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to load story threads", Err: err.Error()})
+		return nil
+	}
+	return thread.DefaultDetector.Detect(threads, reports, now)
+}
+
+// interestProfile builds the interest profile for model from the signals
+// recorded within interest.Horizon.
+func interestProfile(st itemSource, model string) (interest.Profile, error) {
+	now := time.Now()
+	signals, err := st.Signals(now.Add(-interest.Horizon))
+	if err != nil {
+		return interest.Profile{Model: model}, err
+	}
+	ids := make([]string, len(signals))
+	for i, sig := range signals {
+		ids[i] = sig.Item.ID
+	}
+	vectors, err := st.GetModelEmbeddings(ids, model)
+	if err != nil {
+		return interest.Profile{Model: model}, fmt.Errorf("interest vectors: %w", err)
+	}
+	return interest.Build(model, signals, vectors, now), nil
+}
+
+// feedInterest scores the feed's items against the interest profile, or
+// returns nil if it can't be built.
+func feedInterest(st itemSource, model string, embeddings map[string][]float32, logger *otel.Logger) map[string]float64 {
+	p, err := interestProfile(st, model)
+	if err != nil {
+		logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to build interest profile", Err: err.Error()})
+		return nil
+	}
+	return p.Scores(embeddings)
+}
+
+// recordRuleHits stores what the rules stage hid, for the rules panel's
+// "hidden today" counts.
+func recordRuleHits(st itemSource, env *filter.Env, logger *otel.Logger) {
+	if err := st.RecordRuleHits(env.RuleHidden); err != nil {
+		logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to record rule hits", Err: err.Error()})
+	}
+}
+
+// embeddingsOf returns the vectors in embeddings of items only.
+func embeddingsOf(items []store.Item, embeddings map[string][]float32) map[string][]float32 {
+	out := make(map[string][]float32)
+	for _, item := range items {
+		if emb, ok := embeddings[item.ID]; ok {
+			out[item.ID] = emb
+		}
+	}
+	return out
+}
+
+// loadChunks returns the passage vectors of the long items among items,
+// or nil (ranking falls back to document vectors) if they can't be read.
+func loadChunks(st itemSource, items []store.Item, model string, logger *otel.Logger) map[string][]store.Chunk {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	chunks, err := st.GetModelChunks(ids, model)
+	if err != nil {
+		logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "main", Msg: "failed to get chunks", Err: err.Error()})
+		return nil
+	}
+	return chunks
+}
+
+// rerankComplete converts rerank scores for n documents, produced by the
+// reranker called name, into the message the TUI expects, in document
+// order.
+func rerankComplete(query, queryID, name string, n int, scores []rerank.Score, err error) tea.Msg {
+	if err != nil {
+		return ui.RerankComplete{Query: query, Err: err, QueryID: queryID}
+	}
+	result := make([]float32, n)
+	for _, s := range scores {
+		if s.Index < len(result) {
+			result[s.Index] = s.Score
+		}
+	}
+	return ui.RerankComplete{Query: query, Scores: result, QueryID: queryID, Reranker: name}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/coord"
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/fetch"
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+// newRegistry registers every provider type Observer knows how to build.
+func newRegistry(cfg config.Config, st *store.Store) *coord.Registry {
+	reg := coord.NewRegistry()
+	reg.Register("clarion", func(pc config.ProviderConfig, l *otel.Logger) (coord.Provider, error) {
+		return fetch.NewClarionProviderFromConfig(pc, cfg.Sources, st, l)
+	})
+	reg.Register("feeds", func(pc config.ProviderConfig, l *otel.Logger) (coord.Provider, error) {
+		return fetch.NewFeedProviderFromConfig(st, pc, l)
+	})
+	return reg
+}
+
+// newCoordinator loads ~/.observer/config.json and builds the pipeline
+// coordinator: providers from the config, budgets applied to background
+// embedding.
+func newCoordinator(dataDir string, st *store.Store, e embed.Embedder, logger *otel.Logger) *coord.Coordinator {
+	cfg, err := config.Load(config.Path(dataDir))
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	c := coord.NewCoordinator(st, buildProvider(cfg, st, logger), e, logger)
+	c.SetBudget(usage.Budget{
+		DailyTokens:   cfg.Budgets.DailyTokens,
+		MonthlyTokens: cfg.Budgets.MonthlyTokens,
+	})
+	return c
+}
+
+// feedPipeline loads ~/.observer/config.json and builds the feed filter
+// pipeline LoadItems runs (the same one `obs pipeline explain` reports).
+func feedPipeline(dataDir string) filter.Pipeline {
+	cfg, err := config.Load(config.Path(dataDir))
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	p, err := filter.NewRegistry().Build(cfg.Pipeline)
+	if err != nil {
+		log.Fatalf("Failed to build pipeline: %v", err)
+	}
+	return p
+}
+
+// buildProvider builds the fan-out provider from the config's enabled
+// entries (the Clarion catalog and the user's feeds by default).
+func buildProvider(cfg config.Config, st *store.Store, logger *otel.Logger) *coord.MultiProvider {
+	mp, err := newRegistry(cfg, st).Build(cfg.Providers, logger)
+	if err != nil {
+		log.Fatalf("Failed to build providers: %v", err)
+	}
+	logger.Emit(otel.Event{Kind: otel.KindStartup, Level: otel.LevelInfo, Comp: "main", Msg: "providers enabled", Extra: map[string]any{"providers": mp.Names()}})
+	return mp
+}
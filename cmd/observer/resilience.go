@@ -0,0 +1,58 @@
+package main
+
+import (
+	"time"
+
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/rerank"
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// Breaker settings shared by every backend: five consecutive transient
+// failures open it for a minute, after which one trial call probes.
+const (
+	breakerThreshold = 5
+	breakerCooldown  = time.Minute
+)
+
+// Per-call timeouts. Embedding batches on a local Ollama can be slow;
+// reranks sit behind an interactive search.
+const (
+	embedCallTimeout  = 2 * time.Minute
+	rerankCallTimeout = 30 * time.Second
+)
+
+// newBreaker creates a breaker for the named backend that logs when it
+// opens and closes.
+func newBreaker(name string, logger *otel.Logger) *resilience.Breaker {
+	return resilience.NewBreaker(breakerThreshold, breakerCooldown, func(open bool, cause error) {
+		ev := otel.Event{Kind: otel.KindBackendBreaker, Level: otel.LevelInfo, Comp: "main", Source: name, Msg: "circuit breaker closed"}
+		if open {
+			ev.Level, ev.Msg = otel.LevelWarn, "circuit breaker open"
+			if cause != nil {
+				ev.Err = cause.Error()
+			}
+		}
+		logger.Emit(ev)
+	})
+}
+
+// resilientEmbedder wraps a raw embedder in the standard middleware chain.
+// interval spaces calls (0 for none); b may be shared with another
+// embedder for the same backend.
+func resilientEmbedder(e embed.Embedder, interval time.Duration, b *resilience.Breaker, logger *otel.Logger) embed.Embedder {
+	e = embed.WithTimeout(e, embedCallTimeout)
+	e = embed.WithRateLimit(e, interval)
+	e = embed.WithRetry(e, resilience.DefaultRetryPolicy)
+	e = embed.WithCircuitBreaker(e, b)
+	return embed.WithMetrics(e, logger)
+}
+
+// resilientReranker wraps a raw reranker in the standard middleware chain.
+func resilientReranker(r rerank.Reranker, logger *otel.Logger) rerank.Reranker {
+	r = rerank.WithTimeout(r, rerankCallTimeout)
+	r = rerank.WithRetry(r, resilience.DefaultRetryPolicy)
+	r = rerank.WithCircuitBreaker(r, newBreaker(r.Name(), logger))
+	return rerank.WithMetrics(r, logger)
+}
@@ -0,0 +1,255 @@
+// Package config loads Observer's optional user configuration.
+//
+// The file lives at ~/.observer/config.json. Every field is optional: a
+// missing file, or a file that omits a section, yields the defaults, so
+// Observer runs unconfigured exactly as it always has.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config is the top-level configuration file.
+type Config struct {
+	// Providers lists the ingestion providers to fan out to.
+	// Empty means the default set (the Clarion catalog and user feeds).
+	Providers []ProviderConfig `json:"providers,omitempty"`
+
+	// Sources selects and tunes Clarion catalog sources.
+	// Empty means every registered source with default limits.
+	Sources SourcesConfig `json:"sources,omitempty"`
+
+	// Budgets caps AI backend token spend. When a cap is reached,
+	// background embedding pauses; interactive search keeps working.
+	Budgets BudgetsConfig `json:"budgets,omitempty"`
+
+	// Pipeline lists the filter stages applied, in order, to the feed the
+	// TUI shows (and `obs stats`/`obs search` inspect). Empty means the
+	// default: max_age 24h, rules, semantic_dedup 0.85, limit_per_source 50.
+	Pipeline []StageConfig `json:"pipeline,omitempty"`
+}
+
+// StageConfig enables one filter stage.
+type StageConfig struct {
+	Stage   string `json:"stage"`             // registry key: "max_age", "semantic_dedup", ...
+	Name    string `json:"name,omitempty"`    // label in reports; defaults to Stage
+	Enabled *bool  `json:"enabled,omitempty"` // nil means enabled
+
+	// Options carries stage-specific parameters, decoded by the stage's factory.
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// Label returns the stage's name, or its type if unnamed.
+func (s StageConfig) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Stage
+}
+
+// IsEnabled reports whether the stage should run.
+func (s StageConfig) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// BudgetsConfig caps token usage per calendar day and month (local time).
+// Zero means no cap.
+type BudgetsConfig struct {
+	DailyTokens   int64 `json:"daily_tokens,omitempty"`
+	MonthlyTokens int64 `json:"monthly_tokens,omitempty"`
+}
+
+// ProviderConfig enables one ingestion provider.
+type ProviderConfig struct {
+	Name    string `json:"name"`              // unique; tags every item the provider produces
+	Type    string `json:"type"`              // registry key: "clarion", "feeds"
+	Enabled *bool  `json:"enabled,omitempty"` // nil means enabled
+
+	Timeout Duration `json:"timeout,omitempty"` // per-fetch deadline (0 = default)
+
+	// Options carries provider-specific settings, decoded by the provider's factory.
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// SourcesConfig selects which Clarion catalog sources are fetched.
+// A source is fetched if it matches Enable (or Enable is empty) and does
+// not match Disable. Sources muted from the TUI are skipped regardless.
+type SourcesConfig struct {
+	Enable    SourceMatch               `json:"enable,omitempty"`
+	Disable   SourceMatch               `json:"disable,omitempty"`
+	Overrides map[string]SourceOverride `json:"overrides,omitempty"` // keyed by source name
+}
+
+// SourceMatch matches sources by name, type or category (case-insensitive).
+// A source matches if any listed value matches.
+type SourceMatch struct {
+	Names      []string `json:"names,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+// Empty reports whether the match lists nothing.
+func (m SourceMatch) Empty() bool {
+	return len(m.Names) == 0 && len(m.Types) == 0 && len(m.Categories) == 0
+}
+
+// Matches reports which field matched ("name", "type", "category"), or "".
+func (m SourceMatch) Matches(name, typ, category string) string {
+	switch {
+	case containsFold(m.Names, name):
+		return "name"
+	case containsFold(m.Types, typ):
+		return "type"
+	case category != "" && containsFold(m.Categories, category):
+		return "category"
+	}
+	return ""
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceOverride tunes one source. Zero values keep the provider defaults.
+type SourceOverride struct {
+	MaxItems int      `json:"max_items,omitempty"`
+	Timeout  Duration `json:"timeout,omitempty"`
+}
+
+// IsEnabled reports whether the provider should be built.
+func (p ProviderConfig) IsEnabled() bool {
+	return p.Enabled == nil || *p.Enabled
+}
+
+// Duration is a time.Duration that marshals as a Go duration string ("30s").
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Accepts "30s"-style strings
+// or a bare number of seconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		*d = Duration(v)
+		return nil
+	}
+	var secs float64
+	if err := json.Unmarshal(data, &secs); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = Duration(secs * float64(time.Second))
+	return nil
+}
+
+// Path returns the config file path inside dataDir.
+func Path(dataDir string) string {
+	return filepath.Join(dataDir, "config.json")
+}
+
+// Default returns the configuration used when no file exists.
+func Default() Config {
+	return Config{
+		Providers: []ProviderConfig{
+			{Name: "clarion", Type: "clarion"},
+			{Name: "feeds", Type: "feeds"},
+		},
+		Pipeline: []StageConfig{
+			{Stage: "max_age", Options: json.RawMessage(`{"max_age": "24h"}`)},
+			{Stage: "rules"},
+			{Stage: "semantic_dedup", Options: json.RawMessage(`{"threshold": 0.85}`)},
+			{Stage: "limit_per_source", Options: json.RawMessage(`{"max": 50}`)},
+		},
+	}
+}
+
+// Load reads the config file at path. A missing file returns Default().
+// Sections the file omits are filled from Default().
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// applyDefaults fills omitted sections from Default().
+func (c *Config) applyDefaults() {
+	def := Default()
+	if len(c.Providers) == 0 {
+		c.Providers = def.Providers
+	}
+	if len(c.Pipeline) == 0 {
+		c.Pipeline = def.Pipeline
+	}
+}
+
+// Validate checks the configuration for mistakes that would otherwise
+// surface as confusing runtime behaviour.
+func (c Config) Validate() error {
+	seen := make(map[string]bool)
+	for i, p := range c.Providers {
+		if p.Name == "" {
+			return fmt.Errorf("providers[%d]: name is required", i)
+		}
+		if p.Type == "" {
+			return fmt.Errorf("provider %q: type is required", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("provider %q: duplicate name", p.Name)
+		}
+		seen[p.Name] = true
+		if p.Timeout < 0 {
+			return fmt.Errorf("provider %q: timeout must be >= 0", p.Name)
+		}
+	}
+	if c.Budgets.DailyTokens < 0 || c.Budgets.MonthlyTokens < 0 {
+		return fmt.Errorf("budgets: daily_tokens and monthly_tokens must be >= 0")
+	}
+	for name, o := range c.Sources.Overrides {
+		if o.MaxItems < 0 || o.Timeout < 0 {
+			return fmt.Errorf("sources.overrides[%q]: max_items and timeout must be >= 0", name)
+		}
+	}
+	stages := make(map[string]bool)
+	for i, st := range c.Pipeline {
+		if st.Stage == "" {
+			return fmt.Errorf("pipeline[%d]: stage is required", i)
+		}
+		if stages[st.Label()] {
+			return fmt.Errorf("pipeline stage %q: duplicate name (set \"name\" to run a stage twice)", st.Label())
+		}
+		stages[st.Label()] = true
+	}
+	return nil
+}
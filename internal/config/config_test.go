@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad_MissingFileReturnsDefaults(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "nope.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Providers) != 2 || cfg.Providers[0].Type != "clarion" || cfg.Providers[1].Type != "feeds" {
+		t.Errorf("expected default clarion and feeds providers, got %+v", cfg.Providers)
+	}
+}
+
+func TestLoad_Providers(t *testing.T) {
+	path := writeConfig(t, `{
+		"providers": [
+			{"name": "catalog", "type": "clarion", "timeout": "90s", "options": {"source_concurrency": 2}},
+			{"name": "mine", "type": "feeds", "enabled": false, "timeout": 5}
+		]
+	}`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Providers) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(cfg.Providers))
+	}
+	p := cfg.Providers[0]
+	if time.Duration(p.Timeout) != 90*time.Second || string(p.Options) != `{"source_concurrency": 2}` || !p.IsEnabled() {
+		t.Errorf("unexpected first provider: %+v", p)
+	}
+	p = cfg.Providers[1]
+	if time.Duration(p.Timeout) != 5*time.Second || p.IsEnabled() {
+		t.Errorf("unexpected second provider: %+v", p)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"syntax", `{"providers": [`, "parse config"},
+		{"duplicate", `{"providers": [{"name": "a", "type": "clarion"}, {"name": "a", "type": "clarion"}]}`, "duplicate"},
+		{"missing type", `{"providers": [{"name": "a"}]}`, "type is required"},
+		{"bad duration", `{"providers": [{"name": "a", "type": "clarion", "timeout": "soon"}]}`, "invalid duration"},
+		{"missing stage", `{"pipeline": [{"name": "a"}]}`, "stage is required"},
+		{"duplicate stage", `{"pipeline": [{"stage": "dedup"}, {"stage": "dedup"}]}`, "duplicate name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.body))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoad_Sources(t *testing.T) {
+	path := writeConfig(t, `{
+		"sources": {
+			"enable": {"categories": ["tech", "world"]},
+			"disable": {"names": ["Noisy Blog"], "types": ["reddit"]},
+			"overrides": {"Hacker News": {"max_items": 100, "timeout": "10s"}}
+		}
+	}`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Providers) == 0 {
+		t.Error("expected default providers when section omitted")
+	}
+	if got := cfg.Sources.Enable.Matches("Any", "rss", "Tech"); got != "category" {
+		t.Errorf("expected case-insensitive category match, got %q", got)
+	}
+	if got := cfg.Sources.Disable.Matches("noisy blog", "rss", ""); got != "name" {
+		t.Errorf("expected name match, got %q", got)
+	}
+	if got := cfg.Sources.Disable.Matches("Other", "rss", ""); got != "" {
+		t.Errorf("expected no match, got %q", got)
+	}
+	o := cfg.Sources.Overrides["Hacker News"]
+	if o.MaxItems != 100 || time.Duration(o.Timeout) != 10*time.Second {
+		t.Errorf("unexpected override: %+v", o)
+	}
+}
+
+func TestLoad_Budgets(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"budgets": {"daily_tokens": 100000, "monthly_tokens": 2000000}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Budgets.DailyTokens != 100000 || cfg.Budgets.MonthlyTokens != 2000000 {
+		t.Errorf("unexpected budgets: %+v", cfg.Budgets)
+	}
+
+	if _, err := Load(writeConfig(t, `{"budgets": {"daily_tokens": -1}}`)); err == nil {
+		t.Error("expected error for negative budget")
+	}
+}
+
+func TestLoad_Pipeline(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "nope.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Pipeline) != 4 || cfg.Pipeline[1].Stage != "rules" || cfg.Pipeline[2].Stage != "semantic_dedup" {
+		t.Errorf("expected the default pipeline, got %+v", cfg.Pipeline)
+	}
+
+	cfg, err = Load(writeConfig(t, `{
+		"pipeline": [
+			{"stage": "max_age", "options": {"max_age": "6h"}},
+			{"stage": "semantic_dedup", "name": "loose dedup", "enabled": false}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Pipeline) != 2 || string(cfg.Pipeline[0].Options) != `{"max_age": "6h"}` {
+		t.Errorf("unexpected pipeline: %+v", cfg.Pipeline)
+	}
+	if st := cfg.Pipeline[1]; st.Label() != "loose dedup" || st.IsEnabled() || cfg.Pipeline[0].Label() != "max_age" {
+		t.Errorf("unexpected stage labels/enabled: %+v", cfg.Pipeline)
+	}
+}
@@ -3,7 +3,7 @@ package coord
 
 import (
 	"context"
-	"regexp"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -12,8 +12,11 @@ import (
 
 	"github.com/abelbrown/observer/internal/embed"
 	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/resilience"
 	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/thread"
 	"github.com/abelbrown/observer/internal/ui"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
 // fetchInterval is the time between fetch cycles.
@@ -25,11 +28,25 @@ const embedBatchSize = 100
 // embedWorkerInterval is the time between embedding worker cycles.
 const embedWorkerInterval = 2 * time.Second
 
+// maxConsecutiveEmbedFailures ends a sequential embedding pass early: that
+// many failures in a row means the backend is down, not that items are bad.
+const maxConsecutiveEmbedFailures = 3
+
+// maxEmbedPause caps the worker's backoff after cycles where nothing embedded.
+const maxEmbedPause = 5 * time.Minute
+
 // Provider fetches items from external sources.
 type Provider interface {
 	Fetch(ctx context.Context) ([]store.Item, error)
 }
 
+// Sender receives notifications from background work.
+// *tea.Program satisfies it; the daemon supplies its own implementation
+// to fan messages out to socket subscribers.
+type Sender interface {
+	Send(msg tea.Msg)
+}
+
 // Coordinator manages background fetching and embedding.
 // Uses context cancellation as the ONLY stop mechanism.
 type Coordinator struct {
@@ -37,7 +54,18 @@ type Coordinator struct {
 	provider Provider
 	embedder embed.Embedder // optional: nil to disable embedding
 	logger   *otel.Logger
+	retry    store.EmbedRetryPolicy
+	budget   usage.Budget
 	wg       sync.WaitGroup
+
+	// linkMu serializes linkThreads: the fetch loop and the embedding
+	// worker both link, and each run updates threads from its own snapshot.
+	linkMu sync.Mutex
+
+	pauseMu     sync.Mutex
+	pause       time.Duration // current worker backoff; 0 when healthy
+	pausedUntil time.Time
+	overBudget  bool // last budget check result, for logging transitions
 }
 
 // NewCoordinator creates a Coordinator with the given provider.
@@ -51,18 +79,27 @@ func NewCoordinator(s *store.Store, p Provider, e embed.Embedder, l *otel.Logger
 		provider: p,
 		embedder: e,
 		logger:   l,
+		retry:    store.DefaultEmbedRetryPolicy,
 	}
 }
 
+// SetBudget caps the token spend of background embedding. When today's or
+// this month's usage reaches a cap, the worker pauses until the next
+// period; interactive search is never affected. Call before Start.
+func (c *Coordinator) SetBudget(b usage.Budget) {
+	c.budget = b
+}
+
 // Start begins background fetching. Call with a cancellable context.
 // Performs initial fetch immediately, then every 5 minutes.
-func (c *Coordinator) Start(ctx context.Context, program *tea.Program) {
+// The sender is optional (nil to skip completion notifications).
+func (c *Coordinator) Start(ctx context.Context, sender Sender) {
 	c.wg.Add(1)
 	go func() {
 		defer c.wg.Done()
 
 		// Perform initial fetch immediately
-		c.fetchAll(ctx, program)
+		c.fetchAll(ctx, sender)
 
 		// Create ticker for periodic fetches
 		ticker := time.NewTicker(fetchInterval)
@@ -73,7 +110,7 @@ func (c *Coordinator) Start(ctx context.Context, program *tea.Program) {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				c.fetchAll(ctx, program)
+				c.fetchAll(ctx, sender)
 			}
 		}
 	}()
@@ -97,6 +134,10 @@ func (c *Coordinator) StartEmbeddingWorker(ctx context.Context) {
 	go func() {
 		defer c.wg.Done()
 
+		// Items embedded before threads existed, or while the worker
+		// was stopped, are linked first.
+		c.linkThreads(ctx)
+
 		ticker := time.NewTicker(embedWorkerInterval)
 		defer ticker.Stop()
 
@@ -111,71 +152,209 @@ func (c *Coordinator) StartEmbeddingWorker(ctx context.Context) {
 	}()
 }
 
-// embedBatch embeds up to limit items that need embeddings.
-// Returns early if embedder unavailable or context cancelled.
+// embedBatch embeds up to limit items with no vector from the model the
+// embedder will use next: after a fallback chain switches backends, items
+// are re-embedded with the new model, and vectors from the old one stay.
+// Returns early if embedder unavailable, context cancelled, or the worker
+// is backing off after a cycle in which every attempt failed.
 func (c *Coordinator) embedBatch(ctx context.Context, limit int) {
-	if !c.embedder.Available() {
+	if !c.embedder.Available() || c.paused() || c.budgetExceeded() {
 		return
 	}
 
-	items, err := c.store.GetItemsNeedingEmbedding(limit)
+	items, err := c.store.GetItemsNeedingEmbedding(embed.ModelName(c.embedder), limit)
 	if err != nil || len(items) == 0 {
 		return
 	}
 
-	c.embedItems(ctx, items)
+	embedded, failed := c.embedItems(usage.WithPurpose(ctx, usage.PurposeBackground), items)
+	c.updatePause(embedded, failed)
+	if embedded > 0 {
+		c.linkThreads(ctx)
+	}
 }
 
-// htmlTagRe matches HTML tags.
-var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+// paused reports whether the embedding worker is backing off.
+func (c *Coordinator) paused() bool {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	return time.Now().Before(c.pausedUntil)
+}
 
-// whitespaceRe matches runs of whitespace.
-var whitespaceRe = regexp.MustCompile(`\s+`)
+// updatePause doubles the worker backoff after a cycle where nothing
+// embedded and something failed, and resets it after any success.
+func (c *Coordinator) updatePause(embedded, failed int) {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
 
-// sanitizeForEmbedding strips HTML tags, collapses whitespace, and caps at maxChars.
-func sanitizeForEmbedding(s string, maxChars int) string {
-	s = htmlTagRe.ReplaceAllString(s, " ")
-	s = whitespaceRe.ReplaceAllString(s, " ")
-	s = strings.TrimSpace(s)
-	if maxChars <= 0 {
-		return ""
+	if embedded > 0 || failed == 0 {
+		c.pause = 0
+		c.pausedUntil = time.Time{}
+		return
+	}
+	if c.pause == 0 {
+		c.pause = embedWorkerInterval
 	}
-	runes := []rune(s)
-	if len(runes) > maxChars {
-		return string(runes[:maxChars])
+	c.pause *= 2
+	if c.pause > maxEmbedPause {
+		c.pause = maxEmbedPause
+	}
+	c.pausedUntil = time.Now().Add(c.pause)
+	c.logger.Emit(otel.Event{Kind: otel.KindEmbedError, Level: otel.LevelWarn, Comp: "coord", Count: failed, Dur: c.pause, Msg: "all embeddings failed, pausing worker"})
+}
+
+// budgetExceeded reports whether background embedding is over budget,
+// logging when it pauses and resumes.
+func (c *Coordinator) budgetExceeded() bool {
+	if !c.budget.Enabled() {
+		return false
+	}
+	now := time.Now()
+	day, err := c.store.UsageTotals(usage.StartOfDay(now))
+	if err != nil {
+		c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "coord", Msg: "failed to read usage", Err: err.Error()})
+		return false
+	}
+	month, err := c.store.UsageTotals(usage.StartOfMonth(now))
+	if err != nil {
+		c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "coord", Msg: "failed to read usage", Err: err.Error()})
+		return false
+	}
+	checkErr := c.budget.Check(day, month)
+
+	c.pauseMu.Lock()
+	was := c.overBudget
+	c.overBudget = checkErr != nil
+	c.pauseMu.Unlock()
+
+	switch {
+	case checkErr != nil && !was:
+		c.logger.Emit(otel.Event{Kind: otel.KindEmbedError, Level: otel.LevelWarn, Comp: "coord", Msg: "background embedding paused", Err: checkErr.Error()})
+	case checkErr == nil && was:
+		c.logger.Emit(otel.Event{Kind: otel.KindEmbedStart, Level: otel.LevelInfo, Comp: "coord", Msg: "background embedding resumed"})
+	}
+	return checkErr != nil
+}
+
+// recordEmbedFailure counts a failed attempt against the item so it backs
+// off and is eventually dead-lettered instead of being retried every cycle.
+// Only failures the item is to blame for count (resilience.InputFault):
+// rate limits, open breakers, timeouts and outages say nothing about the
+// item, and the consecutive-failure stop and the worker's pause already
+// deal with them.
+func (c *Coordinator) recordEmbedFailure(id string, cause error) {
+	if !resilience.InputFault(cause) {
+		return
+	}
+	job, err := c.store.RecordEmbedFailure(id, cause, c.retry)
+	if err != nil {
+		c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelError, Comp: "coord", Source: id, Msg: "failed to record embed failure", Err: err.Error()})
+		return
+	}
+	if job.State == store.EmbedJobDead {
+		c.logger.Emit(otel.Event{Kind: otel.KindEmbedError, Level: otel.LevelWarn, Comp: "coord", Source: id, Count: job.Attempts, Msg: "embed job dead-lettered", Err: job.LastError})
 	}
-	return s
 }
 
 // embedTextForItem builds the text to embed for a single item.
 // Title + sanitized summary, capped at ~2000 chars (~500 tokens).
 func embedTextForItem(item store.Item) string {
-	text := item.Title
-	if item.Summary != "" {
-		remaining := 2000 - len([]rune(text))
-		if remaining > 0 {
-			clean := sanitizeForEmbedding(item.Summary, remaining)
-			if clean != "" {
-				text += " " + clean
+	return embed.DocumentText(item.Title, item.Summary)
+}
+
+// itemText is an item paired with the text sent to the embedder and the
+// text's content-addressed cache key ("" when caching is off).
+type itemText struct {
+	item store.Item
+	text string
+	key  string
+}
+
+// embedFromCache saves cached vectors for pairs whose text was embedded
+// before and returns the rest, along with the items saved. Items repeating
+// the text of an earlier pair in the batch (syndicated copies) are held
+// back in dups, keyed by cache key, so only one copy is sent; saveEmbedding
+// fills them in.
+func (c *Coordinator) embedFromCache(pairs []itemText, model string) (rest []itemText, dups map[string][]store.Item, hits []store.Item) {
+	if pairs[0].key == "" {
+		return pairs, nil, nil
+	}
+	keys := make([]string, len(pairs))
+	for i, p := range pairs {
+		keys[i] = p.key
+	}
+	cached, err := c.store.GetCachedEmbeddings(keys)
+	if err != nil {
+		c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "coord", Msg: "embedding cache lookup failed", Err: err.Error()})
+		cached = nil
+	}
+
+	dups = make(map[string][]store.Item)
+	seen := make(map[string]bool)
+	for _, p := range pairs {
+		if emb, ok := cached[p.key]; ok {
+			if err := c.store.SaveModelEmbedding(p.item.ID, model, emb); err != nil {
+				c.logger.Emit(otel.Event{Kind: otel.KindError, Level: otel.LevelError, Comp: "coord", Source: p.item.ID, Msg: "failed to save embedding", Err: err.Error()})
+				continue
 			}
+			hits = append(hits, p.item)
+			continue
+		}
+		if seen[p.key] {
+			dups[p.key] = append(dups[p.key], p.item)
+			continue
 		}
+		seen[p.key] = true
+		rest = append(rest, p)
 	}
-	return text
+	return rest, dups, hits
+}
+
+// saveEmbedding stores a freshly computed vector for p, its held-back
+// duplicates, and the cache, all tagged with model (the model that
+// actually produced it, which after a fallback switch may differ from
+// the one p.key was derived from). Returns the items saved.
+func (c *Coordinator) saveEmbedding(p itemText, emb []float32, dups map[string][]store.Item, model string) []store.Item {
+	var saved []store.Item
+	for _, item := range append([]store.Item{p.item}, dups[p.key]...) {
+		if err := c.store.SaveModelEmbedding(item.ID, model, emb); err != nil {
+			c.logger.Emit(otel.Event{Kind: otel.KindError, Level: otel.LevelError, Comp: "coord", Source: item.ID, Msg: "failed to save embedding", Err: err.Error()})
+			continue
+		}
+		saved = append(saved, item)
+	}
+	if p.key != "" && model != "" {
+		key := embed.CacheKey(model, embed.TaskDocument, p.text)
+		if err := c.store.CacheEmbedding(key, model, emb); err != nil {
+			c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "coord", Source: p.item.ID, Msg: "failed to cache embedding", Err: err.Error()})
+		}
+	}
+	return saved
 }
 
 // embedItems generates and saves embeddings for the given items.
+// Texts already in the embedding cache are saved without a backend call.
 // Uses batch embedding if available, otherwise falls back to sequential.
-// If batch embedding fails, falls back to sequential to avoid discarding the entire batch.
-func (c *Coordinator) embedItems(ctx context.Context, items []store.Item) {
+// If batch embedding fails or returns the wrong number of vectors, falls back to
+// sequential to avoid discarding the entire batch.
+// Individual failures the item is to blame for are recorded in the embed job queue. Long items
+// then get their chunks embedded (see embedChunks).
+// Returns how many items were embedded and how many failed.
+func (c *Coordinator) embedItems(ctx context.Context, items []store.Item) (embedded, failed int) {
+	done, failed := c.embedDocuments(ctx, items)
+	c.embedChunks(ctx, done)
+	return len(done), failed
+}
+
+// embedDocuments generates and saves the document vector of each item
+// and returns the items saved and how many failed.
+func (c *Coordinator) embedDocuments(ctx context.Context, items []store.Item) (done []store.Item, failed int) {
 	if len(items) == 0 {
-		return
+		return nil, 0
 	}
 
 	// Build texts for all items, filtering out empty ones
-	type itemText struct {
-		item store.Item
-		text string
-	}
+	model := embed.ModelName(c.embedder)
 	var pairs []itemText
 	for _, item := range items {
 		text := embedTextForItem(item)
@@ -183,10 +362,20 @@ func (c *Coordinator) embedItems(ctx context.Context, items []store.Item) {
 			c.logger.Emit(otel.Event{Kind: otel.KindEmbedError, Level: otel.LevelWarn, Comp: "coord", Source: item.ID, Msg: "skipping embedding: empty text"})
 			continue
 		}
-		pairs = append(pairs, itemText{item: item, text: text})
+		p := itemText{item: item, text: text}
+		if model != "" {
+			p.key = embed.CacheKey(model, embed.TaskDocument, text)
+		}
+		pairs = append(pairs, p)
 	}
 	if len(pairs) == 0 {
-		return
+		return nil, 0
+	}
+
+	// Texts embedded before (by any item) cost nothing.
+	pairs, dups, done := c.embedFromCache(pairs, model)
+	if len(pairs) == 0 {
+		return done, 0
 	}
 
 	// Batch path: single API call for all items
@@ -195,48 +384,166 @@ func (c *Coordinator) embedItems(ctx context.Context, items []store.Item) {
 		for i, p := range pairs {
 			texts[i] = p.text
 		}
-		embeddings, err := batcher.EmbedBatch(ctx, texts)
+		embeddings, batchModel, err := embed.EmbedBatchWithModel(ctx, batcher, texts)
+		if err == nil && len(embeddings) != len(texts) {
+			err = fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
+		}
 		if err != nil {
 			c.logger.Emit(otel.Event{Kind: otel.KindEmbedError, Level: otel.LevelError, Comp: "coord", Msg: "batch embedding failed, falling back to sequential", Err: err.Error()})
 			// Fall through to sequential path below
 		} else {
 			for i, emb := range embeddings {
 				if ctx.Err() != nil {
-					return
-				}
-				if i < len(pairs) {
-					if err := c.store.SaveEmbedding(pairs[i].item.ID, emb); err != nil {
-						c.logger.Emit(otel.Event{Kind: otel.KindError, Level: otel.LevelError, Comp: "coord", Source: pairs[i].item.ID, Msg: "failed to save embedding", Err: err.Error()})
-					}
+					return done, failed
 				}
+				done = append(done, c.saveEmbedding(pairs[i], emb, dups, batchModel)...)
 			}
-			return
+			return done, failed
 		}
 	}
 
-	// Sequential fallback (Ollama, or batch failure)
+	// Sequential fallback (non-batch embedders, or batch failure)
+	consecutive := 0
 	for _, p := range pairs {
 		if ctx.Err() != nil {
-			return
+			return done, failed
 		}
 		if !c.embedder.Available() {
-			return
+			return done, failed
 		}
 
-		embedding, err := c.embedder.Embed(ctx, p.text)
+		embedding, embedModel, err := embed.EmbedWithModel(ctx, c.embedder, p.text)
 		if err != nil {
+			if ctx.Err() != nil {
+				return done, failed // cancelled, not the item's fault
+			}
 			c.logger.Emit(otel.Event{Kind: otel.KindEmbedError, Level: otel.LevelError, Comp: "coord", Source: p.item.ID, Err: err.Error()})
+			c.recordEmbedFailure(p.item.ID, err)
+			failed++
+			consecutive++
+			if consecutive >= maxConsecutiveEmbedFailures {
+				return done, failed
+			}
+			continue
+		}
+		consecutive = 0
+
+		done = append(done, c.saveEmbedding(p, embedding, dups, embedModel)...)
+	}
+	return done, failed
+}
+
+// embedChunks embeds the passages of long items (embed.DocumentChunks)
+// and stores them as the items' chunks. Passage vectors go through the
+// embedding cache like document vectors. A failure is logged and leaves
+// the item with its document vector only.
+func (c *Coordinator) embedChunks(ctx context.Context, items []store.Item) {
+	for _, item := range items {
+		passages := embed.DocumentChunks(item.Summary)
+		if len(passages) == 0 {
 			continue
 		}
+		if ctx.Err() != nil {
+			return
+		}
+		texts := make([]string, len(passages))
+		for i, p := range passages {
+			texts[i] = embed.ChunkText(item.Title, p)
+		}
+		embs, model, err := c.embedTexts(ctx, texts)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Emit(otel.Event{Kind: otel.KindEmbedError, Level: otel.LevelWarn, Comp: "coord", Source: item.ID, Msg: "chunk embedding failed", Err: err.Error()})
+			}
+			continue
+		}
+		chunks := make([]store.Chunk, len(passages))
+		for i, p := range passages {
+			chunks[i] = store.Chunk{Index: i, Text: p, Embedding: embs[i]}
+		}
+		if err := c.store.SaveChunks(item.ID, model, chunks); err != nil {
+			c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelError, Comp: "coord", Source: item.ID, Msg: "failed to save chunks", Err: err.Error()})
+		}
+	}
+}
+
+// embedTexts embeds document texts, taking what it can from the cache,
+// and returns the vectors with the model that produced them. Fails rather
+// than mix models when a fallback switch happens between cache and backend.
+func (c *Coordinator) embedTexts(ctx context.Context, texts []string) ([][]float32, string, error) {
+	model := embed.ModelName(c.embedder)
+	out := make([][]float32, len(texts))
+	var keys []string
+	cached := map[string][]float32{}
+	if model != "" {
+		keys = make([]string, len(texts))
+		for i, text := range texts {
+			keys[i] = embed.CacheKey(model, embed.TaskDocument, text)
+		}
+		var err error
+		if cached, err = c.store.GetCachedEmbeddings(keys); err != nil {
+			c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "coord", Msg: "embedding cache lookup failed", Err: err.Error()})
+			cached = map[string][]float32{}
+		}
+	}
+
+	var missing []int
+	var missingTexts []string
+	for i, text := range texts {
+		if keys != nil {
+			if emb, ok := cached[keys[i]]; ok {
+				out[i] = emb
+				continue
+			}
+		}
+		missing = append(missing, i)
+		missingTexts = append(missingTexts, text)
+	}
+	if len(missing) == 0 {
+		return out, model, nil
+	}
 
-		if err := c.store.SaveEmbedding(p.item.ID, embedding); err != nil {
-			c.logger.Emit(otel.Event{Kind: otel.KindError, Level: otel.LevelError, Comp: "coord", Source: p.item.ID, Msg: "failed to save embedding", Err: err.Error()})
+	var embs [][]float32
+	var embModel string
+	var err error
+	if batcher, ok := c.embedder.(embed.BatchEmbedder); ok {
+		embs, embModel, err = embed.EmbedBatchWithModel(ctx, batcher, missingTexts)
+	} else {
+		embs = make([][]float32, len(missingTexts))
+		for i, text := range missingTexts {
+			var m string
+			if embs[i], m, err = embed.EmbedWithModel(ctx, c.embedder, text); err != nil {
+				break
+			}
+			if i > 0 && m != embModel {
+				err = fmt.Errorf("embedding model changed from %s to %s", embModel, m)
+				break
+			}
+			embModel = m
 		}
 	}
+	if err != nil {
+		return nil, "", err
+	}
+	if len(embs) != len(missing) {
+		return nil, "", fmt.Errorf("expected %d embeddings, got %d", len(missing), len(embs))
+	}
+	if len(missing) < len(texts) && embModel != model {
+		return nil, "", fmt.Errorf("embedding model changed from %s to %s", model, embModel)
+	}
+	for j, i := range missing {
+		out[i] = embs[j]
+		if embModel != "" {
+			if err := c.store.CacheEmbedding(embed.CacheKey(embModel, embed.TaskDocument, texts[i]), embModel, embs[j]); err != nil {
+				c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "coord", Msg: "failed to cache embedding", Err: err.Error()})
+			}
+		}
+	}
+	return out, embModel, nil
 }
 
 // fetchAll fetches from the provider, saves items, sends completion, then embeds.
-func (c *Coordinator) fetchAll(ctx context.Context, program *tea.Program) {
+func (c *Coordinator) fetchAll(ctx context.Context, sender Sender) {
 	if ctx.Err() != nil {
 		return
 	}
@@ -255,8 +562,8 @@ func (c *Coordinator) fetchAll(ctx context.Context, program *tea.Program) {
 		}
 	}
 
-	if program != nil {
-		program.Send(ui.FetchComplete{
+	if sender != nil {
+		sender.Send(ui.FetchComplete{
 			Source:   "all",
 			NewItems: newItems,
 			Err:      err,
@@ -272,14 +579,35 @@ func (c *Coordinator) fetchAll(ctx context.Context, program *tea.Program) {
 // embedNewItems generates embeddings for items that don't have one.
 // Skips silently if embedder is nil or unavailable.
 func (c *Coordinator) embedNewItems(ctx context.Context) {
-	if c.embedder == nil || !c.embedder.Available() {
+	if c.embedder == nil || !c.embedder.Available() || c.budgetExceeded() {
 		return
 	}
 
-	items, err := c.store.GetItemsNeedingEmbedding(embedBatchSize)
+	items, err := c.store.GetItemsNeedingEmbedding(embed.ModelName(c.embedder), embedBatchSize)
 	if err != nil || len(items) == 0 {
 		return
 	}
 
-	c.embedItems(ctx, items)
+	if embedded, _ := c.embedItems(usage.WithPurpose(ctx, usage.PurposeBackground), items); embedded > 0 {
+		c.linkThreads(ctx)
+	}
+}
+
+// linkThreads links every embedded item not yet in a story thread,
+// a batch at a time (see thread.LinkPending).
+func (c *Coordinator) linkThreads(ctx context.Context) {
+	c.linkMu.Lock()
+	defer c.linkMu.Unlock()
+
+	model := embed.ModelName(c.embedder)
+	for ctx.Err() == nil {
+		n, err := thread.LinkPending(ctx, c.store, model, thread.BatchSize)
+		if err != nil {
+			c.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "coord", Msg: "failed to link story threads", Err: err.Error()})
+			return
+		}
+		if n < thread.BatchSize {
+			return
+		}
+	}
 }
@@ -3,11 +3,18 @@ package coord
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/resilience"
 	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
 // mockProvider implements the Provider interface for testing.
@@ -711,6 +718,46 @@ func TestCoordinatorBatchEmbedError(t *testing.T) {
 	}
 }
 
+func TestCoordinatorShortBatchFallsBackToSequential(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	_, err = s.SaveItems([]store.Item{
+		{ID: "item1", SourceType: "rss", SourceName: "TestSource", Title: "Test 1", URL: "http://example.com/1", Published: now, Fetched: now},
+		{ID: "item2", SourceType: "rss", SourceName: "TestSource", Title: "Test 2", URL: "http://example.com/2", Published: now, Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("failed to save items: %v", err)
+	}
+
+	var singleCount int
+	embedder := &mockBatchEmbedder{
+		available: true,
+		embedBatchFunc: func(ctx context.Context, texts []string) ([][]float32, error) {
+			return [][]float32{{0.9, 0.9, 0.9}}, nil // one vector short
+		},
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			singleCount++
+			return []float32{0.1, 0.2, 0.3}, nil
+		},
+	}
+	coord := NewCoordinator(s, &mockProvider{}, embedder, nil)
+	coord.embedBatch(context.Background(), 10)
+
+	if singleCount != 2 {
+		t.Errorf("expected both items embedded one by one, got %d calls", singleCount)
+	}
+	for _, id := range []string{"item1", "item2"} {
+		if emb, _ := s.GetEmbedding(id); len(emb) != 3 || emb[0] != 0.1 {
+			t.Errorf("%s embedding = %v, want the sequential vector", id, emb)
+		}
+	}
+}
+
 func TestCoordinatorBatchEmbedFallback(t *testing.T) {
 	// When batch fails but some items fail individually too,
 	// only successful items should get embeddings.
@@ -867,3 +914,406 @@ func TestCoordinatorEmbeddingWorkerSkipsWhenUnavailable(t *testing.T) {
 		}
 	}
 }
+
+func TestCoordinatorDeadLettersPoisonItem(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	_, err = s.SaveItems([]store.Item{
+		{ID: "poison", SourceType: "rss", SourceName: "TestSource", Title: "Poison", URL: "http://example.com/p", Published: now, Fetched: now},
+		{ID: "fine", SourceType: "rss", SourceName: "TestSource", Title: "Fine", URL: "http://example.com/f", Published: now, Fetched: now.Add(time.Second)},
+	})
+	if err != nil {
+		t.Fatalf("failed to save items: %v", err)
+	}
+
+	var poisonCalls int
+	embedder := &mockEmbedder{
+		available: true,
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			if text == "Poison" {
+				poisonCalls++
+				return nil, &resilience.StatusError{Backend: "mock", Code: http.StatusBadRequest, Body: "bad input"}
+			}
+			return []float32{0.1, 0.2, 0.3}, nil
+		},
+	}
+	coord := NewCoordinator(s, &mockProvider{}, embedder, nil)
+	coord.retry = store.EmbedRetryPolicy{MaxAttempts: 2} // no delay: retry every cycle
+
+	for i := 0; i < 4; i++ {
+		coord.embedBatch(context.Background(), 10)
+		coord.pausedUntil = time.Time{} // don't let the worker backoff hide retries
+	}
+
+	if poisonCalls != 2 {
+		t.Errorf("expected poison item attempted MaxAttempts=2 times, got %d", poisonCalls)
+	}
+	if emb, _ := s.GetEmbedding("fine"); emb == nil {
+		t.Error("expected fine item to be embedded")
+	}
+	dead, err := s.ListEmbedJobs(store.EmbedJobDead, 10)
+	if err != nil {
+		t.Fatalf("ListEmbedJobs: %v", err)
+	}
+	if len(dead) != 1 || dead[0].ItemID != "poison" || dead[0].Attempts != 2 {
+		t.Errorf("expected poison dead-lettered after 2 attempts, got %+v", dead)
+	}
+}
+
+func TestCoordinatorDoesNotDeadLetterOnOutage(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	_, err = s.SaveItems([]store.Item{
+		{ID: "a", SourceType: "rss", SourceName: "TestSource", Title: "A", URL: "http://example.com/a", Published: now, Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("failed to save items: %v", err)
+	}
+
+	outages := []error{
+		&resilience.StatusError{Backend: "mock", Code: http.StatusTooManyRequests},
+		&resilience.StatusError{Backend: "mock", Code: http.StatusServiceUnavailable},
+		resilience.ErrBreakerOpen,
+		context.DeadlineExceeded,
+		errors.New("dial tcp: connection refused"),
+	}
+	var calls int
+	embedder := &mockEmbedder{
+		available: true,
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			calls++
+			return nil, outages[(calls-1)%len(outages)]
+		},
+	}
+	coord := NewCoordinator(s, &mockProvider{}, embedder, nil)
+	coord.retry = store.EmbedRetryPolicy{MaxAttempts: 2}
+
+	for range outages {
+		coord.embedBatch(context.Background(), 10)
+		coord.pausedUntil = time.Time{}
+	}
+
+	if calls != len(outages) {
+		t.Errorf("expected the item retried every cycle, got %d calls", calls)
+	}
+	if jobs, _ := s.ListEmbedJobs("", 10); len(jobs) != 0 {
+		t.Errorf("outages counted against the item: %+v", jobs)
+	}
+}
+
+func TestCoordinatorPausesEmbeddingOverBudget(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	_, err = s.SaveItems([]store.Item{
+		{ID: "a", SourceType: "rss", SourceName: "TestSource", Title: "A", URL: "http://example.com/a", Published: now, Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("failed to save items: %v", err)
+	}
+
+	var calls int
+	embedder := &mockEmbedder{
+		available: true,
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			calls++
+			if p := usage.PurposeFrom(ctx); p != usage.PurposeBackground {
+				t.Errorf("expected background purpose, got %q", p)
+			}
+			return []float32{0.1, 0.2, 0.3}, nil
+		},
+	}
+	coord := NewCoordinator(s, &mockProvider{}, embedder, nil)
+	coord.SetBudget(usage.Budget{DailyTokens: 100})
+
+	if err := s.RecordUsage(usage.Record{Backend: "jina", Model: "m", Kind: usage.KindEmbed, Purpose: usage.PurposeInteractive, Requests: 1, Tokens: 150}); err != nil {
+		t.Fatalf("RecordUsage: %v", err)
+	}
+	coord.embedBatch(context.Background(), 10)
+	if calls != 0 {
+		t.Fatalf("expected no embedding over budget, got %d calls", calls)
+	}
+
+	coord.SetBudget(usage.Budget{DailyTokens: 1000})
+	coord.embedBatch(context.Background(), 10)
+	if calls != 1 {
+		t.Errorf("expected embedding to resume under budget, got %d calls", calls)
+	}
+}
+
+// namedEmbedder is a mockEmbedder that reports a model name, enabling the
+// embedding cache.
+type namedEmbedder struct{ mockEmbedder }
+
+func (m *namedEmbedder) ModelName() string { return "mock/model" }
+
+func TestCoordinatorUsesEmbeddingCache(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	_, err = s.SaveItems([]store.Item{
+		{ID: "wire1", SourceType: "rss", SourceName: "A", Title: "Same story", URL: "http://a.example.com/1", Published: now, Fetched: now},
+		{ID: "wire2", SourceType: "rss", SourceName: "B", Title: "Same story", URL: "http://b.example.com/1", Published: now, Fetched: now},
+		{ID: "other", SourceType: "rss", SourceName: "A", Title: "Other story", URL: "http://a.example.com/2", Published: now, Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("failed to save items: %v", err)
+	}
+
+	var calls []string
+	embedder := &namedEmbedder{mockEmbedder{
+		available: true,
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			calls = append(calls, text)
+			return []float32{0.1, 0.2, 0.3}, nil
+		},
+	}}
+	coord := NewCoordinator(s, &mockProvider{}, embedder, nil)
+	coord.embedBatch(context.Background(), 10)
+
+	if len(calls) != 2 {
+		t.Fatalf("expected syndicated copies embedded once (2 calls), got %v", calls)
+	}
+	for _, id := range []string{"wire1", "wire2", "other"} {
+		if emb, _ := s.GetEmbedding(id); emb == nil {
+			t.Errorf("expected %s to be embedded", id)
+		}
+	}
+
+	// Re-embedding after a clear is served entirely from the cache.
+	if _, err := s.ClearAllEmbeddings(); err != nil {
+		t.Fatalf("ClearAllEmbeddings: %v", err)
+	}
+	coord.embedBatch(context.Background(), 10)
+	if len(calls) != 2 {
+		t.Errorf("expected no backend calls after clear, got %d total", len(calls))
+	}
+	if emb, _ := s.GetEmbedding("other"); emb == nil {
+		t.Error("expected other to be re-embedded from cache")
+	}
+	stats, err := s.EmbedCacheStats()
+	if err != nil {
+		t.Fatalf("EmbedCacheStats: %v", err)
+	}
+	if stats.Entries != 2 || stats.Hits != 3 {
+		t.Errorf("expected 2 entries and 3 hits, got %+v", stats)
+	}
+}
+
+// modelEmbedder is a mockEmbedder that reports the given model name.
+type modelEmbedder struct {
+	mockEmbedder
+	model string
+}
+
+func (m *modelEmbedder) ModelName() string { return m.model }
+
+func TestCoordinatorKeepsFallbackVectors(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	_, err = s.SaveItems([]store.Item{
+		{ID: "a", SourceType: "rss", SourceName: "A", Title: "Story A", URL: "http://a.example.com/1", Published: now, Fetched: now},
+		{ID: "b", SourceType: "rss", SourceName: "B", Title: "Story B", URL: "http://b.example.com/1", Published: now, Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("failed to save items: %v", err)
+	}
+
+	primary := &modelEmbedder{mockEmbedder{embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+		return []float32{1, 0}, nil
+	}}, "mock/primary"}
+	fallback := &modelEmbedder{mockEmbedder{available: true, embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+		return []float32{0, 1}, nil
+	}}, "mock/fallback"}
+	coord := NewCoordinator(s, &mockProvider{}, embed.NewFallbackEmbedder(nil, primary, fallback), nil)
+	ids := []string{"a", "b"}
+
+	// The primary is down: the fallback embeds everything.
+	coord.embedBatch(context.Background(), 10)
+	if got, _ := s.GetModelEmbeddings(ids, "mock/fallback"); len(got) != 2 {
+		t.Fatalf("fallback vectors = %v, want both items", got)
+	}
+
+	// Back on the primary, items are re-embedded with it and the fallback
+	// vectors stay for the next outage.
+	primary.available = true
+	coord.embedBatch(context.Background(), 10)
+	if got, _ := s.GetModelEmbeddings(ids, "mock/primary"); len(got) != 2 {
+		t.Errorf("primary vectors = %v, want both items", got)
+	}
+	if got, _ := s.GetModelEmbeddings(ids, "mock/fallback"); len(got) != 2 {
+		t.Errorf("fallback vectors = %v, want them kept", got)
+	}
+	if n, _ := s.CountItemsNeedingEmbedding("mock/primary"); n != 0 {
+		t.Errorf("%d items still need primary vectors", n)
+	}
+}
+
+func TestCoordinatorEmbedsChunksOfLongItems(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	long := strings.Repeat("The inquiry heard evidence from several witnesses. ", 60)
+	_, err = s.SaveItems([]store.Item{
+		{ID: "long", SourceType: "rss", SourceName: "A", Title: "Inquiry", Summary: long, URL: "http://a.example.com/1", Published: now, Fetched: now},
+		{ID: "short", SourceType: "rss", SourceName: "A", Title: "Brief", Summary: "One line.", URL: "http://a.example.com/2", Published: now, Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("failed to save items: %v", err)
+	}
+
+	calls := 0
+	embedder := &namedEmbedder{mockEmbedder{
+		available: true,
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			calls++
+			return []float32{0.1, 0.2, 0.3}, nil
+		},
+	}}
+	coord := NewCoordinator(s, &mockProvider{}, embedder, nil)
+	coord.embedBatch(context.Background(), 10)
+
+	chunks, err := s.GetModelChunks([]string{"long", "short"}, "mock/model")
+	if err != nil {
+		t.Fatalf("GetModelChunks: %v", err)
+	}
+	want := len(embed.DocumentChunks(long))
+	if len(chunks["long"]) != want || want < 2 {
+		t.Fatalf("expected %d chunks for the long item, got %d", want, len(chunks["long"]))
+	}
+	if _, ok := chunks["short"]; ok {
+		t.Error("short items should not be chunked")
+	}
+	if !strings.HasPrefix(chunks["long"][0].Text, "The inquiry") {
+		t.Errorf("chunk text should be the bare passage, got %q", chunks["long"][0].Text[:20])
+	}
+	// Re-embedding after a clear rebuilds the chunks from the cache.
+	before := calls
+	if _, err := s.ClearAllEmbeddings(); err != nil {
+		t.Fatalf("ClearAllEmbeddings: %v", err)
+	}
+	coord.embedBatch(context.Background(), 10)
+	if calls != before {
+		t.Errorf("expected chunks rebuilt from the cache, got %d new calls", calls-before)
+	}
+	if chunks, _ := s.GetModelChunks([]string{"long"}, "mock/model"); len(chunks["long"]) != want {
+		t.Errorf("expected chunks restored after clear, got %d", len(chunks["long"]))
+	}
+}
+
+func TestCoordinatorLinksEmbeddedItemsIntoThreads(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	mock := &mockProvider{items: []store.Item{
+		{ID: "a", SourceName: "Wire", Title: "Quake hits Chile", URL: "http://example.com/a", Published: now.Add(-time.Hour), Fetched: now},
+		{ID: "b", SourceName: "Blog", Title: "Chile quake toll rises", URL: "http://example.com/b", Published: now, Fetched: now},
+	}}
+	embedder := &mockEmbedder{
+		available: true,
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			return []float32{0.1, 0.2, 0.3}, nil
+		},
+	}
+	coord := NewCoordinator(s, mock, embedder, nil)
+	coord.fetchAll(context.Background(), nil)
+
+	th, items, err := s.ItemThread("b")
+	if err != nil || th == nil {
+		t.Fatalf("ItemThread: %v, %v", th, err)
+	}
+	if len(items) != 2 || items[0].ID != "a" {
+		t.Errorf("expected both reports in one thread, got %v", items)
+	}
+}
+
+func TestCoordinatorLinksThreadsConcurrently(t *testing.T) {
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	// More items than one LinkPending batch, in a handful of stories.
+	embedder := &mockEmbedder{available: true}
+	model := embed.ModelName(embedder)
+	now := time.Now()
+	const stories, perStory = 5, 60
+	var items []store.Item
+	for i := 0; i < stories*perStory; i++ {
+		items = append(items, store.Item{
+			ID: fmt.Sprintf("i%d", i), SourceName: fmt.Sprintf("S%d", i%7), Title: fmt.Sprintf("Story %d report %d", i%stories, i),
+			URL: fmt.Sprintf("http://example.com/%d", i), Published: now.Add(-time.Duration(i) * time.Minute), Fetched: now,
+		})
+	}
+	if _, err := s.SaveItems(items); err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+	for i, item := range items {
+		emb := make([]float32, stories)
+		emb[i%stories] = 1
+		if err := s.SaveModelEmbedding(item.ID, model, emb); err != nil {
+			t.Fatalf("SaveModelEmbedding: %v", err)
+		}
+	}
+
+	coord := NewCoordinator(s, &mockProvider{}, embedder, nil)
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			coord.linkThreads(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	threads, reports, err := s.ThreadItemsSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ThreadItemsSince: %v", err)
+	}
+	if len(threads) != stories {
+		t.Errorf("got %d threads, want one per story (%d)", len(threads), stories)
+	}
+	linked := 0
+	for _, th := range threads {
+		if th.Items != len(reports[th.ID]) {
+			t.Errorf("thread %d: item_count %d, %d linked items", th.ID, th.Items, len(reports[th.ID]))
+		}
+		linked += len(reports[th.ID])
+	}
+	if linked != len(items) {
+		t.Errorf("linked %d items, want each of %d once", linked, len(items))
+	}
+}
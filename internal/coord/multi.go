@@ -0,0 +1,130 @@
+package coord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// defaultProviderTimeout bounds a single provider's Fetch when its
+// config does not set one. Generous: providers apply their own
+// per-source timeouts underneath.
+const defaultProviderTimeout = 2 * time.Minute
+
+// Member is one provider inside a MultiProvider.
+type Member struct {
+	Name     string // tags items and log events
+	Provider Provider
+	Timeout  time.Duration // per-fetch deadline (0 = defaultProviderTimeout)
+}
+
+// MultiProvider fans a fetch out to several providers concurrently.
+// Each member runs under its own timeout, and a failing or panicking
+// member never affects the others. How many sources a member fetches at
+// once is up to the member (see fetch.ClarionOptions.SourceConcurrency).
+// Implements Provider.
+// Thread-safety: Fetch is safe for concurrent use.
+type MultiProvider struct {
+	members []Member
+	logger  *otel.Logger
+}
+
+// NewMultiProvider creates a MultiProvider over the given members.
+func NewMultiProvider(l *otel.Logger, members ...Member) *MultiProvider {
+	if l == nil {
+		l = otel.NewNullLogger()
+	}
+	mp := &MultiProvider{logger: l}
+	for _, m := range members {
+		if m.Timeout <= 0 {
+			m.Timeout = defaultProviderTimeout
+		}
+		mp.members = append(mp.members, m)
+	}
+	return mp
+}
+
+// Names returns the member names in configuration order.
+func (mp *MultiProvider) Names() []string {
+	names := make([]string, len(mp.members))
+	for i, m := range mp.members {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// Fetch runs every member concurrently and merges their items.
+// Items are tagged with the member name unless the provider already set one.
+// Returns an error only if every member failed; partial failures are logged.
+func (mp *MultiProvider) Fetch(ctx context.Context) ([]store.Item, error) {
+	if len(mp.members) == 0 {
+		return nil, nil
+	}
+
+	type result struct {
+		items []store.Item
+		err   error
+	}
+	results := make([]result, len(mp.members))
+
+	var wg sync.WaitGroup
+	for i := range mp.members {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items, err := mp.fetchMember(ctx, &mp.members[i])
+			results[i] = result{items: items, err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	var items []store.Item
+	var errs []error
+	for i, r := range results {
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", mp.members[i].Name, r.err))
+			continue
+		}
+		items = append(items, r.items...)
+	}
+
+	if len(errs) == len(mp.members) {
+		return nil, fmt.Errorf("all %d providers failed: %w", len(errs), errors.Join(errs...))
+	}
+	return items, nil
+}
+
+// fetchMember runs one member under its timeout.
+// Panics are converted to errors so one bad provider cannot take down the fetch loop.
+func (mp *MultiProvider) fetchMember(ctx context.Context, m *Member) (items []store.Item, err error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, m.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			items, err = nil, fmt.Errorf("provider panicked: %v", r)
+		}
+		if err != nil {
+			mp.logger.Emit(otel.Event{Kind: otel.KindFetchError, Level: otel.LevelWarn, Comp: "coord", Source: m.Name, Dur: time.Since(start), Err: err.Error()})
+			return
+		}
+		mp.logger.Emit(otel.Event{Kind: otel.KindFetchComplete, Level: otel.LevelDebug, Comp: "coord", Source: m.Name, Dur: time.Since(start), Count: len(items)})
+	}()
+
+	items, err = m.Provider.Fetch(fetchCtx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i].Provider == "" {
+			items[i].Provider = m.Name
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,119 @@
+package coord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// panicProvider panics on every Fetch.
+type panicProvider struct{}
+
+func (panicProvider) Fetch(ctx context.Context) ([]store.Item, error) {
+	panic("boom")
+}
+
+func TestMultiProvider_MergesAndTagsItems(t *testing.T) {
+	a := &mockProvider{items: []store.Item{{ID: "a1"}, {ID: "a2"}}}
+	b := &mockProvider{items: []store.Item{{ID: "b1", Provider: "preset"}}}
+	mp := NewMultiProvider(nil, Member{Name: "alpha", Provider: a}, Member{Name: "beta", Provider: b})
+
+	items, err := mp.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	got := map[string]string{}
+	for _, item := range items {
+		got[item.ID] = item.Provider
+	}
+	if got["a1"] != "alpha" || got["a2"] != "alpha" {
+		t.Errorf("expected alpha items tagged alpha, got %v", got)
+	}
+	if got["b1"] != "preset" {
+		t.Errorf("expected provider-set tag preserved, got %q", got["b1"])
+	}
+}
+
+func TestMultiProvider_IsolatesFailures(t *testing.T) {
+	good := &mockProvider{items: []store.Item{{ID: "ok"}}}
+	bad := &mockProvider{err: errors.New("network down")}
+	slow := &mockProvider{delay: time.Second}
+
+	mp := NewMultiProvider(nil,
+		Member{Name: "good", Provider: good},
+		Member{Name: "bad", Provider: bad},
+		Member{Name: "panics", Provider: panicProvider{}},
+		Member{Name: "slow", Provider: slow, Timeout: 20 * time.Millisecond},
+	)
+
+	start := time.Now()
+	items, err := mp.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("partial failure should not error, got %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "ok" {
+		t.Errorf("expected only the good provider's item, got %v", items)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("slow provider was not cut off by its timeout (took %v)", time.Since(start))
+	}
+}
+
+func TestMultiProvider_AllFailed(t *testing.T) {
+	mp := NewMultiProvider(nil,
+		Member{Name: "one", Provider: &mockProvider{err: errors.New("x")}},
+		Member{Name: "two", Provider: panicProvider{}},
+	)
+
+	_, err := mp.Fetch(context.Background())
+	if err == nil {
+		t.Fatal("expected error when every provider fails")
+	}
+	if !strings.Contains(err.Error(), "one") || !strings.Contains(err.Error(), "two") {
+		t.Errorf("error should name each failed provider: %v", err)
+	}
+}
+
+func TestRegistry_Build(t *testing.T) {
+	reg := NewRegistry()
+	var built []string
+	reg.Register("mock", func(pc config.ProviderConfig, l *otel.Logger) (Provider, error) {
+		var opts struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(pc.Options, &opts); err != nil {
+			return nil, err
+		}
+		built = append(built, pc.Name)
+		return &mockProvider{items: []store.Item{{ID: opts.ID}}}, nil
+	})
+
+	off := false
+	mp, err := reg.Build([]config.ProviderConfig{
+		{Name: "first", Type: "mock", Options: json.RawMessage(`{"id":"1"}`)},
+		{Name: "second", Type: "mock", Enabled: &off, Options: json.RawMessage(`{"id":"2"}`)},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(built) != 1 || built[0] != "first" {
+		t.Errorf("expected only enabled provider built, got %v", built)
+	}
+	if names := mp.Names(); len(names) != 1 || names[0] != "first" {
+		t.Errorf("unexpected member names %v", names)
+	}
+
+	if _, err := reg.Build([]config.ProviderConfig{{Name: "x", Type: "unknown"}}, nil); err == nil {
+		t.Error("expected error for unknown provider type")
+	}
+}
@@ -0,0 +1,66 @@
+package coord
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/otel"
+)
+
+// Factory builds a Provider from its config entry.
+// Provider-specific settings arrive in cfg.Options.
+type Factory func(cfg config.ProviderConfig, l *otel.Logger) (Provider, error)
+
+// Registry maps provider types to factories so providers can be
+// enabled by configuration rather than hard-wired in main.
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register adds a factory for typ, replacing any existing one.
+func (r *Registry) Register(typ string, f Factory) {
+	r.factories[typ] = f
+}
+
+// Types returns the registered provider types, sorted.
+func (r *Registry) Types() []string {
+	types := make([]string, 0, len(r.factories))
+	for t := range r.factories {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Build constructs a MultiProvider from the enabled entries in cfgs.
+// Unknown types and factory errors are reported rather than skipped so a
+// typo in the config file does not silently drop a provider.
+func (r *Registry) Build(cfgs []config.ProviderConfig, l *otel.Logger) (*MultiProvider, error) {
+	var members []Member
+	for _, pc := range cfgs {
+		if !pc.IsEnabled() {
+			continue
+		}
+		f, ok := r.factories[pc.Type]
+		if !ok {
+			return nil, fmt.Errorf("provider %q: unknown type %q (known: %v)", pc.Name, pc.Type, r.Types())
+		}
+		p, err := f(pc, l)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", pc.Name, err)
+		}
+		members = append(members, Member{
+			Name:     pc.Name,
+			Provider: p,
+			Timeout:  time.Duration(pc.Timeout),
+		})
+	}
+	return NewMultiProvider(l, members...), nil
+}
@@ -0,0 +1,401 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+// dialTimeout bounds connecting to the daemon socket.
+const dialTimeout = 2 * time.Second
+
+// requestTimeout bounds a single request/response round trip.
+// Generous because the first search-pool load reads the whole corpus.
+const requestTimeout = 60 * time.Second
+
+// maxResponseSize caps a single response line (full search pool with vectors).
+const maxResponseSize = 256 << 20
+
+// Client talks to a running daemon. Its item methods mirror the
+// corresponding *store.Store methods so callers can use either.
+// Thread-safety: safe for concurrent use; requests are serialized.
+type Client struct {
+	socketPath string
+	timeout    time.Duration // per round trip; requestTimeout outside tests
+
+	mu     sync.Mutex
+	conn   net.Conn // nil after a broken round trip until the next call redials
+	reader *bufio.Reader
+	enc    *json.Encoder
+	nextID uint64
+	closed bool
+}
+
+// Dial connects to the daemon listening on socketPath.
+// Returns an error if no daemon is answering.
+func Dial(socketPath string) (*Client, error) {
+	c := &Client{socketPath: socketPath, timeout: requestTimeout}
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// connect (re)dials the socket. Callers hold c.mu.
+func (c *Client) connect() error {
+	conn, err := net.DialTimeout("unix", c.socketPath, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("dial daemon: %w", err)
+	}
+	c.conn = conn
+	c.reader = bufio.NewReaderSize(conn, 64*1024)
+	c.enc = json.NewEncoder(conn)
+	return nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// call sends one request and decodes the result into out (if non-nil).
+// A failed round trip (a timeout, a broken, oversized or mismatched
+// response) can leave a late reply in the stream, so the connection is
+// dropped and the next call redials; that also carries the client over
+// a daemon restart.
+func (c *Client) call(method string, params, out any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("%s: %w", method, net.ErrClosed)
+	}
+	c.nextID++
+	req := Request{ID: c.nextID, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("%s: marshal params: %w", method, err)
+		}
+		req.Params = data
+	}
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+	}
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		c.conn.Close()
+		c.conn = nil
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("%s: %s", method, resp.Error)
+	}
+	if out != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, out); err != nil {
+			return fmt.Errorf("%s: parse result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// roundTrip sends req and reads its response. Callers hold c.mu.
+func (c *Client) roundTrip(req Request) (Response, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return Response{}, err
+	}
+	if err := c.enc.Encode(req); err != nil {
+		return Response{}, fmt.Errorf("send: %w", err)
+	}
+
+	line, err := readLine(c.reader)
+	if err != nil {
+		return Response{}, fmt.Errorf("receive: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return Response{}, fmt.Errorf("parse response: %w", err)
+	}
+	if resp.ID != req.ID {
+		return Response{}, fmt.Errorf("response id %d does not match request %d", resp.ID, req.ID)
+	}
+	return resp, nil
+}
+
+// readLine reads one newline-terminated line of any length up to maxResponseSize.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxResponseSize {
+			return nil, errors.New("response too large")
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// GetItems mirrors store.Store.GetItems.
+func (c *Client) GetItems(limit int, includeRead bool) ([]store.Item, error) {
+	var res ItemsResult
+	err := c.call(MethodListItems, ListParams{Limit: limit, IncludeRead: includeRead}, &res)
+	return res.Items, err
+}
+
+// GetItemsSince mirrors store.Store.GetItemsSince.
+func (c *Client) GetItemsSince(since time.Time) ([]store.Item, error) {
+	var res ItemsResult
+	err := c.call(MethodItemsSince, SinceParams{Since: since}, &res)
+	return res.Items, err
+}
+
+// GetItemsWithEmbeddings mirrors store.Store.GetItemsWithEmbeddings.
+func (c *Client) GetItemsWithEmbeddings(ids []string) (map[string][]float32, error) {
+	return c.embeddings(EmbeddingsParams{IDs: ids})
+}
+
+// GetModelEmbeddings mirrors store.Store.GetModelEmbeddings.
+func (c *Client) GetModelEmbeddings(ids []string, model string) (map[string][]float32, error) {
+	return c.embeddings(EmbeddingsParams{IDs: ids, Model: &model})
+}
+
+// GetModelChunks mirrors store.Store.GetModelChunks.
+func (c *Client) GetModelChunks(ids []string, model string) (map[string][]store.Chunk, error) {
+	result := make(map[string][]store.Chunk)
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var res ChunksResult
+	if err := c.call(MethodChunks, ChunksParams{IDs: ids, Model: model}, &res); err != nil {
+		return nil, err
+	}
+	for id, cs := range res.Chunks {
+		for _, wc := range cs {
+			result[id] = append(result[id], store.Chunk{Index: wc.Index, Text: wc.Text, Embedding: decodeVector(wc.Embedding)})
+		}
+	}
+	return result, nil
+}
+
+// embeddings fetches stored vectors.
+func (c *Client) embeddings(p EmbeddingsParams) (map[string][]float32, error) {
+	result := make(map[string][]float32)
+	if len(p.IDs) == 0 {
+		return result, nil
+	}
+	var res EmbeddingsResult
+	if err := c.call(MethodEmbeddings, p, &res); err != nil {
+		return nil, err
+	}
+	for id, data := range res.Embeddings {
+		result[id] = decodeVector(data)
+	}
+	return result, nil
+}
+
+// MarkRead mirrors store.Store.MarkRead.
+func (c *Client) MarkRead(id string) error {
+	return c.call(MethodMarkRead, MarkReadParams{ID: id}, nil)
+}
+
+// ListMutedSources mirrors store.Store.ListMutedSources.
+func (c *Client) ListMutedSources() ([]string, error) {
+	var res MutedResult
+	err := c.call(MethodMuted, nil, &res)
+	return res.Sources, err
+}
+
+// MuteSource mirrors store.Store.MuteSource.
+func (c *Client) MuteSource(name string) error {
+	return c.call(MethodMute, MuteParams{Source: name}, nil)
+}
+
+// ListRules mirrors store.Store.ListRules.
+func (c *Client) ListRules() ([]store.Rule, error) {
+	var res RulesResult
+	err := c.call(MethodRules, nil, &res)
+	return res.Rules, err
+}
+
+// AddRule mirrors store.Store.AddRule.
+func (c *Client) AddRule(r store.Rule) (store.Rule, error) {
+	var added store.Rule
+	err := c.call(MethodRuleAdd, r, &added)
+	return added, err
+}
+
+// DeleteRule mirrors store.Store.DeleteRule.
+func (c *Client) DeleteRule(id int64) (bool, error) {
+	var res RuleDeleteResult
+	err := c.call(MethodRuleDelete, RuleDeleteParams{ID: id}, &res)
+	return res.Deleted, err
+}
+
+// RecordRuleHits mirrors store.Store.RecordRuleHits.
+func (c *Client) RecordRuleHits(hits map[int64][]string) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	return c.call(MethodRuleHits, RuleHitsParams{Hits: hits}, nil)
+}
+
+// ItemThread mirrors store.Store.ItemThread.
+func (c *Client) ItemThread(itemID string) (*store.Thread, []store.Item, error) {
+	var res ThreadResult
+	err := c.call(MethodThread, ThreadParams{ItemID: itemID}, &res)
+	return res.Thread, res.Items, err
+}
+
+// ThreadItemsSince mirrors store.Store.ThreadItemsSince.
+func (c *Client) ThreadItemsSince(since time.Time) ([]store.Thread, map[int64][]store.Item, error) {
+	var res ThreadsResult
+	err := c.call(MethodThreadsSince, SinceParams{Since: since}, &res)
+	return res.Threads, res.Items, err
+}
+
+// MarkSaved mirrors store.Store.MarkSaved.
+func (c *Client) MarkSaved(id string, saved bool) error {
+	return c.call(MethodMarkSaved, MarkSavedParams{ID: id, Saved: saved}, nil)
+}
+
+// Signals mirrors store.Store.Signals.
+func (c *Client) Signals(since time.Time) ([]store.Signal, error) {
+	var res SignalsResult
+	err := c.call(MethodSignals, SinceParams{Since: since}, &res)
+	return res.Signals, err
+}
+
+// RecordSignal mirrors store.Store.RecordSignal.
+func (c *Client) RecordSignal(itemID, kind string) error {
+	return c.call(MethodSignalRecord, SignalParams{ItemID: itemID, Kind: kind}, nil)
+}
+
+// ForgetSignal mirrors store.Store.ForgetSignal.
+func (c *Client) ForgetSignal(itemID, kind string) error {
+	return c.call(MethodSignalForget, SignalParams{ItemID: itemID, Kind: kind}, nil)
+}
+
+// ResetInterest mirrors store.Store.ResetInterest.
+func (c *Client) ResetInterest() error {
+	return c.call(MethodInterestReset, nil, nil)
+}
+
+// SetEmbedPriority mirrors store.Store.SetEmbedPriority.
+func (c *Client) SetEmbedPriority(priority int, ids []string) error {
+	return c.call(MethodPrioritize, PrioritizeParams{Priority: priority, IDs: ids}, nil)
+}
+
+// RecordUsage mirrors store.Store.RecordUsage, so an attached TUI's
+// searches count against the daemon's budgets.
+func (c *Client) RecordUsage(r usage.Record) error {
+	return c.call(MethodUsage, r, nil)
+}
+
+// GetRerankScores mirrors store.Store.GetRerankScores.
+func (c *Client) GetRerankScores(reranker, query string, keys []store.RerankKey) (map[string]float32, error) {
+	if len(keys) == 0 {
+		return make(map[string]float32), nil
+	}
+	var res RerankGetResult
+	if err := c.call(MethodRerankGet, RerankGetParams{Reranker: reranker, Query: query, Keys: keys}, &res); err != nil {
+		return nil, err
+	}
+	if res.Scores == nil {
+		res.Scores = make(map[string]float32)
+	}
+	return res.Scores, nil
+}
+
+// SaveRerankScores mirrors store.Store.SaveRerankScores.
+func (c *Client) SaveRerankScores(reranker, query string, scores []store.RerankScore) error {
+	return c.call(MethodRerankSave, RerankSaveParams{Reranker: reranker, Query: query, Scores: scores}, nil)
+}
+
+// SearchFTS mirrors store.Store.SearchFTS.
+func (c *Client) SearchFTS(query string, limit int) ([]store.Item, error) {
+	var res ItemsResult
+	err := c.call(MethodSearch, SearchParams{Query: query, Limit: limit}, &res)
+	return res.Items, err
+}
+
+// SearchSemantic ranks the daemon's corpus by similarity to query.
+func (c *Client) SearchSemantic(query string, limit int) ([]store.Item, error) {
+	var res ItemsResult
+	err := c.call(MethodSearch, SearchParams{Query: query, Limit: limit, Semantic: true}, &res)
+	return res.Items, err
+}
+
+// Subscribe streams daemon events to fn until ctx is cancelled or the
+// daemon goes away. Uses its own connection so it never blocks requests.
+// Returns nil on cancellation.
+func (c *Client) Subscribe(ctx context.Context, fn func(Event)) error {
+	conn, err := net.DialTimeout("unix", c.socketPath, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	if err := json.NewEncoder(conn).Encode(Request{ID: 1, Method: MethodSubscribe}); err != nil {
+		return fmt.Errorf("subscribe: send: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := readLine(reader)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("subscribe: receive: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("subscribe: parse response: %w", err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("subscribe: %s", resp.Error)
+	}
+
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("subscribe: %w", err)
+		}
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			continue // skip malformed events rather than dropping the stream
+		}
+		fn(ev)
+	}
+}
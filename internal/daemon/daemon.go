@@ -0,0 +1,273 @@
+// Package daemon runs Observer's fetch and embed pipeline headless and serves
+// a local JSON API over a Unix-domain socket.
+//
+// Protocol: newline-delimited JSON. Each request is a single Request line and
+// the server answers with exactly one Response line carrying the same ID.
+// A "subscribe" request switches the connection to streaming mode: after the
+// initial Response, the server writes one Event line per update until the
+// client disconnects.
+package daemon
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// API methods.
+const (
+	MethodListItems  = "items.list"       // ListParams → ItemsResult
+	MethodItemsSince = "items.since"      // SinceParams → ItemsResult
+	MethodEmbeddings = "items.embeddings" // EmbeddingsParams → EmbeddingsResult
+	MethodChunks     = "items.chunks"     // ChunksParams → ChunksResult
+	MethodMarkRead   = "items.mark_read"  // MarkReadParams → empty
+	MethodSearch     = "search"           // SearchParams → ItemsResult
+	MethodMuted      = "sources.muted"    // no params → MutedResult
+	MethodMute       = "sources.mute"     // MuteParams → empty
+	MethodPrioritize = "embed.prioritize" // PrioritizeParams → empty
+	MethodUsage      = "usage.record"     // usage.Record → empty
+	MethodRerankGet  = "rerank.get"       // RerankGetParams → RerankGetResult
+	MethodRerankSave = "rerank.save"      // RerankSaveParams → empty
+	MethodSubscribe  = "subscribe"        // no params → stream of Event
+
+	MethodRules      = "rules.list"   // no params → RulesResult
+	MethodRuleAdd    = "rules.add"    // store.Rule → store.Rule
+	MethodRuleDelete = "rules.delete" // RuleDeleteParams → RuleDeleteResult
+	MethodRuleHits   = "rules.hits"   // RuleHitsParams → empty
+
+	MethodThread       = "threads.item"  // ThreadParams → ThreadResult
+	MethodThreadsSince = "threads.since" // SinceParams → ThreadsResult
+
+	MethodMarkSaved     = "items.mark_saved" // MarkSavedParams → empty
+	MethodSignals       = "interest.signals" // SinceParams → SignalsResult
+	MethodSignalRecord  = "interest.record"  // SignalParams → empty
+	MethodSignalForget  = "interest.forget"  // SignalParams → empty
+	MethodInterestReset = "interest.reset"   // no params → empty
+)
+
+// Event kinds delivered to subscribers.
+const (
+	EventFetchComplete = "fetch.complete"
+	EventItemRead      = "item.read"
+	EventSourceMuted   = "source.muted"
+	EventRulesChanged  = "rules.changed"
+)
+
+// SocketPath returns the API socket path inside dataDir.
+func SocketPath(dataDir string) string {
+	return filepath.Join(dataDir, "observer.sock")
+}
+
+// LockPath returns the single-instance lock file path inside dataDir.
+func LockPath(dataDir string) string {
+	return filepath.Join(dataDir, "observer.lock")
+}
+
+// Request is a single API call.
+type Request struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response answers the Request with the same ID.
+// Exactly one of Result or Error is set.
+type Response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Event is an update pushed to subscribers.
+type Event struct {
+	Kind     string    `json:"kind"`
+	Time     time.Time `json:"t"`
+	Source   string    `json:"source,omitempty"`
+	NewItems int       `json:"new_items,omitempty"`
+	ItemID   string    `json:"item_id,omitempty"`
+	Err      string    `json:"err,omitempty"`
+}
+
+// ListParams selects items by recency, newest first.
+type ListParams struct {
+	Limit       int  `json:"limit"`
+	IncludeRead bool `json:"include_read"`
+}
+
+// SinceParams selects items published after Since.
+type SinceParams struct {
+	Since time.Time `json:"since"`
+}
+
+// EmbeddingsParams requests stored vectors for the given item IDs.
+// Model, if set, restricts them to vectors from that model (see
+// store.Store.GetModelEmbeddings); nil returns every stored vector.
+type EmbeddingsParams struct {
+	IDs   []string `json:"ids"`
+	Model *string  `json:"model,omitempty"`
+}
+
+// ChunksParams requests the passage vectors of the given items that were
+// embedded by Model (see store.Store.GetModelChunks).
+type ChunksParams struct {
+	IDs   []string `json:"ids"`
+	Model string   `json:"model"`
+}
+
+// MarkReadParams marks one item as read.
+type MarkReadParams struct {
+	ID string `json:"id"`
+}
+
+// MarkSavedParams saves or unsaves one item.
+type MarkSavedParams struct {
+	ID    string `json:"id"`
+	Saved bool   `json:"saved"`
+}
+
+// SearchParams runs a search over the store.
+// Lexical (FTS5) by default; Semantic ranks by cosine similarity to the
+// embedded query and requires the daemon to have an embedder.
+type SearchParams struct {
+	Query    string `json:"query"`
+	Limit    int    `json:"limit"`
+	Semantic bool   `json:"semantic,omitempty"`
+}
+
+// MuteParams mutes one source by name.
+type MuteParams struct {
+	Source string `json:"source"`
+}
+
+// PrioritizeParams bumps embedding priority for items
+// (store.EmbedPriorityVisible or store.EmbedPrioritySearch).
+type PrioritizeParams struct {
+	Priority int      `json:"priority"`
+	IDs      []string `json:"ids"`
+}
+
+// RerankGetParams looks up cached rerank scores (see
+// store.Store.GetRerankScores).
+type RerankGetParams struct {
+	Reranker string            `json:"reranker"`
+	Query    string            `json:"query"`
+	Keys     []store.RerankKey `json:"keys"`
+}
+
+// RerankSaveParams caches rerank scores (see store.Store.SaveRerankScores).
+type RerankSaveParams struct {
+	Reranker string              `json:"reranker"`
+	Query    string              `json:"query"`
+	Scores   []store.RerankScore `json:"scores"`
+}
+
+// RuleDeleteParams deletes one rule by ID.
+type RuleDeleteParams struct {
+	ID int64 `json:"id"`
+}
+
+// RuleHitsParams records the items each rule hid (see
+// store.Store.RecordRuleHits).
+type RuleHitsParams struct {
+	Hits map[int64][]string `json:"hits"`
+}
+
+// ThreadParams asks for the story thread an item belongs to.
+type ThreadParams struct {
+	ItemID string `json:"item_id"`
+}
+
+// SignalParams records or forgets one interest signal (see
+// store.Store.RecordSignal).
+type SignalParams struct {
+	ItemID string `json:"item_id"`
+	Kind   string `json:"kind"`
+}
+
+// MutedResult lists muted source names.
+type MutedResult struct {
+	Sources []string `json:"sources"`
+}
+
+// ItemsResult carries a list of items.
+type ItemsResult struct {
+	Items []store.Item `json:"items"`
+}
+
+// RulesResult lists rules with today's hidden counts.
+type RulesResult struct {
+	Rules []store.Rule `json:"rules"`
+}
+
+// RuleDeleteResult reports whether the rule existed.
+type RuleDeleteResult struct {
+	Deleted bool `json:"deleted"`
+}
+
+// ThreadResult carries an item's story thread, nil if it has none, and
+// the thread's items oldest first.
+type ThreadResult struct {
+	Thread *store.Thread `json:"thread"`
+	Items  []store.Item  `json:"items"`
+}
+
+// ThreadsResult carries threads with their reports since a time, by
+// thread ID, oldest first.
+type ThreadsResult struct {
+	Threads []store.Thread         `json:"threads"`
+	Items   map[int64][]store.Item `json:"items"`
+}
+
+// SignalsResult carries interest signals with their items, newest first.
+type SignalsResult struct {
+	Signals []store.Signal `json:"signals"`
+}
+
+// EmbeddingsResult carries vectors keyed by item ID.
+// Vectors are little-endian float32 bytes (base64 in JSON), which is
+// roughly half the size of a JSON number array.
+type EmbeddingsResult struct {
+	Embeddings map[string][]byte `json:"embeddings"`
+}
+
+// RerankGetResult carries cached rerank scores keyed by item ID.
+type RerankGetResult struct {
+	Scores map[string]float32 `json:"scores"`
+}
+
+// ChunksResult carries passage vectors keyed by item ID, in passage order.
+type ChunksResult struct {
+	Chunks map[string][]WireChunk `json:"chunks"`
+}
+
+// WireChunk is a store.Chunk with its vector encoded as in EmbeddingsResult.
+type WireChunk struct {
+	Index     int    `json:"index"`
+	Text      string `json:"text"`
+	Embedding []byte `json:"embedding"`
+}
+
+// encodeVector converts a float32 slice to little-endian bytes.
+func encodeVector(v []float32) []byte {
+	data := make([]byte, len(v)*4)
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(f))
+	}
+	return data
+}
+
+// decodeVector converts little-endian bytes to a float32 slice.
+func decodeVector(data []byte) []float32 {
+	if len(data) == 0 {
+		return nil
+	}
+	v := make([]float32, len(data)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return v
+}
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrLocked is returned by AcquireLock when another process holds the lock.
+var ErrLocked = errors.New("data directory is locked by another observer process")
+
+// Lock is an exclusive, advisory single-instance lock on a data directory.
+// Only the holder may run the fetch and embed pipeline against the DB.
+// The OS releases the lock if the holder dies, so stale lock files are harmless.
+type Lock struct {
+	f *os.File
+}
+
+// AcquireLock takes the single-instance lock for dataDir without blocking.
+// Returns ErrLocked (wrapped with the holder's PID when known) if another
+// process already holds it.
+func AcquireLock(dataDir string) (*Lock, error) {
+	path := LockPath(dataDir)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	if err := lockFile(f); err != nil {
+		holder := readHolder(f)
+		f.Close()
+		if errors.Is(err, ErrLocked) && holder != "" {
+			return nil, fmt.Errorf("%w (pid %s)", ErrLocked, holder)
+		}
+		return nil, err
+	}
+
+	// Record our PID for diagnostics. Failure here is not fatal: the lock
+	// itself is what matters.
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{f: f}, nil
+}
+
+// Release drops the lock. Safe to call on a nil Lock.
+func (l *Lock) Release() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+	err := unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f = nil
+	return err
+}
+
+// readHolder returns the PID recorded in the lock file, or "".
+func readHolder(f *os.File) string {
+	buf := make([]byte, 32)
+	n, _ := f.ReadAt(buf, 0)
+	return strings.TrimSpace(string(buf[:n]))
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package daemon
+
+import "os"
+
+// lockFile is a no-op on platforms without flock. Single-instance
+// protection is not enforced there.
+func lockFile(f *os.File) error { return nil }
+
+// unlockFile is a no-op on platforms without flock.
+func unlockFile(f *os.File) error { return nil }
@@ -0,0 +1,533 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/ui"
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+// maxRequestSize caps a single request line. Embedding requests for a full
+// search pool carry ~10k IDs, so this is generous.
+const maxRequestSize = 4 << 20
+
+// subscriberBuffer is the per-subscriber event queue. Slow subscribers
+// drop events rather than stalling the pipeline.
+const subscriberBuffer = 64
+
+// searchPoolSize is how many recent items semantic search ranks.
+const searchPoolSize = 10000
+
+// Server answers API requests against a Store and fans pipeline updates
+// out to subscribers. Implements coord.Sender.
+// Thread-safety: all methods are safe for concurrent use.
+type Server struct {
+	store    *store.Store
+	embedder embed.Embedder // optional: nil disables semantic search
+	logger   *otel.Logger
+
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+
+	wg sync.WaitGroup
+}
+
+// NewServer creates a Server over the given store.
+// The embedder is optional (nil to disable semantic search).
+func NewServer(s *store.Store, e embed.Embedder, l *otel.Logger) *Server {
+	if l == nil {
+		l = otel.NewNullLogger()
+	}
+	return &Server{
+		store:    s,
+		embedder: e,
+		logger:   l,
+		subs:     make(map[chan Event]struct{}),
+	}
+}
+
+// Serve accepts connections on ln until ctx is cancelled.
+// Closes ln and waits for open connections to finish before returning.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	var conns sync.Map // net.Conn → struct{}, closed on shutdown
+	defer func() {
+		conns.Range(func(k, _ any) bool {
+			k.(net.Conn).Close()
+			return true
+		})
+		s.wg.Wait()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+		conns.Store(conn, struct{}{})
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conns.Delete(conn)
+			defer conn.Close()
+			s.handleConn(ctx, conn)
+		}()
+	}
+}
+
+// Send converts pipeline messages into subscriber events.
+// Unknown message types are ignored.
+func (s *Server) Send(msg tea.Msg) {
+	switch m := msg.(type) {
+	case ui.FetchComplete:
+		ev := Event{Kind: EventFetchComplete, Source: m.Source, NewItems: m.NewItems}
+		if m.Err != nil {
+			ev.Err = m.Err.Error()
+		}
+		s.Publish(ev)
+	}
+}
+
+// Publish delivers ev to every subscriber. Non-blocking: a subscriber
+// whose queue is full misses the event.
+func (s *Server) Publish(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func (s *Server) subscribe() chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	s.mu.Lock()
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan Event) {
+	s.mu.Lock()
+	delete(s.subs, ch)
+	s.mu.Unlock()
+}
+
+// handleConn serves requests on one connection until EOF or subscribe.
+func (s *Server) handleConn(ctx context.Context, conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxRequestSize)
+	enc := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			_ = enc.Encode(Response{Error: fmt.Sprintf("bad request: %v", err)})
+			return
+		}
+
+		if req.Method == MethodSubscribe {
+			if err := enc.Encode(Response{ID: req.ID}); err != nil {
+				return
+			}
+			s.stream(ctx, conn, enc)
+			return
+		}
+
+		start := time.Now()
+		result, err := s.dispatch(ctx, req)
+		resp := Response{ID: req.ID}
+		if err != nil {
+			resp.Error = err.Error()
+			s.logger.Emit(otel.Event{Kind: otel.KindError, Level: otel.LevelWarn, Comp: "daemon", Msg: req.Method, Err: err.Error()})
+		} else if result != nil {
+			data, mErr := json.Marshal(result)
+			if mErr != nil {
+				resp.Error = fmt.Sprintf("marshal result: %v", mErr)
+			} else {
+				resp.Result = data
+			}
+		}
+		s.logger.Emit(otel.Event{Kind: otel.KindDaemonRequest, Level: otel.LevelDebug, Comp: "daemon", Msg: req.Method, Dur: time.Since(start)})
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// stream writes events to a subscriber until ctx ends or the client goes away.
+func (s *Server) stream(ctx context.Context, conn net.Conn, enc *json.Encoder) {
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	// Detect client disconnect: subscribers never send after subscribing,
+	// so any read completion (EOF or error) means the connection is done.
+	gone := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				close(gone)
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-gone:
+			return
+		case ev := <-ch:
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// dispatch runs a single non-streaming request.
+func (s *Server) dispatch(ctx context.Context, req Request) (any, error) {
+	switch req.Method {
+	case MethodListItems:
+		var p ListParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.Limit <= 0 {
+			p.Limit = 500
+		}
+		items, err := s.store.GetItems(p.Limit, p.IncludeRead)
+		if err != nil {
+			return nil, err
+		}
+		return ItemsResult{Items: items}, nil
+
+	case MethodItemsSince:
+		var p SinceParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		items, err := s.store.GetItemsSince(p.Since)
+		if err != nil {
+			return nil, err
+		}
+		return ItemsResult{Items: items}, nil
+
+	case MethodEmbeddings:
+		var p EmbeddingsParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		var embs map[string][]float32
+		var err error
+		if p.Model != nil {
+			embs, err = s.store.GetModelEmbeddings(p.IDs, *p.Model)
+		} else {
+			embs, err = s.store.GetItemsWithEmbeddings(p.IDs)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string][]byte, len(embs))
+		for id, v := range embs {
+			out[id] = encodeVector(v)
+		}
+		return EmbeddingsResult{Embeddings: out}, nil
+
+	case MethodChunks:
+		var p ChunksParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		chunks, err := s.store.GetModelChunks(p.IDs, p.Model)
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string][]WireChunk, len(chunks))
+		for id, cs := range chunks {
+			for _, c := range cs {
+				out[id] = append(out[id], WireChunk{Index: c.Index, Text: c.Text, Embedding: encodeVector(c.Embedding)})
+			}
+		}
+		return ChunksResult{Chunks: out}, nil
+
+	case MethodMarkRead:
+		var p MarkReadParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.ID == "" {
+			return nil, errors.New("mark_read: id is required")
+		}
+		if err := s.store.MarkRead(p.ID); err != nil {
+			return nil, err
+		}
+		s.Publish(Event{Kind: EventItemRead, ItemID: p.ID})
+		return nil, nil
+
+	case MethodMuted:
+		names, err := s.store.ListMutedSources()
+		if err != nil {
+			return nil, err
+		}
+		return MutedResult{Sources: names}, nil
+
+	case MethodMute:
+		var p MuteParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.Source == "" {
+			return nil, errors.New("mute: source is required")
+		}
+		if err := s.store.MuteSource(p.Source); err != nil {
+			return nil, err
+		}
+		s.Publish(Event{Kind: EventSourceMuted, Source: p.Source})
+		return nil, nil
+
+	case MethodPrioritize:
+		var p PrioritizeParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.store.SetEmbedPriority(p.Priority, p.IDs)
+
+	case MethodUsage:
+		var r usage.Record
+		if err := decodeParams(req.Params, &r); err != nil {
+			return nil, err
+		}
+		return nil, s.store.RecordUsage(r)
+
+	case MethodRerankGet:
+		var p RerankGetParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		scores, err := s.store.GetRerankScores(p.Reranker, p.Query, p.Keys)
+		if err != nil {
+			return nil, err
+		}
+		return RerankGetResult{Scores: scores}, nil
+
+	case MethodRerankSave:
+		var p RerankSaveParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.store.SaveRerankScores(p.Reranker, p.Query, p.Scores)
+
+	case MethodRules:
+		rules, err := s.store.ListRules()
+		if err != nil {
+			return nil, err
+		}
+		return RulesResult{Rules: rules}, nil
+
+	case MethodRuleAdd:
+		var r store.Rule
+		if err := decodeParams(req.Params, &r); err != nil {
+			return nil, err
+		}
+		added, err := s.store.AddRule(r)
+		if err != nil {
+			return nil, err
+		}
+		s.Publish(Event{Kind: EventRulesChanged})
+		return added, nil
+
+	case MethodRuleDelete:
+		var p RuleDeleteParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		deleted, err := s.store.DeleteRule(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if deleted {
+			s.Publish(Event{Kind: EventRulesChanged})
+		}
+		return RuleDeleteResult{Deleted: deleted}, nil
+
+	case MethodRuleHits:
+		var p RuleHitsParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.store.RecordRuleHits(p.Hits)
+
+	case MethodThread:
+		var p ThreadParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		thread, items, err := s.store.ItemThread(p.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		return ThreadResult{Thread: thread, Items: items}, nil
+
+	case MethodThreadsSince:
+		var p SinceParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		threads, items, err := s.store.ThreadItemsSince(p.Since)
+		if err != nil {
+			return nil, err
+		}
+		return ThreadsResult{Threads: threads, Items: items}, nil
+
+	case MethodMarkSaved:
+		var p MarkSavedParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.ID == "" {
+			return nil, errors.New("mark_saved: id is required")
+		}
+		return nil, s.store.MarkSaved(p.ID, p.Saved)
+
+	case MethodSignals:
+		var p SinceParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		signals, err := s.store.Signals(p.Since)
+		if err != nil {
+			return nil, err
+		}
+		return SignalsResult{Signals: signals}, nil
+
+	case MethodSignalRecord, MethodSignalForget:
+		var p SignalParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.ItemID == "" || p.Kind == "" {
+			return nil, fmt.Errorf("%s: item_id and kind are required", req.Method)
+		}
+		if req.Method == MethodSignalForget {
+			return nil, s.store.ForgetSignal(p.ItemID, p.Kind)
+		}
+		if err := s.store.RecordSignal(p.ItemID, p.Kind); err != nil {
+			return nil, err
+		}
+		if p.Kind == store.SignalExcluded {
+			s.Publish(Event{Kind: EventItemRead, ItemID: p.ItemID})
+		}
+		return nil, nil
+
+	case MethodInterestReset:
+		return nil, s.store.ResetInterest()
+
+	case MethodSearch:
+		var p SearchParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.search(ctx, p)
+
+	default:
+		return nil, fmt.Errorf("unknown method %q", req.Method)
+	}
+}
+
+// search runs a lexical or semantic search.
+func (s *Server) search(ctx context.Context, p SearchParams) (ItemsResult, error) {
+	if p.Query == "" {
+		return ItemsResult{}, errors.New("search: query is required")
+	}
+	if p.Limit <= 0 {
+		p.Limit = 50
+	}
+
+	if !p.Semantic {
+		items, err := s.store.SearchFTS(p.Query, p.Limit)
+		if err != nil {
+			return ItemsResult{}, err
+		}
+		return ItemsResult{Items: items}, nil
+	}
+
+	if s.embedder == nil || !s.embedder.Available() {
+		return ItemsResult{}, errors.New("search: semantic search unavailable (no embedder)")
+	}
+	queryEmb, model, err := embed.EmbedQueryWithModel(ctx, s.embedder, p.Query)
+	if err != nil {
+		return ItemsResult{}, fmt.Errorf("search: embed query: %w", err)
+	}
+	pool, err := s.store.GetItems(searchPoolSize, true)
+	if err != nil {
+		return ItemsResult{}, err
+	}
+	ids := make([]string, len(pool))
+	for i, item := range pool {
+		ids[i] = item.ID
+	}
+	embs, err := s.store.GetModelEmbeddings(ids, model)
+	if err != nil {
+		return ItemsResult{}, err
+	}
+	chunks, err := s.store.GetModelChunks(ids, model)
+	if err != nil {
+		return ItemsResult{}, err
+	}
+
+	// Only items with vectors (from the query's model) are meaningful in a semantic result set.
+	ranked := filter.RerankByQuery(pool, embs, chunks, queryEmb)
+	result := make([]store.Item, 0, p.Limit)
+	for _, item := range ranked {
+		if len(result) >= p.Limit {
+			break
+		}
+		if _, ok := embs[item.ID]; !ok {
+			break // RerankByQuery places items without vectors last
+		}
+		result = append(result, item)
+	}
+	return ItemsResult{Items: result}, nil
+}
+
+// decodeParams unmarshals raw params into v. Empty params leave v zeroed.
+func decodeParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("bad params: %w", err)
+	}
+	return nil
+}
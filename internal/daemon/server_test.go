@@ -0,0 +1,542 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/ui"
+)
+
+// fixedEmbedder returns a vector derived from the first byte of the text.
+type fixedEmbedder struct{}
+
+func (fixedEmbedder) Available() bool { return true }
+
+func (fixedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if len(text) > 0 && text[0] == 'G' {
+		return []float32{1, 0}, nil
+	}
+	return []float32{0, 1}, nil
+}
+
+// startServer serves a fresh in-memory store on a temp socket.
+func startServer(t *testing.T) (*store.Store, *Server, string) {
+	t.Helper()
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	now := time.Now()
+	items := []store.Item{
+		{ID: "a", SourceType: "rss", SourceName: "Wire", Title: "Go release ships generics", URL: "https://example.com/a", Published: now.Add(-10 * time.Minute), Fetched: now},
+		{ID: "b", SourceType: "rss", SourceName: "Wire", Title: "Markets rally on rate cut", URL: "https://example.com/b", Published: now.Add(-2 * time.Hour), Fetched: now},
+	}
+	if _, err := s.SaveItems(items); err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+	if err := s.SaveEmbedding("a", []float32{1, 0}); err != nil {
+		t.Fatalf("SaveEmbedding: %v", err)
+	}
+	if err := s.SaveEmbedding("b", []float32{0, 1}); err != nil {
+		t.Fatalf("SaveEmbedding: %v", err)
+	}
+
+	srv := NewServer(s, fixedEmbedder{}, nil)
+	sock := filepath.Join(t.TempDir(), "observer.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		srv.Serve(ctx, ln)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return s, srv, sock
+}
+
+func dial(t *testing.T, sock string) *Client {
+	t.Helper()
+	c, err := Dial(sock)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestServer_ListAndSince(t *testing.T) {
+	_, _, sock := startServer(t)
+	c := dial(t, sock)
+
+	items, err := c.GetItems(10, false)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].ID != "a" {
+		t.Errorf("expected newest first, got %s", items[0].ID)
+	}
+
+	recent, err := c.GetItemsSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetItemsSince: %v", err)
+	}
+	if len(recent) != 1 || recent[0].ID != "a" {
+		t.Errorf("expected only item a within the last hour, got %v", recent)
+	}
+}
+
+func TestServer_Embeddings(t *testing.T) {
+	_, _, sock := startServer(t)
+	c := dial(t, sock)
+
+	embs, err := c.GetItemsWithEmbeddings([]string{"a", "b", "missing"})
+	if err != nil {
+		t.Fatalf("GetItemsWithEmbeddings: %v", err)
+	}
+	if len(embs) != 2 {
+		t.Fatalf("expected 2 embeddings, got %d", len(embs))
+	}
+	if got := embs["a"]; len(got) != 2 || got[0] != 1 || got[1] != 0 {
+		t.Errorf("embedding for a did not round-trip: %v", got)
+	}
+}
+
+func TestServer_ModelEmbeddings(t *testing.T) {
+	s, _, sock := startServer(t)
+	c := dial(t, sock)
+	if err := s.SaveModelEmbedding("b", "other/model", []float32{0, 1}); err != nil {
+		t.Fatalf("SaveModelEmbedding: %v", err)
+	}
+
+	embs, err := c.GetModelEmbeddings([]string{"a", "b"}, "other/model")
+	if err != nil {
+		t.Fatalf("GetModelEmbeddings: %v", err)
+	}
+	if len(embs) != 1 || embs["b"] == nil {
+		t.Errorf("expected only b's vector from other/model, got %v", embs)
+	}
+}
+
+func TestServer_Chunks(t *testing.T) {
+	s, _, sock := startServer(t)
+	c := dial(t, sock)
+	chunks := []store.Chunk{{Index: 0, Text: "first", Embedding: []float32{1, 0}}, {Index: 1, Text: "second", Embedding: []float32{0, 1}}}
+	if err := s.SaveChunks("a", "", chunks); err != nil {
+		t.Fatalf("SaveChunks: %v", err)
+	}
+
+	got, err := c.GetModelChunks([]string{"a", "b"}, "")
+	if err != nil {
+		t.Fatalf("GetModelChunks: %v", err)
+	}
+	if len(got) != 1 || len(got["a"]) != 2 || got["a"][1].Text != "second" || got["a"][1].Embedding[1] != 1 {
+		t.Errorf("chunks did not round-trip: %+v", got)
+	}
+}
+
+func TestServer_RerankCache(t *testing.T) {
+	_, _, sock := startServer(t)
+	c := dial(t, sock)
+	item := store.Item{ID: "a", Title: "Chiefs win"}
+	key := store.NewRerankKey(item)
+
+	if err := c.SaveRerankScores("jina/v3", "Chiefs", []store.RerankScore{{RerankKey: key, Score: 0.75}}); err != nil {
+		t.Fatalf("SaveRerankScores: %v", err)
+	}
+	got, err := c.GetRerankScores("jina/v3", "chiefs", []store.RerankKey{key, {ItemID: "b"}})
+	if err != nil {
+		t.Fatalf("GetRerankScores: %v", err)
+	}
+	if len(got) != 1 || got["a"] != 0.75 {
+		t.Errorf("scores did not round-trip: %v", got)
+	}
+}
+
+func TestServer_MarkReadNotifiesSubscribers(t *testing.T) {
+	s, srv, sock := startServer(t)
+	c := dial(t, sock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan Event, 4)
+	subDone := make(chan error, 1)
+	go func() {
+		subDone <- c.Subscribe(ctx, func(ev Event) { events <- ev })
+	}()
+	waitForSubscribers(t, srv)
+
+	if err := c.MarkRead("a"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Kind != EventItemRead || ev.ItemID != "a" {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscriber did not receive item.read event")
+	}
+
+	unread, err := s.GetItems(10, false)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	if len(unread) != 1 || unread[0].ID != "b" {
+		t.Errorf("expected only b unread, got %v", unread)
+	}
+
+	cancel()
+	if err := <-subDone; err != nil {
+		t.Errorf("Subscribe should return nil on cancel, got %v", err)
+	}
+}
+
+func TestServer_SendForwardsFetchComplete(t *testing.T) {
+	_, srv, sock := startServer(t)
+	c := dial(t, sock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan Event, 4)
+	go c.Subscribe(ctx, func(ev Event) { events <- ev })
+	waitForSubscribers(t, srv)
+
+	srv.Send(ui.FetchComplete{Source: "all", NewItems: 3, Err: errors.New("partial")})
+
+	select {
+	case ev := <-events:
+		if ev.Kind != EventFetchComplete || ev.NewItems != 3 || ev.Err != "partial" {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscriber did not receive fetch.complete event")
+	}
+}
+
+func TestServer_Search(t *testing.T) {
+	_, _, sock := startServer(t)
+	c := dial(t, sock)
+
+	lexical, err := c.SearchFTS("markets", 10)
+	if err != nil {
+		t.Fatalf("SearchFTS: %v", err)
+	}
+	if len(lexical) != 1 || lexical[0].ID != "b" {
+		t.Errorf("expected FTS hit b, got %v", lexical)
+	}
+
+	semantic, err := c.SearchSemantic("Go news", 1)
+	if err != nil {
+		t.Fatalf("SearchSemantic: %v", err)
+	}
+	if len(semantic) != 1 || semantic[0].ID != "a" {
+		t.Errorf("expected semantic hit a, got %v", semantic)
+	}
+}
+
+func TestServer_MuteSource(t *testing.T) {
+	s, srv, sock := startServer(t)
+	c := dial(t, sock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan Event, 4)
+	go c.Subscribe(ctx, func(ev Event) { events <- ev })
+	waitForSubscribers(t, srv)
+
+	if err := c.MuteSource("Wire"); err != nil {
+		t.Fatalf("MuteSource: %v", err)
+	}
+	muted, err := c.ListMutedSources()
+	if err != nil {
+		t.Fatalf("ListMutedSources: %v", err)
+	}
+	if len(muted) != 1 || muted[0] != "Wire" {
+		t.Errorf("unexpected muted list %v", muted)
+	}
+	if stored, _ := s.ListMutedSources(); len(stored) != 1 {
+		t.Errorf("mute not persisted: %v", stored)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Kind != EventSourceMuted || ev.Source != "Wire" {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscriber did not receive source.muted event")
+	}
+}
+
+func TestServer_Rules(t *testing.T) {
+	s, srv, sock := startServer(t)
+	c := dial(t, sock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan Event, 4)
+	go c.Subscribe(ctx, func(ev Event) { events <- ev })
+	waitForSubscribers(t, srv)
+
+	rule, err := c.AddRule(store.Rule{Kind: store.RuleSemantic, Action: store.RuleMute, Pattern: "Go release", ExampleID: "a", Embedding: []float32{1, 0}, Model: "m", Threshold: 0.8})
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	if _, err := c.AddRule(store.Rule{Kind: store.RuleRegex, Action: store.RuleMute, Pattern: "("}); err == nil {
+		t.Error("expected an error for an invalid rule")
+	}
+	if err := c.RecordRuleHits(map[int64][]string{rule.ID: {"a"}}); err != nil {
+		t.Fatalf("RecordRuleHits: %v", err)
+	}
+	rules, err := c.ListRules()
+	if err != nil {
+		t.Fatalf("ListRules: %v", err)
+	}
+	if len(rules) != 1 || rules[0].ID != rule.ID || rules[0].HiddenToday != 1 || len(rules[0].Embedding) != 2 {
+		t.Errorf("unexpected rules %+v", rules)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Kind != EventRulesChanged {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscriber did not receive rules.changed event")
+	}
+
+	if ok, err := c.DeleteRule(rule.ID); err != nil || !ok {
+		t.Fatalf("DeleteRule: ok=%v err=%v", ok, err)
+	}
+	if stored, _ := s.ListRules(); len(stored) != 0 {
+		t.Errorf("delete not persisted: %+v", stored)
+	}
+}
+
+func TestServer_Thread(t *testing.T) {
+	s, _, sock := startServer(t)
+	c := dial(t, sock)
+
+	if th, items, err := c.ItemThread("a"); err != nil || th != nil || len(items) != 0 {
+		t.Fatalf("unthreaded item: %+v, %v, %v", th, items, err)
+	}
+	created, _, err := s.LinkThreadItem(store.Thread{Title: "Go release ships generics", Model: "m", Centroid: []float32{1, 0}, Items: 1, First: time.Now(), Latest: time.Now()}, "a", 1)
+	if err != nil {
+		t.Fatalf("LinkThreadItem: %v", err)
+	}
+	th, items, err := c.ItemThread("a")
+	if err != nil || th == nil {
+		t.Fatalf("ItemThread: %+v, %v", th, err)
+	}
+	if th.ID != created.ID || th.Title != "Go release ships generics" || len(items) != 1 || items[0].ID != "a" {
+		t.Errorf("unexpected thread %+v, items %v", th, items)
+	}
+
+	threads, byThread, err := c.ThreadItemsSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ThreadItemsSince: %v", err)
+	}
+	if len(threads) != 1 || len(byThread[created.ID]) != 1 || byThread[created.ID][0].ID != "a" {
+		t.Errorf("unexpected threads %+v, items %v", threads, byThread)
+	}
+}
+
+func TestServer_Interest(t *testing.T) {
+	_, _, sock := startServer(t)
+	c := dial(t, sock)
+
+	if err := c.MarkSaved("a", true); err != nil {
+		t.Fatalf("MarkSaved: %v", err)
+	}
+	if err := c.RecordSignal("b", store.SignalExcluded); err != nil {
+		t.Fatalf("RecordSignal: %v", err)
+	}
+	signals, err := c.Signals(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Signals: %v", err)
+	}
+	if len(signals) != 2 || signals[0].Item.ID != "b" || signals[0].Kind != store.SignalExcluded || signals[1].Item.Title != "Go release ships generics" {
+		t.Errorf("unexpected signals %+v", signals)
+	}
+
+	if err := c.ForgetSignal("b", store.SignalExcluded); err != nil {
+		t.Fatalf("ForgetSignal: %v", err)
+	}
+	if signals, _ := c.Signals(time.Time{}); len(signals) != 1 {
+		t.Errorf("after forget: %+v", signals)
+	}
+	if err := c.ResetInterest(); err != nil {
+		t.Fatalf("ResetInterest: %v", err)
+	}
+	if signals, _ := c.Signals(time.Time{}); len(signals) != 0 {
+		t.Errorf("after reset: %+v", signals)
+	}
+	if err := c.RecordSignal("a", ""); err == nil {
+		t.Error("expected error for a signal without a kind")
+	}
+}
+
+func TestServer_UnknownMethod(t *testing.T) {
+	_, _, sock := startServer(t)
+	c := dial(t, sock)
+
+	if err := c.call("nope", nil, nil); err == nil {
+		t.Error("expected error for unknown method")
+	}
+	// Connection stays usable after an error response.
+	if _, err := c.GetItems(1, true); err != nil {
+		t.Errorf("GetItems after error: %v", err)
+	}
+}
+
+func TestAcquireLock_SingleInstance(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("first AcquireLock: %v", err)
+	}
+
+	if _, err := AcquireLock(dir); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked while held, got %v", err)
+	}
+
+	if err := first.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	second, err := AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireLock after release: %v", err)
+	}
+	second.Release()
+}
+
+// waitForSubscribers blocks until the server has registered a subscriber.
+// Subscribe dials asynchronously, so events published before registration
+// would otherwise be missed.
+func waitForSubscribers(t *testing.T, srv *Server) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		srv.mu.Lock()
+		n := len(srv.subs)
+		srv.mu.Unlock()
+		if n > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("subscriber never registered")
+}
+
+func TestServer_Prioritize(t *testing.T) {
+	s, _, sock := startServer(t)
+	c := dial(t, sock)
+
+	now := time.Now()
+	s.SaveItems([]store.Item{
+		{ID: "new", SourceType: "rss", SourceName: "S", Title: "New", URL: "http://x/new", Published: now, Fetched: now},
+		{ID: "old", SourceType: "rss", SourceName: "S", Title: "Old", URL: "http://x/old", Published: now.Add(-30 * 24 * time.Hour), Fetched: now},
+	})
+
+	if err := c.SetEmbedPriority(store.EmbedPriorityVisible, []string{"old"}); err != nil {
+		t.Fatalf("SetEmbedPriority: %v", err)
+	}
+	items, err := s.GetItemsNeedingEmbedding("", 1)
+	if err != nil {
+		t.Fatalf("GetItemsNeedingEmbedding: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "old" {
+		t.Errorf("expected bumped item first, got %v", items)
+	}
+}
+
+// fakeDaemon answers every request on sock with an empty muted-sources
+// list and the request's ID, holding the first reply back for delay.
+// Closing the returned listener also drops its connections.
+func fakeDaemon(t *testing.T, sock string, delay time.Duration) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	var first sync.Once
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+			go func() {
+				dec, enc := json.NewDecoder(conn), json.NewEncoder(conn)
+				for {
+					var req Request
+					if dec.Decode(&req) != nil {
+						return
+					}
+					first.Do(func() { time.Sleep(delay) })
+					enc.Encode(Response{ID: req.ID, Result: json.RawMessage(`{"sources":[]}`)})
+				}
+			}()
+		}
+	}()
+	return ln
+}
+
+func TestClientRecoversFromTimeoutAndRestart(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "observer.sock")
+	ln := fakeDaemon(t, sock, 200*time.Millisecond)
+	c := dial(t, sock)
+	c.timeout = 50 * time.Millisecond
+
+	if _, err := c.ListMutedSources(); err == nil {
+		t.Fatal("expected the slow call to time out")
+	}
+	// The late reply to the first call must not be read as this one's.
+	time.Sleep(200 * time.Millisecond)
+	if _, err := c.ListMutedSources(); err != nil {
+		t.Fatalf("call after a timeout: %v", err)
+	}
+
+	// The daemon restarts: at most one call fails before the client redials.
+	ln.Close()
+	ln = fakeDaemon(t, sock, 0)
+	defer ln.Close()
+	if _, err := c.ListMutedSources(); err != nil {
+		if _, err := c.ListMutedSources(); err != nil {
+			t.Fatalf("call after a restart: %v", err)
+		}
+	}
+
+	c.Close()
+	if _, err := c.ListMutedSources(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("call after Close = %v, want net.ErrClosed", err)
+	}
+}
@@ -0,0 +1,227 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// FallbackCooldown is how long a failed backend is skipped before the
+// chain tries it again.
+const FallbackCooldown = 30 * time.Second
+
+// ModelEmbedder is implemented by embedders whose model can change from
+// call to call (FallbackEmbedder). Each method also returns the model
+// that produced the vectors, so callers can store them with it and never
+// compare vectors across models.
+type ModelEmbedder interface {
+	EmbedWithModel(ctx context.Context, text string) ([]float32, string, error)
+	EmbedQueryWithModel(ctx context.Context, text string) ([]float32, string, error)
+	EmbedBatchWithModel(ctx context.Context, texts []string) ([][]float32, string, error)
+}
+
+// EmbedWithModel embeds a document and returns the model that produced it.
+func EmbedWithModel(ctx context.Context, e Embedder, text string) ([]float32, string, error) {
+	if me, ok := e.(ModelEmbedder); ok {
+		return me.EmbedWithModel(ctx, text)
+	}
+	emb, err := e.Embed(ctx, text)
+	return emb, ModelName(e), err
+}
+
+// EmbedQueryWithModel embeds a query and returns the model that produced it.
+func EmbedQueryWithModel(ctx context.Context, e Embedder, text string) ([]float32, string, error) {
+	if me, ok := e.(ModelEmbedder); ok {
+		return me.EmbedQueryWithModel(ctx, text)
+	}
+	emb, err := EmbedQuery(ctx, e, text)
+	return emb, ModelName(e), err
+}
+
+// EmbedBatchWithModel embeds documents and returns the model that
+// produced them (all from the same one).
+func EmbedBatchWithModel(ctx context.Context, e BatchEmbedder, texts []string) ([][]float32, string, error) {
+	if me, ok := e.(ModelEmbedder); ok {
+		return me.EmbedBatchWithModel(ctx, texts)
+	}
+	embs, err := e.EmbedBatch(ctx, texts)
+	return embs, ModelName(e), err
+}
+
+// FallbackEmbedder tries an ordered list of embedders, most preferred
+// first, and switches to the next when one is down, out of quota or
+// unavailable (e.g. its circuit breaker is open). A failed backend is
+// skipped for FallbackCooldown and then preferred again, so the chain
+// returns to the primary once it recovers.
+//
+// Every call is served entirely by one backend, and the *WithModel
+// methods report which, so vectors from different models never meet.
+// ModelName reports the backend the next call will try first.
+type FallbackEmbedder struct {
+	backends []Embedder
+	failover *resilience.Failover
+}
+
+// NewFallbackEmbedder creates a chain over backends, most preferred first.
+// onSwitch (optional) is called with the old and new model names when a
+// different backend starts serving calls, and the failure that caused it
+// (nil when returning to a preferred backend).
+func NewFallbackEmbedder(onSwitch func(from, to string, cause error), backends ...Embedder) *FallbackEmbedder {
+	f := &FallbackEmbedder{backends: backends}
+	var hook func(from, to int, cause error)
+	if onSwitch != nil {
+		hook = func(from, to int, cause error) {
+			onSwitch(ModelName(backends[from]), ModelName(backends[to]), cause)
+		}
+	}
+	f.failover = resilience.NewFailover(len(backends), FallbackCooldown, hook)
+	return f
+}
+
+// Backends returns the chain, most preferred first.
+func (f *FallbackEmbedder) Backends() []Embedder { return f.backends }
+
+// Primary returns the model name of the most preferred backend.
+func (f *FallbackEmbedder) Primary() string { return ModelName(f.backends[0]) }
+
+// ModelName returns the model the next call will try first.
+func (f *FallbackEmbedder) ModelName() string {
+	if order := f.order(); len(order) > 0 {
+		return ModelName(f.backends[order[0]])
+	}
+	return ModelName(f.backends[f.failover.Active()])
+}
+
+// Active returns the model name of the backend that served the most
+// recent call.
+func (f *FallbackEmbedder) Active() string {
+	return ModelName(f.backends[f.failover.Active()])
+}
+
+// BackendStatus describes one backend in a chain.
+type BackendStatus struct {
+	Name    string
+	Healthy bool
+	Active  bool
+	LastErr error
+}
+
+// Status reports every backend's health, most preferred first.
+func (f *FallbackEmbedder) Status() []BackendStatus {
+	active := f.failover.Active()
+	out := make([]BackendStatus, len(f.backends))
+	for i, b := range f.backends {
+		healthy, lastErr := f.failover.Healthy(i)
+		out[i] = BackendStatus{Name: ModelName(b), Healthy: healthy && b.Available(), Active: i == active, LastErr: lastErr}
+	}
+	return out
+}
+
+// Available returns true if any backend is available.
+func (f *FallbackEmbedder) Available() bool {
+	for _, b := range f.backends {
+		if b.Available() {
+			return true
+		}
+	}
+	return false
+}
+
+// Embed generates a document embedding with the first backend that succeeds.
+func (f *FallbackEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	emb, _, err := f.EmbedWithModel(ctx, text)
+	return emb, err
+}
+
+// EmbedQuery generates a query embedding with the first backend that succeeds.
+func (f *FallbackEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	emb, _, err := f.EmbedQueryWithModel(ctx, text)
+	return emb, err
+}
+
+// EmbedBatch generates document embeddings with the first backend that
+// succeeds. Backends without batch support embed the texts one by one.
+func (f *FallbackEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embs, _, err := f.EmbedBatchWithModel(ctx, texts)
+	return embs, err
+}
+
+// EmbedWithModel implements ModelEmbedder.
+func (f *FallbackEmbedder) EmbedWithModel(ctx context.Context, text string) ([]float32, string, error) {
+	var out []float32
+	model, err := f.try(ctx, func(e Embedder) error {
+		var err error
+		out, err = e.Embed(ctx, text)
+		return err
+	})
+	return out, model, err
+}
+
+// EmbedQueryWithModel implements ModelEmbedder.
+func (f *FallbackEmbedder) EmbedQueryWithModel(ctx context.Context, text string) ([]float32, string, error) {
+	var out []float32
+	model, err := f.try(ctx, func(e Embedder) error {
+		var err error
+		out, err = EmbedQuery(ctx, e, text)
+		return err
+	})
+	return out, model, err
+}
+
+// EmbedBatchWithModel implements ModelEmbedder.
+func (f *FallbackEmbedder) EmbedBatchWithModel(ctx context.Context, texts []string) ([][]float32, string, error) {
+	var out [][]float32
+	model, err := f.try(ctx, func(e Embedder) error {
+		var err error
+		if be, ok := e.(BatchEmbedder); ok {
+			out, err = be.EmbedBatch(ctx, texts)
+			return err
+		}
+		out = make([][]float32, len(texts))
+		for i, text := range texts {
+			if out[i], err = e.Embed(ctx, text); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return out, model, err
+}
+
+// order returns the indices of the backends to try, in order.
+func (f *FallbackEmbedder) order() []int {
+	return f.failover.Order(func(i int) bool { return f.backends[i].Available() })
+}
+
+// try runs call against each backend in order until one succeeds, and
+// returns that backend's model name. Errors that say nothing about the
+// backend (a rejected input) are returned without trying the next.
+func (f *FallbackEmbedder) try(ctx context.Context, call func(Embedder) error) (string, error) {
+	order := f.order()
+	if len(order) == 0 {
+		return "", errors.New("embed: no backend available")
+	}
+	var lastErr error
+	for _, i := range order {
+		err := call(f.backends[i])
+		if err == nil {
+			f.failover.Success(i)
+			return ModelName(f.backends[i]), nil
+		}
+		if ctx.Err() != nil {
+			return "", err // the caller gave up; not the backend's fault
+		}
+		f.failover.Failure(i, err)
+		if !errors.Is(err, resilience.ErrBreakerOpen) && !resilience.BackendFault(err) {
+			return "", err // a bad input fails everywhere; don't fail over
+		}
+		lastErr = err
+	}
+	if len(order) == 1 {
+		return "", lastErr
+	}
+	return "", fmt.Errorf("embed: all %d backends failed, last: %w", len(order), lastErr)
+}
@@ -0,0 +1,91 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// namedEmbedder is a flakyEmbedder with a model name.
+type namedEmbedder struct {
+	flakyEmbedder
+	model string
+}
+
+func (n *namedEmbedder) ModelName() string { return n.model }
+
+func TestFallbackEmbedderSwitchesOnBackendFault(t *testing.T) {
+	primary := &namedEmbedder{flakyEmbedder{failures: 1, err: &resilience.StatusError{Code: http.StatusServiceUnavailable}}, "primary"}
+	backup := &namedEmbedder{model: "backup"}
+	var switched [2]string
+	f := NewFallbackEmbedder(func(from, to string, cause error) {
+		switched = [2]string{from, to}
+	}, primary, backup)
+
+	_, model, err := f.EmbedWithModel(context.Background(), "x")
+	if err != nil {
+		t.Fatalf("EmbedWithModel() error = %v", err)
+	}
+	if model != "backup" || f.Active() != "backup" {
+		t.Errorf("model = %q, Active() = %q; want backup", model, f.Active())
+	}
+	if switched != [2]string{"primary", "backup"} {
+		t.Errorf("onSwitch = %v", switched)
+	}
+	// The primary cools down, so the next call goes straight to the backup.
+	if f.ModelName() != "backup" {
+		t.Errorf("ModelName() = %q, want backup during cooldown", f.ModelName())
+	}
+	if _, _, err := f.EmbedWithModel(context.Background(), "y"); err != nil || primary.calls != 1 {
+		t.Errorf("expected the cooling primary to be skipped (calls=%d, err=%v)", primary.calls, err)
+	}
+
+	st := f.Status()
+	if len(st) != 2 || st[0].Healthy || st[0].LastErr == nil || !st[1].Active {
+		t.Errorf("unexpected status: %+v", st)
+	}
+}
+
+func TestFallbackEmbedderKeepsBackendOnBadInput(t *testing.T) {
+	primary := &namedEmbedder{flakyEmbedder{failures: 1, err: &resilience.StatusError{Code: http.StatusBadRequest}}, "primary"}
+	backup := &namedEmbedder{model: "backup"}
+	f := NewFallbackEmbedder(nil, primary, backup)
+
+	if _, _, err := f.EmbedWithModel(context.Background(), "x"); err == nil {
+		t.Fatal("expected the 400 to be returned")
+	}
+	if backup.calls != 0 {
+		t.Error("a rejected input should not fail over")
+	}
+	if f.ModelName() != "primary" {
+		t.Errorf("ModelName() = %q, want primary", f.ModelName())
+	}
+}
+
+func TestFallbackEmbedderBatchesOneModel(t *testing.T) {
+	primary := &namedEmbedder{flakyEmbedder{failures: 1, err: errors.New("connection refused")}, "primary"}
+	f := NewFallbackEmbedder(nil, primary, NewLocalEmbedder())
+
+	embs, model, err := EmbedBatchWithModel(context.Background(), f, []string{"a b", "c d"})
+	if err != nil {
+		t.Fatalf("EmbedBatchWithModel() error = %v", err)
+	}
+	if model != ModelName(NewLocalEmbedder()) || len(embs) != 2 {
+		t.Errorf("got %d vectors from %q, want 2 from the local embedder", len(embs), model)
+	}
+}
+
+func TestFallbackEmbedderAllFailed(t *testing.T) {
+	down := &resilience.StatusError{Code: http.StatusBadGateway}
+	f := NewFallbackEmbedder(nil,
+		&namedEmbedder{flakyEmbedder{failures: 5, err: down}, "a"},
+		&namedEmbedder{flakyEmbedder{failures: 5, err: down}, "b"})
+	_, _, err := f.EmbedQueryWithModel(context.Background(), "q")
+	var se *resilience.StatusError
+	if !errors.As(err, &se) {
+		t.Errorf("expected the last backend error to be wrapped, got %v", err)
+	}
+}
@@ -7,21 +7,27 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 
-	"golang.org/x/time/rate"
+	"github.com/abelbrown/observer/internal/resilience"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
 // JinaEmbedder generates embeddings via the Jina AI API.
+// It makes single attempts; wrap it with WithRetry and WithRateLimit
+// (JinaRateInterval) for background use.
 type JinaEmbedder struct {
 	apiKey   string
 	model    string
 	endpoint string
 	client   *http.Client
-	limiter  *rate.Limiter
+	meter    *usage.Meter // optional usage accounting
 }
 
+// JinaRateInterval spaces background requests to stay under Jina's
+// free-tier limit (~80 RPM).
+const JinaRateInterval = 750 * time.Millisecond
+
 // jinaEmbedRequest represents the request body for the Jina embeddings API.
 type jinaEmbedRequest struct {
 	Model      string   `json:"model"`
@@ -33,7 +39,13 @@ type jinaEmbedRequest struct {
 
 // jinaEmbedResponse represents the response from the Jina embeddings API.
 type jinaEmbedResponse struct {
-	Data []jinaEmbedding `json:"data"`
+	Data  []jinaEmbedding `json:"data"`
+	Usage jinaUsage       `json:"usage"`
+}
+
+// jinaUsage is the token accounting Jina returns with every response.
+type jinaUsage struct {
+	TotalTokens int `json:"total_tokens"`
 }
 
 // jinaEmbedding represents a single embedding in the Jina response.
@@ -52,17 +64,17 @@ func NewJinaEmbedder(apiKey, model string) *JinaEmbedder {
 		model:    model,
 		endpoint: "https://api.jina.ai/v1/embeddings",
 		client:   &http.Client{Timeout: 60 * time.Second},
-		limiter:  rate.NewLimiter(rate.Every(750*time.Millisecond), 1), // ~80 RPM
 	}
 }
 
-// SetRateLimit configures the rate limiter. Pass 0 to disable rate limiting.
-func (e *JinaEmbedder) SetRateLimit(interval time.Duration) {
-	if interval <= 0 {
-		e.limiter = rate.NewLimiter(rate.Inf, 1)
-	} else {
-		e.limiter = rate.NewLimiter(rate.Every(interval), 1)
-	}
+// SetUsageRecorder records every API call (tokens, characters, latency) to rec.
+func (e *JinaEmbedder) SetUsageRecorder(rec usage.Recorder) {
+	e.meter = usage.NewMeter(rec, "jina", e.model, usage.KindEmbed)
+}
+
+// ModelName identifies the model behind this embedder's vectors.
+func (e *JinaEmbedder) ModelName() string {
+	return "jina/" + e.model
 }
 
 // Available returns true if the Jina API key is configured.
@@ -155,93 +167,56 @@ func (e *JinaEmbedder) embed(ctx context.Context, input []string, task string) (
 		return nil, fmt.Errorf("embed: failed to marshal request: %w", err)
 	}
 
-	return e.doWithRetry(ctx, jsonBody)
+	start := time.Now()
+	resp, err := e.do(ctx, jsonBody)
+	tokens := 0
+	if resp != nil {
+		tokens = resp.Usage.TotalTokens
+	}
+	e.meter.Observe(ctx, start, tokens, totalChars(input), err)
+	return resp, err
 }
 
-// doWithRetry executes the API request with retry logic for transient errors.
-// Retries up to 3 times on HTTP 429 or 5xx status codes with exponential backoff.
-// On 429, honors the Retry-After header if present.
-func (e *JinaEmbedder) doWithRetry(ctx context.Context, reqBody []byte) (*jinaEmbedResponse, error) {
-	maxRetries := 3
-	backoffs := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second}
-
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		// Wait for rate limiter
-		if err := e.limiter.Wait(ctx); err != nil {
-			return nil, fmt.Errorf("embed: rate limiter wait failed: %w", err)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(reqBody))
-		if err != nil {
-			return nil, fmt.Errorf("embed: failed to create request: %w", err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+e.apiKey)
-
-		resp, err := e.client.Do(req)
-		if err != nil {
-			if ctx.Err() != nil {
-				return nil, fmt.Errorf("embed: request cancelled: %w", ctx.Err())
-			}
-			return nil, fmt.Errorf("embed: request failed: %w", err)
-		}
-
-		body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
-		resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("embed: failed to read response: %w", err)
-		}
+// totalChars counts the characters sent for usage accounting.
+func totalChars(texts []string) int {
+	n := 0
+	for _, t := range texts {
+		n += len([]rune(t))
+	}
+	return n
+}
 
-		// Success
-		if resp.StatusCode == http.StatusOK {
-			var embedResp jinaEmbedResponse
-			if err := json.Unmarshal(body, &embedResp); err != nil {
-				// Truncated/malformed response — treat as retryable
-				lastErr = fmt.Errorf("embed: failed to parse response: %w", err)
-				if attempt < maxRetries {
-					select {
-					case <-ctx.Done():
-						return nil, fmt.Errorf("embed: request cancelled during retry: %w", ctx.Err())
-					case <-time.After(backoffs[attempt]):
-					}
-				}
-				continue
-			}
-			return &embedResp, nil
-		}
+// do performs one API request. Non-2xx responses are returned as
+// *resilience.StatusError; retrying is left to WithRetry.
+func (e *JinaEmbedder) do(ctx context.Context, reqBody []byte) (*jinaEmbedResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("embed: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
 
-		// Determine if we should retry
-		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
-		if !retryable {
-			return nil, fmt.Errorf("embed: jina returned status %d: %s", resp.StatusCode, string(body))
+	resp, err := e.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("embed: request cancelled: %w", ctx.Err())
 		}
+		return nil, fmt.Errorf("embed: request failed: %w", err)
+	}
 
-		lastErr = fmt.Errorf("embed: jina returned status %d: %s", resp.StatusCode, string(body))
-
-		// Don't sleep after the last attempt
-		if attempt < maxRetries {
-			delay := backoffs[attempt]
-
-			// Honor Retry-After header on 429
-			if resp.StatusCode == http.StatusTooManyRequests {
-				if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-					if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
-						delay = time.Duration(seconds) * time.Second
-						if delay > 30*time.Second {
-							delay = 30 * time.Second
-						}
-					}
-				}
-			}
-
-			select {
-			case <-ctx.Done():
-				return nil, fmt.Errorf("embed: request cancelled during retry: %w", ctx.Err())
-			case <-time.After(delay):
-			}
-		}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("embed: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embed: %w", resilience.NewStatusError("jina", resp, string(body)))
 	}
 
-	return nil, fmt.Errorf("embed: all retries exhausted: %w", lastErr)
+	var embedResp jinaEmbedResponse
+	if err := json.Unmarshal(body, &embedResp); err != nil {
+		// Truncated/malformed response — retryable
+		return nil, fmt.Errorf("embed: failed to parse response: %w", err)
+	}
+	return &embedResp, nil
 }
@@ -11,9 +11,13 @@ import (
 	"testing"
 	"time"
 
-	"golang.org/x/time/rate"
+	"github.com/abelbrown/observer/internal/resilience"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
+// fastRetry is resilience.DefaultRetryPolicy without the waiting.
+var fastRetry = resilience.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
 func TestJinaAvailable(t *testing.T) {
 	t.Run("available with API key", func(t *testing.T) {
 		e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
@@ -80,7 +84,6 @@ func TestJinaEmbed(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	result, err := e.Embed(context.Background(), inputText)
 	if err != nil {
@@ -134,7 +137,6 @@ func TestJinaEmbedBatch(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	results, err := e.EmbedBatch(context.Background(), texts)
 	if err != nil {
@@ -188,7 +190,6 @@ func TestJinaEmbedBatchChunking(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	// Create 75 texts, should result in 3 API calls (25 + 25 + 25)
 	texts := make([]string, 75)
@@ -235,7 +236,6 @@ func TestJinaEmbedQuery(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	result, err := e.EmbedQuery(context.Background(), "search query")
 	if err != nil {
@@ -277,9 +277,8 @@ func TestJinaRetryOn429(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
-	result, err := e.Embed(context.Background(), "test")
+	result, err := WithRetry(e, fastRetry).Embed(context.Background(), "test")
 	if err != nil {
 		t.Fatalf("Embed() error = %v", err)
 	}
@@ -318,9 +317,8 @@ func TestJinaRetryOn500(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
-	result, err := e.Embed(context.Background(), "test")
+	result, err := WithRetry(e, fastRetry).Embed(context.Background(), "test")
 	if err != nil {
 		t.Fatalf("Embed() error = %v", err)
 	}
@@ -346,9 +344,8 @@ func TestJinaRetryExhausted(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
-	_, err := e.Embed(context.Background(), "test")
+	_, err := WithRetry(e, fastRetry).Embed(context.Background(), "test")
 	if err == nil {
 		t.Fatal("Embed() expected error after retries exhausted, got nil")
 	}
@@ -372,7 +369,6 @@ func TestJinaEmbedServerError(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	_, err := e.Embed(context.Background(), "test")
 	if err == nil {
@@ -400,7 +396,6 @@ func TestJinaEmbedContextCancellation(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 	defer cancel()
@@ -438,7 +433,6 @@ func TestJinaEmbedBatchOutOfRangeIndex(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	_, err := e.EmbedBatch(context.Background(), []string{"hello", "world", "foo"})
 	if err == nil {
@@ -472,7 +466,6 @@ func TestJinaEmbedBatchPartialResults(t *testing.T) {
 
 	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
 	e.endpoint = server.URL
-	e.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	_, err := e.EmbedBatch(context.Background(), []string{"hello", "world", "foo"})
 	if err == nil {
@@ -483,3 +476,43 @@ func TestJinaEmbedBatchPartialResults(t *testing.T) {
 		t.Errorf("EmbedBatch() error = %v, want 'missing embedding' error", err)
 	}
 }
+
+type usageLog []usage.Record
+
+func (l *usageLog) RecordUsage(r usage.Record) error {
+	*l = append(*l, r)
+	return nil
+}
+
+func TestJinaRecordsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := jinaEmbedResponse{
+			Data:  []jinaEmbedding{{Embedding: []float32{0.1}, Index: 0}, {Embedding: []float32{0.2}, Index: 1}},
+			Usage: jinaUsage{TotalTokens: 17},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	var rec usageLog
+	e := NewJinaEmbedder("test-key", "jina-embeddings-v3")
+	e.endpoint = server.URL
+	e.SetUsageRecorder(&rec)
+
+	ctx := usage.WithPurpose(context.Background(), usage.PurposeBackground)
+	if _, err := e.EmbedBatch(ctx, []string{"hello", "wörld"}); err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+
+	if len(rec) != 1 {
+		t.Fatalf("expected 1 usage record, got %d", len(rec))
+	}
+	r := rec[0]
+	if r.Backend != "jina" || r.Model != "jina-embeddings-v3" || r.Kind != usage.KindEmbed {
+		t.Errorf("unexpected record identity: %+v", r)
+	}
+	if r.Tokens != 17 || r.Chars != 10 || r.Purpose != usage.PurposeBackground || r.Failed {
+		t.Errorf("unexpected record: %+v", r)
+	}
+}
@@ -0,0 +1,183 @@
+package embed
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// LocalDims is the size of LocalEmbedder vectors.
+const LocalDims = 256
+
+// localSeed fixes the random projection. Changing it (or the features)
+// invalidates every stored local vector, so bump the ModelName version too.
+const localSeed = 0x6f62736572766572 // "observer"
+
+// Feature weights: whole words carry the meaning, bigrams add phrase
+// context, character trigrams catch inflections ("election"/"elections")
+// and names split differently across sources.
+const (
+	localUnigramWeight = 1.0
+	localBigramWeight  = 0.6
+	localTrigramWeight = 0.25
+)
+
+// LocalEmbedder is a pure-Go, fully offline embedder: hashed word and
+// character n-grams with log-scaled term frequencies, projected to
+// LocalDims dimensions by a fixed random ±1 matrix and L2-normalized.
+//
+// It knows nothing about synonyms, so it is far weaker than a neural
+// model, but texts sharing vocabulary land close together. That keeps
+// semantic dedup, More Like This and clustering working (roughly) on
+// machines with no network and no Ollama.
+type LocalEmbedder struct{}
+
+// NewLocalEmbedder creates a LocalEmbedder.
+func NewLocalEmbedder() *LocalEmbedder {
+	return &LocalEmbedder{}
+}
+
+// ModelName identifies the model behind this embedder's vectors.
+func (e *LocalEmbedder) ModelName() string {
+	return "local/hash-ngram-v1"
+}
+
+// Available always returns true: there is nothing to connect to.
+func (e *LocalEmbedder) Available() bool {
+	return true
+}
+
+// Embed generates a vector for text. Text with no indexable words yields
+// a zero vector, which is similar to nothing.
+func (e *LocalEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return localEmbed(text), nil
+}
+
+// EmbedBatch embeds each text in turn; there is no per-call overhead to save,
+// but implementing it keeps the coordinator on its batch path.
+func (e *LocalEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	results := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		results[i] = localEmbed(text)
+	}
+	return results, nil
+}
+
+// localEmbed computes the vector for one text.
+func localEmbed(text string) []float32 {
+	words := localTokens(text)
+
+	// Term frequencies per hashed feature, keeping each feature's weight.
+	type feature struct {
+		tf     float64
+		weight float64
+	}
+	features := make(map[uint64]*feature)
+	add := func(kind byte, s string, weight float64) {
+		h := localHash(kind, s)
+		if f, ok := features[h]; ok {
+			f.tf++
+			return
+		}
+		features[h] = &feature{tf: 1, weight: weight}
+	}
+	for i, w := range words {
+		add('u', w, localUnigramWeight)
+		if i > 0 {
+			add('b', words[i-1]+" "+w, localBigramWeight)
+		}
+		padded := []rune("^" + w + "$")
+		for j := 0; j+3 <= len(padded); j++ {
+			add('c', string(padded[j:j+3]), localTrigramWeight)
+		}
+	}
+
+	acc := make([]float64, LocalDims)
+	for h, f := range features {
+		w := f.weight * (1 + math.Log(f.tf))
+		// The feature's row of the projection matrix: LocalDims random
+		// signs drawn from a generator seeded by the feature hash.
+		state := h ^ localSeed
+		for d := 0; d < LocalDims; d += 64 {
+			bits := splitmix64(&state)
+			for k := 0; k < 64 && d+k < LocalDims; k++ {
+				if bits&(1<<k) != 0 {
+					acc[d+k] += w
+				} else {
+					acc[d+k] -= w
+				}
+			}
+		}
+	}
+
+	var norm float64
+	for _, v := range acc {
+		norm += v * v
+	}
+	vec := make([]float32, LocalDims)
+	if norm == 0 {
+		return vec
+	}
+	norm = math.Sqrt(norm)
+	for i, v := range acc {
+		vec[i] = float32(v / norm)
+	}
+	return vec
+}
+
+// localTokens lowercases text and splits it into words, dropping
+// stopwords and single characters.
+func localTokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) < 2 || localStopwords[f] {
+			continue
+		}
+		words = append(words, f)
+	}
+	return words
+}
+
+// localHash hashes a feature string, namespaced by its kind.
+func localHash(kind byte, s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte{kind})
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// splitmix64 advances state and returns the next pseudo-random value.
+func splitmix64(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// localStopwords are common English words that carry no topic.
+var localStopwords = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(`
+		a an and are as at be been but by can could did do does for from had has
+		have he her his how if in into is it its just more most new not of on
+		one or our out over said says she so than that the their them then there
+		these they this to up us was we were what when which who will with would
+		you your about all also after before being between both during
+		each few many much no only other own same should some such through
+		under until very while why may might must now here`) {
+		m[w] = true
+	}
+	return m
+}()
@@ -0,0 +1,57 @@
+package embed
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestLocalEmbedder(t *testing.T) {
+	e := NewLocalEmbedder()
+	ctx := context.Background()
+	embed := func(text string) []float32 {
+		t.Helper()
+		v, err := e.Embed(ctx, text)
+		if err != nil {
+			t.Fatalf("Embed(%q) error = %v", text, err)
+		}
+		return v
+	}
+
+	a := embed("Federal Reserve raises interest rates by a quarter point")
+	if len(a) != LocalDims {
+		t.Fatalf("expected %d dims, got %d", LocalDims, len(a))
+	}
+	var norm float64
+	for _, v := range a {
+		norm += float64(v) * float64(v)
+	}
+	if math.Abs(norm-1) > 1e-4 {
+		t.Errorf("expected unit vector, got squared norm %v", norm)
+	}
+
+	again := embed("Federal Reserve raises interest rates by a quarter point")
+	if CosineSimilarity(a, again) < 0.9999 {
+		t.Error("embedding is not deterministic")
+	}
+
+	syndicated := CosineSimilarity(a, embed("Fed raises interest rates a quarter point, Reuters reports"))
+	related := CosineSimilarity(a, embed("Interest rate hike expected from the Federal Reserve"))
+	unrelated := CosineSimilarity(a, embed("Local team wins championship in overtime thriller"))
+	t.Logf("syndicated=%.3f related=%.3f unrelated=%.3f", syndicated, related, unrelated)
+	if !(syndicated > related && related > unrelated) {
+		t.Errorf("expected syndicated > related > unrelated, got %.3f, %.3f, %.3f", syndicated, related, unrelated)
+	}
+	if unrelated > 0.3 {
+		t.Errorf("unrelated texts too similar: %.3f", unrelated)
+	}
+
+	if v := embed("the and of"); CosineSimilarity(v, a) != 0 {
+		t.Error("stopword-only text should be similar to nothing")
+	}
+
+	batch, err := e.EmbedBatch(ctx, []string{"Federal Reserve raises interest rates by a quarter point", "x"})
+	if err != nil || len(batch) != 2 || CosineSimilarity(batch[0], a) < 0.9999 {
+		t.Errorf("EmbedBatch disagrees with Embed: err=%v", err)
+	}
+}
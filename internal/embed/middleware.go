@@ -0,0 +1,154 @@
+package embed
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// Middleware wraps an Embedder with cross-cutting behaviour so backends
+// only implement the raw call. Each With* decorator preserves what it
+// wraps: a decorated BatchEmbedder is still a BatchEmbedder, and
+// ModelName and EmbedQuery pass through.
+//
+// Typical order, outermost first:
+//
+//	WithMetrics → WithCircuitBreaker → WithRetry → WithRateLimit → WithTimeout → backend
+
+// interceptor runs one backend call (op names it: "embed", "query" or
+// "batch"; n is the number of texts).
+type interceptor func(ctx context.Context, op string, n int, call func(context.Context) error) error
+
+// decorated is an Embedder whose calls go through an interceptor.
+type decorated struct {
+	inner     Embedder
+	intercept interceptor
+	available func() bool // nil: ask inner
+}
+
+// decoratedBatch adds EmbedBatch when the inner embedder has it.
+type decoratedBatch struct {
+	*decorated
+}
+
+// decorate wraps e, keeping BatchEmbedder if e implements it.
+func decorate(e Embedder, ic interceptor, available func() bool) Embedder {
+	d := &decorated{inner: e, intercept: ic, available: available}
+	if _, ok := e.(BatchEmbedder); ok {
+		return decoratedBatch{d}
+	}
+	return d
+}
+
+// Unwrap returns the decorated embedder.
+func (d *decorated) Unwrap() Embedder { return d.inner }
+
+// ModelName passes through the inner embedder's model name.
+func (d *decorated) ModelName() string { return ModelName(d.inner) }
+
+func (d *decorated) Available() bool {
+	if d.available != nil {
+		return d.available()
+	}
+	return d.inner.Available()
+}
+
+func (d *decorated) Embed(ctx context.Context, text string) ([]float32, error) {
+	var out []float32
+	err := d.intercept(ctx, "embed", 1, func(ctx context.Context) error {
+		var err error
+		out, err = d.inner.Embed(ctx, text)
+		return err
+	})
+	return out, err
+}
+
+func (d *decorated) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	var out []float32
+	err := d.intercept(ctx, "query", 1, func(ctx context.Context) error {
+		var err error
+		out, err = EmbedQuery(ctx, d.inner, text)
+		return err
+	})
+	return out, err
+}
+
+func (d decoratedBatch) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	var out [][]float32
+	err := d.intercept(ctx, "batch", len(texts), func(ctx context.Context) error {
+		var err error
+		out, err = d.inner.(BatchEmbedder).EmbedBatch(ctx, texts)
+		return err
+	})
+	return out, err
+}
+
+// WithRetry retries transient failures (429, 5xx, network errors,
+// malformed responses) per p, honouring Retry-After.
+func WithRetry(e Embedder, p resilience.RetryPolicy) Embedder {
+	return decorate(e, func(ctx context.Context, op string, n int, call func(context.Context) error) error {
+		return resilience.Retry(ctx, p, call)
+	}, nil)
+}
+
+// WithCircuitBreaker fails calls fast while b is open, and reports the
+// embedder unavailable then, without probing the backend.
+func WithCircuitBreaker(e Embedder, b *resilience.Breaker) Embedder {
+	return decorate(e, func(ctx context.Context, op string, n int, call func(context.Context) error) error {
+		if err := b.Allow(); err != nil {
+			return err
+		}
+		err := call(ctx)
+		b.Record(err)
+		return err
+	}, func() bool {
+		return !b.Open() && e.Available()
+	})
+}
+
+// WithRateLimit spaces calls at least interval apart. Zero returns e unchanged.
+func WithRateLimit(e Embedder, interval time.Duration) Embedder {
+	if interval <= 0 {
+		return e
+	}
+	limiter := rate.NewLimiter(rate.Every(interval), 1)
+	return decorate(e, func(ctx context.Context, op string, n int, call func(context.Context) error) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		return call(ctx)
+	}, nil)
+}
+
+// WithTimeout bounds each call to d.
+func WithTimeout(e Embedder, d time.Duration) Embedder {
+	return decorate(e, func(ctx context.Context, op string, n int, call func(context.Context) error) error {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return call(ctx)
+	}, nil)
+}
+
+// WithMetrics emits a backend.call event per call (debug on success,
+// warn on failure) with its duration and input count, tagged with the
+// embedder's ModelName. A nil logger returns e unchanged.
+func WithMetrics(e Embedder, l *otel.Logger) Embedder {
+	if l == nil {
+		return e
+	}
+	name := ModelName(e)
+	return decorate(e, func(ctx context.Context, op string, n int, call func(context.Context) error) error {
+		start := time.Now()
+		err := call(ctx)
+		ev := otel.Event{Kind: otel.KindBackendCall, Level: otel.LevelDebug, Comp: "embed", Source: name, Msg: op, Count: n, Dur: time.Since(start)}
+		if err != nil {
+			ev.Level, ev.Err = otel.LevelWarn, err.Error()
+		}
+		l.Emit(ev)
+		return err
+	}, nil)
+}
@@ -0,0 +1,115 @@
+package embed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// flakyEmbedder fails its first failures calls with err, then succeeds.
+type flakyEmbedder struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyEmbedder) Available() bool { return true }
+
+func (f *flakyEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	return []float32{1}, nil
+}
+
+func TestMiddlewarePreservesInterfaces(t *testing.T) {
+	var e Embedder = NewLocalEmbedder()
+	e = WithMetrics(WithCircuitBreaker(WithRetry(WithRateLimit(WithTimeout(e, time.Second), time.Millisecond), fastRetry),
+		resilience.NewBreaker(3, time.Minute, nil)), otel.NewLogger(&bytes.Buffer{}))
+
+	be, ok := e.(BatchEmbedder)
+	if !ok {
+		t.Fatal("decorated batch embedder lost EmbedBatch")
+	}
+	if _, err := be.EmbedBatch(context.Background(), []string{"a b", "c d"}); err != nil {
+		t.Errorf("EmbedBatch() error = %v", err)
+	}
+	if got := ModelName(e); got != "local/hash-ngram-v1" {
+		t.Errorf("ModelName() = %q, want the inner model", got)
+	}
+	if _, ok := WithRetry(&flakyEmbedder{}, fastRetry).(BatchEmbedder); ok {
+		t.Error("decorated non-batch embedder claims EmbedBatch")
+	}
+}
+
+func TestWithRetryEmbedder(t *testing.T) {
+	f := &flakyEmbedder{failures: 2, err: errors.New("connection reset")}
+	if _, err := WithRetry(f, fastRetry).Embed(context.Background(), "x"); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if f.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", f.calls)
+	}
+}
+
+func TestWithCircuitBreakerEmbedder(t *testing.T) {
+	f := &flakyEmbedder{failures: 10, err: &resilience.StatusError{Code: http.StatusServiceUnavailable}}
+	e := WithCircuitBreaker(f, resilience.NewBreaker(2, time.Minute, nil))
+
+	for range 2 {
+		e.Embed(context.Background(), "x")
+	}
+	if e.Available() {
+		t.Error("Available() = true with the breaker open")
+	}
+	if _, err := e.Embed(context.Background(), "x"); !errors.Is(err, resilience.ErrBreakerOpen) {
+		t.Errorf("expected ErrBreakerOpen, got %v", err)
+	}
+	if f.calls != 2 {
+		t.Errorf("open breaker still called the backend: %d calls", f.calls)
+	}
+}
+
+func TestWithTimeoutEmbedder(t *testing.T) {
+	slow := &blockingEmbedder{}
+	_, err := WithTimeout(slow, 10*time.Millisecond).Embed(context.Background(), "x")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+// blockingEmbedder blocks until its context is done.
+type blockingEmbedder struct{}
+
+func (blockingEmbedder) Available() bool { return true }
+
+func (blockingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestWithMetricsEmbedder(t *testing.T) {
+	var buf bytes.Buffer
+	l := otel.NewLogger(&buf)
+	f := &flakyEmbedder{failures: 1, err: errors.New("boom")}
+	e := WithMetrics(f, l)
+	e.Embed(context.Background(), "x")
+	e.Embed(context.Background(), "x")
+	l.Close()
+
+	out := buf.String()
+	if strings.Count(out, `"backend.call"`) != 2 {
+		t.Errorf("expected 2 backend.call events, got:\n%s", out)
+	}
+	if !strings.Contains(out, "boom") {
+		t.Errorf("failed call not logged with its error:\n%s", out)
+	}
+}
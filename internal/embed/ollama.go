@@ -4,10 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/abelbrown/observer/internal/resilience"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
 // OllamaEmbedder generates embeddings via local Ollama server.
@@ -15,6 +22,11 @@ type OllamaEmbedder struct {
 	endpoint string       // e.g., "http://localhost:11434"
 	model    string       // e.g., "nomic-embed-text"
 	client   *http.Client // HTTP client for requests
+	meter    *usage.Meter // optional usage accounting
+
+	batchSize   int         // texts per /api/embed call
+	maxParallel int         // concurrent calls in EmbedBatch
+	legacy      atomic.Bool // server only has /api/embeddings
 }
 
 // ollamaTagsResponse represents the response from GET /api/tags.
@@ -33,11 +45,40 @@ type ollamaEmbedRequest struct {
 	Input string `json:"input"`
 }
 
+// ollamaBatchEmbedRequest is an /api/embed request with array input.
+type ollamaBatchEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
 // ollamaEmbedResponse represents the response from POST /api/embed.
 type ollamaEmbedResponse struct {
-	Embeddings [][]float32 `json:"embeddings"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+}
+
+// ollamaLegacyEmbedRequest is the body for POST /api/embeddings, the
+// single-text endpoint of servers older than 0.3.
+type ollamaLegacyEmbedRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// ollamaLegacyEmbedResponse is the response from POST /api/embeddings.
+type ollamaLegacyEmbedResponse struct {
+	Embedding []float32 `json:"embedding"`
 }
 
+// errNoEmbedRoute means the server predates /api/embed.
+var errNoEmbedRoute = errors.New("endpoint not found")
+
+// Batch defaults: small chunks keep each call well inside the client
+// timeout on CPU-only hosts; a few calls in flight keep a GPU busy.
+const (
+	defaultOllamaBatchSize   = 16
+	defaultOllamaMaxParallel = 4
+)
+
 // NewOllamaEmbedder creates a new OllamaEmbedder with the given endpoint and model.
 func NewOllamaEmbedder(endpoint, model string) *OllamaEmbedder {
 	return &OllamaEmbedder{
@@ -46,9 +87,21 @@ func NewOllamaEmbedder(endpoint, model string) *OllamaEmbedder {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		batchSize:   defaultOllamaBatchSize,
+		maxParallel: defaultOllamaMaxParallel,
 	}
 }
 
+// SetUsageRecorder records every embed call (tokens, characters, latency) to rec.
+func (e *OllamaEmbedder) SetUsageRecorder(rec usage.Recorder) {
+	e.meter = usage.NewMeter(rec, "ollama", e.model, usage.KindEmbed)
+}
+
+// ModelName identifies the model behind this embedder's vectors.
+func (e *OllamaEmbedder) ModelName() string {
+	return "ollama/" + e.model
+}
+
 // Available returns true if the Ollama server is accessible and the model exists.
 // Uses a 3-second timeout for the availability check.
 func (e *OllamaEmbedder) Available() bool {
@@ -99,19 +152,155 @@ func (e *OllamaEmbedder) Available() bool {
 // Embed generates a vector embedding for the given text using Ollama.
 // Respects context cancellation and returns meaningful errors.
 func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
-	reqBody := ollamaEmbedRequest{
-		Model: e.model,
-		Input: text,
+	if e.legacy.Load() {
+		return e.embedLegacy(ctx, text)
+	}
+	start := time.Now()
+	resp, err := e.embed(ctx, ollamaEmbedRequest{Model: e.model, Input: text})
+	if errors.Is(err, errNoEmbedRoute) {
+		e.legacy.Store(true)
+		return e.embedLegacy(ctx, text)
 	}
+	tokens := 0
+	if resp != nil {
+		tokens = resp.PromptEvalCount
+	}
+	e.meter.Observe(ctx, start, tokens, totalChars([]string{text}), err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Embeddings[0], nil
+}
+
+// EmbedBatch generates vector embeddings for multiple texts, sending
+// chunks of batchSize texts per /api/embed call with up to maxParallel
+// calls in flight. result[i] corresponds to texts[i].
+func (e *OllamaEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
+	results := make([][]float32, len(texts))
+	sem := make(chan struct{}, e.maxParallel)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+chunks:
+	for chunkStart := 0; chunkStart < len(texts); chunkStart += e.batchSize {
+		chunkEnd := min(chunkStart+e.batchSize, len(texts))
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break chunks
+		}
+		wg.Add(1)
+		go func(chunkStart, chunkEnd int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			vecs, err := e.embedChunk(ctx, texts[chunkStart:chunkEnd])
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("embed: batch chunk starting at %d failed: %w", chunkStart, err)
+					cancel() // stop the other chunks
+				})
+				return
+			}
+			copy(results[chunkStart:chunkEnd], vecs)
+		}(chunkStart, chunkEnd)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("embed: request cancelled: %w", err)
+	}
+	for i, r := range results {
+		if r == nil {
+			return nil, fmt.Errorf("embed: missing embedding for index %d", i)
+		}
+	}
+	return results, nil
+}
+
+// embedChunk embeds one chunk with a single /api/embed call, or one
+// /api/embeddings call per text on servers that predate /api/embed.
+func (e *OllamaEmbedder) embedChunk(ctx context.Context, texts []string) ([][]float32, error) {
+	if !e.legacy.Load() {
+		start := time.Now()
+		resp, err := e.embed(ctx, ollamaBatchEmbedRequest{Model: e.model, Input: texts})
+		if !errors.Is(err, errNoEmbedRoute) {
+			tokens := 0
+			if resp != nil {
+				tokens = resp.PromptEvalCount
+			}
+			e.meter.Observe(ctx, start, tokens, totalChars(texts), err)
+			if err != nil {
+				return nil, err
+			}
+			// /api/embed returns embeddings in input order.
+			if len(resp.Embeddings) != len(texts) {
+				return nil, fmt.Errorf("embed: ollama returned %d embeddings for %d inputs", len(resp.Embeddings), len(texts))
+			}
+			return resp.Embeddings, nil
+		}
+		e.legacy.Store(true)
+	}
+
+	vecs := make([][]float32, len(texts))
+	for i, text := range texts {
+		v, err := e.embedLegacy(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = v
+	}
+	return vecs, nil
+}
+
+// embedLegacy performs one /api/embeddings call (Ollama < 0.3).
+func (e *OllamaEmbedder) embedLegacy(ctx context.Context, text string) ([]float32, error) {
+	start := time.Now()
+	var resp ollamaLegacyEmbedResponse
+	err := e.post(ctx, "/api/embeddings", ollamaLegacyEmbedRequest{Model: e.model, Prompt: text}, &resp)
+	if err == nil && len(resp.Embedding) == 0 {
+		err = fmt.Errorf("embed: no embeddings returned")
+	}
+	e.meter.Observe(ctx, start, 0, totalChars([]string{text}), err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Embedding, nil
+}
+
+// embed performs one /api/embed call.
+func (e *OllamaEmbedder) embed(ctx context.Context, reqBody any) (*ollamaEmbedResponse, error) {
+	var embedResp ollamaEmbedResponse
+	if err := e.post(ctx, "/api/embed", reqBody, &embedResp); err != nil {
+		return nil, err
+	}
+	if len(embedResp.Embeddings) == 0 {
+		return nil, fmt.Errorf("embed: no embeddings returned")
+	}
+	return &embedResp, nil
+}
+
+// post sends reqBody as JSON to path and decodes the response into out.
+// Returns an error wrapping errNoEmbedRoute if the server doesn't know path.
+func (e *OllamaEmbedder) post(ctx context.Context, path string, reqBody, out any) error {
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("embed: failed to marshal request: %w", err)
+		return fmt.Errorf("embed: failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+"/api/embed", bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+path, bytes.NewReader(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("embed: failed to create request: %w", err)
+		return fmt.Errorf("embed: failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
@@ -119,33 +308,31 @@ func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, err
 	if err != nil {
 		// Check if context was cancelled
 		if ctx.Err() != nil {
-			return nil, fmt.Errorf("embed: request cancelled: %w", ctx.Err())
+			return fmt.Errorf("embed: request cancelled: %w", ctx.Err())
 		}
-		return nil, fmt.Errorf("embed: request failed: %w", err)
+		return fmt.Errorf("embed: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, readErr := io.ReadAll(resp.Body)
 		if readErr != nil {
-			return nil, fmt.Errorf("embed: ollama returned status %d (failed to read body: %v)", resp.StatusCode, readErr)
+			return fmt.Errorf("embed: ollama returned status %d (failed to read body: %v)", resp.StatusCode, readErr)
+		}
+		// Unknown routes get the router's plain-text 404; a missing model
+		// is a JSON error and must not trigger the fallback.
+		if resp.StatusCode == http.StatusNotFound && strings.Contains(string(body), "page not found") {
+			return fmt.Errorf("embed: ollama has no %s: %w", path, errNoEmbedRoute)
 		}
-		return nil, fmt.Errorf("embed: ollama returned status %d: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("embed: %w", resilience.NewStatusError("ollama", resp, string(body)))
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("embed: failed to read response: %w", err)
+		return fmt.Errorf("embed: failed to read response: %w", err)
 	}
-
-	var embedResp ollamaEmbedResponse
-	if err := json.Unmarshal(body, &embedResp); err != nil {
-		return nil, fmt.Errorf("embed: failed to parse response: %w", err)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("embed: failed to parse response: %w", err)
 	}
-
-	if len(embedResp.Embeddings) == 0 {
-		return nil, fmt.Errorf("embed: no embeddings returned")
-	}
-
-	return embedResp.Embeddings[0], nil
+	return nil
 }
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -296,3 +298,140 @@ func TestEmbedInvalidJSON(t *testing.T) {
 		t.Errorf("Embed() error = %v, want parse error", err)
 	}
 }
+
+func TestOllamaEmbedBatch(t *testing.T) {
+	var calls, inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		calls.Add(1)
+
+		var req ollamaBatchEmbedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode batch request: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if len(req.Input) > 3 {
+			t.Errorf("chunk of %d exceeds batch size 3", len(req.Input))
+		}
+		time.Sleep(20 * time.Millisecond) // let chunks overlap
+
+		// Each vector encodes its text so ordering can be checked.
+		resp := ollamaEmbedResponse{PromptEvalCount: len(req.Input)}
+		for _, text := range req.Input {
+			v, _ := strconv.Atoi(text)
+			resp.Embeddings = append(resp.Embeddings, []float32{float32(v)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	embedder := NewOllamaEmbedder(server.URL, "nomic-embed-text")
+	embedder.batchSize = 3
+	embedder.maxParallel = 2
+
+	texts := make([]string, 10)
+	for i := range texts {
+		texts[i] = strconv.Itoa(i)
+	}
+	results, err := embedder.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if len(results) != len(texts) {
+		t.Fatalf("EmbedBatch() returned %d results, want %d", len(results), len(texts))
+	}
+	for i, emb := range results {
+		if emb[0] != float32(i) {
+			t.Errorf("EmbedBatch()[%d] = %v, want %d", i, emb[0], i)
+		}
+	}
+	if calls.Load() != 4 {
+		t.Errorf("expected 4 chunked calls, got %d", calls.Load())
+	}
+	if maxInFlight.Load() > 2 {
+		t.Errorf("expected at most 2 calls in flight, got %d", maxInFlight.Load())
+	}
+}
+
+func TestOllamaEmbedBatchCountMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ollamaEmbedResponse{Embeddings: [][]float32{{0.1}}})
+	}))
+	defer server.Close()
+
+	embedder := NewOllamaEmbedder(server.URL, "nomic-embed-text")
+	_, err := embedder.EmbedBatch(context.Background(), []string{"a", "b"})
+	if err == nil || !strings.Contains(err.Error(), "1 embeddings for 2 inputs") {
+		t.Errorf("EmbedBatch() error = %v, want count mismatch", err)
+	}
+}
+
+func TestOllamaEmbedBatchLegacyFallback(t *testing.T) {
+	var newCalls, legacyCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/embed":
+			newCalls.Add(1)
+			http.Error(w, "404 page not found", http.StatusNotFound)
+		case "/api/embeddings":
+			legacyCalls.Add(1)
+			var req ollamaLegacyEmbedRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode legacy request: %v", err)
+			}
+			v, _ := strconv.Atoi(req.Prompt)
+			json.NewEncoder(w).Encode(ollamaLegacyEmbedResponse{Embedding: []float32{float32(v)}})
+		}
+	}))
+	defer server.Close()
+
+	embedder := NewOllamaEmbedder(server.URL, "nomic-embed-text")
+	embedder.maxParallel = 1
+
+	results, err := embedder.EmbedBatch(context.Background(), []string{"0", "1", "2"})
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	for i, emb := range results {
+		if emb[0] != float32(i) {
+			t.Errorf("EmbedBatch()[%d] = %v, want %d", i, emb[0], i)
+		}
+	}
+	if _, err := embedder.Embed(context.Background(), "3"); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if newCalls.Load() != 1 {
+		t.Errorf("expected /api/embed probed once, got %d calls", newCalls.Load())
+	}
+	if legacyCalls.Load() != 4 {
+		t.Errorf("expected 4 /api/embeddings calls, got %d", legacyCalls.Load())
+	}
+}
+
+func TestOllamaEmbedMissingModelNoFallback(t *testing.T) {
+	var legacyCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/embeddings" {
+			legacyCalls.Add(1)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model \"nomic-embed-text\" not found, try pulling it first"}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOllamaEmbedder(server.URL, "nomic-embed-text")
+	if _, err := embedder.EmbedBatch(context.Background(), []string{"a"}); err == nil {
+		t.Error("EmbedBatch() expected error for missing model")
+	}
+	if legacyCalls.Load() != 0 {
+		t.Error("a missing model must not trigger the legacy fallback")
+	}
+}
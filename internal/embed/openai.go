@@ -0,0 +1,254 @@
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abelbrown/observer/internal/resilience"
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+// defaultOpenAIBatchSize caps inputs per request. Self-hosted servers
+// (llama.cpp, vLLM, LM Studio) often reject very large batches.
+const defaultOpenAIBatchSize = 64
+
+// OpenAICompatConfig configures an OpenAICompatEmbedder.
+type OpenAICompatConfig struct {
+	// BaseURL is the server root, e.g. "http://localhost:8080" or
+	// "https://gateway.example.com/v1". "/v1/embeddings" is appended
+	// (or just "/embeddings" if BaseURL already ends in /v1).
+	BaseURL string
+	Model   string
+	// Dimensions requests truncated vectors from models that support it
+	// (Matryoshka). 0 leaves the field out and uses the model's size.
+	Dimensions int
+	// QueryPrefix and DocPrefix are prepended to queries and documents for
+	// models trained with instructions, e.g. "search_query: " and
+	// "search_document: " for nomic-embed-text.
+	QueryPrefix string
+	DocPrefix   string
+	// APIKey is sent in AuthHeader. With the default header
+	// ("Authorization") it is sent as a bearer token; any other header
+	// (e.g. "api-key", "X-API-Key") gets the raw key. Empty sends nothing.
+	APIKey     string
+	AuthHeader string
+	// BatchSize caps inputs per request (default 64).
+	BatchSize int
+}
+
+// OpenAICompatEmbedder generates embeddings via any server implementing
+// the OpenAI /v1/embeddings schema: llama.cpp server, vLLM, LM Studio,
+// LiteLLM and other self-hosted gateways.
+type OpenAICompatEmbedder struct {
+	cfg      OpenAICompatConfig
+	endpoint string
+	client   *http.Client
+	meter    *usage.Meter // optional usage accounting
+}
+
+// openAIEmbedRequest represents the request body for POST /v1/embeddings.
+type openAIEmbedRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format"`
+}
+
+// openAIEmbedResponse represents the response from POST /v1/embeddings.
+type openAIEmbedResponse struct {
+	Data  []openAIEmbedding `json:"data"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// openAIEmbedding represents a single embedding in the response.
+type openAIEmbedding struct {
+	Embedding []float32 `json:"embedding"`
+	Index     int       `json:"index"`
+}
+
+// openAIErrorResponse is the error body OpenAI-style servers return.
+type openAIErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewOpenAICompatEmbedder creates an embedder for an OpenAI-compatible server.
+func NewOpenAICompatEmbedder(cfg OpenAICompatConfig) *OpenAICompatEmbedder {
+	if cfg.AuthHeader == "" {
+		cfg.AuthHeader = "Authorization"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultOpenAIBatchSize
+	}
+	base := strings.TrimRight(cfg.BaseURL, "/")
+	endpoint := base + "/v1/embeddings"
+	if strings.HasSuffix(base, "/v1") {
+		endpoint = base + "/embeddings"
+	}
+	return &OpenAICompatEmbedder{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// SetUsageRecorder records every API call (tokens, characters, latency) to rec.
+func (e *OpenAICompatEmbedder) SetUsageRecorder(rec usage.Recorder) {
+	e.meter = usage.NewMeter(rec, "openai", e.cfg.Model, usage.KindEmbed)
+}
+
+// ModelName identifies the model behind this embedder's vectors.
+// Dimensions and the document prefix change the vectors, so they are
+// part of the name.
+func (e *OpenAICompatEmbedder) ModelName() string {
+	name := "openai/" + e.cfg.Model
+	if e.cfg.Dimensions > 0 {
+		name += fmt.Sprintf("@%d", e.cfg.Dimensions)
+	}
+	if e.cfg.DocPrefix != "" {
+		name += fmt.Sprintf("+%q", e.cfg.DocPrefix)
+	}
+	return name
+}
+
+// Available returns true if a base URL and model are configured.
+// Does not probe the server; failures surface from Embed.
+func (e *OpenAICompatEmbedder) Available() bool {
+	return e.cfg.BaseURL != "" && e.cfg.Model != ""
+}
+
+// Embed generates a document embedding for the given text.
+func (e *OpenAICompatEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := e.embed(ctx, []string{e.cfg.DocPrefix + text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+// EmbedQuery generates a query embedding, using QueryPrefix.
+func (e *OpenAICompatEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := e.embed(ctx, []string{e.cfg.QueryPrefix + text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+// EmbedBatch generates document embeddings for multiple texts,
+// splitting them into requests of at most BatchSize inputs.
+func (e *OpenAICompatEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	results := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		end := min(start+e.cfg.BatchSize, len(texts))
+		input := make([]string, end-start)
+		for i, t := range texts[start:end] {
+			input[i] = e.cfg.DocPrefix + t
+		}
+		vecs, err := e.embed(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("embed: batch chunk starting at %d failed: %w", start, err)
+		}
+		results = append(results, vecs...)
+	}
+	return results, nil
+}
+
+// embed performs one /v1/embeddings call and returns vectors in input order.
+func (e *OpenAICompatEmbedder) embed(ctx context.Context, input []string) ([][]float32, error) {
+	start := time.Now()
+	resp, err := e.do(ctx, input)
+	tokens := 0
+	if resp != nil {
+		tokens = resp.Usage.TotalTokens
+		if tokens == 0 {
+			tokens = resp.Usage.PromptTokens
+		}
+	}
+	e.meter.Observe(ctx, start, tokens, totalChars(input), err)
+	if err != nil {
+		return nil, err
+	}
+
+	vecs := make([][]float32, len(input))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(input) {
+			return nil, fmt.Errorf("embed: server returned out-of-range index %d for %d inputs", d.Index, len(input))
+		}
+		vecs[d.Index] = d.Embedding
+	}
+	for i, v := range vecs {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("embed: missing embedding for index %d", i)
+		}
+	}
+	return vecs, nil
+}
+
+// do sends the request and decodes the response.
+func (e *OpenAICompatEmbedder) do(ctx context.Context, input []string) (*openAIEmbedResponse, error) {
+	jsonBody, err := json.Marshal(openAIEmbedRequest{
+		Model:          e.cfg.Model,
+		Input:          input,
+		Dimensions:     e.cfg.Dimensions,
+		EncodingFormat: "float",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("embed: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("embed: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.cfg.APIKey != "" {
+		if strings.EqualFold(e.cfg.AuthHeader, "Authorization") {
+			req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+		} else {
+			req.Header.Set(e.cfg.AuthHeader, e.cfg.APIKey)
+		}
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("embed: request cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("embed: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, fmt.Errorf("embed: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg := string(body)
+		var apiErr openAIErrorResponse
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			msg = apiErr.Error.Message
+		}
+		return nil, fmt.Errorf("embed: %w", resilience.NewStatusError("openai", resp, msg))
+	}
+
+	var embedResp openAIEmbedResponse
+	if err := json.Unmarshal(body, &embedResp); err != nil {
+		return nil, fmt.Errorf("embed: failed to parse response: %w", err)
+	}
+	return &embedResp, nil
+}
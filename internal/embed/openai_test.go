@@ -0,0 +1,179 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// openAIServer answers /v1/embeddings with vectors whose first component is
+// the input's position, returned in reverse order to exercise Index.
+func openAIServer(t *testing.T, check func(r *http.Request, req openAIEmbedRequest)) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIEmbedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if check != nil {
+			check(r, req)
+		}
+		var resp openAIEmbedResponse
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			resp.Data = append(resp.Data, openAIEmbedding{Embedding: []float32{float32(i), 1}, Index: i})
+		}
+		resp.Usage.PromptTokens = 3 * len(req.Input)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestOpenAICompatAvailable(t *testing.T) {
+	if !NewOpenAICompatEmbedder(OpenAICompatConfig{BaseURL: "http://localhost:8080", Model: "m"}).Available() {
+		t.Error("Available() = false with base URL and model")
+	}
+	if NewOpenAICompatEmbedder(OpenAICompatConfig{BaseURL: "http://localhost:8080"}).Available() {
+		t.Error("Available() = true without a model")
+	}
+}
+
+func TestOpenAICompatEmbed(t *testing.T) {
+	server := openAIServer(t, func(r *http.Request, req openAIEmbedRequest) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer sk-test" {
+			t.Errorf("unexpected authorization: %q", auth)
+		}
+		if req.Model != "nomic-embed-text" || req.Dimensions != 256 || req.EncodingFormat != "float" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		if len(req.Input) != 1 || req.Input[0] != "search_document: hello" {
+			t.Errorf("expected document prefix, got %q", req.Input)
+		}
+	})
+	defer server.Close()
+
+	e := NewOpenAICompatEmbedder(OpenAICompatConfig{
+		BaseURL:     server.URL + "/",
+		Model:       "nomic-embed-text",
+		Dimensions:  256,
+		QueryPrefix: "search_query: ",
+		DocPrefix:   "search_document: ",
+		APIKey:      "sk-test",
+	})
+	emb, err := e.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(emb) != 2 {
+		t.Errorf("unexpected embedding: %v", emb)
+	}
+}
+
+func TestOpenAICompatEmbedQuery(t *testing.T) {
+	server := openAIServer(t, func(r *http.Request, req openAIEmbedRequest) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf("unexpected path for base URL ending in /v1: %s", r.URL.Path)
+		}
+		if req.Input[0] != "search_query: what happened" {
+			t.Errorf("expected query prefix, got %q", req.Input[0])
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("expected no Authorization header with a custom auth header")
+		}
+		if key := r.Header.Get("X-API-Key"); key != "secret" {
+			t.Errorf("unexpected X-API-Key: %q", key)
+		}
+	})
+	defer server.Close()
+
+	e := NewOpenAICompatEmbedder(OpenAICompatConfig{
+		BaseURL:     server.URL + "/v1",
+		Model:       "m",
+		QueryPrefix: "search_query: ",
+		DocPrefix:   "search_document: ",
+		APIKey:      "secret",
+		AuthHeader:  "X-API-Key",
+	})
+	if _, err := EmbedQuery(context.Background(), e, "what happened"); err != nil {
+		t.Fatalf("EmbedQuery() error = %v", err)
+	}
+}
+
+func TestOpenAICompatEmbedBatch(t *testing.T) {
+	var calls atomic.Int32
+	server := openAIServer(t, func(r *http.Request, req openAIEmbedRequest) {
+		calls.Add(1)
+		if len(req.Input) > 2 {
+			t.Errorf("batch of %d exceeds BatchSize 2", len(req.Input))
+		}
+	})
+	defer server.Close()
+
+	var rec usageLog
+	e := NewOpenAICompatEmbedder(OpenAICompatConfig{BaseURL: server.URL, Model: "m", BatchSize: 2})
+	e.SetUsageRecorder(&rec)
+
+	results, err := e.EmbedBatch(context.Background(), []string{"a", "b", "c", "d", "e"})
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 requests, got %d", calls.Load())
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	// Each chunk restarts its index at 0, in input order.
+	want := []float32{0, 1, 0, 1, 0}
+	for i, emb := range results {
+		if emb[0] != want[i] {
+			t.Errorf("result[%d][0] = %v, want %v", i, emb[0], want[i])
+		}
+	}
+	if len(rec) != 3 || rec[0].Backend != "openai" || rec[0].Tokens != 6 {
+		t.Errorf("unexpected usage records: %+v", rec)
+	}
+}
+
+func TestOpenAICompatServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"input too long"}}`))
+	}))
+	defer server.Close()
+
+	e := NewOpenAICompatEmbedder(OpenAICompatConfig{BaseURL: server.URL, Model: "m"})
+	_, err := e.Embed(context.Background(), "hello")
+	if err == nil || !strings.Contains(err.Error(), "input too long") {
+		t.Errorf("expected server error message, got %v", err)
+	}
+}
+
+func TestOpenAICompatMissingEmbedding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"embedding":[0.1],"index":0}]}`))
+	}))
+	defer server.Close()
+
+	e := NewOpenAICompatEmbedder(OpenAICompatConfig{BaseURL: server.URL, Model: "m"})
+	if _, err := e.EmbedBatch(context.Background(), []string{"a", "b"}); err == nil {
+		t.Error("expected error when the server returns fewer embeddings than inputs")
+	}
+}
+
+func TestOpenAICompatModelName(t *testing.T) {
+	plain := NewOpenAICompatEmbedder(OpenAICompatConfig{Model: "m"}).ModelName()
+	sized := NewOpenAICompatEmbedder(OpenAICompatConfig{Model: "m", Dimensions: 256}).ModelName()
+	prefixed := NewOpenAICompatEmbedder(OpenAICompatConfig{Model: "m", DocPrefix: "passage: "}).ModelName()
+	if plain == sized || plain == prefixed || sized == prefixed {
+		t.Errorf("model names should differ: %q %q %q", plain, sized, prefixed)
+	}
+}
@@ -0,0 +1,167 @@
+package embed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Embedding tasks. Backends like Jina embed queries and documents
+// differently, so the task is part of a cache key.
+const (
+	TaskDocument = "document"
+	TaskQuery    = "query"
+)
+
+// maxDocumentChars caps document text at ~500 tokens.
+const maxDocumentChars = 2000
+
+// htmlTagRe matches HTML tags.
+var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// whitespaceRe matches runs of whitespace.
+var whitespaceRe = regexp.MustCompile(`\s+`)
+
+// Sanitize strips HTML tags, collapses whitespace, and caps at maxChars runes.
+func Sanitize(s string, maxChars int) string {
+	s = htmlTagRe.ReplaceAllString(s, " ")
+	s = whitespaceRe.ReplaceAllString(s, " ")
+	s = strings.TrimSpace(s)
+	if maxChars <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) > maxChars {
+		return string(runes[:maxChars])
+	}
+	return s
+}
+
+// DocumentText builds the text embedded for an item: title plus sanitized
+// summary, capped at ~2000 characters. Every embedding path must use it so
+// identical items produce identical cache keys.
+func DocumentText(title, summary string) string {
+	text := title
+	if summary != "" {
+		remaining := maxDocumentChars - len([]rune(text))
+		if remaining > 0 {
+			if clean := Sanitize(summary, remaining); clean != "" {
+				text += " " + clean
+			}
+		}
+	}
+	return text
+}
+
+// Chunking. Long documents also get one vector per passage, so a story
+// whose relevant part is buried past the first few paragraphs can still
+// match; the document vector alone is dominated by the opening.
+const (
+	chunkChars   = 1000 // ~250 tokens per passage
+	chunkOverlap = 200  // context carried into the next passage
+	maxChunks    = 16
+)
+
+// DocumentChunks splits an item's sanitized summary into overlapping
+// passages of at most ~1000 characters, broken at sentence or word
+// boundaries. Returns nil when the summary fits in one passage: the
+// document vector (DocumentText) already covers it.
+func DocumentChunks(summary string) []string {
+	runes := []rune(Sanitize(summary, maxChunks*chunkChars))
+	if len(runes) <= chunkChars {
+		return nil
+	}
+
+	var chunks []string
+	start := 0
+	for start < len(runes) && len(chunks) < maxChunks {
+		end := start + chunkChars
+		if end >= len(runes) {
+			end = len(runes)
+		} else {
+			end = chunkBoundary(runes, start, end)
+		}
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		if end == len(runes) {
+			break
+		}
+		start = overlapStart(runes, start, end)
+	}
+	return chunks
+}
+
+// chunkBoundary returns where a passage spanning runes[start:end] should
+// end: after the last sentence end in its second half, else after the
+// last space, else at end.
+func chunkBoundary(runes []rune, start, end int) int {
+	floor := start + chunkChars/2
+	for i := end - 1; i > floor; i-- {
+		if runes[i] == ' ' && strings.ContainsRune(".!?", runes[i-1]) {
+			return i
+		}
+	}
+	for i := end - 1; i > floor; i-- {
+		if runes[i] == ' ' {
+			return i
+		}
+	}
+	return end
+}
+
+// overlapStart returns where the passage after runes[prev:end] starts:
+// up to chunkOverlap characters back, at the first sentence start in that
+// window, else the first word start.
+func overlapStart(runes []rune, prev, end int) int {
+	from := end - chunkOverlap
+	if from <= prev {
+		return end
+	}
+	for i := from; i < end; i++ {
+		if runes[i-1] == ' ' && i >= 2 && strings.ContainsRune(".!?", runes[i-2]) {
+			return i
+		}
+	}
+	for i := from; i < end; i++ {
+		if runes[i-1] == ' ' {
+			return i
+		}
+	}
+	return end
+}
+
+// ChunkText builds the text embedded for one passage of an item: the
+// title gives each passage the context of the story it belongs to.
+func ChunkText(title, passage string) string {
+	return title + " " + passage
+}
+
+// ModelNamer is implemented by embedders that can name the model behind
+// their vectors, e.g. "jina/jina-embeddings-v3". Vectors from different
+// models are not comparable, so caches are keyed by it.
+type ModelNamer interface {
+	ModelName() string
+}
+
+// ModelName returns e's model name, or "" if e doesn't report one
+// (in which case its vectors must not be cached).
+func ModelName(e Embedder) string {
+	if n, ok := e.(ModelNamer); ok {
+		return n.ModelName()
+	}
+	return ""
+}
+
+// CacheKey is the content address of an embedding: a hash of the model,
+// task and exact text sent to the backend.
+func CacheKey(model, task, text string) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(task))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package embed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocumentText(t *testing.T) {
+	got := DocumentText("Title", "<p>Some   <b>bold</b>\n summary</p>")
+	if got != "Title Some bold summary" {
+		t.Errorf("DocumentText() = %q", got)
+	}
+	if got := DocumentText("Title", ""); got != "Title" {
+		t.Errorf("DocumentText() without summary = %q", got)
+	}
+	long := DocumentText("Título", strings.Repeat("é", 5000))
+	if n := len([]rune(long)); n != maxDocumentChars+1 {
+		t.Errorf("expected %d runes (cap plus separator), got %d", maxDocumentChars+1, n)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := CacheKey("jina/v3", TaskDocument, "hello")
+	if base != CacheKey("jina/v3", TaskDocument, "hello") {
+		t.Error("CacheKey is not deterministic")
+	}
+	for name, other := range map[string]string{
+		"model": CacheKey("ollama/nomic", TaskDocument, "hello"),
+		"task":  CacheKey("jina/v3", TaskQuery, "hello"),
+		"text":  CacheKey("jina/v3", TaskDocument, "hello!"),
+	} {
+		if other == base {
+			t.Errorf("changing the %s should change the key", name)
+		}
+	}
+	if ModelName(NewJinaEmbedder("k", "jina-embeddings-v3")) != "jina/jina-embeddings-v3" {
+		t.Error("unexpected Jina model name")
+	}
+}
+
+func TestDocumentChunks(t *testing.T) {
+	if got := DocumentChunks("<p>A short summary.</p>"); got != nil {
+		t.Errorf("short summary should not be chunked, got %d chunks", len(got))
+	}
+
+	sentence := "The committee reviewed the proposal in detail today. "
+	long := "<p>" + strings.Repeat(sentence, 60) + "</p>"
+	chunks := DocumentChunks(long)
+	if len(chunks) < 3 {
+		t.Fatalf("expected several chunks for %d chars, got %d", len(long), len(chunks))
+	}
+	for i, c := range chunks {
+		if n := len([]rune(c)); n > chunkChars {
+			t.Errorf("chunk %d has %d chars, want <= %d", i, n, chunkChars)
+		}
+		if strings.Contains(c, "<p>") {
+			t.Errorf("chunk %d not sanitized: %q", i, c[:20])
+		}
+		if !strings.HasPrefix(c, "The committee") {
+			t.Errorf("chunk %d should start at a sentence boundary: %q", i, c[:20])
+		}
+		if i < len(chunks)-1 && !strings.HasSuffix(c, ".") {
+			t.Errorf("chunk %d should end at a sentence boundary: %q", i, c[len(c)-20:])
+		}
+	}
+
+	if got := DocumentChunks(strings.Repeat("word ", 10000)); len(got) != maxChunks {
+		t.Errorf("expected chunks capped at %d, got %d", maxChunks, len(got))
+	}
+}
@@ -4,21 +4,29 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/infblueocean/clarion"
 	_ "github.com/infblueocean/clarion/catalog"
 
+	"github.com/abelbrown/observer/internal/config"
 	"github.com/abelbrown/observer/internal/otel"
 	"github.com/abelbrown/observer/internal/store"
 )
 
 // ClarionProvider fetches items from Clarion sources.
 type ClarionProvider struct {
-	sources []clarion.Source
-	opts    clarion.FetchOptions
-	logger  *otel.Logger
+	sources   []clarion.Source
+	opts      clarion.FetchOptions
+	selection config.SourcesConfig
+	muted     MuteLister // optional: nil means nothing is muted
+	logger    *otel.Logger
+
+	// fetch fetches sources with Clarion; replaced in tests.
+	fetch func(ctx context.Context, opts clarion.FetchOptions, sources ...clarion.Source) []clarion.Result
 }
 
 // NewClarionProvider creates a ClarionProvider.
@@ -30,12 +38,121 @@ func NewClarionProvider(sources []clarion.Source, opts clarion.FetchOptions, l *
 	if l == nil {
 		l = otel.NewNullLogger()
 	}
-	return &ClarionProvider{sources: sources, opts: opts, logger: l}
+	return &ClarionProvider{sources: sources, opts: opts, logger: l, fetch: clarion.FetchWithOptions}
+}
+
+// ClarionOptions are the provider-specific options of a "clarion" entry in
+// the config file. Zero values take the defaults below.
+type ClarionOptions struct {
+	SourceConcurrency int             `json:"source_concurrency,omitempty"` // parallel source fetches (default 10)
+	SourceTimeout     config.Duration `json:"source_timeout,omitempty"`     // per-source deadline (default 30s)
+	MaxItems          int             `json:"max_items,omitempty"`          // items kept per source (default 50)
+}
+
+// defaultSourceConcurrency is how many sources are fetched at once
+// unless source_concurrency says otherwise.
+const defaultSourceConcurrency = 10
+
+// FetchOptions converts the options to Clarion's, applying defaults.
+func (o ClarionOptions) FetchOptions() clarion.FetchOptions {
+	opts := clarion.FetchOptions{
+		MaxConcurrency: defaultSourceConcurrency,
+		Timeout:        30 * time.Second,
+		MaxItems:       50,
+	}
+	if o.SourceConcurrency > 0 {
+		opts.MaxConcurrency = o.SourceConcurrency
+	}
+	if o.SourceTimeout > 0 {
+		opts.Timeout = time.Duration(o.SourceTimeout)
+	}
+	if o.MaxItems > 0 {
+		opts.MaxItems = o.MaxItems
+	}
+	return opts
+}
+
+// NewClarionProviderFromConfig creates a ClarionProvider over all registered
+// sources from a provider config entry, narrowed by the source selection.
+// The mute lister is optional (nil to ignore TUI mutes).
+func NewClarionProviderFromConfig(pc config.ProviderConfig, sel config.SourcesConfig, muted MuteLister, l *otel.Logger) (*ClarionProvider, error) {
+	var o ClarionOptions
+	if len(pc.Options) > 0 {
+		if err := json.Unmarshal(pc.Options, &o); err != nil {
+			return nil, fmt.Errorf("clarion options: %w", err)
+		}
+	}
+	p := NewClarionProvider(nil, o.FetchOptions(), l)
+	p.SetSelection(sel, muted)
+	return p, nil
 }
 
-// Fetch retrieves items from all configured Clarion sources.
+// SetSelection narrows which sources are fetched and applies per-source
+// overrides. Mutes are re-read on every fetch so muting from the TUI
+// takes effect on the next cycle.
+func (p *ClarionProvider) SetSelection(sel config.SourcesConfig, muted MuteLister) {
+	p.selection = sel
+	p.muted = muted
+}
+
+// Sources returns every source under the current selection, including
+// disabled ones with the reason they are skipped.
+func (p *ClarionProvider) Sources() []SourceState {
+	var muted []string
+	if p.muted != nil {
+		var err error
+		muted, err = p.muted.ListMutedSources()
+		if err != nil {
+			// Fetching a muted source is better than fetching nothing.
+			p.logger.Emit(otel.Event{Kind: otel.KindStoreError, Level: otel.LevelWarn, Comp: "fetch", Msg: "failed to list muted sources", Err: err.Error()})
+		}
+	}
+	return SelectSources(p.sources, p.selection, muted, p.opts)
+}
+
+// Fetch retrieves items from all selected Clarion sources. Sources with
+// per-source overrides are fetched alongside the rest, but SourceConcurrency
+// caps the fetch as a whole: every source takes a slot from one shared
+// budget, whatever options it has.
 func (p *ClarionProvider) Fetch(ctx context.Context) ([]store.Item, error) {
-	results := clarion.FetchWithOptions(ctx, p.opts, p.sources...)
+	var selected []SourceState
+	for _, st := range p.Sources() {
+		if st.Enabled {
+			selected = append(selected, st)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	concurrency := p.opts.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultSourceConcurrency
+	}
+	results := make([]clarion.Result, len(selected))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, st := range selected {
+		wg.Add(1)
+		go func(i int, st SourceState) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = clarion.Result{Source: st.Source, Err: ctx.Err()}
+				return
+			}
+			defer func() { <-slots }()
+
+			opts := st.Options
+			opts.MaxConcurrency = 1
+			results[i] = clarion.Result{Source: st.Source}
+			if r := p.fetch(ctx, opts, st.Source); len(r) > 0 {
+				results[i] = r[0]
+			}
+		}(i, st)
+	}
+	wg.Wait()
 
 	var items []store.Item
 	var errCount int
@@ -1,10 +1,15 @@
 package fetch
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/infblueocean/clarion"
+
+	"github.com/abelbrown/observer/internal/config"
 )
 
 func TestConvertItem_AllFields(t *testing.T) {
@@ -182,3 +187,52 @@ func TestNewClarionProvider_NilLogger(t *testing.T) {
 		t.Error("expected non-nil logger when nil passed (should use NullLogger)")
 	}
 }
+
+func TestClarionFetch_ConcurrencyAcrossOverrides(t *testing.T) {
+	var sources []clarion.Source
+	overrides := make(map[string]config.SourceOverride)
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("S%d", i)
+		sources = append(sources, clarion.Source{Name: name, Type: clarion.SourceTypeRSS})
+		overrides[name] = config.SourceOverride{MaxItems: i + 1} // every source its own options
+	}
+	p := NewClarionProvider(sources, clarion.FetchOptions{MaxConcurrency: 3, MaxItems: 50}, nil)
+	p.SetSelection(config.SourcesConfig{Overrides: overrides}, nil)
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	maxItems := make(map[string]int)
+	p.fetch = func(ctx context.Context, opts clarion.FetchOptions, srcs ...clarion.Source) []clarion.Result {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		for _, src := range srcs {
+			maxItems[src.Name] = opts.MaxItems
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		var out []clarion.Result
+		for _, src := range srcs {
+			out = append(out, clarion.Result{Source: src, Items: []clarion.Item{{ID: src.Name, Title: src.Name}}})
+		}
+		return out
+	}
+
+	items, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != len(sources) {
+		t.Errorf("got %d items, want one per source (%d)", len(items), len(sources))
+	}
+	if peak > 3 {
+		t.Errorf("%d sources fetched at once, want at most source_concurrency (3)", peak)
+	}
+	if maxItems["S4"] != 5 {
+		t.Errorf("S4 fetched with max_items %d, want its override 5", maxItems["S4"])
+	}
+}
@@ -0,0 +1,220 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// FeedLister supplies the user's feed subscriptions. *store.Store satisfies it.
+type FeedLister interface {
+	ListFeeds() ([]store.Feed, error)
+}
+
+// FeedOptions configure a FeedProvider. Also the provider-specific options
+// of a "feeds" entry in the config file. Zero values take defaults.
+type FeedOptions struct {
+	MaxConcurrency int             `json:"max_concurrency,omitempty"` // parallel feed fetches (default 10)
+	Timeout        config.Duration `json:"timeout,omitempty"`         // per-feed deadline (default 30s)
+	MaxItems       int             `json:"max_items,omitempty"`       // items kept per feed (default 50)
+}
+
+func (o FeedOptions) withDefaults() FeedOptions {
+	if o.MaxConcurrency <= 0 {
+		o.MaxConcurrency = 10
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = config.Duration(30 * time.Second)
+	}
+	if o.MaxItems <= 0 {
+		o.MaxItems = 50
+	}
+	return o
+}
+
+// FeedProvider fetches the user's own RSS, Atom and JSON Feed subscriptions.
+// The feed list is re-read on every fetch so additions take effect without
+// a restart.
+type FeedProvider struct {
+	feeds  FeedLister
+	opts   FeedOptions
+	client *http.Client
+	logger *otel.Logger
+}
+
+// NewFeedProvider creates a FeedProvider over the given feed list.
+func NewFeedProvider(feeds FeedLister, opts FeedOptions, l *otel.Logger) *FeedProvider {
+	if l == nil {
+		l = otel.NewNullLogger()
+	}
+	return &FeedProvider{
+		feeds:  feeds,
+		opts:   opts.withDefaults(),
+		client: &http.Client{},
+		logger: l,
+	}
+}
+
+// NewFeedProviderFromConfig creates a FeedProvider from a provider config entry.
+func NewFeedProviderFromConfig(feeds FeedLister, pc config.ProviderConfig, l *otel.Logger) (*FeedProvider, error) {
+	var o FeedOptions
+	if len(pc.Options) > 0 {
+		if err := json.Unmarshal(pc.Options, &o); err != nil {
+			return nil, fmt.Errorf("feeds options: %w", err)
+		}
+	}
+	return NewFeedProvider(feeds, o, l), nil
+}
+
+// Fetch retrieves items from every subscribed feed.
+// Returns an error only if the feed list is unreadable or every feed failed.
+func (p *FeedProvider) Fetch(ctx context.Context) ([]store.Item, error) {
+	feeds, err := p.feeds.ListFeeds()
+	if err != nil {
+		return nil, err
+	}
+	if len(feeds) == 0 {
+		return nil, nil
+	}
+
+	results := make([][]store.Item, len(feeds))
+	errs := make([]error, len(feeds))
+	sem := make(chan struct{}, p.opts.MaxConcurrency)
+
+	var wg sync.WaitGroup
+	for i, f := range feeds {
+		wg.Add(1)
+		go func(i int, f store.Feed) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			results[i], errs[i] = p.fetchFeed(ctx, f)
+		}(i, f)
+	}
+	wg.Wait()
+
+	var items []store.Item
+	var errCount int
+	for i, err := range errs {
+		if err != nil {
+			p.logger.Emit(otel.Event{Kind: otel.KindFetchError, Level: otel.LevelWarn, Comp: "fetch", Source: feeds[i].URL, Err: err.Error()})
+			errCount++
+			continue
+		}
+		items = append(items, results[i]...)
+	}
+
+	if errCount == len(feeds) {
+		return nil, fmt.Errorf("all %d feeds failed", errCount)
+	}
+	return items, nil
+}
+
+// fetchFeed downloads and parses one feed.
+func (p *FeedProvider) fetchFeed(ctx context.Context, f store.Feed) ([]store.Item, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.opts.Timeout))
+	defer cancel()
+
+	// gofeed.Parser keeps per-parse state, so use one per fetch.
+	parser := gofeed.NewParser()
+	parser.Client = p.client
+	parser.UserAgent = "Observer/1.0 (+https://github.com/abelbrown/observer)"
+
+	feed, err := parser.ParseURLWithContext(f.URL, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertFeed(feed, f, p.opts.MaxItems, time.Now()), nil
+}
+
+// convertFeed maps a parsed feed to store items, keeping at most maxItems.
+func convertFeed(feed *gofeed.Feed, f store.Feed, maxItems int, now time.Time) []store.Item {
+	sourceName := f.Title
+	if sourceName == "" {
+		sourceName = feed.Title
+	}
+	if sourceName == "" {
+		sourceName = f.URL
+	}
+	sourceType := feed.FeedType // "rss", "atom" or "json"
+	if sourceType == "" {
+		sourceType = "rss"
+	}
+
+	var items []store.Item
+	for _, fi := range feed.Items {
+		if maxItems > 0 && len(items) >= maxItems {
+			break
+		}
+		if fi == nil {
+			continue
+		}
+		items = append(items, convertFeedItem(fi, sourceType, sourceName, now))
+	}
+	return items
+}
+
+// convertFeedItem maps one parsed entry to a store item.
+// IDs hash the same fields as convertItem so a story seen through both
+// Clarion and a user feed dedups on insert.
+func convertFeedItem(fi *gofeed.Item, sourceType, sourceName string, now time.Time) store.Item {
+	link := fi.Link
+	if link == "" && len(fi.Links) > 0 {
+		link = fi.Links[0]
+	}
+
+	id := fi.GUID
+	if id == "" {
+		id = link
+	}
+	if id == "" {
+		id = fi.Title
+	}
+
+	summary := fi.Description
+	if summary == "" && fi.Content != "" {
+		summary = truncate(fi.Content, 500)
+	}
+
+	var author string
+	if fi.Author != nil {
+		author = fi.Author.Name
+	}
+	if author == "" && len(fi.Authors) > 0 && fi.Authors[0] != nil {
+		author = fi.Authors[0].Name
+	}
+
+	published := now
+	switch {
+	case fi.PublishedParsed != nil:
+		published = *fi.PublishedParsed
+	case fi.UpdatedParsed != nil:
+		published = *fi.UpdatedParsed
+	}
+
+	return store.Item{
+		ID:         hashString(id),
+		SourceType: sourceType,
+		SourceName: sourceName,
+		Title:      fi.Title,
+		Summary:    summary,
+		URL:        link,
+		Author:     author,
+		Published:  published,
+		Fetched:    now,
+	}
+}
@@ -0,0 +1,120 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>RSS Feed</title>
+<item><title>RSS one</title><link>https://example.com/r1</link><guid>r1</guid>
+<description>First</description><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+<item><title>RSS two</title><link>https://example.com/r2</link></item>
+</channel></rss>`
+
+const testAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom Feed</title>
+<entry><title>Atom one</title><link href="https://example.com/a1"/><id>a1</id>
+<updated>2006-01-02T15:04:05Z</updated><author><name>Ann</name></author></entry>
+</feed>`
+
+const testJSONFeed = `{"version": "https://jsonfeed.org/version/1.1", "title": "JSON Feed",
+"items": [{"id": "j1", "url": "https://example.com/j1", "title": "JSON one", "content_text": "Body"}]}`
+
+// staticFeeds is a FeedLister over a fixed slice.
+type staticFeeds []store.Feed
+
+func (s staticFeeds) ListFeeds() ([]store.Feed, error) { return s, nil }
+
+func newFeedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rss", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(testRSS)) })
+	mux.HandleFunc("/atom", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(testAtom)) })
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/feed+json")
+		w.Write([]byte(testJSONFeed))
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) { http.Error(w, "nope", http.StatusInternalServerError) })
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFeedProvider_AllFormats(t *testing.T) {
+	srv := newFeedServer(t)
+	feeds := staticFeeds{
+		{URL: srv.URL + "/rss"},
+		{URL: srv.URL + "/atom", Title: "My Atom"},
+		{URL: srv.URL + "/json"},
+		{URL: srv.URL + "/broken"},
+	}
+
+	items, err := NewFeedProvider(feeds, FeedOptions{}, nil).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("expected 4 items (broken feed isolated), got %d", len(items))
+	}
+
+	byTitle := map[string]store.Item{}
+	for _, item := range items {
+		byTitle[item.Title] = item
+	}
+
+	rss := byTitle["RSS one"]
+	if rss.SourceType != "rss" || rss.SourceName != "RSS Feed" || rss.Summary != "First" {
+		t.Errorf("unexpected RSS item: %+v", rss)
+	}
+	if rss.Published.Year() != 2006 {
+		t.Errorf("expected pubDate to be parsed, got %v", rss.Published)
+	}
+
+	atom := byTitle["Atom one"]
+	if atom.SourceType != "atom" || atom.SourceName != "My Atom" || atom.Author != "Ann" {
+		t.Errorf("unexpected Atom item: %+v", atom)
+	}
+
+	js := byTitle["JSON one"]
+	if js.SourceType != "json" || js.URL != "https://example.com/j1" {
+		t.Errorf("unexpected JSON Feed item: %+v", js)
+	}
+
+	if byTitle["RSS two"].Published.IsZero() {
+		t.Error("expected undated item to fall back to fetch time")
+	}
+}
+
+func TestFeedProvider_MaxItems(t *testing.T) {
+	srv := newFeedServer(t)
+	p := NewFeedProvider(staticFeeds{{URL: srv.URL + "/rss"}}, FeedOptions{MaxItems: 1}, nil)
+
+	items, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 1 {
+		t.Errorf("expected MaxItems to cap at 1, got %d", len(items))
+	}
+}
+
+func TestFeedProvider_AllFailed(t *testing.T) {
+	srv := newFeedServer(t)
+	p := NewFeedProvider(staticFeeds{{URL: srv.URL + "/broken"}}, FeedOptions{}, nil)
+
+	if _, err := p.Fetch(context.Background()); err == nil {
+		t.Error("expected error when every feed fails")
+	}
+}
+
+func TestFeedProvider_NoFeeds(t *testing.T) {
+	items, err := NewFeedProvider(staticFeeds{}, FeedOptions{}, nil).Fetch(context.Background())
+	if err != nil || len(items) != 0 {
+		t.Errorf("expected no items and no error, got %d items, err %v", len(items), err)
+	}
+}
@@ -0,0 +1,107 @@
+package fetch
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// opml is the subset of OPML 2.0 used for feed lists.
+type opml struct {
+	XMLName xml.Name    `xml:"opml"`
+	Version string      `xml:"version,attr"`
+	Head    opmlHead    `xml:"head"`
+	Body    opmlOutline `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr,omitempty"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Category string        `xml:"category,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ParseOPML reads feed subscriptions from an OPML document.
+// Feeds nested inside folder outlines take the folder name as their
+// category, which is how most readers export groups.
+func ParseOPML(r io.Reader) ([]store.Feed, error) {
+	var doc opml
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse OPML: %w", err)
+	}
+
+	var feeds []store.Feed
+	var walk func(outlines []opmlOutline, category string)
+	walk = func(outlines []opmlOutline, category string) {
+		for _, o := range outlines {
+			name := o.Title
+			if name == "" {
+				name = o.Text
+			}
+			if o.XMLURL == "" {
+				// Folder: children inherit its name as category.
+				walk(o.Outlines, name)
+				continue
+			}
+			cat := category
+			if o.Category != "" {
+				cat = strings.TrimPrefix(o.Category, "/")
+			}
+			feeds = append(feeds, store.Feed{URL: o.XMLURL, Title: name, Category: cat})
+			walk(o.Outlines, category)
+		}
+	}
+	walk(doc.Body.Outlines, "")
+	return feeds, nil
+}
+
+// WriteOPML writes feeds as an OPML 2.0 document, grouping them into
+// folder outlines by category.
+func WriteOPML(w io.Writer, title string, feeds []store.Feed) error {
+	doc := opml{
+		Version: "2.0",
+		Head:    opmlHead{Title: title, DateCreated: time.Now().UTC().Format(time.RFC1123Z)},
+	}
+
+	folders := make(map[string]int) // category → index in doc.Body.Outlines
+	for _, f := range feeds {
+		o := opmlOutline{Text: f.Title, Title: f.Title, Type: "rss", XMLURL: f.URL}
+		if o.Text == "" {
+			o.Text = f.URL
+		}
+		if f.Category == "" {
+			doc.Body.Outlines = append(doc.Body.Outlines, o)
+			continue
+		}
+		i, ok := folders[f.Category]
+		if !ok {
+			i = len(doc.Body.Outlines)
+			folders[f.Category] = i
+			doc.Body.Outlines = append(doc.Body.Outlines, opmlOutline{Text: f.Category, Title: f.Category})
+		}
+		doc.Body.Outlines[i].Outlines = append(doc.Body.Outlines[i].Outlines, o)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("write OPML: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,74 @@
+package fetch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+func TestParseOPML_Folders(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<opml version="2.0"><head><title>Subs</title></head><body>
+  <outline text="Loose" type="rss" xmlUrl="https://loose.example.com/feed"/>
+  <outline text="Tech">
+    <outline text="Alpha" title="Alpha Blog" type="rss" xmlUrl="https://alpha.example.com/rss"/>
+    <outline text="Beta" type="rss" xmlUrl="https://beta.example.com/atom" category="/science"/>
+  </outline>
+</body></opml>`
+
+	feeds, err := ParseOPML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ParseOPML: %v", err)
+	}
+	if len(feeds) != 3 {
+		t.Fatalf("expected 3 feeds, got %d", len(feeds))
+	}
+	if feeds[0].Category != "" || feeds[0].Title != "Loose" {
+		t.Errorf("unexpected top-level feed: %+v", feeds[0])
+	}
+	if feeds[1].Category != "Tech" || feeds[1].Title != "Alpha Blog" {
+		t.Errorf("expected folder category and title attr, got %+v", feeds[1])
+	}
+	if feeds[2].Category != "science" {
+		t.Errorf("expected category attr to override folder, got %+v", feeds[2])
+	}
+}
+
+func TestParseOPML_Invalid(t *testing.T) {
+	if _, err := ParseOPML(strings.NewReader("not xml")); err == nil {
+		t.Error("expected error for invalid OPML")
+	}
+}
+
+func TestWriteOPML_RoundTrip(t *testing.T) {
+	feeds := []store.Feed{
+		{URL: "https://a.example.com/rss", Title: "A", Category: "news"},
+		{URL: "https://b.example.com/rss", Title: "B"},
+		{URL: "https://c.example.com/rss", Title: "C", Category: "news"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOPML(&buf, "Observer feeds", feeds); err != nil {
+		t.Fatalf("WriteOPML: %v", err)
+	}
+
+	got, err := ParseOPML(&buf)
+	if err != nil {
+		t.Fatalf("ParseOPML: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 feeds after round trip, got %d", len(got))
+	}
+	byURL := map[string]store.Feed{}
+	for _, f := range got {
+		byURL[f.URL] = f
+	}
+	for _, want := range feeds {
+		f := byURL[want.URL]
+		if f.Title != want.Title || f.Category != want.Category {
+			t.Errorf("round trip mismatch for %s: got %+v", want.URL, f)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package fetch
+
+import (
+	"time"
+
+	"github.com/infblueocean/clarion"
+
+	"github.com/abelbrown/observer/internal/config"
+)
+
+// MuteLister supplies sources muted from the TUI. *store.Store satisfies it.
+type MuteLister interface {
+	ListMutedSources() ([]string, error)
+}
+
+// SourceState is one catalog source under the current selection.
+type SourceState struct {
+	Source  clarion.Source
+	Enabled bool
+	Reason  string               // why it is disabled; empty when enabled
+	Options clarion.FetchOptions // effective options after per-source overrides
+}
+
+// SelectSources applies the config selection and mutes to sources.
+// Order is preserved. Precedence: muted, then disable, then enable list.
+func SelectSources(sources []clarion.Source, sel config.SourcesConfig, muted []string, base clarion.FetchOptions) []SourceState {
+	mutedSet := make(map[string]bool, len(muted))
+	for _, name := range muted {
+		mutedSet[name] = true
+	}
+
+	states := make([]SourceState, len(sources))
+	for i, src := range sources {
+		st := SourceState{Source: src, Enabled: true, Options: base}
+		typ := string(src.Type)
+
+		switch {
+		case mutedSet[src.Name]:
+			st.Enabled, st.Reason = false, "muted"
+		case sel.Disable.Matches(src.Name, typ, src.Category) != "":
+			st.Enabled, st.Reason = false, "disabled by "+sel.Disable.Matches(src.Name, typ, src.Category)
+		case !sel.Enable.Empty() && sel.Enable.Matches(src.Name, typ, src.Category) == "":
+			st.Enabled, st.Reason = false, "not enabled"
+		}
+
+		if o, ok := sel.Overrides[src.Name]; ok {
+			if o.MaxItems > 0 {
+				st.Options.MaxItems = o.MaxItems
+			}
+			if o.Timeout > 0 {
+				st.Options.Timeout = time.Duration(o.Timeout)
+			}
+		}
+		states[i] = st
+	}
+	return states
+}
+
+// DisabledSourceNames returns the names of sources the selection skips.
+func DisabledSourceNames(states []SourceState) []string {
+	var names []string
+	for _, st := range states {
+		if !st.Enabled {
+			names = append(names, st.Source.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+package fetch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/infblueocean/clarion"
+
+	"github.com/abelbrown/observer/internal/config"
+)
+
+func testSources() []clarion.Source {
+	return []clarion.Source{
+		{Name: "Hacker News", Type: "hn", Category: "tech"},
+		{Name: "World Wire", Type: clarion.SourceTypeRSS, Category: "world"},
+		{Name: "Noisy Blog", Type: clarion.SourceTypeRSS, Category: "tech"},
+		{Name: "Sports Daily", Type: clarion.SourceTypeRSS, Category: "sports"},
+	}
+}
+
+func TestSelectSources(t *testing.T) {
+	sel := config.SourcesConfig{
+		Enable:  config.SourceMatch{Categories: []string{"tech", "world"}},
+		Disable: config.SourceMatch{Names: []string{"noisy blog"}},
+		Overrides: map[string]config.SourceOverride{
+			"Hacker News": {MaxItems: 100, Timeout: config.Duration(5 * time.Second)},
+		},
+	}
+	base := clarion.FetchOptions{MaxConcurrency: 10, Timeout: 30 * time.Second, MaxItems: 50}
+
+	states := SelectSources(testSources(), sel, []string{"World Wire"}, base)
+	if len(states) != 4 {
+		t.Fatalf("expected 4 states, got %d", len(states))
+	}
+
+	want := []struct {
+		enabled bool
+		reason  string
+	}{
+		{true, ""},
+		{false, "muted"},
+		{false, "disabled by name"},
+		{false, "not enabled"},
+	}
+	for i, w := range want {
+		if states[i].Enabled != w.enabled || states[i].Reason != w.reason {
+			t.Errorf("%s: enabled=%v reason=%q, want %v %q", states[i].Source.Name, states[i].Enabled, states[i].Reason, w.enabled, w.reason)
+		}
+	}
+
+	hn := states[0].Options
+	if hn.MaxItems != 100 || hn.Timeout != 5*time.Second || hn.MaxConcurrency != 10 {
+		t.Errorf("override not applied: %+v", hn)
+	}
+	if states[1].Options.MaxItems != 50 {
+		t.Errorf("expected base options for non-overridden source, got %+v", states[1].Options)
+	}
+
+	disabled := DisabledSourceNames(states)
+	if len(disabled) != 3 {
+		t.Errorf("expected 3 disabled names, got %v", disabled)
+	}
+}
+
+func TestSelectSources_EmptySelectionEnablesAll(t *testing.T) {
+	states := SelectSources(testSources(), config.SourcesConfig{}, nil, clarion.FetchOptions{})
+	for _, st := range states {
+		if !st.Enabled {
+			t.Errorf("%s disabled with empty selection (%s)", st.Source.Name, st.Reason)
+		}
+	}
+}
+
+// staticMutes is a MuteLister over a fixed slice.
+type staticMutes []string
+
+func (m staticMutes) ListMutedSources() ([]string, error) { return m, nil }
+
+func TestClarionProvider_SourcesHonoursMutes(t *testing.T) {
+	p := NewClarionProvider(testSources(), clarion.FetchOptions{}, nil)
+	p.SetSelection(config.SourcesConfig{}, staticMutes{"Sports Daily"})
+
+	var enabled int
+	for _, st := range p.Sources() {
+		if st.Enabled {
+			enabled++
+		} else if st.Source.Name != "Sports Daily" {
+			t.Errorf("unexpected disabled source %s", st.Source.Name)
+		}
+	}
+	if enabled != 3 {
+		t.Errorf("expected 3 enabled sources, got %d", enabled)
+	}
+}
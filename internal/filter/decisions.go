@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// Decision records what a pipeline stage did with one item.
+type Decision struct {
+	Item   store.Item
+	Stage  string // the stage's name
+	Kept   bool
+	Reason string
+
+	// DuplicateOf is the ID of the kept item this one duplicated (dedup
+	// stages), Similarity their cosine (1 for the same URL or title).
+	DuplicateOf string
+	Similarity  float32
+}
+
+// Trace collects the pipeline's decisions when set as Env.Trace. Every
+// item a stage drops gets a decision; kept items only get one when
+// something notable happened (an allow rule overriding a mute). An item
+// without a dropped decision survived.
+type Trace struct {
+	Decisions []Decision
+}
+
+// Dropped returns the decisions that dropped items, in pipeline order.
+func (t *Trace) Dropped() []Decision {
+	return t.filter(func(d Decision) bool { return !d.Kept })
+}
+
+// DroppedBy returns the items the named stage dropped.
+func (t *Trace) DroppedBy(stage string) []Decision {
+	return t.filter(func(d Decision) bool { return !d.Kept && d.Stage == stage })
+}
+
+func (t *Trace) filter(keep func(Decision) bool) []Decision {
+	if t == nil {
+		return nil
+	}
+	var out []Decision
+	for _, d := range t.Decisions {
+		if keep(d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// dropFunc receives the decisions of a traced filter; nil when untraced.
+type dropFunc func(d Decision)
+
+// recorder returns a dropFunc recording into e.Trace under stage, or nil
+// if e isn't tracing.
+func (e *Env) recorder(stage string) dropFunc {
+	if e == nil || e.Trace == nil {
+		return nil
+	}
+	return func(d Decision) {
+		d.Stage = stage
+		e.Trace.Decisions = append(e.Trace.Decisions, d)
+	}
+}
+
+// settle records a generic decision for each item of in missing from out
+// that stage dropped without saying why (registered stages need not
+// report), recorded being the trace length before the stage ran.
+func (e *Env) settle(stage string, in, out []store.Item, recorded int) {
+	if e == nil || e.Trace == nil || len(out) == len(in) && len(e.Trace.Decisions) == recorded {
+		return
+	}
+	seen := make(map[string]bool, len(out))
+	for _, item := range out {
+		seen[item.ID] = true
+	}
+	for _, d := range e.Trace.Decisions[recorded:] {
+		seen[d.Item.ID] = seen[d.Item.ID] || !d.Kept
+	}
+	record := e.recorder(stage)
+	for _, item := range in {
+		if !seen[item.ID] {
+			record(Decision{Item: item, Reason: "dropped by " + stage})
+		}
+	}
+}
+
+// ExcludeMuted is ExcludeSources for the sources muted from the TUI,
+// recording its drops in env's trace (if any) as stage "muted sources".
+func ExcludeMuted(items []store.Item, sources []string, env *Env) []store.Item {
+	record := env.recorder("muted sources")
+	if record == nil {
+		return excludeSources(items, sources, nil)
+	}
+	return excludeSources(items, sources, func(d Decision) {
+		d.Reason = fmt.Sprintf("source %q is muted", d.Item.SourceName)
+		record(d)
+	})
+}
+
+// shortDuration formats d the way the feed shows ages: "45m", "26h", "3d".
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 72*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,108 @@
+package filter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+func TestPipelineTrace(t *testing.T) {
+	r := NewRegistry()
+	r.Register("drop_b", func(json.RawMessage) (StageFunc, error) {
+		return func(items []store.Item, env *Env) []store.Item {
+			var out []store.Item
+			for _, item := range items {
+				if item.SourceName != "b" {
+					out = append(out, item)
+				}
+			}
+			return out
+		}, nil
+	})
+	p, err := r.Build([]config.StageConfig{
+		{Stage: "max_age", Options: json.RawMessage(`{"max_age": "24h"}`)},
+		{Stage: "rules"},
+		{Stage: "dedup"},
+		{Stage: "semantic_dedup", Name: "near", Options: json.RawMessage(`{"threshold": 0.9}`)},
+		{Stage: "limit_per_source", Options: json.RawMessage(`{"max": 1}`)},
+		{Stage: "drop_b"},
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	now := time.Now()
+	items := []store.Item{
+		{ID: "1", Title: "Rates held", URL: "u1", SourceName: "a", Published: now.Add(-time.Minute)},
+		{ID: "2", Title: "Rates held", URL: "u2", SourceName: "c", Published: now.Add(-2 * time.Minute)},
+		{ID: "3", Title: "Central bank keeps rates", URL: "u3", SourceName: "d", Published: now.Add(-3 * time.Minute)},
+		{ID: "4", Title: "Crypto slump", URL: "u4", SourceName: "e", Published: now.Add(-4 * time.Minute)},
+		{ID: "5", Title: "Crypto explainer", URL: "u5", SourceName: "f", Author: "Jane", Published: now.Add(-5 * time.Minute)},
+		{ID: "6", Title: "Second from a", URL: "u6", SourceName: "a", Published: now.Add(-6 * time.Minute)},
+		{ID: "7", Title: "From b", URL: "u7", SourceName: "b", Published: now.Add(-7 * time.Minute)},
+		{ID: "8", Title: "Old news", URL: "u8", SourceName: "g", Published: now.Add(-30 * time.Hour)},
+	}
+	rs, _ := NewRuleSet([]store.Rule{
+		{ID: 1, Kind: store.RuleKeyword, Action: store.RuleMute, Pattern: "crypto"},
+		{ID: 2, Kind: store.RuleAuthor, Action: store.RuleAllow, Pattern: "Jane"},
+	}, "")
+	env := &Env{
+		Rules:      rs,
+		Embeddings: map[string][]float32{"1": {1, 0}, "3": {0.99, 0.1}, "5": {0, 1}},
+		Trace:      &Trace{},
+	}
+	kept := p.Run(items, env)
+	if got := strings.Join(idsOf(kept), ","); got != "1,5" {
+		t.Fatalf("kept %s, want 1,5", got)
+	}
+
+	want := map[string]struct{ stage, reason, dupOf string }{
+		"8": {"max_age", "older than 24h (published 30h ago)", ""},
+		"4": {"rules", `muted by rule 1 (keyword "crypto")`, ""},
+		"2": {"dedup", `same title as "Rates held"`, "1"},
+		"3": {"near", `near-duplicate of "Rates held" (0.99)`, "1"},
+		"6": {"limit_per_source", "not among the 1 newest from a", ""},
+		"7": {"drop_b", "dropped by drop_b", ""},
+	}
+	dropped := env.Trace.Dropped()
+	if len(dropped) != len(want) {
+		t.Errorf("%d drops recorded, want %d: %+v", len(dropped), len(want), dropped)
+	}
+	for _, d := range dropped {
+		w, ok := want[d.Item.ID]
+		if !ok || d.Stage != w.stage || d.Reason != w.reason || d.DuplicateOf != w.dupOf {
+			t.Errorf("item %s: stage %q, reason %q, duplicate of %q; want %+v", d.Item.ID, d.Stage, d.Reason, d.DuplicateOf, w)
+		}
+	}
+	if d := env.Trace.DroppedBy("near"); len(d) != 1 || d[0].Similarity < 0.99 {
+		t.Errorf("DroppedBy(near) = %+v", d)
+	}
+
+	var allowed []Decision
+	for _, d := range env.Trace.Decisions {
+		if d.Kept {
+			allowed = append(allowed, d)
+		}
+	}
+	if len(allowed) != 1 || allowed[0].Item.ID != "5" || !strings.Contains(allowed[0].Reason, `allowed by rule 2 (author "Jane")`) {
+		t.Errorf("kept decisions = %+v", allowed)
+	}
+}
+
+func TestExcludeMuted(t *testing.T) {
+	items := []store.Item{{ID: "1", SourceName: "a"}, {ID: "2", SourceName: "b"}}
+
+	if got := ExcludeMuted(items, []string{"b"}, &Env{}); len(got) != 1 {
+		t.Errorf("untraced: kept %v", idsOf(got))
+	}
+	env := &Env{Trace: &Trace{}}
+	ExcludeMuted(items, []string{"b"}, env)
+	d := env.Trace.Dropped()
+	if len(d) != 1 || d[0].Item.ID != "2" || d[0].Stage != "muted sources" || d[0].Reason != `source "b" is muted` {
+		t.Errorf("decisions = %+v", d)
+	}
+}
@@ -4,6 +4,7 @@ package filter
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -30,6 +31,10 @@ var commonPrefixes = []string{
 
 // ByAge removes items older than maxAge based on Published time.
 func ByAge(items []store.Item, maxAge time.Duration) []store.Item {
+	return byAge(items, maxAge, nil)
+}
+
+func byAge(items []store.Item, maxAge time.Duration, drop dropFunc) []store.Item {
 	if len(items) == 0 {
 		return []store.Item{}
 	}
@@ -40,6 +45,8 @@ func ByAge(items []store.Item, maxAge time.Duration) []store.Item {
 	for _, item := range items {
 		if item.Published.After(cutoff) {
 			result = append(result, item)
+		} else if drop != nil {
+			drop(Decision{Item: item, Reason: fmt.Sprintf("older than %s (published %s ago)", shortDuration(maxAge), shortDuration(time.Since(item.Published)))})
 		}
 	}
 
@@ -68,6 +75,34 @@ func BySource(items []store.Item, sources []string) []store.Item {
 	return result
 }
 
+// ExcludeSources drops items from the specified source names.
+// Returns items unchanged if sources is empty.
+func ExcludeSources(items []store.Item, sources []string) []store.Item {
+	return excludeSources(items, sources, nil)
+}
+
+func excludeSources(items []store.Item, sources []string, drop dropFunc) []store.Item {
+	if len(sources) == 0 {
+		return items
+	}
+
+	excluded := make(map[string]bool, len(sources))
+	for _, s := range sources {
+		excluded[s] = true
+	}
+
+	result := make([]store.Item, 0, len(items))
+	for _, item := range items {
+		if !excluded[item.SourceName] {
+			result = append(result, item)
+		} else if drop != nil {
+			drop(Decision{Item: item, Reason: fmt.Sprintf("source %q excluded", item.SourceName)})
+		}
+	}
+
+	return result
+}
+
 // normalizeTitle normalizes a title for comparison by lowercasing and
 // removing common news prefixes.
 func normalizeTitle(title string) string {
@@ -88,32 +123,43 @@ func normalizeTitle(title string) string {
 // Also removes items with very similar titles (case-insensitive, ignoring
 // common prefixes like "Breaking:", "Update:", etc.)
 func Dedup(items []store.Item) []store.Item {
+	return dedup(items, nil)
+}
+
+func dedup(items []store.Item, drop dropFunc) []store.Item {
 	if len(items) == 0 {
 		return []store.Item{}
 	}
 
-	seenURLs := make(map[string]bool)
-	seenTitles := make(map[string]bool)
+	// First occurrence by URL and by normalized title
+	seenURLs := make(map[string]store.Item)
+	seenTitles := make(map[string]store.Item)
 	result := make([]store.Item, 0, len(items))
 
 	for _, item := range items {
 		// Check URL deduplication
-		if item.URL != "" && seenURLs[item.URL] {
+		if first, ok := seenURLs[item.URL]; ok && item.URL != "" {
+			if drop != nil {
+				drop(duplicate(item, first, "same URL as", 1))
+			}
 			continue
 		}
 
 		// Check title deduplication
 		normalizedTitle := normalizeTitle(item.Title)
-		if normalizedTitle != "" && seenTitles[normalizedTitle] {
+		if first, ok := seenTitles[normalizedTitle]; ok && normalizedTitle != "" {
+			if drop != nil {
+				drop(duplicate(item, first, "same title as", 1))
+			}
 			continue
 		}
 
 		// Mark as seen
 		if item.URL != "" {
-			seenURLs[item.URL] = true
+			seenURLs[item.URL] = item
 		}
 		if normalizedTitle != "" {
-			seenTitles[normalizedTitle] = true
+			seenTitles[normalizedTitle] = item
 		}
 
 		result = append(result, item)
@@ -126,6 +172,10 @@ func Dedup(items []store.Item) []store.Item {
 // Keeps the most recent items (by Published time) for each source.
 // The result is sorted by Published DESC to ensure deterministic order.
 func LimitPerSource(items []store.Item, maxPerSource int) []store.Item {
+	return limitPerSource(items, maxPerSource, nil)
+}
+
+func limitPerSource(items []store.Item, maxPerSource int, drop dropFunc) []store.Item {
 	if len(items) == 0 || maxPerSource <= 0 {
 		return []store.Item{}
 	}
@@ -150,6 +200,11 @@ func LimitPerSource(items []store.Item, maxPerSource int) []store.Item {
 			limit = len(sourceItems)
 		}
 		result = append(result, sourceItems[:limit]...)
+		if drop != nil {
+			for _, item := range sourceItems[limit:] {
+				drop(Decision{Item: item, Reason: fmt.Sprintf("not among the %d newest from %s", maxPerSource, item.SourceName)})
+			}
+		}
 	}
 
 	// Sort final result by Published DESC for deterministic order
@@ -161,11 +216,17 @@ func LimitPerSource(items []store.Item, maxPerSource int) []store.Item {
 	return result
 }
 
-// RerankByQuery reranks items by cosine similarity to a query embedding.
+// RerankByQuery reranks items by cosine similarity to one or more query
+// vectors. An item scores its best match between any query vector and any
+// of its own vectors: the document embedding and, for long items, its
+// chunks (max-sim), so a relevant passage deep in a long article counts.
+// More-like-this passes the seed's document and chunk vectors as queries.
+// chunks may be nil.
 // Items with embeddings are sorted by similarity (highest first).
 // Items without embeddings are placed at the end, maintaining their original order.
-func RerankByQuery(items []store.Item, embeddings map[string][]float32, queryEmbedding []float32) []store.Item {
-	if len(items) == 0 || len(queryEmbedding) == 0 || embeddings == nil {
+func RerankByQuery(items []store.Item, embeddings map[string][]float32, chunks map[string][]store.Chunk, queries ...[]float32) []store.Item {
+	queries = nonEmpty(queries)
+	if len(items) == 0 || len(queries) == 0 || embeddings == nil {
 		return items
 	}
 
@@ -179,7 +240,7 @@ func RerankByQuery(items []store.Item, embeddings map[string][]float32, queryEmb
 	for _, item := range items {
 		s := scored{item: item}
 		if emb, ok := embeddings[item.ID]; ok && len(emb) > 0 {
-			s.similarity = embed.CosineSimilarity(emb, queryEmbedding)
+			s.similarity, _ = MaxSim(queries, emb, chunks[item.ID])
 			s.hasEmbed = true
 		}
 		scoredItems = append(scoredItems, s)
@@ -207,6 +268,62 @@ func RerankByQuery(items []store.Item, embeddings map[string][]float32, queryEmb
 	return result
 }
 
+// MaxSim returns the highest cosine similarity between any query vector
+// and an item's vectors (its document embedding emb and its chunks), and
+// the index in chunks of the best-matching chunk (-1 if there are none).
+func MaxSim(queries [][]float32, emb []float32, chunks []store.Chunk) (float32, int) {
+	best, bestChunk := float32(-1), -1
+	var bestChunkSim float32
+	for _, q := range queries {
+		if len(q) == 0 {
+			continue
+		}
+		if len(emb) > 0 {
+			if sim := embed.CosineSimilarity(emb, q); sim > best {
+				best = sim
+			}
+		}
+		for i, c := range chunks {
+			sim := embed.CosineSimilarity(c.Embedding, q)
+			if bestChunk < 0 || sim > bestChunkSim {
+				bestChunk, bestChunkSim = i, sim
+			}
+		}
+	}
+	if bestChunk >= 0 && bestChunkSim > best {
+		best = bestChunkSim
+	}
+	return best, bestChunk
+}
+
+// MatchingPassages returns, for each item with chunks, the passage that
+// best matches the queries: the "why this matched" snippet for search
+// results.
+func MatchingPassages(items []store.Item, chunks map[string][]store.Chunk, queries ...[]float32) map[string]string {
+	queries = nonEmpty(queries)
+	if len(chunks) == 0 || len(queries) == 0 {
+		return nil
+	}
+	passages := make(map[string]string)
+	for _, item := range items {
+		if _, i := MaxSim(queries, nil, chunks[item.ID]); i >= 0 {
+			passages[item.ID] = chunks[item.ID][i].Text
+		}
+	}
+	return passages
+}
+
+// nonEmpty drops empty query vectors.
+func nonEmpty(queries [][]float32) [][]float32 {
+	var out [][]float32
+	for _, q := range queries {
+		if len(q) > 0 {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
 // RerankByCrossEncoder reranks items using a cross-encoder reranker model.
 // Takes the top N candidates and scores them against the query using the reranker.
 // Returns items sorted by relevance score (highest first).
@@ -271,47 +388,40 @@ type Score struct {
 // SemanticDedup removes semantically similar items using embeddings.
 // Uses cosine similarity with threshold (e.g., 0.85).
 // Falls back to URL dedup if embeddings unavailable for an item.
-// First occurrence wins.
+// First occurrence wins. See NearDuplicates for the clusters.
 func SemanticDedup(items []store.Item, embeddings map[string][]float32, threshold float32) []store.Item {
-	if len(items) == 0 {
-		return []store.Item{}
-	}
-
-	seenURLs := make(map[string]bool)
-	var seenEmbeddings [][]float32
-
-	result := make([]store.Item, 0, len(items))
+	return clusterHeads(NearDuplicates(items, embeddings, threshold), nil)
+}
 
-	for _, item := range items {
-		// URL dedup (always)
-		if item.URL != "" && seenURLs[item.URL] {
+// clusterHeads returns each cluster's kept item, reporting the rest to
+// drop as its duplicates.
+func clusterHeads(clusters []Cluster, drop dropFunc) []store.Item {
+	result := make([]store.Item, 0, len(clusters))
+	for _, c := range clusters {
+		result = append(result, c.Item)
+		if drop == nil {
 			continue
 		}
-
-		// Semantic dedup (if embedding available)
-		if emb, ok := embeddings[item.ID]; ok {
-			isDup := false
-			for _, seen := range seenEmbeddings {
-				if embed.CosineSimilarity(emb, seen) > threshold {
-					isDup = true
-					break
-				}
-			}
-			if isDup {
-				continue
+		for _, s := range c.Similar {
+			if s.Item.URL != "" && s.Item.URL == c.Item.URL {
+				drop(duplicate(s.Item, c.Item, "same URL as", 1))
+			} else {
+				drop(duplicate(s.Item, c.Item, "near-duplicate of", s.Similarity))
 			}
-			seenEmbeddings = append(seenEmbeddings, emb)
 		}
-
-		if item.URL != "" {
-			seenURLs[item.URL] = true
-		}
-		result = append(result, item)
 	}
-
 	return result
 }
 
+// duplicate is the decision dropping item as a duplicate of first.
+func duplicate(item, first store.Item, what string, similarity float32) Decision {
+	reason := fmt.Sprintf("%s %q", what, first.Title)
+	if similarity < 1 {
+		reason += fmt.Sprintf(" (%.2f)", similarity)
+	}
+	return Decision{Item: item, Reason: reason, DuplicateOf: first.ID, Similarity: similarity}
+}
+
 // CosineSimilarity calculates the cosine similarity between two vectors.
 // Wrapper around embed.CosineSimilarity for convenience.
 func CosineSimilarity(a, b []float32) float32 {
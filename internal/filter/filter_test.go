@@ -80,6 +80,24 @@ func TestBySource(t *testing.T) {
 	}
 }
 
+func TestExcludeSources(t *testing.T) {
+	items := []store.Item{
+		{ID: "1", Title: "Item 1", SourceName: "TechNews"},
+		{ID: "2", Title: "Item 2", SourceName: "SportsFeed"},
+		{ID: "3", Title: "Item 3", SourceName: "TechNews"},
+	}
+
+	result := ExcludeSources(items, []string{"TechNews"})
+	if len(result) != 1 || result[0].ID != "2" {
+		t.Errorf("expected only item 2, got %v", result)
+	}
+
+	// Nil sources list keeps everything
+	if got := ExcludeSources(items, nil); len(got) != 3 {
+		t.Errorf("expected 3 items with no exclusions, got %d", len(got))
+	}
+}
+
 func TestBySourceEmpty(t *testing.T) {
 	items := []store.Item{
 		{ID: "1", Title: "Item 1", SourceName: "TechNews"},
@@ -528,7 +546,7 @@ func TestRerankByQuery(t *testing.T) {
 	// Query embedding close to items 1 and 4
 	queryEmbedding := []float32{1.0, 0.0, 0.0}
 
-	result := RerankByQuery(items, embeddings, queryEmbedding)
+	result := RerankByQuery(items, embeddings, nil, queryEmbedding)
 
 	if len(result) != 4 {
 		t.Fatalf("expected 4 items (all items returned), got %d", len(result))
@@ -556,7 +574,7 @@ func TestRerankByQueryNoEmbeddings(t *testing.T) {
 
 	queryEmbedding := []float32{1.0, 0.0, 0.0}
 
-	result := RerankByQuery(items, embeddings, queryEmbedding)
+	result := RerankByQuery(items, embeddings, nil, queryEmbedding)
 
 	if len(result) != 3 {
 		t.Fatalf("expected 3 items, got %d", len(result))
@@ -584,7 +602,7 @@ func TestRerankByQueryMixedEmbeddings(t *testing.T) {
 
 	queryEmbedding := []float32{1.0, 0.0, 0.0}
 
-	result := RerankByQuery(items, embeddings, queryEmbedding)
+	result := RerankByQuery(items, embeddings, nil, queryEmbedding)
 
 	if len(result) != 4 {
 		t.Fatalf("expected 4 items, got %d", len(result))
@@ -629,7 +647,7 @@ func TestRerankByQueryEmptyQuery(t *testing.T) {
 	}
 
 	// Empty query embedding
-	result := RerankByQuery(items, embeddings, []float32{})
+	result := RerankByQuery(items, embeddings, nil, []float32{})
 
 	// Should return items unchanged
 	if len(result) != 2 {
@@ -640,7 +658,7 @@ func TestRerankByQueryEmptyQuery(t *testing.T) {
 	}
 
 	// Nil query embedding
-	result = RerankByQuery(items, embeddings, nil)
+	result = RerankByQuery(items, embeddings, nil, nil)
 	if len(result) != 2 {
 		t.Fatalf("expected 2 items, got %d", len(result))
 	}
@@ -650,17 +668,52 @@ func TestRerankByQueryEmptyItems(t *testing.T) {
 	embeddings := map[string][]float32{}
 	queryEmbedding := []float32{1.0, 0.0, 0.0}
 
-	result := RerankByQuery([]store.Item{}, embeddings, queryEmbedding)
+	result := RerankByQuery([]store.Item{}, embeddings, nil, queryEmbedding)
 	if len(result) != 0 {
 		t.Errorf("expected 0 items, got %d", len(result))
 	}
 
-	result = RerankByQuery(nil, embeddings, queryEmbedding)
+	result = RerankByQuery(nil, embeddings, nil, queryEmbedding)
 	if result != nil {
 		t.Error("expected nil for nil input")
 	}
 }
 
+func TestRerankByQueryMaxSimOverChunks(t *testing.T) {
+	items := []store.Item{
+		{ID: "short", Title: "Short"},
+		{ID: "long", Title: "Long"},
+	}
+	embeddings := map[string][]float32{
+		"short": {0.6, 0.8},
+		"long":  {0.0, 1.0}, // the opening is about something else
+	}
+	chunks := map[string][]store.Chunk{
+		"long": {
+			{Index: 0, Text: "opening", Embedding: []float32{0, 1}},
+			{Index: 1, Text: "the relevant passage", Embedding: []float32{1, 0}},
+		},
+	}
+	query := []float32{1, 0}
+
+	if got := RerankByQuery(items, embeddings, nil, query); got[0].ID != "short" {
+		t.Fatalf("without chunks the short item should win, got %s", got[0].ID)
+	}
+	if got := RerankByQuery(items, embeddings, chunks, query); got[0].ID != "long" {
+		t.Errorf("a matching chunk should lift the long item, got %s", got[0].ID)
+	}
+
+	passages := MatchingPassages(items, chunks, query)
+	if len(passages) != 1 || passages["long"] != "the relevant passage" {
+		t.Errorf("MatchingPassages() = %v", passages)
+	}
+
+	// More-like-this: several query vectors, best pair wins.
+	if sim, best := MaxSim([][]float32{{0, 1}, {1, 0}}, embeddings["short"], nil); sim < 0.79 || best != -1 {
+		t.Errorf("MaxSim() = %v, %d; want 0.8 from the document vector", sim, best)
+	}
+}
+
 // mockReranker implements Reranker for testing.
 type mockReranker struct {
 	available bool
@@ -0,0 +1,354 @@
+package filter
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// Similar is an item folded into a Cluster as a near-duplicate.
+type Similar struct {
+	Item       store.Item
+	Similarity float32 // cosine to the cluster's item; 1 for the same URL
+}
+
+// Cluster is an item semantic dedup kept, with the later items that
+// duplicated it.
+type Cluster struct {
+	Item    store.Item
+	Similar []Similar
+}
+
+// Random-hyperplane LSH parameters. Each vector is sketched by the signs
+// of its projections onto lshPlanes sparse random hyperplanes; each table
+// buckets by lshBits of the sketch, picked at random, and enough tables
+// are used that a pair at the threshold shares a bucket in at least one
+// with probability about lshRecall. Candidates whose sketches differ in
+// more bits than a pair at the threshold would (by six standard
+// deviations) are skipped; the rest are verified with the exact cosine,
+// so LSH only ever costs a missed pair, never a false one.
+const (
+	lshExactMax  = 512 // up to this many vectors, compare every pair
+	lshPlanes    = 256
+	lshBits      = 12
+	lshMaxTables = 128
+	lshPlaneDims = 32 // non-zero components per hyperplane
+	lshRecall    = 0.999
+	lshSeed      = 0x0b5e7e
+)
+
+// NearDuplicates groups items the way SemanticDedup decides: walking
+// items in order, an item joins the cluster of the first kept item with
+// the same URL or a vector more similar than threshold, else it starts
+// its own. Items without a vector only match by URL. Returns one cluster
+// per kept item, in order.
+//
+// Above lshExactMax vectors the similar kept items are found by
+// random-hyperplane LSH rather than by comparing against every one, so
+// 50k items take seconds instead of many minutes.
+func NearDuplicates(items []store.Item, embeddings map[string][]float32, threshold float32) []Cluster {
+	if len(items) == 0 {
+		return []Cluster{}
+	}
+
+	var index vectorIndex = &exactIndex{}
+	if n := countEmbedded(items, embeddings); n > lshExactMax {
+		index = newLSHIndex(items, embeddings, threshold)
+	}
+	return nearDuplicates(items, embeddings, threshold, index)
+}
+
+func nearDuplicates(items []store.Item, embeddings map[string][]float32, threshold float32, index vectorIndex) []Cluster {
+	clusters := make([]Cluster, 0, len(items))
+	byURL := make(map[string]int) // URL -> cluster
+	var heads []int               // index vector -> cluster
+
+	for _, item := range items {
+		// URL dedup (always)
+		if c, ok := byURL[item.URL]; ok && item.URL != "" {
+			clusters[c].Similar = append(clusters[c].Similar, Similar{Item: item, Similarity: 1})
+			continue
+		}
+
+		// Semantic dedup (if embedding available)
+		if emb, ok := embeddings[item.ID]; ok {
+			if k, sim := index.match(emb, threshold); k >= 0 {
+				c := heads[k]
+				clusters[c].Similar = append(clusters[c].Similar, Similar{Item: item, Similarity: sim})
+				continue
+			}
+			index.add(emb)
+			heads = append(heads, len(clusters))
+		}
+
+		if item.URL != "" {
+			byURL[item.URL] = len(clusters)
+		}
+		clusters = append(clusters, Cluster{Item: item})
+	}
+	return clusters
+}
+
+func countEmbedded(items []store.Item, embeddings map[string][]float32) int {
+	n := 0
+	for _, item := range items {
+		if _, ok := embeddings[item.ID]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+// vectorIndex holds the vectors of kept items, in the order added.
+type vectorIndex interface {
+	// match returns the first added vector more similar to v than
+	// threshold, with the similarity, or -1.
+	match(v []float32, threshold float32) (int, float32)
+	add(v []float32)
+}
+
+// exactIndex compares against every vector.
+type exactIndex struct {
+	vectors [][]float32
+}
+
+func (x *exactIndex) match(v []float32, threshold float32) (int, float32) {
+	for i, seen := range x.vectors {
+		if sim := embed.CosineSimilarity(v, seen); sim > threshold {
+			return i, sim
+		}
+	}
+	return -1, 0
+}
+
+func (x *exactIndex) add(v []float32) {
+	x.vectors = append(x.vectors, v)
+}
+
+// lshIndex buckets vectors by their signatures in each table and only
+// compares against vectors sharing a bucket.
+type lshIndex struct {
+	dim     int
+	planes  []hyperplane // lshPlanes
+	offsets []float32    // each plane's projection of the mean vector
+	bits    [][]int      // per table, the sketch bits of its signature
+	tables  []map[uint32][]int32
+	vectors [][]float32 // as added, for the exact cosine
+	units   [][]float32 // normalized, for the quick one
+
+	sketches   []sketch // as added
+	maxHamming int
+
+	// seen marks candidates already compared for the current query.
+	seen  []uint32
+	query uint32
+
+	// Scratch for the vector being matched or added.
+	sigs   []uint32
+	sketch sketch
+}
+
+// sketch holds one bit per hyperplane: the side a vector falls on.
+type sketch [lshPlanes / 64]uint64
+
+func (s sketch) bit(i int) bool {
+	return s[i/64]&(1<<(i%64)) != 0
+}
+
+func hamming(a, b sketch) int {
+	n := 0
+	for i := range a {
+		n += bits.OnesCount64(a[i] ^ b[i])
+	}
+	return n
+}
+
+// hyperplane is a sparse random hyperplane through the origin: ±1 at a
+// few components.
+type hyperplane struct {
+	dims  []int32
+	signs []float32
+}
+
+func (h hyperplane) project(v []float32) float32 {
+	var sum float32
+	for i, d := range h.dims {
+		sum += h.signs[i] * v[d]
+	}
+	return sum
+}
+
+// newLSHIndex sizes an index for items' vectors at threshold. The
+// hyperplanes pass through the vectors' mean rather than the origin:
+// embeddings share a large common component, and centering spreads
+// unrelated items across buckets.
+func newLSHIndex(items []store.Item, embeddings map[string][]float32, threshold float32) *lshIndex {
+	dim := 0
+	for _, item := range items {
+		if emb := embeddings[item.ID]; len(emb) > 0 {
+			dim = len(emb)
+			break
+		}
+	}
+	x := &lshIndex{dim: dim}
+	if dim == 0 {
+		return x
+	}
+
+	// Tables for the recall target: a pair at the threshold agrees on a
+	// hyperplane with probability 1 - angle/π.
+	p := 1 - math.Acos(math.Max(-1, math.Min(1, float64(threshold))))/math.Pi
+	x.maxHamming = int(math.Ceil(lshPlanes*(1-p) + 6*math.Sqrt(lshPlanes*p*(1-p))))
+	tables := lshMaxTables
+	if collide := math.Pow(p, lshBits); collide < 1 {
+		tables = int(math.Ceil(math.Log(1-lshRecall) / math.Log(1-collide)))
+	}
+	tables = max(1, min(tables, lshMaxTables))
+
+	rng := rand.New(rand.NewPCG(lshSeed, uint64(dim)))
+	nonZero := min(dim, lshPlaneDims)
+	x.planes = make([]hyperplane, lshPlanes)
+	for i := range x.planes {
+		h := hyperplane{dims: make([]int32, nonZero), signs: make([]float32, nonZero)}
+		for j := range h.dims {
+			h.dims[j] = int32(rng.IntN(dim))
+			h.signs[j] = 1
+			if rng.IntN(2) == 0 {
+				h.signs[j] = -1
+			}
+		}
+		x.planes[i] = h
+	}
+	x.bits = make([][]int, tables)
+	for t := range x.bits {
+		x.bits[t] = rng.Perm(lshPlanes)[:lshBits]
+	}
+
+	mean := make([]float32, dim)
+	n := 0
+	for _, item := range items {
+		emb := embeddings[item.ID]
+		if len(emb) != dim {
+			continue
+		}
+		for i, v := range emb {
+			mean[i] += v
+		}
+		n++
+	}
+	for i := range mean {
+		mean[i] /= float32(n)
+	}
+	x.offsets = make([]float32, len(x.planes))
+	for i, h := range x.planes {
+		x.offsets[i] = h.project(mean)
+	}
+
+	x.tables = make([]map[uint32][]int32, tables)
+	for t := range x.tables {
+		x.tables[t] = make(map[uint32][]int32)
+	}
+	x.sigs = make([]uint32, tables)
+	return x
+}
+
+// signatures returns v's bucket in each table, and leaves its sketch in
+// x.sketch. Both are overwritten by the next call.
+func (x *lshIndex) signatures(v []float32) []uint32 {
+	x.sketch = sketch{}
+	for i, h := range x.planes {
+		if h.project(v) > x.offsets[i] {
+			x.sketch[i/64] |= 1 << (i % 64)
+		}
+	}
+	for t, picks := range x.bits {
+		var sig uint32
+		for b, i := range picks {
+			if x.sketch.bit(i) {
+				sig |= 1 << b
+			}
+		}
+		x.sigs[t] = sig
+	}
+	return x.sigs
+}
+
+func (x *lshIndex) match(v []float32, threshold float32) (int, float32) {
+	if len(v) != x.dim || x.dim == 0 {
+		return -1, 0 // not comparable with the indexed vectors
+	}
+	var unit []float32
+	x.query++
+	best, bestSim := -1, float32(0)
+	for t, sig := range x.signatures(v) {
+		for _, k := range x.tables[t][sig] {
+			if x.seen[k] == x.query {
+				continue
+			}
+			x.seen[k] = x.query
+			// The first matching vector wins, as in a linear scan.
+			if best >= 0 && int(k) > best {
+				continue
+			}
+			if hamming(x.sketch, x.sketches[k]) > x.maxHamming {
+				continue
+			}
+			// The float32 dot product of unit vectors rules most of the
+			// rest out; the margin leaves the decision on the threshold
+			// to the exact cosine.
+			if unit == nil {
+				unit = normalized(v)
+			}
+			if dot(unit, x.units[k]) <= threshold-1e-4 {
+				continue
+			}
+			if sim := embed.CosineSimilarity(v, x.vectors[k]); sim > threshold {
+				best, bestSim = int(k), sim
+			}
+		}
+	}
+	return best, bestSim
+}
+
+func (x *lshIndex) add(v []float32) {
+	k := int32(len(x.vectors))
+	x.vectors = append(x.vectors, v)
+	x.units = append(x.units, normalized(v))
+	x.seen = append(x.seen, 0)
+	if len(v) != x.dim || x.dim == 0 {
+		x.sketches = append(x.sketches, sketch{})
+		return
+	}
+	sigs := x.signatures(v)
+	x.sketches = append(x.sketches, x.sketch)
+	for t, sig := range sigs {
+		x.tables[t][sig] = append(x.tables[t][sig], k)
+	}
+}
+
+func normalized(v []float32) []float32 {
+	var norm float64
+	for _, f := range v {
+		norm += float64(f) * float64(f)
+	}
+	unit := make([]float32, len(v))
+	if norm == 0 {
+		return unit
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i, f := range v {
+		unit[i] = f * scale
+	}
+	return unit
+}
+
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
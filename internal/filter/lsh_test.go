@@ -0,0 +1,121 @@
+package filter
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// syntheticStories returns n stories of perStory items each, shuffled:
+// every item is its story's vector plus noise (cosine ~0.95 between
+// items of a story), all sharing a common component as real embeddings
+// do.
+func syntheticStories(n, perStory, dim int) ([]store.Item, map[string][]float32) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	common := make([]float32, dim)
+	for i := range common {
+		common[i] = float32(rng.NormFloat64())
+	}
+	var items []store.Item
+	embeddings := make(map[string][]float32)
+	for s := 0; s < n; s++ {
+		story := make([]float32, dim)
+		for i := range story {
+			story[i] = float32(rng.NormFloat64()) + common[i]
+		}
+		for k := 0; k < perStory; k++ {
+			id := fmt.Sprintf("s%d-%d", s, k)
+			v := make([]float32, dim)
+			for i := range v {
+				v[i] = story[i] + 0.25*float32(rng.NormFloat64())
+			}
+			items = append(items, store.Item{ID: id, Title: id, URL: "https://example.com/" + id, SourceName: fmt.Sprintf("src%d", k)})
+			embeddings[id] = v
+		}
+	}
+	rng.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+	return items, embeddings
+}
+
+func TestNearDuplicatesLSHMatchesExact(t *testing.T) {
+	items, embeddings := syntheticStories(300, 6, 128)
+	if n := countEmbedded(items, embeddings); n <= lshExactMax {
+		t.Fatalf("%d vectors would take the exact path", n)
+	}
+
+	exact := nearDuplicates(items, embeddings, 0.85, &exactIndex{})
+	for _, run := range []struct {
+		name     string
+		clusters []Cluster
+	}{
+		{"index", nearDuplicates(items, embeddings, 0.85, newLSHIndex(items, embeddings, 0.85))},
+		{"NearDuplicates", NearDuplicates(items, embeddings, 0.85)},
+	} {
+		t.Run(run.name, func(t *testing.T) {
+			assertSameClusters(t, run.clusters, exact)
+		})
+	}
+	if len(exact) != 300 {
+		t.Fatalf("exact scan found %d clusters, want 300", len(exact))
+	}
+}
+
+func assertSameClusters(t *testing.T, lsh, exact []Cluster) {
+	t.Helper()
+	if len(lsh) != len(exact) {
+		t.Fatalf("LSH found %d clusters, exact %d", len(lsh), len(exact))
+	}
+	for i := range exact {
+		e, l := exact[i], lsh[i]
+		if e.Item.ID != l.Item.ID || len(e.Similar) != len(l.Similar) {
+			t.Fatalf("cluster %d: LSH %s (+%d), exact %s (+%d)", i, l.Item.ID, len(l.Similar), e.Item.ID, len(e.Similar))
+		}
+		for j := range e.Similar {
+			if e.Similar[j].Item.ID != l.Similar[j].Item.ID || e.Similar[j].Similarity != l.Similar[j].Similarity {
+				t.Errorf("cluster %s member %d: LSH %+v, exact %+v", e.Item.ID, j, l.Similar[j], e.Similar[j])
+			}
+		}
+	}
+}
+
+func BenchmarkNearDuplicates(b *testing.B) {
+	items, embeddings := syntheticStories(10000, 5, 256)
+	b.ResetTimer()
+	for range b.N {
+		if clusters := NearDuplicates(items, embeddings, 0.85); len(clusters) != 10000 {
+			b.Fatalf("%d clusters, want 10000", len(clusters))
+		}
+	}
+}
+
+func TestNearDuplicatesClusters(t *testing.T) {
+	items := []store.Item{
+		{ID: "1", URL: "u1", SourceName: "Reuters"},
+		{ID: "2", URL: "u2", SourceName: "AP"},
+		{ID: "3", URL: "u1", SourceName: "Reuters"},
+		{ID: "4", URL: "u4", SourceName: "BBC"},
+		{ID: "5", URL: "u5", SourceName: "Blog"},
+	}
+	embeddings := map[string][]float32{
+		"1": {1, 0},
+		"2": {0.95, 0.1},
+		"4": {0, 1},
+		"5": {0.9, 0.15},
+	}
+	clusters := NearDuplicates(items, embeddings, 0.85)
+	if len(clusters) != 2 || clusters[0].Item.ID != "1" || clusters[1].Item.ID != "4" {
+		t.Fatalf("clusters %+v", clusters)
+	}
+	var got []string
+	for _, s := range clusters[0].Similar {
+		got = append(got, fmt.Sprintf("%s:%.2f", s.Item.ID, s.Similarity))
+	}
+	if fmt.Sprint(got) != "[2:0.99 3:1.00 5:0.99]" {
+		t.Errorf("similar to 1: %v", got)
+	}
+	if len(clusters[1].Similar) != 0 {
+		t.Errorf("4 should have no duplicates, got %+v", clusters[1].Similar)
+	}
+}
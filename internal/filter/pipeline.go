@@ -0,0 +1,300 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// Env is what stages may use besides the items.
+type Env struct {
+	// Embeddings by item ID. If nil, the first stage that needs vectors
+	// calls LoadEmbeddings with the items reaching it, so cheap stages
+	// (max_age) shrink the set before any vectors are read.
+	Embeddings     map[string][]float32
+	LoadEmbeddings func(items []store.Item) map[string][]float32
+
+	// Rules is applied by the "rules" stage (nil: none). The stage adds
+	// what it hid and boosted to Rules* below.
+	Rules       *RuleSet
+	RuleHidden  map[int64][]string
+	RuleBoosted map[string]bool
+
+	// Trace, if set, collects a decision for every item a stage drops.
+	Trace *Trace
+
+	// Clusters maps the items semantic_dedup kept to the near-duplicates
+	// it folded into them.
+	Clusters map[string][]Similar
+
+	stage string // name of the running stage, for Trace
+}
+
+// dropper returns the running stage's recorder: nil unless tracing.
+func (e *Env) dropper() dropFunc {
+	return e.recorder(e.stage)
+}
+
+// EmbeddingsFor returns e.Embeddings, loading them for items first if
+// they haven't been. Never nil.
+func (e *Env) EmbeddingsFor(items []store.Item) map[string][]float32 {
+	if e.Embeddings == nil && e.LoadEmbeddings != nil {
+		e.Embeddings = e.LoadEmbeddings(items)
+	}
+	if e.Embeddings == nil {
+		e.Embeddings = make(map[string][]float32)
+	}
+	return e.Embeddings
+}
+
+// StageFunc filters items. Like the package's other functions it must
+// not modify its input.
+type StageFunc func(items []store.Item, env *Env) []store.Item
+
+// StageFactory builds a stage from its options (nil when the config
+// gives none). Unknown options are an error.
+type StageFactory func(options json.RawMessage) (StageFunc, error)
+
+// Registry maps stage names to factories so the feed pipeline can be
+// defined by configuration rather than hard-wired in each caller.
+type Registry struct {
+	factories map[string]StageFactory
+}
+
+// NewRegistry creates a Registry with the built-in stages:
+//
+//	max_age           {"max_age": "24h"}     drop items published earlier
+//	dedup             (no options)           drop repeated URLs and titles
+//	semantic_dedup    {"threshold": 0.85}    fold near-duplicate vectors into clusters (URL dedup without one)
+//	limit_per_source  {"max": 50}            keep each source's first max items
+//	exclude_sources   {"sources": [...]}     drop the named sources
+//	rules             (no options)           apply the user's mute/boost/allow rules (Env.Rules)
+func NewRegistry() *Registry {
+	r := &Registry{factories: make(map[string]StageFactory)}
+	r.Register("max_age", func(options json.RawMessage) (StageFunc, error) {
+		var o struct {
+			MaxAge config.Duration `json:"max_age"`
+		}
+		if err := decodeOptions(options, &o); err != nil {
+			return nil, err
+		}
+		if o.MaxAge <= 0 {
+			return nil, fmt.Errorf("max_age must be > 0")
+		}
+		return func(items []store.Item, env *Env) []store.Item {
+			return byAge(items, time.Duration(o.MaxAge), env.dropper())
+		}, nil
+	})
+	r.Register("dedup", func(options json.RawMessage) (StageFunc, error) {
+		if err := decodeOptions(options, &struct{}{}); err != nil {
+			return nil, err
+		}
+		return func(items []store.Item, env *Env) []store.Item {
+			return dedup(items, env.dropper())
+		}, nil
+	})
+	r.Register("semantic_dedup", func(options json.RawMessage) (StageFunc, error) {
+		o := struct {
+			Threshold float32 `json:"threshold"`
+		}{Threshold: 0.85}
+		if err := decodeOptions(options, &o); err != nil {
+			return nil, err
+		}
+		if o.Threshold <= 0 || o.Threshold > 1 {
+			return nil, fmt.Errorf("threshold must be in (0, 1]")
+		}
+		return func(items []store.Item, env *Env) []store.Item {
+			clusters := NearDuplicates(items, env.EmbeddingsFor(items), o.Threshold)
+			if env.Clusters == nil {
+				env.Clusters = make(map[string][]Similar)
+			}
+			for _, c := range clusters {
+				if len(c.Similar) > 0 {
+					env.Clusters[c.Item.ID] = append(env.Clusters[c.Item.ID], c.Similar...)
+				}
+			}
+			return clusterHeads(clusters, env.dropper())
+		}, nil
+	})
+	r.Register("limit_per_source", func(options json.RawMessage) (StageFunc, error) {
+		o := struct {
+			Max int `json:"max"`
+		}{Max: 50}
+		if err := decodeOptions(options, &o); err != nil {
+			return nil, err
+		}
+		if o.Max <= 0 {
+			return nil, fmt.Errorf("max must be > 0")
+		}
+		return func(items []store.Item, env *Env) []store.Item {
+			return limitPerSource(items, o.Max, env.dropper())
+		}, nil
+	})
+	r.Register("exclude_sources", func(options json.RawMessage) (StageFunc, error) {
+		var o struct {
+			Sources []string `json:"sources"`
+		}
+		if err := decodeOptions(options, &o); err != nil {
+			return nil, err
+		}
+		return func(items []store.Item, env *Env) []store.Item {
+			return excludeSources(items, o.Sources, env.dropper())
+		}, nil
+	})
+	r.Register("rules", func(options json.RawMessage) (StageFunc, error) {
+		if err := decodeOptions(options, &struct{}{}); err != nil {
+			return nil, err
+		}
+		return applyRules, nil
+	})
+	return r
+}
+
+// applyRules is the "rules" stage.
+func applyRules(items []store.Item, env *Env) []store.Item {
+	if env.Rules == nil || env.Rules.Len() == 0 {
+		return items
+	}
+	var embeddings map[string][]float32
+	if env.Rules.semantic {
+		embeddings = env.EmbeddingsFor(items)
+	}
+	kept, res := env.Rules.Apply(items, embeddings)
+	if env.RuleHidden == nil {
+		env.RuleHidden = make(map[int64][]string)
+	}
+	for id, ids := range res.Hidden {
+		env.RuleHidden[id] = append(env.RuleHidden[id], ids...)
+	}
+	if env.RuleBoosted == nil {
+		env.RuleBoosted = make(map[string]bool)
+	}
+	for id := range res.Boosted {
+		env.RuleBoosted[id] = true
+	}
+	if record := env.dropper(); record != nil {
+		env.Rules.record(items, res, record)
+	}
+	return kept
+}
+
+// decodeOptions decodes stage options into v, rejecting unknown fields
+// so a misspelt parameter doesn't silently keep its default.
+func decodeOptions(options json.RawMessage, v any) error {
+	if len(bytes.TrimSpace(options)) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(options))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("options: %w", err)
+	}
+	return nil
+}
+
+// Register adds a factory for name, replacing any existing one.
+func (r *Registry) Register(name string, f StageFactory) {
+	r.factories[name] = f
+}
+
+// Stages returns the registered stage names, sorted.
+func (r *Registry) Stages() []string {
+	names := make([]string, 0, len(r.factories))
+	for n := range r.factories {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build instantiates the enabled stages of cfgs, in order.
+func (r *Registry) Build(cfgs []config.StageConfig) (Pipeline, error) {
+	var p Pipeline
+	for _, sc := range cfgs {
+		if !sc.IsEnabled() {
+			continue
+		}
+		f, ok := r.factories[sc.Stage]
+		if !ok {
+			return nil, fmt.Errorf("pipeline stage %q: unknown stage %q (known: %v)", sc.Label(), sc.Stage, r.Stages())
+		}
+		apply, err := f(sc.Options)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline stage %q: %w", sc.Label(), err)
+		}
+		p = append(p, Stage{Name: sc.Label(), Type: sc.Stage, Options: sc.Options, apply: apply})
+	}
+	return p, nil
+}
+
+// Stage is one configured step of a Pipeline.
+type Stage struct {
+	Name    string
+	Type    string
+	Options json.RawMessage
+	apply   StageFunc
+}
+
+// Pipeline is an ordered list of filter stages.
+type Pipeline []Stage
+
+// Only returns the stages of p of the given types, in order.
+func (p Pipeline) Only(types ...string) Pipeline {
+	var out Pipeline
+	for _, s := range p {
+		for _, t := range types {
+			if s.Type == t {
+				out = append(out, s)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// StageCount reports what one stage did in Explain.
+type StageCount struct {
+	Stage   Stage
+	In, Out int
+	Elapsed time.Duration
+}
+
+// Run applies every stage in order.
+func (p Pipeline) Run(items []store.Item, env *Env) []store.Item {
+	for _, s := range p {
+		items = s.run(items, env)
+	}
+	return items
+}
+
+// run applies s, recording in env.Trace whatever it dropped without
+// a reason.
+func (s Stage) run(items []store.Item, env *Env) []store.Item {
+	recorded := 0
+	if env.Trace != nil {
+		recorded = len(env.Trace.Decisions)
+	}
+	env.stage = s.Name
+	out := s.apply(items, env)
+	env.stage = ""
+	env.settle(s.Name, items, out, recorded)
+	return out
+}
+
+// Explain is Run, also reporting each stage's input and output counts
+// and time.
+func (p Pipeline) Explain(items []store.Item, env *Env) ([]store.Item, []StageCount) {
+	counts := make([]StageCount, len(p))
+	for i, s := range p {
+		start := time.Now()
+		in := len(items)
+		items = s.run(items, env)
+		counts[i] = StageCount{Stage: s, In: in, Out: len(items), Elapsed: time.Since(start)}
+	}
+	return items, counts
+}
@@ -0,0 +1,129 @@
+package filter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+func TestRegistryBuildDefaultPipeline(t *testing.T) {
+	p, err := NewRegistry().Build(config.Default().Pipeline)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(p) != 4 || p[0].Type != "max_age" || p[1].Type != "rules" || p[2].Type != "semantic_dedup" || p[3].Type != "limit_per_source" {
+		t.Fatalf("unexpected pipeline: %+v", p)
+	}
+
+	now := time.Now()
+	items := []store.Item{
+		{ID: "1", SourceName: "a", Title: "Fresh", Published: now.Add(-time.Hour)},
+		{ID: "2", SourceName: "b", Title: "Fresh, reworded", Published: now.Add(-2 * time.Hour)},
+		{ID: "3", SourceName: "a", Title: "Stale", Published: now.Add(-48 * time.Hour)},
+		{ID: "4", SourceName: "c", Title: "Different", Published: now.Add(-3 * time.Hour)},
+	}
+	var loaded []string
+	env := &Env{LoadEmbeddings: func(items []store.Item) map[string][]float32 {
+		for _, item := range items {
+			loaded = append(loaded, item.ID)
+		}
+		return map[string][]float32{
+			"1": {1, 0},
+			"2": {0.99, 0.1},
+			"4": {0, 1},
+		}
+	}}
+
+	out, counts := p.Explain(items, env)
+	if got := idsOf(out); strings.Join(got, ",") != "1,4" {
+		t.Errorf("Explain kept %v, want [1 4]", got)
+	}
+	if strings.Join(loaded, ",") != "1,2,4" {
+		t.Errorf("loaded embeddings for %v, want only items surviving max_age", loaded)
+	}
+	want := [][2]int{{4, 3}, {3, 3}, {3, 2}, {2, 2}}
+	for i, c := range counts {
+		if c.In != want[i][0] || c.Out != want[i][1] {
+			t.Errorf("stage %s: %d -> %d, want %d -> %d", c.Stage.Name, c.In, c.Out, want[i][0], want[i][1])
+		}
+	}
+
+	if got := idsOf(p.Run(items, &Env{})); strings.Join(got, ",") != "1,2,4" {
+		t.Errorf("Run without embeddings kept %v, want URL dedup only", got)
+	}
+
+	if only := p.Only("semantic_dedup", "max_age"); len(only) != 2 || only[0].Type != "max_age" || only[1].Type != "semantic_dedup" {
+		t.Errorf("Only kept %+v, want max_age then semantic_dedup", only)
+	}
+}
+
+func TestRegistryBuildOptions(t *testing.T) {
+	disabled := false
+	p, err := NewRegistry().Build([]config.StageConfig{
+		{Stage: "exclude_sources", Options: json.RawMessage(`{"sources": ["b"]}`)},
+		{Stage: "limit_per_source", Name: "one each", Options: json.RawMessage(`{"max": 1}`)},
+		{Stage: "dedup", Enabled: &disabled},
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(p) != 2 || p[1].Name != "one each" {
+		t.Fatalf("unexpected pipeline: %+v", p)
+	}
+	now := time.Now()
+	items := []store.Item{
+		{ID: "1", SourceName: "a", Published: now},
+		{ID: "2", SourceName: "b", Published: now.Add(-time.Minute)},
+		{ID: "3", SourceName: "a", Published: now.Add(-2 * time.Minute)},
+		{ID: "4", SourceName: "c", Published: now.Add(-3 * time.Minute)},
+	}
+	if got := idsOf(p.Run(items, &Env{})); strings.Join(got, ",") != "1,4" {
+		t.Errorf("Run kept %v, want [1 4]", got)
+	}
+}
+
+func TestRegistryBuildErrors(t *testing.T) {
+	tests := []struct {
+		stage config.StageConfig
+		want  string
+	}{
+		{config.StageConfig{Stage: "nope"}, `unknown stage "nope"`},
+		{config.StageConfig{Stage: "semantic_dedup", Options: json.RawMessage(`{"treshold": 0.9}`)}, "unknown field"},
+		{config.StageConfig{Stage: "semantic_dedup", Options: json.RawMessage(`{"threshold": 1.5}`)}, "threshold"},
+		{config.StageConfig{Stage: "max_age"}, "max_age must be > 0"},
+		{config.StageConfig{Stage: "limit_per_source", Name: "cap", Options: json.RawMessage(`{"max": -1}`)}, `pipeline stage "cap"`},
+	}
+	for _, tt := range tests {
+		_, err := NewRegistry().Build([]config.StageConfig{tt.stage})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Build(%+v) error = %v, want %q", tt.stage, err, tt.want)
+		}
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	r.Register("first", func(json.RawMessage) (StageFunc, error) {
+		return func(items []store.Item, env *Env) []store.Item {
+			return items[:1]
+		}, nil
+	})
+	p, err := r.Build([]config.StageConfig{{Stage: "first"}})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if got := p.Run([]store.Item{{ID: "1"}, {ID: "2"}}, &Env{}); len(got) != 1 {
+		t.Errorf("Run kept %d items, want 1", len(got))
+	}
+	found := false
+	for _, s := range r.Stages() {
+		found = found || s == "first"
+	}
+	if !found {
+		t.Errorf("Stages() = %v, missing the registered stage", r.Stages())
+	}
+}
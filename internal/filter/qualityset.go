@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"math"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// LabelledQuery is a search query with the IDs of the QualitySet items
+// judged relevant to it; every other item is judged irrelevant.
+type LabelledQuery struct {
+	Query     string
+	Embedding []float32 // synthetic topic vector, comparable with QualitySet.Embeddings
+	Relevant  []string
+}
+
+// IsRelevant reports whether the item with id is labelled relevant to q.
+func (q LabelledQuery) IsRelevant(id string) bool {
+	for _, r := range q.Relevant {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
+
+// QualitySet is a small labelled corpus of headlines across six topics.
+// The search quality tests rank it with synthetic topic embeddings;
+// `obs calibrate` scores it with real backends to fit score calibrations.
+type QualitySet struct {
+	Items []store.Item
+	// Embeddings are 6-dimensional topic vectors by item ID:
+	//   [0] sports   [1] tech   [2] finance   [3] politics   [4] science   [5] weather
+	// Items have high values in their topic dimension and low noise elsewhere.
+	Embeddings map[string][]float32
+	Queries    []LabelledQuery
+}
+
+// NewQualitySet returns the labelled corpus, published now.
+func NewQualitySet() QualitySet {
+	now := time.Now()
+
+	items := []store.Item{
+		// Sports cluster
+		{ID: "nfl1", Title: "NFL Draft 2025: Top Prospects", SourceName: "espn", Published: now},
+		{ID: "nfl2", Title: "Mahomes Leads Chiefs to Victory", SourceName: "espn", Published: now},
+		{ID: "nfl3", Title: "Super Bowl LVIII Highlights", SourceName: "fox", Published: now},
+		{ID: "nba1", Title: "NBA Playoffs: Lakers vs Celtics Preview", SourceName: "espn", Published: now},
+
+		// Tech cluster
+		{ID: "tech1", Title: "GPT-5 Released with Multimodal Reasoning", SourceName: "hn", Published: now},
+		{ID: "tech2", Title: "Rust 2.0 Announced with Async Improvements", SourceName: "hn", Published: now},
+		{ID: "tech3", Title: "Apple Vision Pro Sales Disappoint", SourceName: "ars", Published: now},
+		{ID: "tech4", Title: "SQLite Adds Vector Search Extension", SourceName: "hn", Published: now},
+
+		// Finance cluster
+		{ID: "fin1", Title: "Federal Reserve Holds Rates Steady", SourceName: "wsj", Published: now},
+		{ID: "fin2", Title: "NVIDIA Stock Hits All-Time High", SourceName: "wsj", Published: now},
+		{ID: "fin3", Title: "Bitcoin Surges Past $100K", SourceName: "ft", Published: now},
+
+		// Politics cluster
+		{ID: "pol1", Title: "EU Passes AI Regulation Act", SourceName: "bbc", Published: now},
+		{ID: "pol2", Title: "Ukraine Peace Talks Resume", SourceName: "bbc", Published: now},
+
+		// Science cluster
+		{ID: "sci1", Title: "James Webb Detects New Exoplanet", SourceName: "nature", Published: now},
+		{ID: "sci2", Title: "CRISPR Gene Therapy for Sickle Cell", SourceName: "nature", Published: now},
+
+		// Weather (noise)
+		{ID: "wx1", Title: "Severe Thunderstorm Warning Texas", SourceName: "weather", Published: now},
+	}
+
+	//                          sports  tech  finance  politics  science  weather
+	embeddings := map[string][]float32{
+		"nfl1":  normalize([]float32{0.95, 0.02, 0.01, 0.01, 0.00, 0.00}),
+		"nfl2":  normalize([]float32{0.93, 0.01, 0.01, 0.00, 0.00, 0.00}),
+		"nfl3":  normalize([]float32{0.97, 0.01, 0.00, 0.01, 0.00, 0.00}),
+		"nba1":  normalize([]float32{0.90, 0.02, 0.01, 0.01, 0.00, 0.00}),
+		"tech1": normalize([]float32{0.01, 0.92, 0.02, 0.05, 0.02, 0.00}),
+		"tech2": normalize([]float32{0.00, 0.96, 0.01, 0.01, 0.01, 0.00}),
+		"tech3": normalize([]float32{0.01, 0.88, 0.10, 0.01, 0.00, 0.00}), // slight finance overlap (stock price)
+		"tech4": normalize([]float32{0.01, 0.94, 0.01, 0.01, 0.02, 0.00}),
+		"fin1":  normalize([]float32{0.00, 0.02, 0.95, 0.05, 0.00, 0.00}), // slight politics overlap (Fed policy)
+		"fin2":  normalize([]float32{0.00, 0.15, 0.90, 0.01, 0.00, 0.00}), // slight tech overlap (NVIDIA)
+		"fin3":  normalize([]float32{0.00, 0.08, 0.92, 0.01, 0.00, 0.00}),
+		"pol1":  normalize([]float32{0.01, 0.10, 0.02, 0.90, 0.01, 0.00}), // slight tech overlap (AI regulation)
+		"pol2":  normalize([]float32{0.00, 0.01, 0.01, 0.96, 0.00, 0.00}),
+		"sci1":  normalize([]float32{0.00, 0.05, 0.00, 0.01, 0.95, 0.00}),
+		"sci2":  normalize([]float32{0.00, 0.08, 0.00, 0.01, 0.93, 0.00}), // slight tech overlap (CRISPR tech)
+		"wx1":   normalize([]float32{0.01, 0.00, 0.00, 0.00, 0.02, 0.95}),
+	}
+
+	queries := []LabelledQuery{
+		{Query: "NFL football and NBA basketball", Embedding: normalize([]float32{0.98, 0.01, 0.00, 0.00, 0.00, 0.00}), Relevant: []string{"nfl1", "nfl2", "nfl3", "nba1"}},
+		{Query: "new software, AI models and gadgets", Embedding: normalize([]float32{0.00, 0.97, 0.01, 0.01, 0.02, 0.00}), Relevant: []string{"tech1", "tech2", "tech3", "tech4"}},
+		{Query: "stock market and interest rates", Embedding: normalize([]float32{0.00, 0.02, 0.97, 0.02, 0.00, 0.00}), Relevant: []string{"fin1", "fin2", "fin3"}},
+		{Query: "legislation and diplomacy", Embedding: normalize([]float32{0.00, 0.05, 0.00, 0.97, 0.00, 0.00}), Relevant: []string{"pol1", "pol2"}},
+		{Query: "space telescopes and medical research", Embedding: normalize([]float32{0.00, 0.03, 0.00, 0.00, 0.98, 0.00}), Relevant: []string{"sci1", "sci2"}},
+		{Query: "storm forecast", Embedding: normalize([]float32{0.00, 0.00, 0.00, 0.00, 0.01, 0.99}), Relevant: []string{"wx1"}},
+	}
+
+	return QualitySet{Items: items, Embeddings: embeddings, Queries: queries}
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	norm := float32(math.Sqrt(sum))
+	if norm == 0 {
+		return v
+	}
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
@@ -0,0 +1,189 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// RuleSet is a compiled list of user rules (store.Rule), applied by the
+// "rules" pipeline stage.
+type RuleSet struct {
+	rules    []compiledRule
+	semantic bool // some rule needs item vectors
+}
+
+type compiledRule struct {
+	store.Rule
+	re *regexp.Regexp // keyword and regex rules
+}
+
+// NewRuleSet compiles rules for items whose vectors come from model.
+// Semantic rules whose example was embedded by another model are left
+// out: the vectors aren't comparable.
+func NewRuleSet(rules []store.Rule, model string) (*RuleSet, error) {
+	rs := &RuleSet{}
+	for _, r := range rules {
+		c := compiledRule{Rule: r}
+		switch r.Kind {
+		case store.RuleKeyword:
+			c.re = regexp.MustCompile(keywordPattern(r.Pattern))
+		case store.RuleRegex:
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", r.ID, err)
+			}
+			c.re = re
+		case store.RuleSemantic:
+			if r.Model != model {
+				continue
+			}
+			rs.semantic = true
+		}
+		rs.rules = append(rs.rules, c)
+	}
+	return rs, nil
+}
+
+// keywordPattern matches keyword as a whole word, in any case: "AI"
+// shouldn't mute "said". Go's \b only knows ASCII word characters, so the
+// boundaries are spelled out with Unicode classes, and only on a side
+// where the keyword starts or ends with a word character: "C++" has none
+// after it, ".NET" none before.
+func keywordPattern(keyword string) string {
+	const word = `\p{L}\p{M}\p{N}_`
+	keyword = strings.TrimSpace(keyword)
+	pattern := `(?i)` + regexp.QuoteMeta(keyword)
+	if first, _ := utf8.DecodeRuneInString(keyword); isWordRune(first) {
+		pattern = `(?:^|[^` + word + `])` + pattern
+	}
+	if last, _ := utf8.DecodeLastRuneInString(keyword); isWordRune(last) {
+		pattern += `(?:$|[^` + word + `])`
+	}
+	return pattern
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.In(r, unicode.L, unicode.M, unicode.N)
+}
+
+// Len returns the number of rules in effect.
+func (rs *RuleSet) Len() int {
+	return len(rs.rules)
+}
+
+// matches reports whether r matches item, whose vector is emb (nil if
+// it has none).
+func (r compiledRule) matches(item store.Item, emb []float32) bool {
+	switch r.Kind {
+	case store.RuleKeyword, store.RuleRegex:
+		return r.re.MatchString(item.Title) || r.re.MatchString(item.Summary)
+	case store.RuleSource:
+		return strings.EqualFold(item.SourceName, strings.TrimSpace(r.Pattern))
+	case store.RuleAuthor:
+		return item.Author != "" && strings.EqualFold(strings.TrimSpace(item.Author), strings.TrimSpace(r.Pattern))
+	case store.RuleSemantic:
+		if item.ID == r.ExampleID {
+			return true
+		}
+		return len(emb) > 0 && CosineSimilarity(emb, r.Embedding) >= r.Threshold
+	}
+	return false
+}
+
+// RuleResult reports what a RuleSet did to a list of items.
+type RuleResult struct {
+	Hidden  map[int64][]string // mute rule ID -> IDs of the items it hid
+	Boosted map[string]bool    // IDs of kept items a boost rule matched
+	Allowed map[string]int64   // IDs of muted items an allow rule kept -> its ID
+}
+
+// Apply returns items without those a mute rule matches, unless an allow
+// rule matches them too. Order is kept; boosted items are reported for
+// the caller to rank. An item hidden by several rules counts for each.
+func (rs *RuleSet) Apply(items []store.Item, embeddings map[string][]float32) ([]store.Item, RuleResult) {
+	res := RuleResult{Hidden: make(map[int64][]string), Boosted: make(map[string]bool), Allowed: make(map[string]int64)}
+	if len(rs.rules) == 0 {
+		return items, res
+	}
+	kept := make([]store.Item, 0, len(items))
+	var muted []int64
+	for _, item := range items {
+		muted = muted[:0]
+		var allowedBy int64
+		boosted := false
+		emb := embeddings[item.ID]
+		for _, r := range rs.rules {
+			if !r.matches(item, emb) {
+				continue
+			}
+			switch r.Action {
+			case store.RuleMute:
+				muted = append(muted, r.ID)
+			case store.RuleAllow:
+				if allowedBy == 0 {
+					allowedBy = r.ID
+				}
+			case store.RuleBoost:
+				boosted = true
+			}
+		}
+		if len(muted) > 0 {
+			if allowedBy == 0 {
+				for _, id := range muted {
+					res.Hidden[id] = append(res.Hidden[id], item.ID)
+				}
+				continue
+			}
+			res.Allowed[item.ID] = allowedBy
+		}
+		if boosted {
+			res.Boosted[item.ID] = true
+		}
+		kept = append(kept, item)
+	}
+	return kept, res
+}
+
+// record turns res, the result of applying rs to items, into decisions:
+// one per hidden item naming the rules that hid it, and one per muted
+// item an allow rule kept.
+func (rs *RuleSet) record(items []store.Item, res RuleResult, record dropFunc) {
+	hiddenBy := make(map[string][]string)
+	for _, r := range rs.rules {
+		for _, id := range res.Hidden[r.ID] {
+			hiddenBy[id] = append(hiddenBy[id], r.describe())
+		}
+	}
+	for _, item := range items {
+		if why, ok := hiddenBy[item.ID]; ok {
+			record(Decision{Item: item, Reason: "muted by " + strings.Join(why, ", ")})
+		} else if id, ok := res.Allowed[item.ID]; ok {
+			record(Decision{Item: item, Kept: true, Reason: "muted, but allowed by " + rs.describe(id)})
+		}
+	}
+}
+
+// describe returns the rule with the given ID as a reason names it.
+func (rs *RuleSet) describe(id int64) string {
+	for _, r := range rs.rules {
+		if r.ID == id {
+			return r.describe()
+		}
+	}
+	return fmt.Sprintf("rule %d", id)
+}
+
+func (r compiledRule) describe() string {
+	switch r.Kind {
+	case store.RuleRegex:
+		return fmt.Sprintf("rule %d (regex /%s/)", r.ID, r.Pattern)
+	case store.RuleSemantic:
+		return fmt.Sprintf("rule %d (similar to %q)", r.ID, r.Pattern)
+	}
+	return fmt.Sprintf("rule %d (%s %q)", r.ID, r.Kind, r.Pattern)
+}
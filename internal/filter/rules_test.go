@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/abelbrown/observer/internal/config"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+func TestRuleSetApply(t *testing.T) {
+	rules := []store.Rule{
+		{ID: 1, Kind: store.RuleKeyword, Action: store.RuleMute, Pattern: "crypto"},
+		{ID: 2, Kind: store.RuleSource, Action: store.RuleMute, Pattern: "tabloid"},
+		{ID: 3, Kind: store.RuleAuthor, Action: store.RuleAllow, Pattern: "Jane Doe"},
+		{ID: 4, Kind: store.RuleRegex, Action: store.RuleBoost, Pattern: `(?i)^webb\b`},
+		{ID: 5, Kind: store.RuleSemantic, Action: store.RuleMute, Pattern: "Transfer rumours", ExampleID: "ex", Embedding: []float32{1, 0}, Model: "m", Threshold: 0.9},
+		{ID: 6, Kind: store.RuleSemantic, Action: store.RuleMute, Pattern: "Other model", Embedding: []float32{0, 1}, Model: "other", Threshold: 0.5},
+	}
+	rs, err := NewRuleSet(rules, "m")
+	if err != nil {
+		t.Fatalf("NewRuleSet: %v", err)
+	}
+	if rs.Len() != 5 {
+		t.Errorf("Len() = %d, want 5 (the other model's semantic rule left out)", rs.Len())
+	}
+
+	items := []store.Item{
+		{ID: "1", Title: "Crypto exchange collapses", SourceName: "wire"},
+		{ID: "2", Title: "Cryptography prize announced", SourceName: "wire"},
+		{ID: "3", Title: "Celebrity news", SourceName: "Tabloid"},
+		{ID: "4", Title: "Crypto explained", SourceName: "wire", Author: "Jane Doe"},
+		{ID: "5", Title: "Webb spots a new galaxy", SourceName: "nature"},
+		{ID: "6", Title: "Striker set for move", SourceName: "sport"},
+		{ID: "ex", Title: "Transfer rumours", SourceName: "sport"},
+		{ID: "7", Title: "Cup final report", SourceName: "sport"},
+	}
+	embeddings := map[string][]float32{
+		"6": {0.98, 0.2},
+		"7": {0.2, 0.98},
+	}
+
+	kept, res := rs.Apply(items, embeddings)
+	if got := strings.Join(idsOf(kept), ","); got != "2,4,5,7" {
+		t.Errorf("kept %s, want 2,4,5,7", got)
+	}
+	if got := strings.Join(res.Hidden[1], ","); got != "1" {
+		t.Errorf("keyword rule hid %s, want 1 (4 is allowed, 2 is another word)", got)
+	}
+	if got := strings.Join(res.Hidden[2], ","); got != "3" {
+		t.Errorf("source rule hid %s, want 3", got)
+	}
+	if got := strings.Join(res.Hidden[5], ","); got != "6,ex" {
+		t.Errorf("semantic rule hid %s, want 6,ex", got)
+	}
+	if len(res.Boosted) != 1 || !res.Boosted["5"] {
+		t.Errorf("Boosted = %v, want 5", res.Boosted)
+	}
+
+	if _, err := NewRuleSet([]store.Rule{{ID: 9, Kind: store.RuleRegex, Pattern: "("}}, "m"); err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+}
+
+func TestKeywordRuleBoundaries(t *testing.T) {
+	tests := []struct {
+		keyword, title string
+		want           bool
+	}{
+		{"café", "New café opens downtown", true},
+		{"café", "Cafés close early", false},
+		{"café", "Cafeteria prices rise", false},
+		{"Zürich", "Floods in zürich", true},
+		{"Zürich", "Zürichsee water level", false},
+		{"Zürich", "Greater-Zürich rail plan", true},
+		{"C++", "C++ 26 is out", true},
+		{"C++", "What's new in C++?", true},
+		{"C++", "ABC++ tooling", false},
+		{"C++", "C is older", false},
+		{".NET", "Microsoft ships .NET 9", true},
+		{".NET", "Upgrading ASP.NET apps", true},
+		{".NET", "Cable.network outage", false},
+		{"$TSLA", "$TSLA falls after delivery miss", true},
+		{"$TSLA", "Why is $tsla up?", true},
+		{"$TSLA", "$TSLAQ is not a ticker", false},
+		{"AI", "Officials said nothing", false},
+	}
+	for _, tt := range tests {
+		rs, err := NewRuleSet([]store.Rule{{ID: 1, Kind: store.RuleKeyword, Action: store.RuleMute, Pattern: tt.keyword}}, "")
+		if err != nil {
+			t.Fatalf("NewRuleSet(%q): %v", tt.keyword, err)
+		}
+		_, res := rs.Apply([]store.Item{{ID: "1", Title: tt.title}}, nil)
+		if got := len(res.Hidden[1]) == 1; got != tt.want {
+			t.Errorf("keyword %q on %q: matched %v, want %v", tt.keyword, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestRulesStage(t *testing.T) {
+	p, err := NewRegistry().Build([]config.StageConfig{
+		{Stage: "rules"},
+		{Stage: "limit_per_source", Options: json.RawMessage(`{"max": 1}`)},
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	items := []store.Item{
+		{ID: "1", Title: "Crypto slump", SourceName: "a"},
+		{ID: "2", Title: "Rates held", SourceName: "a"},
+	}
+
+	// Without rules the stage passes everything through.
+	if got := p.Run(items, &Env{}); len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("Run without rules = %v", idsOf(got))
+	}
+
+	rs, _ := NewRuleSet([]store.Rule{{ID: 7, Kind: store.RuleKeyword, Action: store.RuleMute, Pattern: "crypto"}}, "")
+	loaded := false
+	env := &Env{Rules: rs, LoadEmbeddings: func([]store.Item) map[string][]float32 {
+		loaded = true
+		return nil
+	}}
+	if got := p.Run(items, env); len(got) != 1 || got[0].ID != "2" {
+		t.Errorf("Run with rules = %v, want [2]", idsOf(got))
+	}
+	if len(env.RuleHidden[7]) != 1 || env.RuleHidden[7][0] != "1" {
+		t.Errorf("RuleHidden = %v", env.RuleHidden)
+	}
+	if loaded {
+		t.Error("vectors loaded for rules that don't need them")
+	}
+}
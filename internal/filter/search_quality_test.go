@@ -2,93 +2,19 @@ package filter
 
 import (
 	"context"
-	"math"
 	"testing"
-	"time"
 
+	"github.com/abelbrown/observer/internal/rerank"
 	"github.com/abelbrown/observer/internal/store"
 )
 
-// Topic-clustered embeddings for search quality testing.
-//
-// Each embedding is a 6-dimensional vector where dimensions represent:
-//   [0] sports   [1] tech   [2] finance   [3] politics   [4] science   [5] weather
-//
-// Items have high values in their topic dimension and low noise elsewhere.
-// This lets us test that cosine similarity correctly surfaces topically
-// relevant items, which is the core of what makes search results "good."
-
-func normalize(v []float32) []float32 {
-	var sum float64
-	for _, x := range v {
-		sum += float64(x) * float64(x)
-	}
-	norm := float32(math.Sqrt(sum))
-	if norm == 0 {
-		return v
-	}
-	out := make([]float32, len(v))
-	for i, x := range v {
-		out[i] = x / norm
-	}
-	return out
-}
-
-// qualityCorpus returns items and their topic embeddings.
+// qualityCorpus returns the labelled set's items and their topic embeddings
+// (see QualitySet). Topic vectors let us test that cosine similarity
+// correctly surfaces topically relevant items, which is the core of what
+// makes search results "good."
 func qualityCorpus() ([]store.Item, map[string][]float32) {
-	now := time.Now()
-
-	items := []store.Item{
-		// Sports cluster
-		{ID: "nfl1", Title: "NFL Draft 2025: Top Prospects", SourceName: "espn", Published: now},
-		{ID: "nfl2", Title: "Mahomes Leads Chiefs to Victory", SourceName: "espn", Published: now},
-		{ID: "nfl3", Title: "Super Bowl LVIII Highlights", SourceName: "fox", Published: now},
-		{ID: "nba1", Title: "NBA Playoffs: Lakers vs Celtics Preview", SourceName: "espn", Published: now},
-
-		// Tech cluster
-		{ID: "tech1", Title: "GPT-5 Released with Multimodal Reasoning", SourceName: "hn", Published: now},
-		{ID: "tech2", Title: "Rust 2.0 Announced with Async Improvements", SourceName: "hn", Published: now},
-		{ID: "tech3", Title: "Apple Vision Pro Sales Disappoint", SourceName: "ars", Published: now},
-		{ID: "tech4", Title: "SQLite Adds Vector Search Extension", SourceName: "hn", Published: now},
-
-		// Finance cluster
-		{ID: "fin1", Title: "Federal Reserve Holds Rates Steady", SourceName: "wsj", Published: now},
-		{ID: "fin2", Title: "NVIDIA Stock Hits All-Time High", SourceName: "wsj", Published: now},
-		{ID: "fin3", Title: "Bitcoin Surges Past $100K", SourceName: "ft", Published: now},
-
-		// Politics cluster
-		{ID: "pol1", Title: "EU Passes AI Regulation Act", SourceName: "bbc", Published: now},
-		{ID: "pol2", Title: "Ukraine Peace Talks Resume", SourceName: "bbc", Published: now},
-
-		// Science cluster
-		{ID: "sci1", Title: "James Webb Detects New Exoplanet", SourceName: "nature", Published: now},
-		{ID: "sci2", Title: "CRISPR Gene Therapy for Sickle Cell", SourceName: "nature", Published: now},
-
-		// Weather (noise)
-		{ID: "wx1", Title: "Severe Thunderstorm Warning Texas", SourceName: "weather", Published: now},
-	}
-
-	//                          sports  tech  finance  politics  science  weather
-	embeddings := map[string][]float32{
-		"nfl1":  normalize([]float32{0.95, 0.02, 0.01, 0.01, 0.00, 0.00}),
-		"nfl2":  normalize([]float32{0.93, 0.01, 0.01, 0.00, 0.00, 0.00}),
-		"nfl3":  normalize([]float32{0.97, 0.01, 0.00, 0.01, 0.00, 0.00}),
-		"nba1":  normalize([]float32{0.90, 0.02, 0.01, 0.01, 0.00, 0.00}),
-		"tech1": normalize([]float32{0.01, 0.92, 0.02, 0.05, 0.02, 0.00}),
-		"tech2": normalize([]float32{0.00, 0.96, 0.01, 0.01, 0.01, 0.00}),
-		"tech3": normalize([]float32{0.01, 0.88, 0.10, 0.01, 0.00, 0.00}), // slight finance overlap (stock price)
-		"tech4": normalize([]float32{0.01, 0.94, 0.01, 0.01, 0.02, 0.00}),
-		"fin1":  normalize([]float32{0.00, 0.02, 0.95, 0.05, 0.00, 0.00}), // slight politics overlap (Fed policy)
-		"fin2":  normalize([]float32{0.00, 0.15, 0.90, 0.01, 0.00, 0.00}), // slight tech overlap (NVIDIA)
-		"fin3":  normalize([]float32{0.00, 0.08, 0.92, 0.01, 0.00, 0.00}),
-		"pol1":  normalize([]float32{0.01, 0.10, 0.02, 0.90, 0.01, 0.00}), // slight tech overlap (AI regulation)
-		"pol2":  normalize([]float32{0.00, 0.01, 0.01, 0.96, 0.00, 0.00}),
-		"sci1":  normalize([]float32{0.00, 0.05, 0.00, 0.01, 0.95, 0.00}),
-		"sci2":  normalize([]float32{0.00, 0.08, 0.00, 0.01, 0.93, 0.00}), // slight tech overlap (CRISPR tech)
-		"wx1":   normalize([]float32{0.01, 0.00, 0.00, 0.00, 0.02, 0.95}),
-	}
-
-	return items, embeddings
+	set := NewQualitySet()
+	return set.Items, set.Embeddings
 }
 
 // --- Cosine Ranking Quality Tests ---
@@ -99,7 +25,7 @@ func TestSearchQuality_SportsQuery(t *testing.T) {
 	// Query: "football NFL" → sports dimension dominant
 	queryEmb := normalize([]float32{0.98, 0.01, 0.00, 0.00, 0.00, 0.00})
 
-	result := RerankByQuery(items, embeddings, queryEmb)
+	result := RerankByQuery(items, embeddings, nil, queryEmb)
 
 	// Top 4 results should be all sports items
 	top4 := idSet(result[:4])
@@ -124,7 +50,7 @@ func TestSearchQuality_TechQuery(t *testing.T) {
 	// Query: "programming language AI" → tech dimension dominant
 	queryEmb := normalize([]float32{0.00, 0.97, 0.01, 0.01, 0.02, 0.00})
 
-	result := RerankByQuery(items, embeddings, queryEmb)
+	result := RerankByQuery(items, embeddings, nil, queryEmb)
 
 	// Top 4 should be tech items
 	top4 := idSet(result[:4])
@@ -152,7 +78,7 @@ func TestSearchQuality_FinanceQuery(t *testing.T) {
 	// Query: "stock market interest rates" → finance dimension
 	queryEmb := normalize([]float32{0.00, 0.02, 0.97, 0.02, 0.00, 0.00})
 
-	result := RerankByQuery(items, embeddings, queryEmb)
+	result := RerankByQuery(items, embeddings, nil, queryEmb)
 
 	// Top 3 should be finance items
 	top3 := idSet(result[:3])
@@ -177,7 +103,7 @@ func TestSearchQuality_CrossDomainQuery(t *testing.T) {
 	// Query: "AI technology stocks" → mix of tech + finance
 	queryEmb := normalize([]float32{0.00, 0.60, 0.50, 0.01, 0.01, 0.00})
 
-	result := RerankByQuery(items, embeddings, queryEmb)
+	result := RerankByQuery(items, embeddings, nil, queryEmb)
 
 	// Top results should be tech and finance items, not sports/weather
 	top6 := idSet(result[:6])
@@ -205,7 +131,7 @@ func TestSearchQuality_IrrelevantItemsRankLow(t *testing.T) {
 	// Query: "NFL football" → pure sports
 	queryEmb := normalize([]float32{0.99, 0.00, 0.00, 0.00, 0.00, 0.00})
 
-	result := RerankByQuery(items, embeddings, queryEmb)
+	result := RerankByQuery(items, embeddings, nil, queryEmb)
 
 	// All 4 sports items should occupy the top 4 positions.
 	sportsIDs := map[string]bool{"nfl1": true, "nfl2": true, "nfl3": true, "nba1": true}
@@ -340,7 +266,7 @@ func TestSearchQuality_FullPipeline_SportsQuery(t *testing.T) {
 
 	// Step 1: Cosine ranking
 	queryEmb := normalize([]float32{0.98, 0.01, 0.00, 0.00, 0.00, 0.00})
-	cosineResult := RerankByQuery(items, embeddings, queryEmb)
+	cosineResult := RerankByQuery(items, embeddings, nil, queryEmb)
 
 	// Cosine should get sports items to the top
 	cosineTop4 := idSet(cosineResult[:4])
@@ -399,7 +325,7 @@ func TestSearchQuality_FullPipeline_NoEmbeddingsFallback(t *testing.T) {
 	emptyEmb := map[string][]float32{}
 	queryEmb := normalize([]float32{0.98, 0.01, 0.00, 0.00, 0.00, 0.00})
 
-	result := RerankByQuery(items, emptyEmb, queryEmb)
+	result := RerankByQuery(items, emptyEmb, nil, queryEmb)
 
 	// With no embeddings, items stay in input order
 	for i, item := range result {
@@ -410,6 +336,43 @@ func TestSearchQuality_FullPipeline_NoEmbeddingsFallback(t *testing.T) {
 	}
 }
 
+// --- Calibration Tests ---
+
+func TestSearchQuality_CalibratedCosineThreshold(t *testing.T) {
+	set := NewQualitySet()
+
+	// Fit on every labelled query-item pair, as `obs calibrate` does.
+	var sims []float32
+	var labels []bool
+	for _, q := range set.Queries {
+		for _, item := range set.Items {
+			sims = append(sims, CosineSimilarity(q.Embedding, set.Embeddings[item.ID]))
+			labels = append(labels, q.IsRelevant(item.ID))
+		}
+	}
+	cal, err := rerank.FitCalibration(sims, labels)
+	if err != nil {
+		t.Fatalf("FitCalibration() error = %v", err)
+	}
+
+	// One threshold on calibrated scores keeps exactly the relevant items.
+	for _, q := range set.Queries {
+		scores := make([]rerank.Score, len(set.Items))
+		for i, item := range set.Items {
+			scores[i] = rerank.Score{Index: i, Score: cal.Apply(CosineSimilarity(q.Embedding, set.Embeddings[item.ID]))}
+		}
+		kept := rerank.FilterAboveThreshold(scores, 0.5)
+		if len(kept) != len(q.Relevant) {
+			t.Errorf("%q: %d items above 0.5, want %d", q.Query, len(kept), len(q.Relevant))
+		}
+		for _, s := range kept {
+			if id := set.Items[s.Index].ID; !q.IsRelevant(id) {
+				t.Errorf("%q: irrelevant item %s calibrated to %.2f", q.Query, id, s.Score)
+			}
+		}
+	}
+}
+
 // --- Helpers ---
 
 func idSet(items []store.Item) map[string]bool {
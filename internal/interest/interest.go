@@ -0,0 +1,170 @@
+// Package interest learns what the user cares about from what they do
+// with items locally: reads, saves and more-like-this seeds pull a
+// positive centroid towards their vectors, exclusions a negative one.
+// Each signal decays with age. Nothing leaves the machine, and every
+// contributing item is listed with its weight so the profile can be
+// inspected and pruned.
+package interest
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+const (
+	// HalfLife is how long a signal takes to lose half its weight.
+	HalfLife = 14 * 24 * time.Hour
+	// Horizon is how far back signals are loaded: older ones weigh
+	// under 1% and are left out.
+	Horizon = 90 * 24 * time.Hour
+)
+
+// Weights is the weight of a fresh signal by kind. Exclusions count
+// towards the negative centroid.
+var Weights = map[string]float64{
+	store.SignalRead:         1,
+	store.SignalMoreLikeThis: 2,
+	store.SignalSaved:        3,
+	store.SignalExcluded:     1,
+}
+
+// Contribution is one signal's share of the profile.
+type Contribution struct {
+	store.Signal
+	// Weight is the signal's decayed weight, negative for exclusions.
+	// It's 0 if the item has no vector from the profile's model.
+	Weight   float64
+	Embedded bool
+}
+
+// Profile is the interest profile for one embedding model.
+type Profile struct {
+	Model    string
+	Positive []float32 // weighted mean of read, saved and seed vectors; nil without any
+	Negative []float32 // weighted mean of excluded vectors; nil without any
+
+	// Contributions lists every signal, heaviest first, then unembedded
+	// ones newest first.
+	Contributions []Contribution
+}
+
+// Decay returns the share of its weight a signal keeps after age.
+func Decay(age time.Duration) float64 {
+	if age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(HalfLife))
+}
+
+// Build computes the profile at now from signals (see store.Signals) and
+// the vectors model gave their items.
+func Build(model string, signals []store.Signal, vectors map[string][]float32, now time.Time) Profile {
+	p := Profile{Model: model}
+	var pos, neg []float64
+	var posWeight, negWeight float64
+	for _, sig := range signals {
+		c := Contribution{Signal: sig}
+		v, ok := vectors[sig.Item.ID]
+		if ok && len(v) > 0 {
+			c.Embedded = true
+			w := Weights[sig.Kind] * Decay(now.Sub(sig.At))
+			if sig.Kind == store.SignalExcluded {
+				c.Weight = -w
+				neg = accumulate(neg, v, w)
+				negWeight += w
+			} else {
+				c.Weight = w
+				pos = accumulate(pos, v, w)
+				posWeight += w
+			}
+		}
+		p.Contributions = append(p.Contributions, c)
+	}
+	p.Positive = mean(pos, posWeight)
+	p.Negative = mean(neg, negWeight)
+
+	sort.SliceStable(p.Contributions, func(i, j int) bool {
+		a, b := p.Contributions[i], p.Contributions[j]
+		if a.Embedded != b.Embedded {
+			return a.Embedded
+		}
+		if wa, wb := math.Abs(a.Weight), math.Abs(b.Weight); wa != wb {
+			return wa > wb
+		}
+		return a.At.After(b.At)
+	})
+	return p
+}
+
+// accumulate adds w times v to sum, skipping vectors of another
+// dimension than the first one seen.
+func accumulate(sum []float64, v []float32, w float64) []float64 {
+	if sum == nil {
+		sum = make([]float64, len(v))
+	}
+	if len(v) != len(sum) {
+		return sum
+	}
+	for i, x := range v {
+		sum[i] += w * float64(x)
+	}
+	return sum
+}
+
+func mean(sum []float64, weight float64) []float32 {
+	if weight == 0 {
+		return nil
+	}
+	out := make([]float32, len(sum))
+	for i, x := range sum {
+		out[i] = float32(x / weight)
+	}
+	return out
+}
+
+// Empty reports whether no signal has a vector, so Score says nothing.
+func (p Profile) Empty() bool {
+	return p.Positive == nil && p.Negative == nil
+}
+
+// Score returns how well v matches the profile: its cosine to the
+// positive centroid minus its cosine to the negative one. ok is false
+// if the profile is empty or v is of another dimension.
+func (p Profile) Score(v []float32) (score float64, ok bool) {
+	if len(p.Positive) == len(v) && len(v) > 0 {
+		score += float64(embed.CosineSimilarity(v, p.Positive))
+		ok = true
+	}
+	if len(p.Negative) == len(v) && len(v) > 0 {
+		score -= float64(embed.CosineSimilarity(v, p.Negative))
+		ok = true
+	}
+	return score, ok
+}
+
+// Scores returns the score of every vector the profile can score.
+func (p Profile) Scores(vectors map[string][]float32) map[string]float64 {
+	if p.Empty() {
+		return nil
+	}
+	scores := make(map[string]float64, len(vectors))
+	for id, v := range vectors {
+		if s, ok := p.Score(v); ok {
+			scores[id] = s
+		}
+	}
+	return scores
+}
+
+// Counts returns how many signals of each kind the profile holds.
+func (p Profile) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, c := range p.Contributions {
+		counts[c.Kind]++
+	}
+	return counts
+}
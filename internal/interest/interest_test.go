@@ -0,0 +1,65 @@
+package interest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+func TestBuild(t *testing.T) {
+	now := time.Now()
+	signals := []store.Signal{
+		{Item: store.Item{ID: "read-old"}, Kind: store.SignalRead, At: now.Add(-HalfLife)},
+		{Item: store.Item{ID: "saved"}, Kind: store.SignalSaved, At: now},
+		{Item: store.Item{ID: "noise"}, Kind: store.SignalExcluded, At: now},
+		{Item: store.Item{ID: "no-vector"}, Kind: store.SignalRead, At: now},
+	}
+	vectors := map[string][]float32{
+		"read-old": {0, 1, 0},
+		"saved":    {1, 0, 0},
+		"noise":    {0, 0, 1},
+	}
+	p := Build("m", signals, vectors, now)
+
+	var ids []string
+	for _, c := range p.Contributions {
+		ids = append(ids, c.Item.ID)
+	}
+	if len(ids) != 4 || ids[0] != "saved" || ids[1] != "noise" || ids[2] != "read-old" || ids[3] != "no-vector" {
+		t.Fatalf("contributions = %v, want saved, noise, read-old, no-vector", ids)
+	}
+	if w := p.Contributions[2].Weight; math.Abs(w-0.5) > 1e-9 {
+		t.Errorf("read a half-life ago weighs %v, want 0.5", w)
+	}
+	if w := p.Contributions[1].Weight; w != -1 {
+		t.Errorf("fresh exclusion weighs %v, want -1", w)
+	}
+	if c := p.Contributions[3]; c.Embedded || c.Weight != 0 {
+		t.Errorf("unembedded signal = %+v, want no weight", c)
+	}
+	// Saved (3) and a half-decayed read (0.5) average to 3/3.5, 0.5/3.5.
+	if got := p.Positive; len(got) != 3 || math.Abs(float64(got[0])-3/3.5) > 1e-6 || math.Abs(float64(got[1])-0.5/3.5) > 1e-6 {
+		t.Errorf("positive centroid = %v", got)
+	}
+
+	liked, _ := p.Score([]float32{1, 0, 0})
+	excluded, _ := p.Score([]float32{0, 0, 1})
+	if liked <= 0 || excluded >= 0 {
+		t.Errorf("scores: like saved %v, like excluded %v", liked, excluded)
+	}
+	if _, ok := p.Score([]float32{1, 0}); ok {
+		t.Error("scored a vector of another dimension")
+	}
+	if counts := p.Counts(); counts[store.SignalRead] != 2 || counts[store.SignalExcluded] != 1 {
+		t.Errorf("counts = %v", counts)
+	}
+}
+
+func TestEmptyProfile(t *testing.T) {
+	p := Build("m", []store.Signal{{Item: store.Item{ID: "a"}, Kind: store.SignalRead, At: time.Now()}}, nil, time.Now())
+	if !p.Empty() || p.Scores(map[string][]float32{"x": {1}}) != nil {
+		t.Errorf("profile without vectors should be empty: %+v", p)
+	}
+}
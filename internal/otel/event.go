@@ -44,9 +44,19 @@ const (
 	KindSearchComplete EventKind = "search.complete"
 	KindSearchCancel   EventKind = "search.cancel"
 
+	// Backend events (embed/rerank middleware and fallback chains)
+	KindBackendCall    EventKind = "backend.call"
+	KindBackendBreaker EventKind = "backend.breaker" // circuit breaker opened or closed
+	KindBackendSwitch  EventKind = "backend.switch"  // fallback chain moved to another backend
+
 	// Store events
 	KindStoreError EventKind = "store.error"
 
+	// Daemon events
+	KindDaemonStart   EventKind = "daemon.start"
+	KindDaemonRequest EventKind = "daemon.request"
+	KindDaemonAttach  EventKind = "daemon.attach"
+
 	// UI events
 	KindKeyPress   EventKind = "ui.key"
 	KindViewRender EventKind = "ui.render"
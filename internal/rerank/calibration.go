@@ -0,0 +1,222 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Calibration maps a backend's raw scores to probabilities of relevance
+// with a logistic curve (Platt scaling): p = 1 / (1 + exp(-(A*score + B))).
+// Raw scores from different backends live on different scales (Jina's
+// squashed logits, Ollama's yes/no probabilities, BM25F, cosine
+// similarity); calibrated ones can share one threshold.
+type Calibration struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+	// N is the number of labelled query-document pairs it was fitted on.
+	N        int       `json:"n"`
+	FittedAt time.Time `json:"fitted_at"`
+}
+
+// Apply returns the calibrated probability that score means relevant.
+func (c Calibration) Apply(score float32) float32 {
+	return float32(1 / (1 + math.Exp(-(c.A*float64(score) + c.B))))
+}
+
+// Midpoint returns the raw score that calibrates to 0.5.
+func (c Calibration) Midpoint() float64 {
+	if c.A == 0 {
+		return math.NaN()
+	}
+	return -c.B / c.A
+}
+
+// FitCalibration fits a Calibration to raw scores and their relevance
+// labels by maximum likelihood, using Platt's smoothed targets so that
+// perfectly separated scores still give a finite curve. It needs at
+// least one relevant and one irrelevant example.
+func FitCalibration(scores []float32, relevant []bool) (Calibration, error) {
+	if len(scores) != len(relevant) {
+		return Calibration{}, fmt.Errorf("calibrate: %d scores for %d labels", len(scores), len(relevant))
+	}
+	var pos, neg float64
+	for _, r := range relevant {
+		if r {
+			pos++
+		} else {
+			neg++
+		}
+	}
+	if pos == 0 || neg == 0 {
+		return Calibration{}, errors.New("calibrate: need both relevant and irrelevant examples")
+	}
+
+	hi, lo := (pos+1)/(pos+2), 1/(neg+2)
+	targets := make([]float64, len(scores))
+	for i, r := range relevant {
+		targets[i] = lo
+		if r {
+			targets[i] = hi
+		}
+	}
+	loss := func(a, b float64) float64 {
+		var l float64
+		for i, s := range scores {
+			z := a*float64(s) + b
+			l += targets[i]*softplus(-z) + (1-targets[i])*softplus(z)
+		}
+		return l
+	}
+
+	// Newton's method with a backtracking line search (Lin, Lin & Weng,
+	// "A note on Platt's probabilistic outputs", 2007).
+	a, b := 0.0, math.Log((pos+1)/(neg+1))
+	current := loss(a, b)
+	for iter := 0; iter < 100; iter++ {
+		var ga, gb, haa, hab, hbb float64
+		for i, s := range scores {
+			f := float64(s)
+			p := 1 / (1 + math.Exp(-(a*f + b)))
+			d := p - targets[i]
+			ga += d * f
+			gb += d
+			w := p * (1 - p)
+			haa += w * f * f
+			hab += w * f
+			hbb += w
+		}
+		if math.Abs(ga) < 1e-6 && math.Abs(gb) < 1e-6 {
+			break
+		}
+		haa, hbb = haa+1e-12, hbb+1e-12
+		det := haa*hbb - hab*hab
+		da := -(hbb*ga - hab*gb) / det
+		db := -(haa*gb - hab*ga) / det
+		slope := ga*da + gb*db
+
+		step := 1.0
+		for ; step >= 1e-10; step /= 2 {
+			na, nb := a+step*da, b+step*db
+			if l := loss(na, nb); l < current+1e-4*step*slope {
+				a, b, current = na, nb, l
+				break
+			}
+		}
+		if step < 1e-10 {
+			break
+		}
+	}
+	return Calibration{A: a, B: b, N: len(scores), FittedAt: time.Now()}, nil
+}
+
+// softplus returns log(1 + e^x) without overflow.
+func softplus(x float64) float64 {
+	if x > 30 {
+		return x
+	}
+	return math.Log1p(math.Exp(x))
+}
+
+// Calibrations holds fitted calibrations by reranker Name, and by
+// CosineKey for embedding models.
+type Calibrations map[string]Calibration
+
+// CalibrationPath returns the path of the calibration file in dataDir.
+func CalibrationPath(dataDir string) string {
+	return filepath.Join(dataDir, "calibration.json")
+}
+
+// CosineKey returns the Calibrations key for cosine similarities between
+// vectors from the embedding model.
+func CosineKey(model string) string {
+	return "cosine/" + model
+}
+
+// LoadCalibrations reads the calibration file at path. A missing file
+// returns no calibrations.
+func LoadCalibrations(path string) (Calibrations, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Calibrations{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read calibrations: %w", err)
+	}
+	c := Calibrations{}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse calibrations %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the calibrations to path.
+func (c Calibrations) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal calibrations: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write calibrations: %w", err)
+	}
+	return nil
+}
+
+// Wrap returns r with its calibration applied, or r unchanged if it has
+// none.
+func (c Calibrations) Wrap(r Reranker) Reranker {
+	if cal, ok := c[r.Name()]; ok {
+		return WithCalibration(r, cal)
+	}
+	return r
+}
+
+// Cosine calibrates a cosine similarity between vectors from model.
+// ok is false if the model has no calibration.
+func (c Calibrations) Cosine(model string, similarity float32) (p float32, ok bool) {
+	cal, ok := c[CosineKey(model)]
+	if !ok {
+		return 0, false
+	}
+	return cal.Apply(similarity), true
+}
+
+// calibrated is a Reranker whose scores are mapped through a Calibration.
+type calibrated struct {
+	*decorated
+	cal Calibration
+}
+
+// WithCalibration maps r's scores through c. Name, AutoReranks and
+// Available pass through, so calibrated rerankers chain and cache like
+// their backends.
+func WithCalibration(r Reranker, c Calibration) Reranker {
+	return &calibrated{
+		decorated: &decorated{inner: r, intercept: func(ctx context.Context, n int, call func(context.Context) error) error {
+			return call(ctx)
+		}},
+		cal: c,
+	}
+}
+
+func (c *calibrated) Rerank(ctx context.Context, query string, documents []string) ([]Score, error) {
+	scores, err := c.decorated.Rerank(ctx, query, documents)
+	return c.apply(scores), err
+}
+
+func (c *calibrated) RerankDocuments(ctx context.Context, query string, queryEmbedding []float32, docs []Document) ([]Score, error) {
+	scores, err := c.decorated.RerankDocuments(ctx, query, queryEmbedding, docs)
+	return c.apply(scores), err
+}
+
+func (c *calibrated) apply(scores []Score) []Score {
+	for i := range scores {
+		scores[i].Score = c.cal.Apply(scores[i].Score)
+	}
+	return scores
+}
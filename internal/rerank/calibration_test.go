@@ -0,0 +1,121 @@
+package rerank
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestFitCalibrationPutsBackendsOnOneScale(t *testing.T) {
+	// Two backends rank the same pairs correctly on very different scales:
+	// one bunches everything near 0, the other spreads over [0.2, 1].
+	relevant := []bool{true, true, true, false, false, false, false, false}
+	low := []float32{0.09, 0.12, 0.15, 0.01, 0.02, 0.04, 0.03, 0.07}
+	high := []float32{0.82, 0.95, 0.99, 0.20, 0.35, 0.55, 0.41, 0.62}
+
+	for name, scores := range map[string][]float32{"low": low, "high": high} {
+		c, err := FitCalibration(scores, relevant)
+		if err != nil {
+			t.Fatalf("%s: FitCalibration() error = %v", name, err)
+		}
+		if c.N != len(scores) || c.A <= 0 {
+			t.Errorf("%s: %+v, want N = %d and A > 0", name, c, len(scores))
+		}
+		for i, s := range scores {
+			p := c.Apply(s)
+			if relevant[i] && p <= 0.5 || !relevant[i] && p >= 0.5 {
+				t.Errorf("%s: score %v (relevant %v) calibrated to %v", name, s, relevant[i], p)
+			}
+		}
+		if m := c.Midpoint(); m < float64(scores[7]) || m > float64(scores[0]) {
+			t.Errorf("%s: midpoint %v outside the gap between the classes", name, m)
+		}
+	}
+
+	if _, err := FitCalibration([]float32{0.1, 0.2}, []bool{true, true}); err == nil {
+		t.Error("expected an error without irrelevant examples")
+	}
+	if _, err := FitCalibration([]float32{0.1}, []bool{true, false}); err == nil {
+		t.Error("expected an error for mismatched lengths")
+	}
+}
+
+func TestFitCalibrationOverlappingScores(t *testing.T) {
+	// Noisy labels: the fit should stay finite and monotonic.
+	scores := []float32{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+	relevant := []bool{false, false, true, false, true, false, true, true, true}
+	c, err := FitCalibration(scores, relevant)
+	if err != nil {
+		t.Fatalf("FitCalibration() error = %v", err)
+	}
+	if math.IsNaN(c.A) || math.IsInf(c.A, 0) || c.A <= 0 {
+		t.Fatalf("A = %v, want a finite positive slope", c.A)
+	}
+	if c.Apply(0.1) >= c.Apply(0.9) {
+		t.Errorf("calibration not increasing: %v >= %v", c.Apply(0.1), c.Apply(0.9))
+	}
+}
+
+func TestCalibrationsSaveLoad(t *testing.T) {
+	path := CalibrationPath(t.TempDir())
+	c, err := LoadCalibrations(path)
+	if err != nil || len(c) != 0 {
+		t.Fatalf("LoadCalibrations(missing) = %v, %v; want empty", c, err)
+	}
+
+	c["jina/jina-reranker-v3"] = Calibration{A: 12, B: -6, N: 96}
+	c[CosineKey("local/hash-ngram-v1")] = Calibration{A: 20, B: -8, N: 96}
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadCalibrations(path)
+	if err != nil {
+		t.Fatalf("LoadCalibrations() error = %v", err)
+	}
+	if loaded["jina/jina-reranker-v3"].A != 12 || loaded["jina/jina-reranker-v3"].N != 96 {
+		t.Errorf("loaded %+v", loaded)
+	}
+	if p, ok := loaded.Cosine("local/hash-ngram-v1", 0.4); !ok || p != (Calibration{A: 20, B: -8}).Apply(0.4) {
+		t.Errorf("Cosine() = %v, %v", p, ok)
+	}
+	if _, ok := loaded.Cosine("other", 0.4); ok {
+		t.Error("Cosine() ok for an uncalibrated model")
+	}
+
+	if _, err := LoadCalibrations(filepath.Join(t.TempDir())); err == nil {
+		t.Error("expected an error reading a directory")
+	}
+}
+
+func TestWithCalibration(t *testing.T) {
+	c := Calibrations{LocalName: {A: 10, B: -5}}
+	r := c.Wrap(NewLocalReranker())
+	if r.Name() != LocalName || !r.Available() {
+		t.Errorf("Name/Available = %q/%v, want pass-through", r.Name(), r.Available())
+	}
+	if ar, ok := r.(AutoReranker); !ok || !ar.AutoReranks() {
+		t.Error("AutoReranks should pass through")
+	}
+
+	docs := []Document{{Title: "Volcano erupts in Iceland"}, {Title: "Gardening tips"}}
+	raw, _ := NewLocalReranker().RerankDocuments(context.Background(), "volcano", nil, docs)
+	scores, err := RerankDocuments(context.Background(), r, "volcano", nil, docs)
+	if err != nil {
+		t.Fatalf("RerankDocuments() error = %v", err)
+	}
+	for i, s := range scores {
+		if want := c[LocalName].Apply(raw[i].Score); s.Score != want {
+			t.Errorf("scores[%d] = %v, want calibrated %v", i, s.Score, want)
+		}
+	}
+	if scores, _ := r.Rerank(context.Background(), "volcano", []string{"Gardening tips"}); scores[0].Score != c[LocalName].Apply(0) {
+		t.Errorf("Rerank() = %v, want calibrated", scores)
+	}
+
+	if plain := c.Wrap(&flakyReranker{}); plain.Name() != "flaky" {
+		t.Error("Wrap should leave uncalibrated rerankers alone")
+	} else if _, ok := plain.(*calibrated); ok {
+		t.Error("Wrap wrapped an uncalibrated reranker")
+	}
+}
@@ -0,0 +1,162 @@
+package rerank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// FallbackCooldown is how long a failed backend is skipped before the
+// chain tries it again.
+const FallbackCooldown = 30 * time.Second
+
+// NameReporter is implemented by rerankers whose backend can change from
+// call to call (FallbackReranker). RerankWithName also returns the Name
+// of the reranker that produced the scores.
+type NameReporter interface {
+	RerankWithName(ctx context.Context, query string, documents []string) ([]Score, string, error)
+}
+
+// RerankWithName reranks documents and returns the name of the reranker
+// that scored them. Scores from different rerankers are not comparable.
+func RerankWithName(ctx context.Context, r Reranker, query string, documents []string) ([]Score, string, error) {
+	if nr, ok := r.(NameReporter); ok {
+		return nr.RerankWithName(ctx, query, documents)
+	}
+	scores, err := r.Rerank(ctx, query, documents)
+	return scores, r.Name(), err
+}
+
+// RerankDocumentsWithName is RerankDocuments that also returns the name
+// of the reranker that scored the documents, like RerankWithName.
+func RerankDocumentsWithName(ctx context.Context, r Reranker, query string, queryEmbedding []float32, docs []Document) ([]Score, string, error) {
+	if f, ok := r.(*FallbackReranker); ok {
+		return f.RerankDocumentsWithName(ctx, query, queryEmbedding, docs)
+	}
+	scores, err := RerankDocuments(ctx, r, query, queryEmbedding, docs)
+	return scores, r.Name(), err
+}
+
+// FallbackReranker tries an ordered list of rerankers, most preferred
+// first, and switches to the next when one is down, out of quota or
+// unavailable. A failed backend is skipped for FallbackCooldown and then
+// preferred again. Each call is scored entirely by one backend.
+type FallbackReranker struct {
+	backends []Reranker
+	failover *resilience.Failover
+}
+
+// NewFallbackReranker creates a chain over backends, most preferred first.
+// onSwitch (optional) is called with the old and new reranker names when a
+// different backend starts serving calls, and the failure that caused it
+// (nil when returning to a preferred backend).
+func NewFallbackReranker(onSwitch func(from, to string, cause error), backends ...Reranker) *FallbackReranker {
+	f := &FallbackReranker{backends: backends}
+	var hook func(from, to int, cause error)
+	if onSwitch != nil {
+		hook = func(from, to int, cause error) {
+			onSwitch(backends[from].Name(), backends[to].Name(), cause)
+		}
+	}
+	f.failover = resilience.NewFailover(len(backends), FallbackCooldown, hook)
+	return f
+}
+
+// Name returns the name of the reranker the next call will try first.
+func (f *FallbackReranker) Name() string {
+	if order := f.order(); len(order) > 0 {
+		return f.backends[order[0]].Name()
+	}
+	return f.backends[f.failover.Active()].Name()
+}
+
+// Active returns the name of the reranker that served the most recent call.
+func (f *FallbackReranker) Active() string {
+	return f.backends[f.failover.Active()].Name()
+}
+
+// Primary returns the name of the most preferred reranker.
+func (f *FallbackReranker) Primary() string { return f.backends[0].Name() }
+
+// AutoReranks follows the primary: the UI picks its rerank mode once, at
+// startup.
+func (f *FallbackReranker) AutoReranks() bool {
+	if ar, ok := f.backends[0].(AutoReranker); ok {
+		return ar.AutoReranks()
+	}
+	return false
+}
+
+// Available returns true if any backend is available.
+func (f *FallbackReranker) Available() bool {
+	for _, b := range f.backends {
+		if b.Available() {
+			return true
+		}
+	}
+	return false
+}
+
+// Rerank scores documents with the first backend that succeeds.
+func (f *FallbackReranker) Rerank(ctx context.Context, query string, documents []string) ([]Score, error) {
+	scores, _, err := f.RerankWithName(ctx, query, documents)
+	return scores, err
+}
+
+// RerankWithName implements NameReporter.
+func (f *FallbackReranker) RerankWithName(ctx context.Context, query string, documents []string) ([]Score, string, error) {
+	return f.try(ctx, func(r Reranker) ([]Score, error) {
+		return r.Rerank(ctx, query, documents)
+	})
+}
+
+// RerankDocuments implements DocumentReranker: each backend gets the
+// documents in the form it takes.
+func (f *FallbackReranker) RerankDocuments(ctx context.Context, query string, queryEmbedding []float32, docs []Document) ([]Score, error) {
+	scores, _, err := f.RerankDocumentsWithName(ctx, query, queryEmbedding, docs)
+	return scores, err
+}
+
+// RerankDocumentsWithName is RerankDocuments that also returns the name
+// of the reranker that scored the documents.
+func (f *FallbackReranker) RerankDocumentsWithName(ctx context.Context, query string, queryEmbedding []float32, docs []Document) ([]Score, string, error) {
+	return f.try(ctx, func(r Reranker) ([]Score, error) {
+		return RerankDocuments(ctx, r, query, queryEmbedding, docs)
+	})
+}
+
+// try runs call on the first backend that succeeds and returns its name.
+func (f *FallbackReranker) try(ctx context.Context, call func(Reranker) ([]Score, error)) ([]Score, string, error) {
+	order := f.order()
+	if len(order) == 0 {
+		return nil, "", errors.New("rerank: no backend available")
+	}
+	var lastErr error
+	for _, i := range order {
+		scores, err := call(f.backends[i])
+		if err == nil {
+			f.failover.Success(i)
+			return scores, f.backends[i].Name(), nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", err
+		}
+		f.failover.Failure(i, err)
+		if !errors.Is(err, resilience.ErrBreakerOpen) && !resilience.BackendFault(err) {
+			return nil, "", err
+		}
+		lastErr = err
+	}
+	if len(order) == 1 {
+		return nil, "", lastErr
+	}
+	return nil, "", fmt.Errorf("rerank: all %d backends failed, last: %w", len(order), lastErr)
+}
+
+// order returns the indices of the backends to try, in order.
+func (f *FallbackReranker) order() []int {
+	return f.failover.Order(func(i int) bool { return f.backends[i].Available() })
+}
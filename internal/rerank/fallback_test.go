@@ -0,0 +1,59 @@
+package rerank
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// namedReranker is a flakyReranker with a name.
+type namedReranker struct {
+	flakyReranker
+	name string
+	auto bool
+}
+
+func (n *namedReranker) Name() string      { return n.name }
+func (n *namedReranker) AutoReranks() bool { return n.auto }
+
+func TestFallbackRerankerSwitchesOnBackendFault(t *testing.T) {
+	primary := &namedReranker{flakyReranker{failures: 1, err: &resilience.StatusError{Code: http.StatusTooManyRequests}}, "jina", true}
+	backup := &namedReranker{name: "ollama"}
+	var to string
+	f := NewFallbackReranker(func(_, next string, _ error) { to = next }, primary, backup)
+
+	if !f.AutoReranks() {
+		t.Error("AutoReranks() should follow the primary")
+	}
+	scores, name, err := RerankWithName(context.Background(), f, "q", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("RerankWithName() error = %v", err)
+	}
+	if name != "ollama" || len(scores) != 2 || to != "ollama" {
+		t.Errorf("scored by %q (switch to %q), %d scores; want ollama, 2", name, to, len(scores))
+	}
+	if f.Name() != "ollama" || f.Primary() != "jina" {
+		t.Errorf("Name() = %q, Primary() = %q", f.Name(), f.Primary())
+	}
+}
+
+func TestFallbackRerankerKeepsBackendOnBadInput(t *testing.T) {
+	primary := &namedReranker{flakyReranker{failures: 1, err: &resilience.StatusError{Code: http.StatusBadRequest}}, "jina", true}
+	backup := &namedReranker{name: "ollama"}
+	f := NewFallbackReranker(nil, primary, backup)
+	if _, err := f.Rerank(context.Background(), "q", []string{"a"}); err == nil {
+		t.Fatal("expected the 400 to be returned")
+	}
+	if backup.calls != 0 {
+		t.Error("a rejected input should not fail over")
+	}
+}
+
+func TestRerankWithNamePlainReranker(t *testing.T) {
+	_, name, err := RerankWithName(context.Background(), &flakyReranker{}, "q", []string{"a"})
+	if err != nil || name != "flaky" {
+		t.Errorf("RerankWithName() = %q, %v; want flaky", name, err)
+	}
+}
@@ -7,19 +7,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 
-	"golang.org/x/time/rate"
+	"github.com/abelbrown/observer/internal/resilience"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
 // JinaReranker scores documents against queries using the Jina AI rerank API.
+// It makes a single attempt per call; wrap it with WithRetry for transient
+// failures.
 type JinaReranker struct {
 	apiKey   string
 	model    string
 	endpoint string
 	client   *http.Client
-	limiter  *rate.Limiter
+	meter    *usage.Meter // optional usage accounting
 }
 
 // NewJinaReranker creates a reranker using the Jina AI rerank API.
@@ -32,11 +34,15 @@ func NewJinaReranker(apiKey, model string) *JinaReranker {
 		apiKey:   apiKey,
 		model:    model,
 		endpoint: "https://api.jina.ai/v1/rerank",
-		client:  &http.Client{Timeout: 30 * time.Second},
-		limiter: rate.NewLimiter(rate.Inf, 1), // no throttle — reranker is only used for interactive search
+		client:   &http.Client{Timeout: 30 * time.Second},
 	}
 }
 
+// SetUsageRecorder records every API call (tokens, characters, latency) to rec.
+func (r *JinaReranker) SetUsageRecorder(rec usage.Recorder) {
+	r.meter = usage.NewMeter(rec, "jina", r.model, usage.KindRerank)
+}
+
 // Available returns true if the Jina API key is configured.
 func (r *JinaReranker) Available() bool {
 	return r.apiKey != ""
@@ -58,10 +64,6 @@ func (r *JinaReranker) Rerank(ctx context.Context, query string, documents []str
 		return nil, nil
 	}
 
-	if err := r.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter: %w", err)
-	}
-
 	reqBody := jinaRerankRequest{
 		Model:     r.model,
 		Query:     query,
@@ -74,15 +76,19 @@ func (r *JinaReranker) Rerank(ctx context.Context, query string, documents []str
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	respBody, err := r.doWithRetry(ctx, jsonBody)
+	start := time.Now()
+	respBody, err := r.do(ctx, jsonBody)
 	if err != nil {
+		r.meter.Observe(ctx, start, 0, requestChars(query, documents), err)
 		return nil, err
 	}
 
 	var jinaResp jinaRerankResponse
 	if err := json.Unmarshal(respBody, &jinaResp); err != nil {
+		r.meter.Observe(ctx, start, 0, requestChars(query, documents), err)
 		return nil, fmt.Errorf("parse response: %w", err)
 	}
+	r.meter.Observe(ctx, start, jinaResp.Usage.TotalTokens, requestChars(query, documents), nil)
 
 	// Initialize all scores to 0.
 	scores := make([]Score, len(documents))
@@ -103,89 +109,34 @@ func (r *JinaReranker) Rerank(ctx context.Context, query string, documents []str
 	return scores, nil
 }
 
-// doWithRetry executes the Jina API request with retry logic for transient errors.
-// Retries up to 3 times on HTTP 429 or 5xx with exponential backoff (1s, 2s, 4s).
-// Honors the Retry-After header on 429 responses.
-func (r *JinaReranker) doWithRetry(ctx context.Context, jsonBody []byte) ([]byte, error) {
-	maxRetries := 3
-	backoffs := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second}
-
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(jsonBody))
-		if err != nil {
-			return nil, fmt.Errorf("create request: %w", err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+r.apiKey)
-
-		resp, err := r.client.Do(req)
-		if err != nil {
-			if ctx.Err() != nil {
-				return nil, fmt.Errorf("request cancelled: %w", ctx.Err())
-			}
-			lastErr = fmt.Errorf("request failed: %w", err)
-			if attempt < maxRetries {
-				select {
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				case <-time.After(backoffs[attempt]):
-				}
-			}
-			continue
-		}
-
-		body, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-		resp.Body.Close()
-		if readErr != nil {
-			lastErr = fmt.Errorf("read response: %w", readErr)
-			if attempt < maxRetries {
-				select {
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				case <-time.After(backoffs[attempt]):
-				}
-			}
-			continue
-		}
-
-		if resp.StatusCode == http.StatusOK {
-			return body, nil
-		}
+// do sends one request to the Jina API and returns the response body.
+// Non-200 responses are returned as *resilience.StatusError; retrying
+// is left to WithRetry.
+func (r *JinaReranker) do(ctx context.Context, jsonBody []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
 
-		// Retry on 429 (rate limited) or 5xx (server error).
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-			lastErr = fmt.Errorf("jina API error (status %d): %s", resp.StatusCode, string(body))
-
-			if attempt < maxRetries {
-				delay := backoffs[attempt]
-
-				// Honor Retry-After header on 429.
-				if resp.StatusCode == http.StatusTooManyRequests {
-					if ra := resp.Header.Get("Retry-After"); ra != "" {
-						if seconds, parseErr := strconv.Atoi(ra); parseErr == nil && seconds > 0 {
-							delay = time.Duration(seconds) * time.Second
-							if delay > 30*time.Second {
-								delay = 30 * time.Second
-							}
-						}
-					}
-				}
-
-				select {
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				case <-time.After(delay):
-				}
-			}
-			continue
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("request cancelled: %w", ctx.Err())
 		}
-
-		// Non-retryable error (e.g. 400, 401, 403).
-		return nil, fmt.Errorf("jina API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
+	defer resp.Body.Close()
 
-	return nil, fmt.Errorf("jina API request failed after %d retries: %w", maxRetries, lastErr)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rerank: %w", resilience.NewStatusError("jina", resp, string(body)))
+	}
+	return body, nil
 }
 
 // jinaRerankRequest is the request body for the Jina rerank API.
@@ -199,6 +150,9 @@ type jinaRerankRequest struct {
 // jinaRerankResponse is the response body from the Jina rerank API.
 type jinaRerankResponse struct {
 	Results []jinaRerankResult `json:"results"`
+	Usage   struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
 }
 
 // jinaRerankResult is a single document's relevance score from the Jina API.
@@ -9,9 +9,12 @@ import (
 	"testing"
 	"time"
 
-	"golang.org/x/time/rate"
+	"github.com/abelbrown/observer/internal/resilience"
 )
 
+// fastRetry is the default policy with test-sized delays.
+var fastRetry = resilience.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
 func TestJinaRerankerAvailable(t *testing.T) {
 	r := NewJinaReranker("test-key", "")
 	if !r.Available() {
@@ -86,7 +89,6 @@ func TestJinaRerankerRerank(t *testing.T) {
 
 	r := NewJinaReranker("test-key", "jina-reranker-v3")
 	r.endpoint = server.URL
-	r.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	docs := []string{
 		"Global warming accelerates ice melt",
@@ -117,7 +119,6 @@ func TestJinaRerankerRerank(t *testing.T) {
 
 func TestJinaRerankerEmptyDocs(t *testing.T) {
 	r := NewJinaReranker("test-key", "jina-reranker-v3")
-	r.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	scores, err := r.Rerank(context.Background(), "query", nil)
 	if err != nil {
@@ -158,9 +159,8 @@ func TestJinaRerankerRetryOn429(t *testing.T) {
 
 	r := NewJinaReranker("test-key", "jina-reranker-v3")
 	r.endpoint = server.URL
-	r.limiter = rate.NewLimiter(rate.Inf, 1)
 
-	scores, err := r.Rerank(context.Background(), "query", []string{"doc1"})
+	scores, err := WithRetry(r, fastRetry).Rerank(context.Background(), "query", []string{"doc1"})
 	if err != nil {
 		t.Fatalf("Rerank() error: %v", err)
 	}
@@ -199,9 +199,8 @@ func TestJinaRerankerRetryOn500(t *testing.T) {
 
 	r := NewJinaReranker("test-key", "jina-reranker-v3")
 	r.endpoint = server.URL
-	r.limiter = rate.NewLimiter(rate.Inf, 1)
 
-	scores, err := r.Rerank(context.Background(), "query", []string{"doc1"})
+	scores, err := WithRetry(r, fastRetry).Rerank(context.Background(), "query", []string{"doc1"})
 	if err != nil {
 		t.Fatalf("Rerank() error: %v", err)
 	}
@@ -230,9 +229,8 @@ func TestJinaRerankerServerError(t *testing.T) {
 
 	r := NewJinaReranker("test-key", "jina-reranker-v3")
 	r.endpoint = server.URL
-	r.limiter = rate.NewLimiter(rate.Inf, 1)
 
-	_, err := r.Rerank(context.Background(), "query", []string{"doc1"})
+	_, err := WithRetry(r, fastRetry).Rerank(context.Background(), "query", []string{"doc1"})
 	if err == nil {
 		t.Fatal("expected error for 400 response, got nil")
 	}
@@ -258,7 +256,6 @@ func TestJinaRerankerContextCancellation(t *testing.T) {
 
 	r := NewJinaReranker("test-key", "jina-reranker-v3")
 	r.endpoint = server.URL
-	r.limiter = rate.NewLimiter(rate.Inf, 1)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately.
@@ -0,0 +1,344 @@
+package rerank
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Document is a structured document for rerankers that score fields
+// separately. Text flattens it for the plain Reranker interface.
+type Document struct {
+	Title     string
+	Summary   string
+	Author    string
+	Published time.Time // zero: unknown
+	Embedding []float32 // optional, blended by LocalReranker
+}
+
+// Text returns the document as the "title - summary" string rerankers
+// are given by the UI.
+func (d Document) Text() string {
+	if d.Summary != "" {
+		return d.Title + " - " + d.Summary
+	}
+	return d.Title
+}
+
+// DocumentReranker is an optional extension of Reranker for backends
+// that use document fields and embeddings (LocalReranker). queryEmbedding
+// may be nil; document embeddings must come from the same model.
+type DocumentReranker interface {
+	RerankDocuments(ctx context.Context, query string, queryEmbedding []float32, docs []Document) ([]Score, error)
+}
+
+// RerankDocuments scores docs with r, using its field-aware scoring when
+// r implements DocumentReranker and the flattened Text otherwise.
+func RerankDocuments(ctx context.Context, r Reranker, query string, queryEmbedding []float32, docs []Document) ([]Score, error) {
+	if dr, ok := r.(DocumentReranker); ok {
+		return dr.RerankDocuments(ctx, query, queryEmbedding, docs)
+	}
+	return r.Rerank(ctx, query, documentTexts(docs))
+}
+
+func documentTexts(docs []Document) []string {
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = d.Text()
+	}
+	return texts
+}
+
+// BM25F parameters. Titles are short and dense, so they weigh most and
+// are barely length-normalized; a query naming the author is a strong
+// signal but author fields have no meaningful length.
+const (
+	bm25K1 = 1.2
+
+	titleWeight   = 3.0
+	summaryWeight = 1.0
+	authorWeight  = 2.0
+
+	titleB   = 0.3
+	summaryB = 0.75
+
+	// recencyHalfLife halves the recency feature every 24h.
+	recencyHalfLife = 24 * time.Hour
+)
+
+// DefaultCosineWeight is the share of the text score given to embedding
+// similarity when both the query and a document have vectors.
+const DefaultCosineWeight = 0.3
+
+// LocalReranker scores documents in-process, without a model: BM25F over
+// title, summary and author, with a bonus for query terms appearing close
+// together and for recent documents. It is the last resort when no
+// cross-encoder is configured or reachable. Term statistics come from
+// the candidate set, so scores are relative to the documents in a call.
+type LocalReranker struct {
+	// CosineWeight blends max(0, cosine) between the query and document
+	// embeddings into the text score, when both are given.
+	CosineWeight float64
+
+	now func() time.Time // for tests
+}
+
+// NewLocalReranker creates a LocalReranker with DefaultCosineWeight.
+func NewLocalReranker() *LocalReranker {
+	return &LocalReranker{CosineWeight: DefaultCosineWeight, now: time.Now}
+}
+
+// LocalName is the Name of LocalReranker.
+const LocalName = "local/bm25f"
+
+// Cacheable reports whether scores from the reranker called name may be
+// cached and reused. Cross-encoder scores depend only on the query and
+// the document; LocalReranker's depend on the other candidates and the
+// clock, and are cheaper to recompute than to look up.
+func Cacheable(name string) bool {
+	return name != "" && name != LocalName
+}
+
+// Name returns LocalName.
+func (l *LocalReranker) Name() string { return LocalName }
+
+// Available always returns true.
+func (l *LocalReranker) Available() bool { return true }
+
+// AutoReranks returns true: scoring a page of results takes microseconds.
+func (l *LocalReranker) AutoReranks() bool { return true }
+
+// Rerank scores "title - summary" documents, as built by Document.Text.
+func (l *LocalReranker) Rerank(ctx context.Context, query string, documents []string) ([]Score, error) {
+	docs := make([]Document, len(documents))
+	for i, d := range documents {
+		title, summary, _ := strings.Cut(d, " - ")
+		docs[i] = Document{Title: title, Summary: summary}
+	}
+	return l.RerankDocuments(ctx, query, nil, docs)
+}
+
+// fieldTokens is a document's tokens per BM25F field.
+type fieldTokens struct {
+	title, summary, author []string
+}
+
+// RerankDocuments implements DocumentReranker. Scores are in [0, 1].
+func (l *LocalReranker) RerankDocuments(ctx context.Context, query string, queryEmbedding []float32, docs []Document) ([]Score, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	scores := make([]Score, len(docs))
+	for i := range scores {
+		scores[i].Index = i
+	}
+	terms := uniqueTerms(tokenize(query))
+	if len(docs) == 0 || (len(terms) == 0 && len(queryEmbedding) == 0) {
+		return scores, nil
+	}
+
+	fields := make([]fieldTokens, len(docs))
+	var titleLen, summaryLen float64
+	df := make(map[string]int, len(terms))
+	for i, d := range docs {
+		f := fieldTokens{title: tokenize(d.Title), summary: tokenize(d.Summary), author: tokenize(d.Author)}
+		fields[i] = f
+		titleLen += float64(len(f.title))
+		summaryLen += float64(len(f.summary))
+		for _, t := range terms {
+			if contains(f.title, t) || contains(f.summary, t) || contains(f.author, t) {
+				df[t]++
+			}
+		}
+	}
+	n := float64(len(docs))
+	avgTitle, avgSummary := math.Max(titleLen/n, 1), math.Max(summaryLen/n, 1)
+
+	idf := make(map[string]float64, len(terms))
+	var idfSum float64
+	for _, t := range terms {
+		d := float64(df[t])
+		idf[t] = math.Log(1 + (n-d+0.5)/(d+0.5))
+		idfSum += idf[t]
+	}
+
+	now := time.Now()
+	if l.now != nil {
+		now = l.now()
+	}
+	for i, d := range docs {
+		f := fields[i]
+		var bm25 float64
+		for _, t := range terms {
+			tf := titleWeight*count(f.title, t)/(1-titleB+titleB*float64(len(f.title))/avgTitle) +
+				summaryWeight*count(f.summary, t)/(1-summaryB+summaryB*float64(len(f.summary))/avgSummary) +
+				authorWeight*count(f.author, t)
+			bm25 += idf[t] * tf / (tf + bm25K1)
+		}
+		// tf/(tf+k1) < 1, so each term contributes less than its IDF and
+		// dividing by the IDF sum bounds the score by 1.
+		text := 0.0
+		if idfSum > 0 {
+			text = bm25 / idfSum
+		}
+		if len(queryEmbedding) > 0 && len(d.Embedding) == len(queryEmbedding) {
+			cos := math.Max(0, cosine(queryEmbedding, d.Embedding))
+			if len(terms) == 0 {
+				text = cos
+			} else {
+				text = (1-l.CosineWeight)*text + l.CosineWeight*cos
+			}
+		}
+		if text == 0 {
+			continue
+		}
+		prox := proximity(terms, append(append([]string{}, f.title...), f.summary...))
+		rec := 0.0
+		if !d.Published.IsZero() {
+			age := math.Max(0, now.Sub(d.Published).Hours())
+			rec = math.Exp2(-age / recencyHalfLife.Hours())
+		}
+		scores[i].Score = float32(math.Min(1, text*(0.8+0.1*prox+0.1*rec)))
+	}
+	return scores, nil
+}
+
+// proximity returns how tightly the query terms cluster in tokens: the
+// number of distinct terms matched over the length of the shortest window
+// containing all of them. Single-term queries score 1 when matched.
+func proximity(terms, tokens []string) float64 {
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+	matched := make(map[string]bool)
+	for _, tok := range tokens {
+		if want[tok] {
+			matched[tok] = true
+		}
+	}
+	m := len(matched)
+	if m == 0 {
+		return 0
+	}
+	if len(terms) == 1 {
+		return 1
+	}
+	if m < 2 {
+		return 0
+	}
+	// Sliding window over tokens covering all matched terms.
+	best := len(tokens)
+	have := make(map[string]int, m)
+	lo := 0
+	for hi, tok := range tokens {
+		if !matched[tok] {
+			continue
+		}
+		have[tok]++
+		for len(have) == m {
+			if span := hi - lo + 1; span < best {
+				best = span
+			}
+			if t := tokens[lo]; matched[t] {
+				if have[t]--; have[t] == 0 {
+					delete(have, t)
+				}
+			}
+			lo++
+		}
+	}
+	return float64(m) / float64(best)
+}
+
+// stopwords are dropped from queries and documents.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "have": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"were": true, "what": true, "with": true, "after": true, "about": true,
+}
+
+// tokenize lowercases s, splits it on non-alphanumerics, drops stopwords
+// and stems what's left.
+func tokenize(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := words[:0]
+	for _, w := range words {
+		if stopwords[w] {
+			continue
+		}
+		out = append(out, stem(w))
+	}
+	return out
+}
+
+// stem strips common English inflections ("celebrates", "celebrated"
+// and "celebrating" → "celebrat"; "rallies" → "rally") so query and
+// document forms meet. It is not a full Porter stemmer; short words are
+// left alone.
+func stem(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y"
+	case len(w) > 5 && strings.HasSuffix(w, "ing"):
+		w = w[:len(w)-3]
+	case len(w) > 4 && strings.HasSuffix(w, "ed"):
+		w = w[:len(w)-2]
+	case len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+		w = w[:len(w)-1]
+	}
+	if len(w) > 3 && strings.HasSuffix(w, "e") {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+func uniqueTerms(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	var out []string
+	for _, t := range tokens {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func count(tokens []string, t string) float64 {
+	var n float64
+	for _, tok := range tokens {
+		if tok == t {
+			n++
+		}
+	}
+	return n
+}
+
+func contains(tokens []string, t string) bool {
+	for _, tok := range tokens {
+		if tok == t {
+			return true
+		}
+	}
+	return false
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
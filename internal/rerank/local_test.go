@@ -0,0 +1,127 @@
+package rerank
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+func TestLocalRerankerRanksMatchesFirst(t *testing.T) {
+	docs := []string{
+		"Stock market rallies on Fed interest rate decision",
+		"Chiefs defeat 49ers in overtime thriller to win Super Bowl LVIII",
+		"SpaceX launches 40 Starlink satellites into orbit",
+		"Patrick Mahomes named Super Bowl MVP after historic performance",
+		"Sports betting sites crash during championship game",
+		"Super Bowl halftime show draws record 120 million viewers",
+	}
+	l := NewLocalReranker()
+	scores, err := l.Rerank(context.Background(), "super bowl", docs)
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if len(scores) != len(docs) {
+		t.Fatalf("got %d scores, want %d", len(scores), len(docs))
+	}
+	top := TopN(scores, 3)
+	for _, i := range top {
+		if i != 1 && i != 3 && i != 5 {
+			t.Errorf("top 3 = %v, want the Super Bowl headlines (1, 3, 5)", top)
+			break
+		}
+	}
+	for _, s := range scores {
+		if s.Score < 0 || s.Score > 1 {
+			t.Errorf("score %d = %v, want [0, 1]", s.Index, s.Score)
+		}
+	}
+	if scores[0].Score != 0 || scores[2].Score != 0 {
+		t.Errorf("unrelated headlines scored %v, %v; want 0", scores[0].Score, scores[2].Score)
+	}
+	if !l.Available() || !l.AutoReranks() || l.Name() != "local/bm25f" {
+		t.Errorf("Available/AutoReranks/Name = %v/%v/%q", l.Available(), l.AutoReranks(), l.Name())
+	}
+}
+
+func TestLocalRerankerFields(t *testing.T) {
+	docs := []Document{
+		{Title: "Markets close mixed", Summary: "Analysts expect the central bank to hold rates as inflation cools."},
+		{Title: "Inflation cools for a third month", Summary: "Markets close mixed."},
+		{Title: "Weekly column", Author: "Paul Krugman", Summary: "Notes on trade."},
+		{Title: "Gardening tips", Summary: "Plant bulbs in the autumn."},
+	}
+	scores, err := NewLocalReranker().RerankDocuments(context.Background(), "inflation", nil, docs)
+	if err != nil {
+		t.Fatalf("RerankDocuments() error = %v", err)
+	}
+	if scores[1].Score <= scores[0].Score {
+		t.Errorf("title match %v should beat summary match %v", scores[1].Score, scores[0].Score)
+	}
+
+	scores, _ = NewLocalReranker().RerankDocuments(context.Background(), "krugman", nil, docs)
+	if scores[2].Score == 0 || scores[0].Score != 0 {
+		t.Errorf("author query scored %v (author) and %v (other); want only the author's", scores[2].Score, scores[0].Score)
+	}
+}
+
+func TestLocalRerankerProximityAndRecency(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewLocalReranker()
+	l.now = func() time.Time { return now }
+
+	docs := []Document{
+		{Title: "Fed holds interest rate steady"},
+		{Title: "Interest grows in Fed watchers as rate bets shift"},
+	}
+	scores, _ := l.RerankDocuments(context.Background(), "interest rate", nil, docs)
+	if scores[0].Score <= scores[1].Score {
+		t.Errorf("adjacent terms %v should beat scattered terms %v", scores[0].Score, scores[1].Score)
+	}
+
+	docs = []Document{
+		{Title: "Volcano erupts in Iceland", Published: now.Add(-72 * time.Hour)},
+		{Title: "Volcano erupts in Iceland", Published: now.Add(-time.Hour)},
+	}
+	scores, _ = l.RerankDocuments(context.Background(), "volcano iceland", nil, docs)
+	if scores[1].Score <= scores[0].Score {
+		t.Errorf("recent %v should beat old %v", scores[1].Score, scores[0].Score)
+	}
+}
+
+func TestLocalRerankerBlendsCosine(t *testing.T) {
+	docs := []Document{
+		{Title: "Quarterback throws four touchdowns", Embedding: []float32{1, 0}},
+		{Title: "Senate passes budget", Embedding: []float32{0, 1}},
+	}
+	l := NewLocalReranker()
+	scores, _ := l.RerankDocuments(context.Background(), "football", []float32{1, 0.1}, docs)
+	if scores[0].Score <= scores[1].Score {
+		t.Errorf("semantic match %v should beat %v without shared words", scores[0].Score, scores[1].Score)
+	}
+
+	l.CosineWeight = 0
+	scores, _ = l.RerankDocuments(context.Background(), "football", []float32{1, 0.1}, docs)
+	if scores[0].Score != 0 {
+		t.Errorf("CosineWeight 0 scored %v, want 0", scores[0].Score)
+	}
+}
+
+func TestRerankDocumentsThroughChain(t *testing.T) {
+	down := &namedReranker{flakyReranker{failures: 1, err: &resilience.StatusError{Code: http.StatusServiceUnavailable}}, "jina", true}
+	f := NewFallbackReranker(nil, WithRetry(down, resilience.RetryPolicy{}), WithTimeout(NewLocalReranker(), time.Second))
+	docs := []Document{{Title: "Weekly column", Author: "Paul Krugman"}, {Title: "Gardening tips"}}
+
+	scores, err := RerankDocuments(context.Background(), f, "krugman", nil, docs)
+	if err != nil {
+		t.Fatalf("RerankDocuments() error = %v", err)
+	}
+	if down.calls != 1 {
+		t.Errorf("primary called %d times, want 1", down.calls)
+	}
+	if scores[0].Score == 0 {
+		t.Error("the local fallback should see the author field")
+	}
+}
@@ -0,0 +1,135 @@
+package rerank
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// Middleware wraps a Reranker with cross-cutting behaviour so backends
+// only implement the raw call. Decorators keep the inner reranker's Name
+// and AutoReranks. Typical order, outermost first:
+//
+//	WithMetrics → WithCircuitBreaker → WithRetry → WithRateLimit → WithTimeout → backend
+
+// interceptor runs one Rerank call over n documents.
+type interceptor func(ctx context.Context, n int, call func(context.Context) error) error
+
+// decorated is a Reranker whose calls go through an interceptor.
+type decorated struct {
+	inner     Reranker
+	intercept interceptor
+	available func() bool // nil: ask inner
+}
+
+// Unwrap returns the decorated reranker.
+func (d *decorated) Unwrap() Reranker { return d.inner }
+
+func (d *decorated) Name() string { return d.inner.Name() }
+
+// AutoReranks passes through the inner reranker's preference (false if
+// it doesn't state one).
+func (d *decorated) AutoReranks() bool {
+	if ar, ok := d.inner.(AutoReranker); ok {
+		return ar.AutoReranks()
+	}
+	return false
+}
+
+func (d *decorated) Available() bool {
+	if d.available != nil {
+		return d.available()
+	}
+	return d.inner.Available()
+}
+
+func (d *decorated) Rerank(ctx context.Context, query string, documents []string) ([]Score, error) {
+	var out []Score
+	err := d.intercept(ctx, len(documents), func(ctx context.Context) error {
+		var err error
+		out, err = d.inner.Rerank(ctx, query, documents)
+		return err
+	})
+	return out, err
+}
+
+// RerankDocuments passes structured documents through to the inner
+// reranker (flattened if it doesn't take them), under the interceptor.
+func (d *decorated) RerankDocuments(ctx context.Context, query string, queryEmbedding []float32, docs []Document) ([]Score, error) {
+	var out []Score
+	err := d.intercept(ctx, len(docs), func(ctx context.Context) error {
+		var err error
+		out, err = RerankDocuments(ctx, d.inner, query, queryEmbedding, docs)
+		return err
+	})
+	return out, err
+}
+
+// WithRetry retries transient failures (429, 5xx, network errors,
+// malformed responses) per p, honouring Retry-After.
+func WithRetry(r Reranker, p resilience.RetryPolicy) Reranker {
+	return &decorated{inner: r, intercept: func(ctx context.Context, n int, call func(context.Context) error) error {
+		return resilience.Retry(ctx, p, call)
+	}}
+}
+
+// WithCircuitBreaker fails calls fast while b is open, and reports the
+// reranker unavailable then, without probing the backend.
+func WithCircuitBreaker(r Reranker, b *resilience.Breaker) Reranker {
+	return &decorated{inner: r, intercept: func(ctx context.Context, n int, call func(context.Context) error) error {
+		if err := b.Allow(); err != nil {
+			return err
+		}
+		err := call(ctx)
+		b.Record(err)
+		return err
+	}, available: func() bool {
+		return !b.Open() && r.Available()
+	}}
+}
+
+// WithRateLimit spaces calls at least interval apart. Zero returns r unchanged.
+func WithRateLimit(r Reranker, interval time.Duration) Reranker {
+	if interval <= 0 {
+		return r
+	}
+	limiter := rate.NewLimiter(rate.Every(interval), 1)
+	return &decorated{inner: r, intercept: func(ctx context.Context, n int, call func(context.Context) error) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		return call(ctx)
+	}}
+}
+
+// WithTimeout bounds each call to d.
+func WithTimeout(r Reranker, d time.Duration) Reranker {
+	return &decorated{inner: r, intercept: func(ctx context.Context, n int, call func(context.Context) error) error {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return call(ctx)
+	}}
+}
+
+// WithMetrics emits a backend.call event per call (debug on success,
+// warn on failure) with its duration and document count. A nil logger
+// returns r unchanged.
+func WithMetrics(r Reranker, l *otel.Logger) Reranker {
+	if l == nil {
+		return r
+	}
+	return &decorated{inner: r, intercept: func(ctx context.Context, n int, call func(context.Context) error) error {
+		start := time.Now()
+		err := call(ctx)
+		ev := otel.Event{Kind: otel.KindBackendCall, Level: otel.LevelDebug, Comp: "rerank", Source: r.Name(), Msg: "rerank", Count: n, Dur: time.Since(start)}
+		if err != nil {
+			ev.Level, ev.Err = otel.LevelWarn, err.Error()
+		}
+		l.Emit(ev)
+		return err
+	}}
+}
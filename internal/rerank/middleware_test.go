@@ -0,0 +1,87 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/otel"
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// flakyReranker fails its first failures calls with err, then scores every
+// document 0.5.
+type flakyReranker struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyReranker) Available() bool   { return true }
+func (f *flakyReranker) Name() string      { return "flaky" }
+func (f *flakyReranker) AutoReranks() bool { return true }
+
+func (f *flakyReranker) Rerank(ctx context.Context, query string, documents []string) ([]Score, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	scores := make([]Score, len(documents))
+	for i := range scores {
+		scores[i] = Score{Index: i, Score: 0.5}
+	}
+	return scores, nil
+}
+
+func TestMiddlewarePreservesNameAndAutoReranks(t *testing.T) {
+	r := WithMetrics(WithCircuitBreaker(WithRetry(WithTimeout(&flakyReranker{}, time.Second), fastRetry),
+		resilience.NewBreaker(3, time.Minute, nil)), otel.NewLogger(&bytes.Buffer{}))
+	if r.Name() != "flaky" {
+		t.Errorf("Name() = %q, want flaky", r.Name())
+	}
+	ar, ok := r.(AutoReranker)
+	if !ok || !ar.AutoReranks() {
+		t.Error("decorated reranker lost AutoReranks")
+	}
+	scores, err := r.Rerank(context.Background(), "q", []string{"a", "b"})
+	if err != nil || len(scores) != 2 {
+		t.Errorf("Rerank() = %v, %v", scores, err)
+	}
+}
+
+func TestWithRetryReranker(t *testing.T) {
+	f := &flakyReranker{failures: 1, err: &resilience.StatusError{Code: http.StatusTooManyRequests}}
+	if _, err := WithRetry(f, fastRetry).Rerank(context.Background(), "q", []string{"a"}); err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if f.calls != 2 {
+		t.Errorf("expected 2 calls, got %d", f.calls)
+	}
+}
+
+func TestWithCircuitBreakerReranker(t *testing.T) {
+	f := &flakyReranker{failures: 10, err: errors.New("connection refused")}
+	r := WithCircuitBreaker(f, resilience.NewBreaker(1, time.Minute, nil))
+	r.Rerank(context.Background(), "q", []string{"a"})
+	if r.Available() {
+		t.Error("Available() = true with the breaker open")
+	}
+	if _, err := r.Rerank(context.Background(), "q", []string{"a"}); !errors.Is(err, resilience.ErrBreakerOpen) {
+		t.Errorf("expected ErrBreakerOpen, got %v", err)
+	}
+}
+
+func TestWithMetricsReranker(t *testing.T) {
+	var buf bytes.Buffer
+	l := otel.NewLogger(&buf)
+	WithMetrics(&flakyReranker{}, l).Rerank(context.Background(), "q", []string{"a", "b", "c"})
+	l.Close()
+	out := buf.String()
+	if !strings.Contains(out, `"backend.call"`) || !strings.Contains(out, `"flaky"`) {
+		t.Errorf("expected a backend.call event for flaky, got:\n%s", out)
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/abelbrown/observer/internal/resilience"
+	"github.com/abelbrown/observer/internal/usage"
 )
 
 // OllamaReranker implements Reranker using Ollama with Qwen3-Reranker models.
@@ -19,7 +22,8 @@ type OllamaReranker struct {
 	endpoint    string
 	model       string
 	client      *http.Client
-	concurrency int // Max parallel requests to Ollama
+	concurrency int          // Max parallel requests to Ollama
+	meter       *usage.Meter // optional usage accounting
 }
 
 // NewOllamaReranker creates a reranker using Ollama.
@@ -45,6 +49,12 @@ func NewOllamaReranker(endpoint, model string) *OllamaReranker {
 	return r
 }
 
+// SetUsageRecorder records every Rerank call (characters, latency) to rec.
+// One record covers all the per-document requests of a call.
+func (r *OllamaReranker) SetUsageRecorder(rec usage.Recorder) {
+	r.meter = usage.NewMeter(rec, "ollama", r.model, usage.KindRerank)
+}
+
 // Name returns the reranker identifier.
 func (r *OllamaReranker) Name() string {
 	if r.model == "" {
@@ -136,6 +146,7 @@ func (r *OllamaReranker) Rerank(ctx context.Context, query string, documents []s
 		return nil, fmt.Errorf("reranker not available (model: %s)", r.model)
 	}
 
+	start := time.Now()
 	scores := make([]Score, len(documents))
 	for i := range scores {
 		scores[i] = Score{Index: i, Score: 0.5} // Default neutral score
@@ -173,9 +184,12 @@ func (r *OllamaReranker) Rerank(ctx context.Context, query string, documents []s
 
 	// Return error only if all requests failed
 	if errCount == int64(len(documents)) {
-		return nil, fmt.Errorf("all %d rerank requests failed", len(documents))
+		err := fmt.Errorf("all %d rerank requests failed", len(documents))
+		r.meter.Observe(ctx, start, 0, requestChars(query, documents), err)
+		return nil, err
 	}
 
+	r.meter.Observe(ctx, start, 0, requestChars(query, documents), nil)
 	return scores, nil
 }
 
@@ -234,7 +248,7 @@ func (r *OllamaReranker) ScoreOne(ctx context.Context, query, doc string) (float
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("ollama error (status %d): %s", resp.StatusCode, string(respBody))
+		return 0, resilience.NewStatusError("ollama", resp, string(respBody))
 	}
 
 	return r.parseResponse(respBody)
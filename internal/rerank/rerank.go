@@ -30,10 +30,20 @@ type AutoReranker interface {
 	AutoReranks() bool
 }
 
+// requestChars counts the characters a rerank call sends, for usage accounting.
+// The query is sent once per call.
+func requestChars(query string, documents []string) int {
+	n := len([]rune(query))
+	for _, d := range documents {
+		n += len([]rune(d))
+	}
+	return n
+}
+
 // Score represents a document's relevance score.
 type Score struct {
 	Index int     // Original index in the documents slice
-	Score float32 // Relevance score in [0, 1]
+	Score float32 // Relevance score in [0, 1]; only calibrated scores are comparable across backends
 }
 
 // SortByScore returns scores sorted by relevance (highest first).
@@ -65,6 +75,8 @@ func TopN(scores []Score, n int) []int {
 }
 
 // FilterAboveThreshold returns scores above the given threshold, sorted by score.
+// Raw scores from different backends need different thresholds; map them
+// through a Calibration first to use one threshold for all.
 func FilterAboveThreshold(scores []Score, threshold float32) []Score {
 	var result []Score
 	for _, s := range scores {
@@ -0,0 +1,254 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/abelbrown/observer/internal/resilience"
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+// defaultTEIBatchSize matches text-embeddings-inference's default
+// --max-client-batch-size; larger requests are rejected.
+const defaultTEIBatchSize = 32
+
+// TEIConfig configures a TEIReranker.
+type TEIConfig struct {
+	// BaseURL is the server root, e.g. "http://localhost:8080" or
+	// "http://gpu-box:7997/v1". "/rerank" is appended unless BaseURL
+	// already ends in it.
+	BaseURL string
+	// Model is sent with each request. Servers hosting a single model
+	// (TEI, llama.cpp) ignore it and may leave it empty.
+	Model string
+	// APIKey is sent in AuthHeader. With the default header
+	// ("Authorization") it is sent as a bearer token; any other header
+	// gets the raw key. Empty sends nothing.
+	APIKey     string
+	AuthHeader string
+	// Logits applies a sigmoid to the returned scores, for servers that
+	// return raw logits (llama.cpp). Scores outside [0, 1] are always
+	// treated as logits.
+	Logits bool
+	// BatchSize caps documents per request (default 32).
+	BatchSize int
+}
+
+// TEIReranker scores documents with a self-hosted cross-encoder behind the
+// common /rerank schema: HuggingFace text-embeddings-inference, Infinity,
+// llama.cpp server with --reranking, and Cohere-style gateways. Like
+// JinaReranker it scores a whole batch per call and makes a single attempt;
+// wrap it with WithRetry for transient failures.
+type TEIReranker struct {
+	cfg      TEIConfig
+	endpoint string
+	client   *http.Client
+	meter    *usage.Meter // optional usage accounting
+}
+
+// teiRerankRequest is the request body for POST /rerank. TEI reads the
+// documents from "texts", the others from "documents", so both are sent.
+type teiRerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	Texts     []string `json:"texts"`
+	TopN      int      `json:"top_n"`
+}
+
+// teiRerankResult is one document's score. TEI returns a bare array of
+// {index, score}; Infinity and llama.cpp return
+// {results: [{index, relevance_score}]}.
+type teiRerankResult struct {
+	Index          int      `json:"index"`
+	Score          *float64 `json:"score"`
+	RelevanceScore *float64 `json:"relevance_score"`
+}
+
+// teiRerankResponse is the object form of the /rerank response.
+type teiRerankResponse struct {
+	Results []teiRerankResult `json:"results"`
+	Usage   struct {
+		TotalTokens  int `json:"total_tokens"`
+		PromptTokens int `json:"prompt_tokens"`
+	} `json:"usage"`
+}
+
+// NewTEIReranker creates a reranker for a /rerank server.
+func NewTEIReranker(cfg TEIConfig) *TEIReranker {
+	if cfg.AuthHeader == "" {
+		cfg.AuthHeader = "Authorization"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultTEIBatchSize
+	}
+	endpoint := strings.TrimRight(cfg.BaseURL, "/")
+	if !strings.HasSuffix(endpoint, "/rerank") {
+		endpoint += "/rerank"
+	}
+	return &TEIReranker{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetUsageRecorder records every API call (tokens, characters, latency) to rec.
+func (r *TEIReranker) SetUsageRecorder(rec usage.Recorder) {
+	r.meter = usage.NewMeter(rec, "tei", r.model(), usage.KindRerank)
+}
+
+// Available returns true if a base URL is configured.
+// Does not probe the server; failures surface from Rerank.
+func (r *TEIReranker) Available() bool {
+	return r.cfg.BaseURL != ""
+}
+
+// AutoReranks returns true: one batch call per search, like Jina.
+func (r *TEIReranker) AutoReranks() bool { return true }
+
+// Name returns "tei/<model>", or "tei/<host>" when no model is set.
+func (r *TEIReranker) Name() string {
+	return "tei/" + r.model()
+}
+
+// model names the model for logs and usage: the configured model, else
+// the server's host.
+func (r *TEIReranker) model() string {
+	if r.cfg.Model != "" {
+		return r.cfg.Model
+	}
+	if u, err := url.Parse(r.endpoint); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return r.cfg.BaseURL
+}
+
+// Rerank scores documents against the query, in requests of at most
+// BatchSize documents. Returns a Score slice with the same length as
+// documents, in corresponding order.
+func (r *TEIReranker) Rerank(ctx context.Context, query string, documents []string) ([]Score, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+	scores := make([]Score, 0, len(documents))
+	for start := 0; start < len(documents); start += r.cfg.BatchSize {
+		end := min(start+r.cfg.BatchSize, len(documents))
+		batch, err := r.rerank(ctx, query, documents[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			batch[i].Index += start
+		}
+		scores = append(scores, batch...)
+	}
+	return scores, nil
+}
+
+// rerank performs one /rerank call and returns scores in document order.
+func (r *TEIReranker) rerank(ctx context.Context, query string, documents []string) ([]Score, error) {
+	jsonBody, err := json.Marshal(teiRerankRequest{
+		Model:     r.cfg.Model,
+		Query:     query,
+		Documents: documents,
+		Texts:     documents,
+		TopN:      len(documents),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	start := time.Now()
+	results, tokens, err := r.do(ctx, jsonBody)
+	r.meter.Observe(ctx, start, tokens, requestChars(query, documents), err)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]float64, len(documents))
+	logits := r.cfg.Logits
+	for _, res := range results {
+		if res.Index < 0 || res.Index >= len(documents) {
+			return nil, fmt.Errorf("rerank: server returned out-of-range index %d for %d documents", res.Index, len(documents))
+		}
+		switch {
+		case res.RelevanceScore != nil:
+			raw[res.Index] = *res.RelevanceScore
+		case res.Score != nil:
+			raw[res.Index] = *res.Score
+		}
+		if raw[res.Index] < 0 || raw[res.Index] > 1 {
+			logits = true
+		}
+	}
+
+	scores := make([]Score, len(documents))
+	for i, s := range raw {
+		if logits {
+			s = 1 / (1 + math.Exp(-s))
+		}
+		scores[i] = Score{Index: i, Score: float32(s)}
+	}
+	return scores, nil
+}
+
+// do sends one request and returns the decoded results and token usage.
+// Non-200 responses are returned as *resilience.StatusError; retrying
+// is left to WithRetry.
+func (r *TEIReranker) do(ctx context.Context, jsonBody []byte) ([]teiRerankResult, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.cfg.APIKey != "" {
+		if strings.EqualFold(r.cfg.AuthHeader, "Authorization") {
+			req.Header.Set("Authorization", "Bearer "+r.cfg.APIKey)
+		} else {
+			req.Header.Set(r.cfg.AuthHeader, r.cfg.APIKey)
+		}
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, fmt.Errorf("request cancelled: %w", ctx.Err())
+		}
+		return nil, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, 0, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("rerank: %w", resilience.NewStatusError("tei", resp, string(body)))
+	}
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var results []teiRerankResult
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return nil, 0, fmt.Errorf("parse response: %w", err)
+		}
+		return results, 0, nil
+	}
+	var parsed teiRerankResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, 0, fmt.Errorf("parse response: %w", err)
+	}
+	tokens := parsed.Usage.TotalTokens
+	if tokens == 0 {
+		tokens = parsed.Usage.PromptTokens
+	}
+	return parsed.Results, tokens, nil
+}
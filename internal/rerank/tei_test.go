@@ -0,0 +1,157 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/abelbrown/observer/internal/resilience"
+)
+
+// teiServer answers /rerank like text-embeddings-inference: a bare array
+// of {index, score}, sorted by score, where a document's score is its
+// length over 100.
+func teiServer(t *testing.T, check func(r *http.Request, req teiRerankRequest)) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req teiRerankRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if check != nil {
+			check(r, req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "[")
+		for i := len(req.Texts) - 1; i >= 0; i-- {
+			if i < len(req.Texts)-1 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"index":%d,"score":%g}`, i, float64(len(req.Texts[i]))/100)
+		}
+		fmt.Fprint(w, "]")
+	}))
+}
+
+func TestTEIRerankerConfig(t *testing.T) {
+	r := NewTEIReranker(TEIConfig{BaseURL: "http://gpu-box:8080/"})
+	if !r.Available() || !r.AutoReranks() {
+		t.Error("expected Available() and AutoReranks() with a base URL")
+	}
+	if r.endpoint != "http://gpu-box:8080/rerank" {
+		t.Errorf("endpoint = %q", r.endpoint)
+	}
+	if r.Name() != "tei/gpu-box:8080" {
+		t.Errorf("Name() = %q, want the host without a model", r.Name())
+	}
+	r = NewTEIReranker(TEIConfig{BaseURL: "http://localhost:7997/v1/rerank", Model: "bge-reranker-v2-m3"})
+	if r.endpoint != "http://localhost:7997/v1/rerank" || r.Name() != "tei/bge-reranker-v2-m3" {
+		t.Errorf("endpoint = %q, Name() = %q", r.endpoint, r.Name())
+	}
+	if NewTEIReranker(TEIConfig{}).Available() {
+		t.Error("Available() = true without a base URL")
+	}
+}
+
+func TestTEIRerankerRerank(t *testing.T) {
+	server := teiServer(t, func(r *http.Request, req teiRerankRequest) {
+		if r.URL.Path != "/rerank" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("X-API-Key"); auth != "secret" {
+			t.Errorf("X-API-Key = %q", auth)
+		}
+		if req.Query != "climate" || req.Model != "bge" || req.TopN != len(req.Documents) || len(req.Documents) != len(req.Texts) {
+			t.Errorf("unexpected request: %+v", req)
+		}
+	})
+	defer server.Close()
+
+	r := NewTEIReranker(TEIConfig{BaseURL: server.URL, Model: "bge", APIKey: "secret", AuthHeader: "X-API-Key"})
+	scores, err := r.Rerank(context.Background(), "climate", []string{"a", "abcdefghij", "abcde"})
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	want := []float32{0.01, 0.1, 0.05}
+	for i, s := range scores {
+		if s.Index != i || s.Score != want[i] {
+			t.Errorf("scores[%d] = %+v, want {%d %v}", i, s, i, want[i])
+		}
+	}
+}
+
+func TestTEIRerankerBatches(t *testing.T) {
+	var calls atomic.Int32
+	server := teiServer(t, func(r *http.Request, req teiRerankRequest) {
+		calls.Add(1)
+		if len(req.Texts) > 2 {
+			t.Errorf("batch of %d documents, want at most 2", len(req.Texts))
+		}
+	})
+	defer server.Close()
+
+	r := NewTEIReranker(TEIConfig{BaseURL: server.URL, BatchSize: 2})
+	scores, err := r.Rerank(context.Background(), "q", []string{"a", "ab", "abc", "abcd", "abcde"})
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if calls.Load() != 3 || len(scores) != 5 {
+		t.Fatalf("%d calls, %d scores; want 3, 5", calls.Load(), len(scores))
+	}
+	for i, s := range scores {
+		if s.Index != i || s.Score != float32(i+1)/100 {
+			t.Errorf("scores[%d] = %+v", i, s)
+		}
+	}
+}
+
+func TestTEIRerankerResultsObject(t *testing.T) {
+	// llama.cpp: Cohere-style results with raw logits.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"model":"m","results":[{"index":1,"relevance_score":-3.5},{"index":0,"relevance_score":4.0}],"usage":{"prompt_tokens":12,"total_tokens":12}}`)
+	}))
+	defer server.Close()
+
+	r := NewTEIReranker(TEIConfig{BaseURL: server.URL + "/v1"})
+	scores, err := r.Rerank(context.Background(), "q", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if scores[0].Score < 0.9 || scores[1].Score > 0.1 {
+		t.Errorf("logits not squashed into [0, 1]: %+v", scores)
+	}
+}
+
+func TestTEIRerankerErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("bad") != "" {
+			fmt.Fprint(w, `[{"index":5,"score":0.5}]`)
+			return
+		}
+		http.Error(w, `{"error":"batch size 40 > maximum allowed batch size 32"}`, http.StatusRequestEntityTooLarge)
+	}))
+	defer server.Close()
+
+	_, err := NewTEIReranker(TEIConfig{BaseURL: server.URL}).Rerank(context.Background(), "q", []string{"a"})
+	var se *resilience.StatusError
+	if !errors.As(err, &se) || se.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Rerank() error = %v, want a 413 StatusError", err)
+	}
+
+	r := NewTEIReranker(TEIConfig{BaseURL: server.URL})
+	r.endpoint += "?bad=1"
+	if _, err := r.Rerank(context.Background(), "q", []string{"a"}); err == nil {
+		t.Error("expected an error for an out-of-range index")
+	}
+
+	if scores, err := r.Rerank(context.Background(), "q", nil); err != nil || scores != nil {
+		t.Errorf("Rerank(nil) = %v, %v", scores, err)
+	}
+}
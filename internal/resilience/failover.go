@@ -0,0 +1,138 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BackendFault reports whether err says something about the backend
+// rather than the request: transient failures (see Retryable) and
+// authentication or quota errors (401, 402, 403). A backend failing this
+// way should be skipped for a while; one rejecting a single bad input
+// should not.
+func BackendFault(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		switch se.Code {
+		case http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusForbidden:
+			return true
+		}
+	}
+	return Retryable(err)
+}
+
+// InputFault reports whether err blames the request itself: a client
+// error such as a rejected or too-long input, which retrying the same
+// input elsewhere or later won't fix. Backend faults, an open breaker
+// and cancellation are not.
+func InputFault(err error) bool {
+	if err == nil || errors.Is(err, ErrBreakerOpen) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return !BackendFault(err)
+}
+
+// Failover tracks the health of an ordered list of backends, most
+// preferred first. A backend that fails with a BackendFault is skipped for
+// Cooldown, after which it is preferred again, so the chain drifts back to
+// the primary once it recovers.
+// Thread-safe.
+type Failover struct {
+	cooldown time.Duration
+	onSwitch func(from, to int, cause error)
+
+	mu        sync.Mutex
+	downUntil []time.Time
+	lastErr   []error
+	active    int
+	cause     error // failure that moved active off its previous backend
+}
+
+// NewFailover creates a Failover over n backends, all healthy, with the
+// first active. onSwitch (optional) is called when a different backend
+// starts serving calls, with the failure that caused the switch (nil when
+// moving back to a preferred backend).
+func NewFailover(n int, cooldown time.Duration, onSwitch func(from, to int, cause error)) *Failover {
+	return &Failover{
+		cooldown:  cooldown,
+		onSwitch:  onSwitch,
+		downUntil: make([]time.Time, n),
+		lastErr:   make([]error, n),
+	}
+}
+
+// Order returns the backends to try, in preference order: those
+// available and not cooling down. If every available backend is cooling
+// down they are all returned anyway, since a stale failure is better than
+// no attempt.
+func (f *Failover) Order(available func(i int) bool) []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	var healthy, cooling []int
+	for i, until := range f.downUntil {
+		if !available(i) {
+			continue
+		}
+		if now.Before(until) {
+			cooling = append(cooling, i)
+		} else {
+			healthy = append(healthy, i)
+		}
+	}
+	if len(healthy) == 0 {
+		return cooling
+	}
+	return healthy
+}
+
+// Success records that backend i served a call.
+func (f *Failover) Success(i int) {
+	f.mu.Lock()
+	f.downUntil[i], f.lastErr[i] = time.Time{}, nil
+	from := f.active
+	f.active = i
+	cause := f.cause
+	if i < from {
+		cause = nil // recovered, not failed over
+	}
+	f.cause = nil
+	onSwitch := f.onSwitch
+	f.mu.Unlock()
+
+	if from != i && onSwitch != nil {
+		onSwitch(from, i, cause)
+	}
+}
+
+// Failure records that backend i failed a call with err. Only
+// BackendFaults put it in cooldown.
+func (f *Failover) Failure(i int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastErr[i] = err
+	if BackendFault(err) {
+		f.downUntil[i] = time.Now().Add(f.cooldown)
+		if i == f.active {
+			f.cause = err
+		}
+	}
+}
+
+// Active returns the backend that served the most recent successful call.
+func (f *Failover) Active() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// Healthy reports whether backend i is out of cooldown, with its last
+// error (nil after a success).
+func (f *Failover) Healthy(i int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !time.Now().Before(f.downUntil[i]), f.lastErr[i]
+}
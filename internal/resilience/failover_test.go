@@ -0,0 +1,106 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func all(int) bool { return true }
+
+func TestBackendFault(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&StatusError{Code: http.StatusBadRequest}, false},
+		{&StatusError{Code: http.StatusUnauthorized}, true},
+		{&StatusError{Code: http.StatusPaymentRequired}, true},
+		{&StatusError{Code: http.StatusServiceUnavailable}, true},
+		{errors.New("connection refused"), true},
+	}
+	for _, tt := range tests {
+		if got := BackendFault(tt.err); got != tt.want {
+			t.Errorf("BackendFault(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestInputFault(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&StatusError{Code: http.StatusBadRequest}, true},
+		{fmt.Errorf("embed: %w", &StatusError{Code: http.StatusRequestEntityTooLarge}), true},
+		{&StatusError{Code: http.StatusTooManyRequests}, false},
+		{&StatusError{Code: http.StatusForbidden}, false},
+		{&StatusError{Code: http.StatusBadGateway}, false},
+		{ErrBreakerOpen, false},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+		{errors.New("connection refused"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := InputFault(tt.err); got != tt.want {
+			t.Errorf("InputFault(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFailoverSkipsFailedBackendUntilCooldown(t *testing.T) {
+	type sw struct {
+		from, to int
+		caused   bool
+	}
+	var switches []sw
+	f := NewFailover(3, 20*time.Millisecond, func(from, to int, cause error) {
+		switches = append(switches, sw{from, to, cause != nil})
+	})
+
+	f.Failure(0, &StatusError{Code: http.StatusServiceUnavailable})
+	if got := f.Order(all); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Fatalf("Order() = %v, want [1 2]", got)
+	}
+	f.Success(1)
+	if f.Active() != 1 {
+		t.Errorf("Active() = %d, want 1", f.Active())
+	}
+	if ok, err := f.Healthy(0); ok || err == nil {
+		t.Errorf("Healthy(0) = %v, %v; want false with the error", ok, err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if got := f.Order(all); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Fatalf("Order() after cooldown = %v, want the primary first", got)
+	}
+	f.Success(0)
+
+	want := []sw{{0, 1, true}, {1, 0, false}}
+	if !reflect.DeepEqual(switches, want) {
+		t.Errorf("switches = %v, want %v", switches, want)
+	}
+}
+
+func TestFailoverIgnoresBadInput(t *testing.T) {
+	f := NewFailover(2, time.Minute, nil)
+	f.Failure(0, &StatusError{Code: http.StatusBadRequest})
+	if got := f.Order(all); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("Order() = %v, a rejected input should not cool the backend down", got)
+	}
+}
+
+func TestFailoverReturnsCoolingBackendsWhenAllDown(t *testing.T) {
+	f := NewFailover(3, time.Minute, nil)
+	f.Failure(0, errors.New("down"))
+	f.Failure(2, errors.New("down"))
+	unavailable1 := func(i int) bool { return i != 1 }
+	if got := f.Order(unavailable1); !reflect.DeepEqual(got, []int{0, 2}) {
+		t.Errorf("Order() = %v, want [0 2]", got)
+	}
+}
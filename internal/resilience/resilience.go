@@ -0,0 +1,194 @@
+// Package resilience provides the retry, circuit-breaker and error
+// classification building blocks behind the embed and rerank middleware
+// (embed.WithRetry, rerank.WithCircuitBreaker, ...). Backends report HTTP
+// failures as *StatusError and leave retrying to the middleware.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StatusError is a non-2xx response from a backend.
+type StatusError struct {
+	Backend    string        // "jina", "ollama", "openai"
+	Code       int           // HTTP status code
+	Body       string        // response body or extracted error message
+	RetryAfter time.Duration // from the Retry-After header; 0 if absent
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d: %s", e.Backend, e.Code, e.Body)
+}
+
+// NewStatusError builds a StatusError from a response and its body,
+// parsing Retry-After (seconds form) if present.
+func NewStatusError(backend string, resp *http.Response, body string) *StatusError {
+	e := &StatusError{Backend: backend, Code: resp.StatusCode, Body: body}
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if seconds, err := strconv.Atoi(ra); err == nil && seconds > 0 {
+			e.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return e
+}
+
+// ErrBreakerOpen is returned without calling the backend while a circuit
+// breaker is open.
+var ErrBreakerOpen = errors.New("circuit breaker open")
+
+// Retryable reports whether err is transient: rate limiting, server
+// errors, network failures, timeouts and malformed responses. Client
+// errors (4xx other than 429), cancellation by the caller and an open
+// breaker are not.
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, ErrBreakerOpen) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code == http.StatusTooManyRequests || se.Code >= 500
+	}
+	return true
+}
+
+// RetryPolicy controls Retry.
+type RetryPolicy struct {
+	MaxRetries int           // retries after the first attempt
+	BaseDelay  time.Duration // delay before the first retry; doubles each time
+	MaxDelay   time.Duration // cap on any delay, including Retry-After
+}
+
+// DefaultRetryPolicy retries 3 times after 1s, 2s and 4s, honouring
+// Retry-After up to 30s.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  time.Second,
+	MaxDelay:   30 * time.Second,
+}
+
+// delay returns the wait before retry number attempt (0-based).
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	d := p.BaseDelay << attempt
+	var se *StatusError
+	if errors.As(err, &se) && se.RetryAfter > 0 {
+		d = se.RetryAfter
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// Retry calls call until it succeeds, returns a non-retryable error, or
+// MaxRetries retries have failed. Stops early if ctx is done.
+func Retry(ctx context.Context, p RetryPolicy, call func(context.Context) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = call(ctx)
+		if err == nil || !Retryable(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return err // the caller gave up; the per-attempt error says why
+		}
+		if attempt >= p.MaxRetries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cancelled during retry: %w", ctx.Err())
+		case <-time.After(p.delay(attempt, err)):
+		}
+	}
+	if p.MaxRetries == 0 {
+		return err
+	}
+	return fmt.Errorf("all retries exhausted: %w", err)
+}
+
+// Breaker is a circuit breaker shared by all calls to one backend. After
+// Threshold consecutive transient failures it opens: calls fail fast with
+// ErrBreakerOpen for Cooldown, then a single trial call is let through
+// (half-open). The trial's success closes the breaker; its failure
+// re-opens it. Client errors (see Retryable) don't count: a bad input is
+// the item's fault, not the backend's.
+// Thread-safe.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	onChange  func(open bool, cause error)
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	open      bool
+	trial     bool // half-open trial call in flight
+}
+
+// NewBreaker creates a closed Breaker. onChange (optional) is called
+// when the breaker opens or closes, with the failure that opened it.
+func NewBreaker(threshold int, cooldown time.Duration, onChange func(open bool, cause error)) *Breaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &Breaker{threshold: threshold, cooldown: cooldown, onChange: onChange}
+}
+
+// Allow returns ErrBreakerOpen if a call may not proceed now.
+// Every allowed call must be followed by Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return nil
+	}
+	if b.trial || time.Now().Before(b.openUntil) {
+		return ErrBreakerOpen
+	}
+	b.trial = true
+	return nil
+}
+
+// Record reports the outcome of an allowed call.
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	var changed, open bool
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Says nothing about the backend; a cancelled trial lets the
+		// next call try instead.
+		b.trial = false
+	case err == nil || !Retryable(err):
+		changed = b.open
+		b.failures, b.open, b.trial = 0, false, false
+	default:
+		b.failures++
+		if b.trial || b.failures >= b.threshold {
+			changed = !b.open
+			b.open, b.trial = true, false
+			b.openUntil = time.Now().Add(b.cooldown)
+		}
+	}
+	open = b.open
+	onChange := b.onChange
+	b.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange(open, err)
+	}
+}
+
+// Open reports whether the breaker is rejecting calls right now.
+// A breaker whose cooldown has elapsed reports closed so a caller
+// checking availability will make the trial call.
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open && (b.trial || time.Now().Before(b.openUntil))
+}
@@ -0,0 +1,156 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var fast = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{ErrBreakerOpen, false},
+		{context.Canceled, false},
+		{&StatusError{Code: http.StatusBadRequest}, false},
+		{&StatusError{Code: http.StatusUnauthorized}, false},
+		{&StatusError{Code: http.StatusTooManyRequests}, true},
+		{&StatusError{Code: http.StatusBadGateway}, true},
+		{context.DeadlineExceeded, true},
+		{errors.New("connection reset"), true},
+	}
+	for _, tt := range tests {
+		if got := Retryable(tt.err); got != tt.want {
+			t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNewStatusErrorParsesRetryAfter(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"7"}}}
+	se := NewStatusError("jina", resp, "slow down")
+	if se.RetryAfter != 7*time.Second {
+		t.Errorf("RetryAfter = %v, want 7s", se.RetryAfter)
+	}
+	if se.Error() != "jina returned status 429: slow down" {
+		t.Errorf("Error() = %q", se.Error())
+	}
+}
+
+func TestRetryRecoversFromTransientFailure(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), fast, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return &StatusError{Code: http.StatusServiceUnavailable}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Retry() = %v after %d calls, want nil after 3", err, calls)
+	}
+}
+
+func TestRetryStopsOnClientError(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), fast, func(context.Context) error {
+		calls++
+		return &StatusError{Code: http.StatusBadRequest}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Retry() = %v after %d calls, want error after 1", err, calls)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	calls := 0
+	cause := &StatusError{Code: http.StatusInternalServerError}
+	err := Retry(context.Background(), fast, func(context.Context) error {
+		calls++
+		return cause
+	})
+	if calls != 4 {
+		t.Errorf("expected 4 calls (1 + 3 retries), got %d", calls)
+	}
+	var se *StatusError
+	if !errors.As(err, &se) {
+		t.Errorf("expected the last StatusError to be wrapped, got %v", err)
+	}
+}
+
+func TestRetryDelayHonoursRetryAfterUpToMax(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+	if d := p.delay(2, errors.New("x")); d != 4*time.Second {
+		t.Errorf("delay(2) = %v, want 4s", d)
+	}
+	if d := p.delay(0, &StatusError{Code: 429, RetryAfter: 10 * time.Second}); d != 10*time.Second {
+		t.Errorf("delay with Retry-After = %v, want 10s", d)
+	}
+	if d := p.delay(0, &StatusError{Code: 429, RetryAfter: time.Hour}); d != 30*time.Second {
+		t.Errorf("delay with long Retry-After = %v, want the 30s cap", d)
+	}
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	var changes []bool
+	b := NewBreaker(2, 20*time.Millisecond, func(open bool, cause error) {
+		changes = append(changes, open)
+	})
+	fail := &StatusError{Code: http.StatusBadGateway}
+
+	b.Record(fail)
+	if b.Open() {
+		t.Fatal("breaker opened before reaching the threshold")
+	}
+	b.Record(fail)
+	if !b.Open() || !errors.Is(b.Allow(), ErrBreakerOpen) {
+		t.Fatal("breaker should be open after 2 failures")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if b.Open() {
+		t.Fatal("breaker should report closed once the cooldown elapses")
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("trial call rejected: %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatal("a second call was let through while the trial was in flight")
+	}
+	b.Record(nil)
+	if b.Open() || b.Allow() != nil {
+		t.Fatal("breaker should close after a successful trial")
+	}
+
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("onChange calls = %v, want [true false]", changes)
+	}
+}
+
+func TestBreakerFailedTrialReopens(t *testing.T) {
+	b := NewBreaker(1, 10*time.Millisecond, nil)
+	b.Record(errors.New("down"))
+	time.Sleep(20 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("trial call rejected: %v", err)
+	}
+	b.Record(errors.New("still down"))
+	if !b.Open() {
+		t.Error("breaker should re-open after a failed trial")
+	}
+}
+
+func TestBreakerIgnoresClientErrorsAndCancellation(t *testing.T) {
+	b := NewBreaker(1, time.Minute, nil)
+	b.Record(&StatusError{Code: http.StatusBadRequest})
+	b.Record(context.Canceled)
+	if b.Open() {
+		t.Error("client errors and cancellation should not open the breaker")
+	}
+}
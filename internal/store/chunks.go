@@ -0,0 +1,146 @@
+package store
+
+import "fmt"
+
+// Chunk is one passage of a long item with its own vector. Search scores
+// an item by its best-matching vector (document or chunk) and shows the
+// best chunk as the reason it matched.
+type Chunk struct {
+	Index     int
+	Text      string // the passage as shown to the user
+	Embedding []float32
+}
+
+// migrateChunks creates the item_chunks table if it doesn't exist, and
+// rebuilds tables created before chunks were kept per model.
+func (s *Store) migrateChunks() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS item_chunks (
+			item_id TEXT NOT NULL,
+			idx INTEGER NOT NULL,
+			text TEXT NOT NULL,
+			embedding BLOB NOT NULL,
+			embedding_model TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (item_id, embedding_model, idx)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	var keyed int
+	err = s.db.QueryRow("SELECT pk FROM pragma_table_info('item_chunks') WHERE name = 'embedding_model'").Scan(&keyed)
+	if err != nil {
+		return fmt.Errorf("check chunks key: %w", err)
+	}
+	if keyed > 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("rebuild chunks: %w", err)
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`
+		ALTER TABLE item_chunks RENAME TO item_chunks_old;
+		CREATE TABLE item_chunks (
+			item_id TEXT NOT NULL,
+			idx INTEGER NOT NULL,
+			text TEXT NOT NULL,
+			embedding BLOB NOT NULL,
+			embedding_model TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (item_id, embedding_model, idx)
+		);
+		INSERT INTO item_chunks SELECT item_id, idx, text, embedding, embedding_model FROM item_chunks_old;
+		DROP TABLE item_chunks_old;
+	`)
+	if err != nil {
+		return fmt.Errorf("rebuild chunks: %w", err)
+	}
+	return tx.Commit()
+}
+
+// SaveChunks replaces the chunks model produced for an item with chunks.
+// Chunks from other models are kept.
+// Thread-safe: acquires write lock.
+func (s *Store) SaveChunks(itemID, model string, chunks []Chunk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM item_chunks WHERE item_id = ? AND embedding_model = ?", itemID, model); err != nil {
+		return fmt.Errorf("delete chunks: %w", err)
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO item_chunks (item_id, idx, text, embedding, embedding_model)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, c := range chunks {
+		if _, err := stmt.Exec(itemID, c.Index, c.Text, encodeEmbedding(c.Embedding), model); err != nil {
+			return fmt.Errorf("insert chunk %d: %w", c.Index, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetModelChunks returns the chunks of the given items that were embedded
+// by model, in passage order. Items without chunks are absent.
+// Thread-safe: acquires read lock.
+func (s *Store) GetModelChunks(ids []string, model string) (map[string][]Chunk, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string][]Chunk)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := "SELECT item_id, idx, text, embedding FROM item_chunks WHERE item_id IN (?" + repeatString(",?", len(ids)-1) + ") AND embedding_model = ? ORDER BY item_id, idx"
+	args := make([]any, 0, len(ids)+1)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, model)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get chunks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var c Chunk
+		var data []byte
+		if err := rows.Scan(&id, &c.Index, &c.Text, &data); err != nil {
+			return nil, fmt.Errorf("get chunks: %w", err)
+		}
+		c.Embedding = decodeEmbedding(data)
+		result[id] = append(result[id], c)
+	}
+	return result, rows.Err()
+}
+
+// CountChunks returns the number of stored chunk vectors, from every
+// model, and how many items they belong to.
+// Thread-safe: acquires read lock.
+func (s *Store) CountChunks() (chunks, items int, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	err = s.db.QueryRow("SELECT COUNT(*), COUNT(DISTINCT item_id) FROM item_chunks").Scan(&chunks, &items)
+	if err != nil {
+		return 0, 0, fmt.Errorf("count chunks: %w", err)
+	}
+	return chunks, items, nil
+}
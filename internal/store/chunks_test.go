@@ -0,0 +1,106 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChunks(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	_, err = st.SaveItems([]Item{
+		{ID: "long", SourceType: "rss", SourceName: "S", Title: "Long", URL: "http://x/1", Published: now, Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+
+	chunks := []Chunk{
+		{Index: 0, Text: "first passage", Embedding: []float32{1, 0}},
+		{Index: 1, Text: "second passage", Embedding: []float32{0, 1}},
+	}
+	if err := st.SaveChunks("long", "jina/v3", chunks); err != nil {
+		t.Fatalf("SaveChunks: %v", err)
+	}
+	// Saving again replaces rather than appends.
+	if err := st.SaveChunks("long", "jina/v3", chunks); err != nil {
+		t.Fatalf("SaveChunks: %v", err)
+	}
+
+	got, err := st.GetModelChunks([]string{"long", "missing"}, "jina/v3")
+	if err != nil {
+		t.Fatalf("GetModelChunks: %v", err)
+	}
+	if len(got) != 1 || len(got["long"]) != 2 {
+		t.Fatalf("expected 2 chunks for one item, got %v", got)
+	}
+	if c := got["long"][1]; c.Index != 1 || c.Text != "second passage" || c.Embedding[1] != 1 {
+		t.Errorf("chunk did not round-trip: %+v", c)
+	}
+	if other, _ := st.GetModelChunks([]string{"long"}, "local/hash"); len(other) != 0 {
+		t.Errorf("chunks from another model should be left out, got %v", other)
+	}
+
+	// Another model's chunks are kept alongside.
+	if err := st.SaveChunks("long", "local/hash", chunks[:1]); err != nil {
+		t.Fatalf("SaveChunks: %v", err)
+	}
+	if got, _ := st.GetModelChunks([]string{"long"}, "jina/v3"); len(got["long"]) != 2 {
+		t.Errorf("saving local/hash chunks replaced jina's: %v", got)
+	}
+
+	if n, items, err := st.CountChunks(); err != nil || n != 3 || items != 1 {
+		t.Errorf("CountChunks = %d, %d, %v; want 3, 1", n, items, err)
+	}
+
+	if _, err := st.ClearAllEmbeddings(); err != nil {
+		t.Fatalf("ClearAllEmbeddings: %v", err)
+	}
+	if n, _, _ := st.CountChunks(); n != 0 {
+		t.Errorf("expected chunks cleared with the embeddings, got %d", n)
+	}
+}
+
+func TestMigrateChunksKeysModel(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	// Recreate the table as it was before chunks were kept per model.
+	_, err = st.db.Exec(`
+		DROP TABLE item_chunks;
+		CREATE TABLE item_chunks (
+			item_id TEXT NOT NULL,
+			idx INTEGER NOT NULL,
+			text TEXT NOT NULL,
+			embedding BLOB NOT NULL,
+			embedding_model TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (item_id, idx)
+		);
+		INSERT INTO item_chunks VALUES ('a', 0, 'passage', x'0000803f', 'jina/v3');
+	`)
+	if err != nil {
+		t.Fatalf("create old table: %v", err)
+	}
+	if err := st.migrateChunks(); err != nil {
+		t.Fatalf("migrateChunks: %v", err)
+	}
+	if err := st.migrateChunks(); err != nil {
+		t.Fatalf("migrateChunks again: %v", err)
+	}
+
+	st.SaveItems([]Item{{ID: "a", SourceType: "rss", SourceName: "S", Title: "A", URL: "http://x/a", Published: time.Now()}})
+	if err := st.SaveChunks("a", "local/hash", []Chunk{{Index: 0, Text: "passage", Embedding: []float32{1}}}); err != nil {
+		t.Fatalf("SaveChunks: %v", err)
+	}
+	if got, _ := st.GetModelChunks([]string{"a"}, "jina/v3"); len(got["a"]) != 1 || got["a"][0].Embedding[0] != 1 {
+		t.Errorf("migrated chunk = %v", got)
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// EmbedCacheStats summarizes the content-addressed embedding cache.
+type EmbedCacheStats struct {
+	Entries int64 // cached vectors, i.e. texts embedded by a backend
+	Hits    int64 // lookups served from the cache instead of a backend
+}
+
+// HitRate is the fraction of embeddings served from the cache.
+func (s EmbedCacheStats) HitRate() float64 {
+	if s.Hits+s.Entries == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Entries)
+}
+
+// migrateEmbedCache creates the embed_cache table if it doesn't exist.
+// Keys are embed.CacheKey hashes, so entries survive ClearAllEmbeddings
+// and re-embedding the same corpus with the same model is free.
+func (s *Store) migrateEmbedCache() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS embed_cache (
+			key TEXT PRIMARY KEY,
+			model TEXT NOT NULL,
+			embedding BLOB NOT NULL,
+			created_at DATETIME NOT NULL,
+			hits INTEGER NOT NULL DEFAULT 0
+		);
+	`)
+	return err
+}
+
+// GetCachedEmbeddings returns cached vectors for the given keys and counts
+// each key found as a hit. Missing keys are absent from the result.
+// Thread-safe: acquires write lock.
+func (s *Store) GetCachedEmbeddings(keys []string) (map[string][]float32, error) {
+	result := make(map[string][]float32)
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	args := make([]any, len(keys))
+	for i, k := range keys {
+		args[i] = k
+	}
+	in := "(?" + repeatString(",?", len(keys)-1) + ")"
+
+	rows, err := s.db.Query("SELECT key, embedding FROM embed_cache WHERE key IN "+in, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get cached embeddings: %w", err)
+	}
+	for rows.Next() {
+		var key string
+		var data []byte
+		if err := rows.Scan(&key, &data); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("get cached embeddings: %w", err)
+		}
+		result[key] = decodeEmbedding(data)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get cached embeddings: %w", err)
+	}
+
+	// A key asked for twice (syndicated copies) is two hits.
+	counts := make(map[string]int)
+	for _, k := range keys {
+		if _, ok := result[k]; ok {
+			counts[k]++
+		}
+	}
+	for k, n := range counts {
+		if _, err := s.db.Exec("UPDATE embed_cache SET hits = hits + ? WHERE key = ?", n, k); err != nil {
+			return nil, fmt.Errorf("count embed cache hits: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// CacheEmbedding stores a vector under its content key. Existing entries
+// are left untouched.
+// Thread-safe: acquires write lock.
+func (s *Store) CacheEmbedding(key, model string, embedding []float32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		INSERT INTO embed_cache (key, model, embedding, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(key) DO NOTHING
+	`, key, model, encodeEmbedding(embedding), time.Now())
+	if err != nil {
+		return fmt.Errorf("cache embedding: %w", err)
+	}
+	return nil
+}
+
+// EmbedCacheStats counts cache entries and hits.
+// Thread-safe: acquires read lock.
+func (s *Store) EmbedCacheStats() (EmbedCacheStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var st EmbedCacheStats
+	err := s.db.QueryRow("SELECT COUNT(*), COALESCE(SUM(hits), 0) FROM embed_cache").Scan(&st.Entries, &st.Hits)
+	if err != nil {
+		return EmbedCacheStats{}, fmt.Errorf("embed cache stats: %w", err)
+	}
+	return st, nil
+}
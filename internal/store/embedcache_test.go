@@ -0,0 +1,41 @@
+package store
+
+import "testing"
+
+func TestEmbedCache(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	if err := st.CacheEmbedding("k1", "jina/v3", []float32{0.1, 0.2}); err != nil {
+		t.Fatalf("CacheEmbedding: %v", err)
+	}
+	// A second write for the same key keeps the first vector.
+	if err := st.CacheEmbedding("k1", "jina/v3", []float32{0.9, 0.9}); err != nil {
+		t.Fatalf("CacheEmbedding: %v", err)
+	}
+
+	got, err := st.GetCachedEmbeddings([]string{"k1", "missing"})
+	if err != nil {
+		t.Fatalf("GetCachedEmbeddings: %v", err)
+	}
+	if len(got) != 1 || len(got["k1"]) != 2 || got["k1"][0] != 0.1 {
+		t.Errorf("unexpected cache result: %v", got)
+	}
+	if _, err := st.GetCachedEmbeddings([]string{"k1"}); err != nil {
+		t.Fatalf("GetCachedEmbeddings: %v", err)
+	}
+
+	stats, err := st.EmbedCacheStats()
+	if err != nil {
+		t.Fatalf("EmbedCacheStats: %v", err)
+	}
+	if stats.Entries != 1 || stats.Hits != 2 {
+		t.Errorf("expected 1 entry and 2 hits, got %+v", stats)
+	}
+	if rate := stats.HitRate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("expected hit rate 2/3, got %v", rate)
+	}
+}
@@ -0,0 +1,296 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Embed job states.
+const (
+	EmbedJobPending = "pending" // failed at least once; retried after NextAttempt
+	EmbedJobDead    = "dead"    // gave up after MaxAttempts; only requeued by hand
+)
+
+// Embedding priorities, highest first. Items without a priority bump rank
+// as EmbedPriorityRecent if unread and published within recentEmbedWindow,
+// otherwise EmbedPriorityBackfill. Ties go to the newest item.
+const (
+	EmbedPriorityBackfill = 0
+	EmbedPriorityRecent   = 1
+	EmbedPrioritySearch   = 2 // in the TUI's search pool
+	EmbedPriorityVisible  = 3 // on screen in the TUI
+)
+
+// recentEmbedWindow is how far back unread items count as recent news.
+const recentEmbedWindow = 24 * time.Hour
+
+// EmbedRetryPolicy controls backoff and dead-lettering of failed embed jobs.
+type EmbedRetryPolicy struct {
+	MaxAttempts int           // failures before a job is dead-lettered
+	BaseDelay   time.Duration // delay after the first failure
+	MaxDelay    time.Duration // cap on the doubling delay
+}
+
+// DefaultEmbedRetryPolicy retries after 1m, 2m, 4m, 8m, then dead-letters.
+var DefaultEmbedRetryPolicy = EmbedRetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   time.Minute,
+	MaxDelay:    time.Hour,
+}
+
+// Delay returns the backoff after the given number of failed attempts (1-based).
+func (p EmbedRetryPolicy) Delay(attempts int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempts && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// EmbedJob is the retry record for an item whose embedding failed.
+// Items that have never failed (or been bumped with SetEmbedPriority) have
+// no job row: every item without a vector from the worker's model is
+// implicitly queued.
+type EmbedJob struct {
+	ItemID      string
+	Title       string
+	SourceName  string
+	State       string
+	Attempts    int
+	LastError   string
+	NextAttempt time.Time
+	Updated     time.Time
+}
+
+// EmbedQueueStats summarizes the embedding queue.
+type EmbedQueueStats struct {
+	Ready   int // items eligible for embedding now
+	Backoff int // failed items waiting for their next attempt
+	Dead    int // dead-lettered items
+}
+
+// migrateEmbedJobs creates the embed_jobs table if it doesn't exist and
+// adds the priority column to tables created before it existed.
+func (s *Store) migrateEmbedJobs() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS embed_jobs (
+			item_id TEXT PRIMARY KEY,
+			state TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS idx_embed_jobs_state ON embed_jobs(state, next_attempt_at);
+	`)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = s.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('embed_jobs') WHERE name = 'priority'").Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check priority column: %w", err)
+	}
+	if count == 0 {
+		if _, err := s.db.Exec("ALTER TABLE embed_jobs ADD COLUMN priority INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return fmt.Errorf("add priority column: %w", err)
+		}
+	}
+	return nil
+}
+
+// embedReadyJoin excludes dead items and items still backing off.
+// Expects the items table aliased as i, and embedReadyWhere takes the
+// model and one time argument (now).
+const embedReadyJoin = `
+		LEFT JOIN embed_jobs j ON j.item_id = i.id`
+
+const embedReadyWhere = `
+		WHERE ` + embeddingMissing + `
+			AND (j.item_id IS NULL OR (j.state = 'pending' AND j.next_attempt_at <= ?))`
+
+// embedPriorityOrder ranks due items by priority tier, newest first within
+// a tier. Takes one time argument (the recent-news cutoff).
+const embedPriorityOrder = `
+		ORDER BY MAX(COALESCE(j.priority, 0),
+			CASE WHEN i.read = 0 AND i.published_at >= ? THEN 1 ELSE 0 END) DESC,
+			i.published_at DESC`
+
+// SetEmbedPriority makes ids the set of items bumped to priority: items
+// previously bumped to the same priority fall back to the default policy.
+// The bump only matters while an item lacks a vector from the worker's
+// model; unknown IDs are ignored.
+// Thread-safe: acquires write lock.
+func (s *Store) SetEmbedPriority(priority int, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("set embed priority: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE embed_jobs SET priority = 0 WHERE priority = ?", priority); err != nil {
+		return fmt.Errorf("set embed priority: %w", err)
+	}
+	// Rows that only existed to carry a bump are no longer needed.
+	if _, err := tx.Exec("DELETE FROM embed_jobs WHERE priority = 0 AND attempts = 0"); err != nil {
+		return fmt.Errorf("set embed priority: %w", err)
+	}
+
+	if len(ids) > 0 {
+		now := time.Now()
+		args := []any{now, now, priority}
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		_, err := tx.Exec(`
+			INSERT INTO embed_jobs (item_id, state, attempts, last_error, next_attempt_at, updated_at, priority)
+			SELECT id, 'pending', 0, '', ?, ?, ?
+			FROM items
+			WHERE id IN (?`+repeatString(",?", len(ids)-1)+`)
+			ON CONFLICT(item_id) DO UPDATE SET priority = MAX(priority, excluded.priority)
+		`, args...)
+		if err != nil {
+			return fmt.Errorf("set embed priority: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// RecordEmbedFailure counts a failed embedding attempt for an item and
+// schedules the next one per policy, dead-lettering the job once
+// MaxAttempts is reached. Returns the updated job.
+// Thread-safe: acquires write lock.
+func (s *Store) RecordEmbedFailure(id string, cause error, p EmbedRetryPolicy) (EmbedJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return EmbedJob{}, fmt.Errorf("record embed failure for %s: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	job := EmbedJob{ItemID: id}
+	err = tx.QueryRow("SELECT attempts FROM embed_jobs WHERE item_id = ?", id).Scan(&job.Attempts)
+	if err != nil && err != sql.ErrNoRows {
+		return EmbedJob{}, fmt.Errorf("record embed failure for %s: %w", id, err)
+	}
+
+	now := time.Now()
+	job.Attempts++
+	job.State = EmbedJobPending
+	if p.MaxAttempts > 0 && job.Attempts >= p.MaxAttempts {
+		job.State = EmbedJobDead
+	}
+	if cause != nil {
+		job.LastError = cause.Error()
+	}
+	job.NextAttempt = now.Add(p.Delay(job.Attempts))
+	job.Updated = now
+
+	_, err = tx.Exec(`
+		INSERT INTO embed_jobs (item_id, state, attempts, last_error, next_attempt_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(item_id) DO UPDATE SET
+			state = excluded.state,
+			attempts = excluded.attempts,
+			last_error = excluded.last_error,
+			next_attempt_at = excluded.next_attempt_at,
+			updated_at = excluded.updated_at
+	`, job.ItemID, job.State, job.Attempts, job.LastError, job.NextAttempt, job.Updated)
+	if err != nil {
+		return EmbedJob{}, fmt.Errorf("record embed failure for %s: %w", id, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return EmbedJob{}, fmt.Errorf("record embed failure for %s: %w", id, err)
+	}
+	return job, nil
+}
+
+// ListEmbedJobs returns failed jobs in the given state ("" for all),
+// most recently updated first, up to limit.
+// Thread-safe: acquires read lock.
+func (s *Store) ListEmbedJobs(state string, limit int) ([]EmbedJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT j.item_id, COALESCE(i.title, ''), COALESCE(i.source_name, ''),
+			j.state, j.attempts, j.last_error, j.next_attempt_at, j.updated_at
+		FROM embed_jobs j
+		LEFT JOIN items i ON i.id = j.item_id
+		WHERE j.attempts > 0 AND (? = '' OR j.state = ?)
+		ORDER BY j.updated_at DESC
+		LIMIT ?
+	`, state, state, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list embed jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []EmbedJob
+	for rows.Next() {
+		var j EmbedJob
+		if err := rows.Scan(&j.ItemID, &j.Title, &j.SourceName, &j.State, &j.Attempts,
+			&j.LastError, &j.NextAttempt, &j.Updated); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// EmbedQueueStats counts ready, backing-off and dead items among those
+// with no vector from model.
+// Thread-safe: acquires read lock.
+func (s *Store) EmbedQueueStats(model string) (EmbedQueueStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var st EmbedQueueStats
+	err := s.db.QueryRow(`
+		SELECT
+			COALESCE(SUM(CASE WHEN j.item_id IS NULL OR (j.state = 'pending' AND j.next_attempt_at <= ?) THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN j.state = 'pending' AND j.next_attempt_at > ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN j.state = 'dead' THEN 1 ELSE 0 END), 0)
+		FROM items i`+embedReadyJoin+`
+		WHERE `+embeddingMissing+`
+	`, time.Now(), time.Now(), model).Scan(&st.Ready, &st.Backoff, &st.Dead)
+	if err != nil {
+		return EmbedQueueStats{}, fmt.Errorf("embed queue stats: %w", err)
+	}
+	return st, nil
+}
+
+// RequeueEmbedJobs clears the failure history of the given items so they
+// are retried on the next cycle. With no IDs, requeues every dead job.
+// Returns the number of jobs requeued.
+// Thread-safe: acquires write lock.
+func (s *Store) RequeueEmbedJobs(ids ...string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := "DELETE FROM embed_jobs WHERE state = 'dead'"
+	var args []any
+	if len(ids) > 0 {
+		query = "DELETE FROM embed_jobs WHERE item_id IN (?" + repeatString(",?", len(ids)-1) + ")"
+		args = make([]any, len(ids))
+		for i, id := range ids {
+			args[i] = id
+		}
+	}
+	result, err := s.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("requeue embed jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmbedRetryPolicyDelay(t *testing.T) {
+	p := EmbedRetryPolicy{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute}
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, w := range want {
+		if got := p.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestEmbedJobs_BackoffAndDeadLetter(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	_, err = st.SaveItems([]Item{
+		{ID: "bad", SourceType: "rss", SourceName: "S", Title: "Bad", URL: "http://x/bad", Published: now, Fetched: now},
+		{ID: "good", SourceType: "rss", SourceName: "S", Title: "Good", URL: "http://x/good", Published: now, Fetched: now.Add(time.Second)},
+	})
+	if err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+
+	policy := EmbedRetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	job, err := st.RecordEmbedFailure("bad", errors.New("400 input rejected"), policy)
+	if err != nil {
+		t.Fatalf("RecordEmbedFailure: %v", err)
+	}
+	if job.State != EmbedJobPending || job.Attempts != 1 {
+		t.Errorf("after first failure: state=%s attempts=%d", job.State, job.Attempts)
+	}
+
+	// The failed item backs off; only the good one is due.
+	items, err := st.GetItemsNeedingEmbedding("", 10)
+	if err != nil {
+		t.Fatalf("GetItemsNeedingEmbedding: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "good" {
+		t.Errorf("expected only good item to be due, got %v", items)
+	}
+	stats, err := st.EmbedQueueStats("")
+	if err != nil {
+		t.Fatalf("EmbedQueueStats: %v", err)
+	}
+	if stats != (EmbedQueueStats{Ready: 1, Backoff: 1}) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	job, err = st.RecordEmbedFailure("bad", errors.New("400 again"), policy)
+	if err != nil {
+		t.Fatalf("RecordEmbedFailure: %v", err)
+	}
+	if job.State != EmbedJobDead || job.Attempts != 2 {
+		t.Errorf("after second failure: state=%s attempts=%d", job.State, job.Attempts)
+	}
+
+	dead, err := st.ListEmbedJobs(EmbedJobDead, 10)
+	if err != nil {
+		t.Fatalf("ListEmbedJobs: %v", err)
+	}
+	if len(dead) != 1 || dead[0].ItemID != "bad" || dead[0].Title != "Bad" || dead[0].LastError != "400 again" {
+		t.Errorf("unexpected dead jobs: %+v", dead)
+	}
+
+	n, err := st.RequeueEmbedJobs()
+	if err != nil || n != 1 {
+		t.Fatalf("RequeueEmbedJobs: n=%d err=%v", n, err)
+	}
+	items, _ = st.GetItemsNeedingEmbedding("", 10)
+	if len(items) != 2 {
+		t.Errorf("expected both items due after requeue, got %d", len(items))
+	}
+}
+
+func TestEmbedJobs_SaveEmbeddingClearsJob(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	st.SaveItems([]Item{{ID: "a", SourceType: "rss", SourceName: "S", Title: "A", URL: "http://x/a", Published: now, Fetched: now}})
+
+	if _, err := st.RecordEmbedFailure("a", errors.New("timeout"), DefaultEmbedRetryPolicy); err != nil {
+		t.Fatalf("RecordEmbedFailure: %v", err)
+	}
+	if err := st.SaveEmbedding("a", []float32{1, 0}); err != nil {
+		t.Fatalf("SaveEmbedding: %v", err)
+	}
+	jobs, err := st.ListEmbedJobs("", 10)
+	if err != nil {
+		t.Fatalf("ListEmbedJobs: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("expected job cleared after successful embedding, got %+v", jobs)
+	}
+}
+
+func TestGetItemsNeedingEmbedding_PriorityOrder(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	old := now.Add(-7 * 24 * time.Hour)
+	_, err = st.SaveItems([]Item{
+		{ID: "old", SourceType: "rss", SourceName: "S", Title: "Old", URL: "http://x/old", Published: old, Fetched: now},
+		{ID: "older", SourceType: "rss", SourceName: "S", Title: "Older", URL: "http://x/older", Published: old.Add(-time.Hour), Fetched: now},
+		{ID: "today", SourceType: "rss", SourceName: "S", Title: "Today", URL: "http://x/today", Published: now.Add(-time.Hour), Fetched: now},
+		{ID: "today-read", SourceType: "rss", SourceName: "S", Title: "Read", URL: "http://x/read", Published: now, Fetched: now, Read: true},
+		{ID: "pooled", SourceType: "rss", SourceName: "S", Title: "Pooled", URL: "http://x/pooled", Published: old.Add(-2 * time.Hour), Fetched: now},
+		{ID: "visible", SourceType: "rss", SourceName: "S", Title: "Visible", URL: "http://x/visible", Published: old.Add(-3 * time.Hour), Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+
+	if err := st.SetEmbedPriority(EmbedPrioritySearch, []string{"pooled"}); err != nil {
+		t.Fatalf("SetEmbedPriority: %v", err)
+	}
+	if err := st.SetEmbedPriority(EmbedPriorityVisible, []string{"visible"}); err != nil {
+		t.Fatalf("SetEmbedPriority: %v", err)
+	}
+
+	got := embedOrder(t, st)
+	want := []string{"visible", "pooled", "today", "today-read", "old", "older"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	// A new visible window replaces the old one.
+	if err := st.SetEmbedPriority(EmbedPriorityVisible, []string{"older"}); err != nil {
+		t.Fatalf("SetEmbedPriority: %v", err)
+	}
+	got = embedOrder(t, st)
+	want = []string{"older", "pooled", "today", "today-read", "old", "visible"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("after re-bump order = %v, want %v", got, want)
+	}
+
+	// Bumps don't show up as failed jobs.
+	jobs, _ := st.ListEmbedJobs("", 10)
+	if len(jobs) != 0 {
+		t.Errorf("expected no failed jobs, got %+v", jobs)
+	}
+}
+
+func embedOrder(t *testing.T, st *Store) []string {
+	t.Helper()
+	items, err := st.GetItemsNeedingEmbedding("", 10)
+	if err != nil {
+		t.Fatalf("GetItemsNeedingEmbedding: %v", err)
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
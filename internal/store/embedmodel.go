@@ -0,0 +1,190 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrateEmbeddingModel adds the embedding_model column if it doesn't
+// exist. Vectors stored before it existed have an empty model until
+// AdoptEmbeddings attributes them.
+func (s *Store) migrateEmbeddingModel() error {
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM pragma_table_info('items')
+		WHERE name = 'embedding_model'
+	`).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		_, err = s.db.Exec(`ALTER TABLE items ADD COLUMN embedding_model TEXT NOT NULL DEFAULT ''`)
+		if err != nil {
+			return fmt.Errorf("add embedding_model column: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateItemEmbeddings creates the item_embeddings table, which keeps
+// one vector per item and model so vectors built while a fallback backend
+// served survive the return to the primary (and the other way round), and
+// moves vectors stored on the items table into it.
+func (s *Store) migrateItemEmbeddings() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS item_embeddings (
+			item_id TEXT NOT NULL,
+			embedding_model TEXT NOT NULL DEFAULT '',
+			embedding BLOB NOT NULL,
+			updated_at DATETIME NOT NULL,
+			PRIMARY KEY (item_id, embedding_model)
+		);
+		CREATE INDEX IF NOT EXISTS idx_item_embeddings_model ON item_embeddings(embedding_model);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		INSERT OR IGNORE INTO item_embeddings (item_id, embedding_model, embedding, updated_at)
+		SELECT id, embedding_model, embedding, fetched_at FROM items
+		WHERE embedding IS NOT NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("move embeddings: %w", err)
+	}
+	if _, err := s.db.Exec("UPDATE items SET embedding = NULL, embedding_model = '' WHERE embedding IS NOT NULL"); err != nil {
+		return fmt.Errorf("move embeddings: %w", err)
+	}
+	return nil
+}
+
+// AdoptEmbeddings attributes vectors with no recorded model to model if
+// they have dims dimensions. Before models were recorded every vector
+// came from the one configured backend, but that need not be the backend
+// configured now: a vector of another length can't be from model, and is
+// left unattributed. Items that already have a vector from model keep it.
+// Returns the number of vectors adopted.
+// Thread-safe: acquires write lock.
+func (s *Store) AdoptEmbeddings(model string, dims int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.Exec(`
+		UPDATE OR IGNORE item_embeddings SET embedding_model = ?
+		WHERE embedding_model = '' AND length(embedding) = ?
+	`, model, dims*4)
+	if err != nil {
+		return 0, fmt.Errorf("adopt embeddings: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// EmbeddingDims returns the dimension of the vectors stored from model,
+// or 0 if there are none.
+// Thread-safe: acquires read lock.
+func (s *Store) EmbeddingDims(model string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var n int
+	err := s.db.QueryRow("SELECT length(embedding) FROM item_embeddings WHERE embedding_model = ? LIMIT 1", model).Scan(&n)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("embedding dims of %s: %w", model, err)
+	}
+	return n / 4, nil
+}
+
+// ClearEmbeddingsExcept deletes the vectors and chunks of every model
+// other than model, unrecorded ones included. Vectors from fallback
+// models are what search uses while the primary is down, so this only
+// runs on request (obs backfill --prune).
+// Returns the number of vectors deleted.
+// Thread-safe: acquires write lock.
+func (s *Store) ClearEmbeddingsExcept(model string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.Exec("DELETE FROM item_embeddings WHERE embedding_model != ?", model)
+	if err != nil {
+		return 0, fmt.Errorf("clear embeddings except %s: %w", model, err)
+	}
+	if _, err := s.db.Exec("DELETE FROM item_chunks WHERE embedding_model != ?", model); err != nil {
+		return 0, fmt.Errorf("clear chunks except %s: %w", model, err)
+	}
+	return result.RowsAffected()
+}
+
+// GetModelEmbeddings returns embeddings for the given item IDs that were
+// produced by model. Vectors from other models are left out: they live in
+// a different space, and comparing them would give meaningless scores.
+// Thread-safe: acquires read lock.
+func (s *Store) GetModelEmbeddings(ids []string, model string) (map[string][]float32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string][]float32)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := "SELECT item_id, embedding FROM item_embeddings WHERE item_id IN (?" + repeatString(",?", len(ids)-1) + ") AND embedding_model = ?"
+	args := make([]any, 0, len(ids)+1)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, model)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get model embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("get model embeddings: %w", err)
+		}
+		result[id] = decodeEmbedding(data)
+	}
+	return result, rows.Err()
+}
+
+// EmbeddingModelCounts returns how many items hold a vector from each
+// model ("" for vectors whose model was never recorded).
+// Thread-safe: acquires read lock.
+func (s *Store) EmbeddingModelCounts() (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT embedding_model, COUNT(*) FROM item_embeddings
+		GROUP BY embedding_model
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("count embedding models: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var model string
+		var n int
+		if err := rows.Scan(&model, &n); err != nil {
+			return nil, fmt.Errorf("count embedding models: %w", err)
+		}
+		counts[model] = n
+	}
+	return counts, rows.Err()
+}
+
+// embeddingMissing is a condition true for items (aliased as i) with no
+// vector from a model, given as its one argument.
+const embeddingMissing = `NOT EXISTS (
+			SELECT 1 FROM item_embeddings e WHERE e.item_id = i.id AND e.embedding_model = ?)`
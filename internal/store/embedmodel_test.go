@@ -0,0 +1,127 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmbeddingModels(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	_, err = st.SaveItems([]Item{
+		{ID: "legacy", SourceType: "rss", SourceName: "S", Title: "Legacy", URL: "http://x/1", Published: now, Fetched: now},
+		{ID: "jina", SourceType: "rss", SourceName: "S", Title: "Jina", URL: "http://x/2", Published: now, Fetched: now},
+		{ID: "local", SourceType: "rss", SourceName: "S", Title: "Local", URL: "http://x/3", Published: now, Fetched: now},
+		{ID: "other", SourceType: "rss", SourceName: "S", Title: "Other", URL: "http://x/4", Published: now, Fetched: now},
+	})
+	if err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+	if err := st.SaveEmbedding("legacy", []float32{1, 0}); err != nil {
+		t.Fatalf("SaveEmbedding: %v", err)
+	}
+	// Built by some other backend: its length says it isn't jina's.
+	if err := st.SaveEmbedding("other", []float32{1, 0, 0}); err != nil {
+		t.Fatalf("SaveEmbedding: %v", err)
+	}
+	if err := st.SaveModelEmbedding("jina", "jina/v3", []float32{0, 1}); err != nil {
+		t.Fatalf("SaveModelEmbedding: %v", err)
+	}
+	if err := st.SaveModelEmbedding("local", "local/hash", []float32{1, 1}); err != nil {
+		t.Fatalf("SaveModelEmbedding: %v", err)
+	}
+
+	ids := []string{"legacy", "jina", "local", "other"}
+	got, err := st.GetModelEmbeddings(ids, "jina/v3")
+	if err != nil {
+		t.Fatalf("GetModelEmbeddings: %v", err)
+	}
+	if len(got) != 1 || got["jina"] == nil {
+		t.Errorf("expected only the jina vector, got %v", got)
+	}
+
+	dims, err := st.EmbeddingDims("jina/v3")
+	if err != nil || dims != 2 {
+		t.Fatalf("EmbeddingDims = %d, %v; want 2", dims, err)
+	}
+	if dims, _ := st.EmbeddingDims("missing/model"); dims != 0 {
+		t.Errorf("EmbeddingDims of a model without vectors = %d", dims)
+	}
+	if n, err := st.AdoptEmbeddings("jina/v3", dims); err != nil || n != 1 {
+		t.Fatalf("AdoptEmbeddings = %d, %v; want 1", n, err)
+	}
+	if got, _ := st.GetModelEmbeddings(ids, "jina/v3"); len(got) != 2 || got["other"] != nil {
+		t.Errorf("expected only the legacy vector of jina's length to be adopted, got %v", got)
+	}
+
+	// A fallback vector for an item joins its primary one.
+	if err := st.SaveModelEmbedding("jina", "local/hash", []float32{0, 2}); err != nil {
+		t.Fatalf("SaveModelEmbedding: %v", err)
+	}
+	if got, _ := st.GetModelEmbeddings(ids, "jina/v3"); got["jina"][1] != 1 {
+		t.Errorf("fallback vector replaced the primary one: %v", got)
+	}
+	if emb, _ := st.GetEmbedding("jina"); len(emb) != 2 || emb[1] != 2 {
+		t.Errorf("GetEmbedding = %v, want the latest vector", emb)
+	}
+
+	counts, err := st.EmbeddingModelCounts()
+	if err != nil {
+		t.Fatalf("EmbeddingModelCounts: %v", err)
+	}
+	if counts["jina/v3"] != 2 || counts["local/hash"] != 2 || counts[""] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+
+	needing, err := st.GetItemsNeedingEmbedding("jina/v3", 10)
+	if err != nil {
+		t.Fatalf("GetItemsNeedingEmbedding: %v", err)
+	}
+	if len(needing) != 2 {
+		t.Errorf("expected local and other to need jina vectors, got %v", needing)
+	}
+	if n, _ := st.CountItemsNeedingEmbedding("local/hash"); n != 2 {
+		t.Errorf("CountItemsNeedingEmbedding(local/hash) = %d, want 2", n)
+	}
+
+	if n, err := st.ClearEmbeddingsExcept("jina/v3"); err != nil || n != 3 {
+		t.Fatalf("ClearEmbeddingsExcept = %d, %v; want 3", n, err)
+	}
+	if got, _ := st.GetModelEmbeddings(ids, "local/hash"); len(got) != 0 {
+		t.Errorf("expected the fallback vectors to be pruned, got %v", got)
+	}
+	if got, _ := st.GetModelEmbeddings(ids, "jina/v3"); len(got) != 2 {
+		t.Errorf("pruning lost primary vectors: %v", got)
+	}
+}
+
+func TestMigrateItemEmbeddings(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	st.SaveItems([]Item{{ID: "a", SourceType: "rss", SourceName: "S", Title: "A", URL: "http://x/a", Published: now, Fetched: now}})
+	// A vector stored on the items table, as before item_embeddings.
+	if _, err := st.db.Exec("UPDATE items SET embedding = ?, embedding_model = 'jina/v3' WHERE id = 'a'", encodeEmbedding([]float32{1, 2})); err != nil {
+		t.Fatalf("store legacy vector: %v", err)
+	}
+	if err := st.migrateItemEmbeddings(); err != nil {
+		t.Fatalf("migrateItemEmbeddings: %v", err)
+	}
+	if got, _ := st.GetModelEmbeddings([]string{"a"}, "jina/v3"); len(got["a"]) != 2 || got["a"][1] != 2 {
+		t.Errorf("vector not moved: %v", got)
+	}
+	var left int
+	st.db.QueryRow("SELECT COUNT(*) FROM items WHERE embedding IS NOT NULL").Scan(&left)
+	if left != 0 {
+		t.Errorf("%d vectors left on items", left)
+	}
+}
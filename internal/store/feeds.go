@@ -0,0 +1,101 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Feed is a user-defined feed subscription (RSS, Atom or JSON Feed).
+type Feed struct {
+	URL      string
+	Title    string // display name; empty means use the feed's own title
+	Category string
+	Added    time.Time
+}
+
+// migrateFeeds creates the feeds table if it doesn't exist.
+func (s *Store) migrateFeeds() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS feeds (
+			url TEXT PRIMARY KEY,
+			title TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			added_at DATETIME NOT NULL
+		)
+	`)
+	return err
+}
+
+// AddFeed stores a feed subscription, updating title and category if
+// the URL already exists. Returns true if the feed is new.
+// Thread-safe: acquires write lock.
+func (s *Store) AddFeed(f Feed) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.URL == "" {
+		return false, fmt.Errorf("add feed: url is required")
+	}
+	if f.Added.IsZero() {
+		f.Added = time.Now()
+	}
+
+	var exists int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM feeds WHERE url = ?", f.URL).Scan(&exists); err != nil {
+		return false, fmt.Errorf("add feed %s: %w", f.URL, err)
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO feeds (url, title, category, added_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET title = excluded.title, category = excluded.category
+	`, f.URL, f.Title, f.Category, f.Added)
+	if err != nil {
+		return false, fmt.Errorf("add feed %s: %w", f.URL, err)
+	}
+	return exists == 0, nil
+}
+
+// RemoveFeed deletes a feed subscription. Items already fetched from it
+// are kept. Returns false if no such feed existed.
+// Thread-safe: acquires write lock.
+func (s *Store) RemoveFeed(url string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.Exec("DELETE FROM feeds WHERE url = ?", url)
+	if err != nil {
+		return false, fmt.Errorf("remove feed %s: %w", url, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ListFeeds returns all feed subscriptions ordered by category, then title.
+// Thread-safe: acquires read lock.
+func (s *Store) ListFeeds() ([]Feed, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT url, title, category, added_at
+		FROM feeds
+		ORDER BY category, title, url
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list feeds: %w", err)
+	}
+	defer rows.Close()
+
+	var feeds []Feed
+	for rows.Next() {
+		var f Feed
+		if err := rows.Scan(&f.URL, &f.Title, &f.Category, &f.Added); err != nil {
+			return nil, fmt.Errorf("scan feed: %w", err)
+		}
+		feeds = append(feeds, f)
+	}
+	return feeds, rows.Err()
+}
@@ -0,0 +1,55 @@
+package store
+
+import "testing"
+
+func TestFeeds_AddListRemove(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	added, err := st.AddFeed(Feed{URL: "https://b.example.com/rss", Title: "Beta", Category: "tech"})
+	if err != nil || !added {
+		t.Fatalf("AddFeed: added=%v err=%v", added, err)
+	}
+	if _, err := st.AddFeed(Feed{URL: "https://a.example.com/atom", Title: "Alpha", Category: "news"}); err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+
+	// Re-adding updates metadata and reports not-new.
+	added, err = st.AddFeed(Feed{URL: "https://b.example.com/rss", Title: "Beta Renamed", Category: "tech"})
+	if err != nil {
+		t.Fatalf("AddFeed (update): %v", err)
+	}
+	if added {
+		t.Error("expected re-add to report existing feed")
+	}
+
+	feeds, err := st.ListFeeds()
+	if err != nil {
+		t.Fatalf("ListFeeds: %v", err)
+	}
+	if len(feeds) != 2 {
+		t.Fatalf("expected 2 feeds, got %d", len(feeds))
+	}
+	if feeds[0].Category != "news" || feeds[1].Title != "Beta Renamed" {
+		t.Errorf("unexpected order or metadata: %+v", feeds)
+	}
+	if feeds[0].Added.IsZero() {
+		t.Error("expected added_at to be set")
+	}
+
+	removed, err := st.RemoveFeed("https://a.example.com/atom")
+	if err != nil || !removed {
+		t.Fatalf("RemoveFeed: removed=%v err=%v", removed, err)
+	}
+	removed, err = st.RemoveFeed("https://a.example.com/atom")
+	if err != nil || removed {
+		t.Errorf("second RemoveFeed: removed=%v err=%v", removed, err)
+	}
+
+	if _, err := st.AddFeed(Feed{}); err == nil {
+		t.Error("expected error for empty URL")
+	}
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Interest signal kinds: what the user did with an item.
+const (
+	SignalRead         = "read"     // opened from the feed
+	SignalSaved        = "saved"    // saved for later
+	SignalMoreLikeThis = "mlt"      // used as a more-like-this seed
+	SignalExcluded     = "excluded" // dismissed as less like this
+)
+
+// Signal is one thing the user did with an item, the input to the
+// local interest profile.
+type Signal struct {
+	Item Item
+	Kind string
+	At   time.Time
+}
+
+// migrateInterest creates the interest_signals table if it doesn't exist.
+// An item carries each kind of signal at most once, at its latest time.
+func (s *Store) migrateInterest() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS interest_signals (
+			item_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			at DATETIME NOT NULL,
+			PRIMARY KEY (item_id, kind)
+		);
+		CREATE INDEX IF NOT EXISTS idx_interest_signals_at ON interest_signals(at);
+	`)
+	return err
+}
+
+// RecordSignal records that the user did kind with an item now. An
+// excluded item is marked read so it leaves the feed, and loses its
+// read and saved signals: dismissing it outweighs having opened it.
+// Thread-safe: acquires write lock.
+func (s *Store) RecordSignal(itemID, kind string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if kind != SignalExcluded {
+		return s.recordSignal(itemID, kind, time.Now())
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin exclude %s: %w", itemID, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE items SET read = 1, saved = 0 WHERE id = ?", itemID); err != nil {
+		return fmt.Errorf("exclude %s: %w", itemID, err)
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM interest_signals WHERE item_id = ? AND kind IN (?, ?)
+	`, itemID, SignalRead, SignalSaved); err != nil {
+		return fmt.Errorf("exclude %s: %w", itemID, err)
+	}
+	if _, err := tx.Exec(`
+		INSERT OR REPLACE INTO interest_signals (item_id, kind, at) VALUES (?, ?, ?)
+	`, itemID, kind, time.Now()); err != nil {
+		return fmt.Errorf("exclude %s: %w", itemID, err)
+	}
+	return tx.Commit()
+}
+
+// recordSignal upserts a signal. Caller must hold s.mu for writing.
+func (s *Store) recordSignal(itemID, kind string, at time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO interest_signals (item_id, kind, at) VALUES (?, ?, ?)
+	`, itemID, kind, at)
+	if err != nil {
+		return fmt.Errorf("record %s signal for %s: %w", kind, itemID, err)
+	}
+	return nil
+}
+
+// ForgetSignal removes one signal so it no longer shapes the profile.
+// Forgetting a saved signal does not unsave the item.
+// Thread-safe: acquires write lock.
+func (s *Store) ForgetSignal(itemID, kind string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("DELETE FROM interest_signals WHERE item_id = ? AND kind = ?", itemID, kind)
+	if err != nil {
+		return fmt.Errorf("forget %s signal for %s: %w", kind, itemID, err)
+	}
+	return nil
+}
+
+// ResetInterest forgets every signal. Read and saved states are kept.
+// Thread-safe: acquires write lock.
+func (s *Store) ResetInterest() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.Exec("DELETE FROM interest_signals"); err != nil {
+		return fmt.Errorf("reset interest: %w", err)
+	}
+	return nil
+}
+
+// Signals returns the signals recorded after since with their items,
+// newest first.
+// Thread-safe: acquires read lock.
+func (s *Store) Signals(since time.Time) ([]Signal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT sig.kind, sig.at,
+			i.id, i.source_type, i.source_name, i.title, i.summary, i.url, i.author,
+			i.published_at, i.fetched_at, i.read, i.saved, i.provider
+		FROM interest_signals sig
+		JOIN items i ON i.id = sig.item_id
+		WHERE sig.at > ?
+		ORDER BY sig.at DESC
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("signals since %s: %w", since.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	var signals []Signal
+	for rows.Next() {
+		var sig Signal
+		var readInt, savedInt int
+		if err := rows.Scan(&sig.Kind, &sig.At,
+			&sig.Item.ID, &sig.Item.SourceType, &sig.Item.SourceName, &sig.Item.Title,
+			&sig.Item.Summary, &sig.Item.URL, &sig.Item.Author, &sig.Item.Published,
+			&sig.Item.Fetched, &readInt, &savedInt, &sig.Item.Provider,
+		); err != nil {
+			return nil, fmt.Errorf("scan signal: %w", err)
+		}
+		sig.Item.Read = readInt != 0
+		sig.Item.Saved = savedInt != 0
+		signals = append(signals, sig)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("signals: %w", err)
+	}
+	return signals, nil
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterestSignals(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	items := []Item{
+		{ID: "a", SourceName: "Wire", Title: "Read me", URL: "https://a", Published: now},
+		{ID: "b", SourceName: "Wire", Title: "Save me", URL: "https://b", Published: now},
+		{ID: "c", SourceName: "Blog", Title: "Not for me", URL: "https://c", Published: now},
+	}
+	if _, err := st.SaveItems(items); err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+
+	if err := st.MarkRead("a"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if err := st.MarkSaved("b", true); err != nil {
+		t.Fatalf("MarkSaved: %v", err)
+	}
+	if err := st.RecordSignal("b", SignalMoreLikeThis); err != nil {
+		t.Fatalf("RecordSignal: %v", err)
+	}
+	if err := st.MarkRead("c"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if err := st.RecordSignal("c", SignalExcluded); err != nil {
+		t.Fatalf("RecordSignal(excluded): %v", err)
+	}
+
+	signals, err := st.Signals(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Signals: %v", err)
+	}
+	kinds := make(map[string]string)
+	for _, sig := range signals {
+		kinds[sig.Item.ID+"/"+sig.Kind] = sig.Item.Title
+	}
+	want := []string{"a/read", "b/saved", "b/mlt", "c/excluded"}
+	if len(signals) != len(want) {
+		t.Fatalf("signals = %v, want %v", kinds, want)
+	}
+	for _, k := range want {
+		if _, ok := kinds[k]; !ok {
+			t.Errorf("missing signal %s in %v", k, kinds)
+		}
+	}
+	if signals[0].Item.ID != "c" {
+		t.Errorf("newest signal is %s, want c", signals[0].Item.ID)
+	}
+
+	unread, _ := st.GetItems(10, false)
+	if len(unread) != 1 || unread[0].ID != "b" {
+		t.Errorf("unread = %v, want only b (excluded items are marked read)", unread)
+	}
+
+	if err := st.MarkSaved("b", false); err != nil {
+		t.Fatalf("MarkSaved(false): %v", err)
+	}
+	if err := st.ForgetSignal("a", SignalRead); err != nil {
+		t.Fatalf("ForgetSignal: %v", err)
+	}
+	if signals, _ := st.Signals(time.Time{}); len(signals) != 2 {
+		t.Errorf("after unsave and forget: %d signals, want 2", len(signals))
+	}
+	if signals, _ := st.Signals(now.Add(time.Hour)); len(signals) != 0 {
+		t.Errorf("signals after a future time: %d", len(signals))
+	}
+
+	if err := st.ResetInterest(); err != nil {
+		t.Fatalf("ResetInterest: %v", err)
+	}
+	if signals, _ := st.Signals(time.Time{}); len(signals) != 0 {
+		t.Errorf("after reset: %d signals", len(signals))
+	}
+}
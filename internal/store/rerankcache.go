@@ -0,0 +1,193 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RerankKey identifies the text a reranker scored: the item and a hash of
+// its title and summary, so an edited item is scored again.
+type RerankKey struct {
+	ItemID      string
+	ContentHash string
+}
+
+// NewRerankKey returns the rerank cache key for item's current text.
+func NewRerankKey(item Item) RerankKey {
+	h := sha256.New()
+	h.Write([]byte(item.Title))
+	h.Write([]byte{0})
+	h.Write([]byte(item.Summary))
+	return RerankKey{ItemID: item.ID, ContentHash: hex.EncodeToString(h.Sum(nil)[:16])}
+}
+
+// RerankScore is a cross-encoder score for one item.
+type RerankScore struct {
+	RerankKey
+	Score float32
+}
+
+// RerankCacheStats summarizes the rerank score cache.
+type RerankCacheStats struct {
+	Entries int64 // cached scores, i.e. documents scored by a backend
+	Hits    int64 // scores served from the cache instead of a backend
+}
+
+// HitRate is the fraction of scores served from the cache.
+func (s RerankCacheStats) HitRate() float64 {
+	if s.Hits+s.Entries == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Entries)
+}
+
+// migrateRerankCache creates the rerank_cache table if it doesn't exist.
+// Scores are keyed by reranker name, normalized query and item; the
+// content hash is checked on lookup. Scores from different rerankers are
+// not comparable, so the name is part of the key.
+func (s *Store) migrateRerankCache() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS rerank_cache (
+			reranker TEXT NOT NULL,
+			query TEXT NOT NULL,
+			item_id TEXT NOT NULL,
+			content_hash TEXT NOT NULL,
+			score REAL NOT NULL,
+			created_at DATETIME NOT NULL,
+			hits INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (reranker, query, item_id)
+		);
+	`)
+	return err
+}
+
+// normalizeRerankQuery folds case and whitespace, so "Super  Bowl" and
+// "super bowl" share scores.
+func normalizeRerankQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// GetRerankScores returns cached scores by item ID for query as scored by
+// reranker, and counts each one found as a hit. Items whose text changed
+// since they were scored are absent from the result.
+// Thread-safe: acquires write lock.
+func (s *Store) GetRerankScores(reranker, query string, keys []RerankKey) (map[string]float32, error) {
+	result := make(map[string]float32)
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := normalizeRerankQuery(query)
+	args := []any{reranker, q}
+	for _, k := range keys {
+		args = append(args, k.ItemID)
+	}
+	in := "(?" + repeatString(",?", len(keys)-1) + ")"
+
+	rows, err := s.db.Query("SELECT item_id, content_hash, score FROM rerank_cache WHERE reranker = ? AND query = ? AND item_id IN "+in, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get rerank scores: %w", err)
+	}
+	type cached struct {
+		hash  string
+		score float32
+	}
+	found := make(map[string]cached)
+	for rows.Next() {
+		var id, hash string
+		var score float64
+		if err := rows.Scan(&id, &hash, &score); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("get rerank scores: %w", err)
+		}
+		found[id] = cached{hash, float32(score)}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get rerank scores: %w", err)
+	}
+
+	for _, k := range keys {
+		if f, ok := found[k.ItemID]; ok && f.hash == k.ContentHash {
+			result[k.ItemID] = f.score
+		}
+	}
+	for id := range result {
+		if _, err := s.db.Exec("UPDATE rerank_cache SET hits = hits + 1 WHERE reranker = ? AND query = ? AND item_id = ?", reranker, q, id); err != nil {
+			return nil, fmt.Errorf("count rerank cache hits: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// SaveRerankScores caches scores for query as scored by reranker,
+// replacing earlier scores for the same items.
+// Thread-safe: acquires write lock.
+func (s *Store) SaveRerankScores(reranker, query string, scores []RerankScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("save rerank scores: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := normalizeRerankQuery(query)
+	now := time.Now()
+	for _, sc := range scores {
+		_, err := tx.Exec(`
+			INSERT INTO rerank_cache (reranker, query, item_id, content_hash, score, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(reranker, query, item_id) DO UPDATE SET
+				content_hash = excluded.content_hash,
+				score = excluded.score,
+				created_at = excluded.created_at
+		`, reranker, q, sc.ItemID, sc.ContentHash, float64(sc.Score), now)
+		if err != nil {
+			return fmt.Errorf("save rerank scores: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("save rerank scores: %w", err)
+	}
+	return nil
+}
+
+// RerankCacheStats counts cache entries and hits.
+// Thread-safe: acquires read lock.
+func (s *Store) RerankCacheStats() (RerankCacheStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var st RerankCacheStats
+	err := s.db.QueryRow("SELECT COUNT(*), COALESCE(SUM(hits), 0) FROM rerank_cache").Scan(&st.Entries, &st.Hits)
+	if err != nil {
+		return RerankCacheStats{}, fmt.Errorf("rerank cache stats: %w", err)
+	}
+	return st, nil
+}
+
+// ClearRerankScores deletes every cached score from reranker, e.g. after
+// its calibration changes. Returns the number of entries deleted.
+// Thread-safe: acquires write lock.
+func (s *Store) ClearRerankScores(reranker string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.db.Exec("DELETE FROM rerank_cache WHERE reranker = ?", reranker)
+	if err != nil {
+		return 0, fmt.Errorf("clear rerank scores: %w", err)
+	}
+	return res.RowsAffected()
+}
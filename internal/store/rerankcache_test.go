@@ -0,0 +1,65 @@
+package store
+
+import "testing"
+
+func TestRerankCache(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	a := Item{ID: "a", Title: "Chiefs win Super Bowl"}
+	b := Item{ID: "b", Title: "Fed holds rates"}
+	err = st.SaveRerankScores("jina/v3", "Super  Bowl", []RerankScore{
+		{RerankKey: NewRerankKey(a), Score: 0.9},
+		{RerankKey: NewRerankKey(b), Score: 0.1},
+	})
+	if err != nil {
+		t.Fatalf("SaveRerankScores: %v", err)
+	}
+
+	// The query is normalized; b's text has changed since it was scored.
+	b.Summary = "Updated with the statement"
+	got, err := st.GetRerankScores("jina/v3", "super bowl ", []RerankKey{NewRerankKey(a), NewRerankKey(b), {ItemID: "missing"}})
+	if err != nil {
+		t.Fatalf("GetRerankScores: %v", err)
+	}
+	if len(got) != 1 || got["a"] < 0.89 || got["a"] > 0.91 {
+		t.Errorf("unexpected cache result: %v", got)
+	}
+
+	// Scores from another reranker are not shared.
+	got, err = st.GetRerankScores("ollama/qwen3", "super bowl", []RerankKey{NewRerankKey(a)})
+	if err != nil || len(got) != 0 {
+		t.Errorf("other reranker got %v, %v; want nothing", got, err)
+	}
+
+	// Rescoring replaces the entry.
+	if err := st.SaveRerankScores("jina/v3", "super bowl", []RerankScore{{RerankKey: NewRerankKey(b), Score: 0.2}}); err != nil {
+		t.Fatalf("SaveRerankScores: %v", err)
+	}
+	got, _ = st.GetRerankScores("jina/v3", "super bowl", []RerankKey{NewRerankKey(b)})
+	if len(got) != 1 {
+		t.Errorf("rescored item missing: %v", got)
+	}
+
+	stats, err := st.RerankCacheStats()
+	if err != nil {
+		t.Fatalf("RerankCacheStats: %v", err)
+	}
+	if stats.Entries != 2 || stats.Hits != 2 {
+		t.Errorf("expected 2 entries and 2 hits, got %+v", stats)
+	}
+
+	// Clearing one reranker leaves the others.
+	if err := st.SaveRerankScores("ollama/qwen3", "super bowl", []RerankScore{{RerankKey: NewRerankKey(a), Score: 0.7}}); err != nil {
+		t.Fatalf("SaveRerankScores: %v", err)
+	}
+	if n, err := st.ClearRerankScores("jina/v3"); err != nil || n != 2 {
+		t.Errorf("ClearRerankScores = %d, %v; want 2", n, err)
+	}
+	if stats, _ := st.RerankCacheStats(); stats.Entries != 1 {
+		t.Errorf("expected 1 entry left, got %+v", stats)
+	}
+}
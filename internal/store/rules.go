@@ -0,0 +1,229 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Rule kinds: what a rule matches.
+const (
+	RuleKeyword  = "keyword"  // Pattern is a word or phrase in the title or summary
+	RuleRegex    = "regex"    // Pattern is a regular expression over the title and summary
+	RuleSource   = "source"   // Pattern is a source name
+	RuleAuthor   = "author"   // Pattern is an author
+	RuleSemantic = "semantic" // Embedding is the vector of an example item
+)
+
+// Rule actions: what happens to matching items.
+const (
+	RuleMute  = "mute"  // hidden from the feed
+	RuleBoost = "boost" // listed first in their time band
+	RuleAllow = "allow" // never hidden by a mute rule
+)
+
+// Rule is a user-defined feed rule, created from the TUI.
+type Rule struct {
+	ID     int64
+	Kind   string
+	Action string
+	// Pattern is the keyword, regex, source or author; for semantic rules,
+	// the example's title (for display).
+	Pattern string
+
+	// Semantic rules: items whose vector from Model is at least Threshold
+	// cosine-similar to the example's Embedding match.
+	ExampleID string
+	Embedding []float32
+	Model     string
+	Threshold float32
+
+	Created time.Time
+
+	// HiddenToday is the number of items the rule hid since local
+	// midnight (see RecordRuleHits). Set by ListRules.
+	HiddenToday int
+}
+
+// Validate checks the rule's kind, action and pattern.
+func (r Rule) Validate() error {
+	switch r.Action {
+	case RuleMute, RuleBoost, RuleAllow:
+	default:
+		return fmt.Errorf("rule: unknown action %q", r.Action)
+	}
+	switch r.Kind {
+	case RuleKeyword, RuleSource, RuleAuthor:
+		if strings.TrimSpace(r.Pattern) == "" {
+			return fmt.Errorf("rule: %s pattern is required", r.Kind)
+		}
+	case RuleRegex:
+		if r.Pattern == "" {
+			return errors.New("rule: regex pattern is required")
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("rule: %w", err)
+		}
+	case RuleSemantic:
+		if len(r.Embedding) == 0 || r.Model == "" {
+			return errors.New("rule: semantic rule needs the example's embedding and model")
+		}
+		if r.Threshold <= 0 || r.Threshold > 1 {
+			return fmt.Errorf("rule: threshold %v not in (0, 1]", r.Threshold)
+		}
+	default:
+		return fmt.Errorf("rule: unknown kind %q", r.Kind)
+	}
+	return nil
+}
+
+// migrateRules creates the rules and rule_hits tables if they don't
+// exist. rule_hits records each item a mute rule hid, once per day, for
+// the TUI's "hidden today" counts.
+func (s *Store) migrateRules() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			action TEXT NOT NULL,
+			pattern TEXT NOT NULL DEFAULT '',
+			example_id TEXT NOT NULL DEFAULT '',
+			embedding BLOB,
+			model TEXT NOT NULL DEFAULT '',
+			threshold REAL NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS rule_hits (
+			rule_id INTEGER NOT NULL,
+			item_id TEXT NOT NULL,
+			day TEXT NOT NULL,
+			PRIMARY KEY (rule_id, day, item_id)
+		);
+	`)
+	return err
+}
+
+// AddRule validates and stores a rule, returning it with its ID and
+// creation time set.
+// Thread-safe: acquires write lock.
+func (s *Store) AddRule(r Rule) (Rule, error) {
+	if err := r.Validate(); err != nil {
+		return Rule{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Created.IsZero() {
+		r.Created = time.Now()
+	}
+	var embedding []byte
+	if len(r.Embedding) > 0 {
+		embedding = encodeEmbedding(r.Embedding)
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO rules (kind, action, pattern, example_id, embedding, model, threshold, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, r.Kind, r.Action, r.Pattern, r.ExampleID, embedding, r.Model, r.Threshold, r.Created)
+	if err != nil {
+		return Rule{}, fmt.Errorf("add rule: %w", err)
+	}
+	if r.ID, err = result.LastInsertId(); err != nil {
+		return Rule{}, fmt.Errorf("add rule: %w", err)
+	}
+	r.HiddenToday = 0
+	return r, nil
+}
+
+// DeleteRule removes a rule and its hit counts. Returns false if no such
+// rule existed.
+// Thread-safe: acquires write lock.
+func (s *Store) DeleteRule(id int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.Exec("DELETE FROM rules WHERE id = ?", id)
+	if err != nil {
+		return false, fmt.Errorf("delete rule %d: %w", id, err)
+	}
+	if _, err := s.db.Exec("DELETE FROM rule_hits WHERE rule_id = ?", id); err != nil {
+		return false, fmt.Errorf("delete rule %d: %w", id, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ListRules returns every rule, oldest first, with today's hidden counts.
+// Thread-safe: acquires read lock.
+func (s *Store) ListRules() ([]Rule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT r.id, r.kind, r.action, r.pattern, r.example_id, r.embedding, r.model, r.threshold, r.created_at,
+			(SELECT COUNT(*) FROM rule_hits h WHERE h.rule_id = r.id AND h.day = ?)
+		FROM rules r
+		ORDER BY r.id
+	`, ruleDay(time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("list rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []Rule
+	for rows.Next() {
+		var r Rule
+		var embedding []byte
+		if err := rows.Scan(&r.ID, &r.Kind, &r.Action, &r.Pattern, &r.ExampleID, &embedding, &r.Model, &r.Threshold, &r.Created, &r.HiddenToday); err != nil {
+			return nil, fmt.Errorf("list rules: %w", err)
+		}
+		r.Embedding = decodeEmbedding(embedding)
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// RecordRuleHits records that each rule hid the given item IDs today.
+// An item counts once per rule per day however often the feed reloads.
+// Hits from earlier days are pruned.
+// Thread-safe: acquires write lock.
+func (s *Store) RecordRuleHits(hits map[int64][]string) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	day := ruleDay(time.Now())
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("record rule hits: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM rule_hits WHERE day < ?", day); err != nil {
+		return fmt.Errorf("record rule hits: %w", err)
+	}
+	stmt, err := tx.Prepare("INSERT OR IGNORE INTO rule_hits (rule_id, item_id, day) VALUES (?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("record rule hits: %w", err)
+	}
+	defer stmt.Close()
+	for id, items := range hits {
+		for _, itemID := range items {
+			if _, err := stmt.Exec(id, itemID, day); err != nil {
+				return fmt.Errorf("record rule hits: %w", err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// ruleDay is the rule_hits day key: the local calendar date.
+func ruleDay(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	mute, err := st.AddRule(Rule{Kind: RuleKeyword, Action: RuleMute, Pattern: "crypto"})
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	if mute.ID == 0 || mute.Created.IsZero() {
+		t.Errorf("AddRule did not set ID and Created: %+v", mute)
+	}
+	like, err := st.AddRule(Rule{Kind: RuleSemantic, Action: RuleBoost, Pattern: "Webb finds water", ExampleID: "a", Embedding: []float32{0.6, 0.8}, Model: "m", Threshold: 0.8})
+	if err != nil {
+		t.Fatalf("AddRule(semantic): %v", err)
+	}
+
+	if err := st.RecordRuleHits(map[int64][]string{mute.ID: {"x", "y"}}); err != nil {
+		t.Fatalf("RecordRuleHits: %v", err)
+	}
+	// Reloading the feed hides the same items again: counted once.
+	if err := st.RecordRuleHits(map[int64][]string{mute.ID: {"y", "z"}}); err != nil {
+		t.Fatalf("RecordRuleHits: %v", err)
+	}
+
+	rules, err := st.ListRules()
+	if err != nil {
+		t.Fatalf("ListRules: %v", err)
+	}
+	if len(rules) != 2 || rules[0].ID != mute.ID || rules[1].ID != like.ID {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+	if rules[0].HiddenToday != 3 || rules[1].HiddenToday != 0 {
+		t.Errorf("HiddenToday = %d, %d; want 3, 0", rules[0].HiddenToday, rules[1].HiddenToday)
+	}
+	if r := rules[1]; len(r.Embedding) != 2 || r.Embedding[1] != 0.8 || r.Model != "m" || r.Threshold != 0.8 || r.ExampleID != "a" {
+		t.Errorf("semantic rule did not round-trip: %+v", r)
+	}
+
+	ok, err := st.DeleteRule(mute.ID)
+	if err != nil || !ok {
+		t.Fatalf("DeleteRule: ok=%v err=%v", ok, err)
+	}
+	if ok, _ := st.DeleteRule(mute.ID); ok {
+		t.Error("second DeleteRule reported a deletion")
+	}
+	if rules, _ := st.ListRules(); len(rules) != 1 {
+		t.Errorf("expected 1 rule after delete, got %d", len(rules))
+	}
+}
+
+func TestAddRuleValidates(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	tests := []struct {
+		rule Rule
+		want string
+	}{
+		{Rule{Kind: RuleKeyword, Action: "hide", Pattern: "x"}, "unknown action"},
+		{Rule{Kind: "title", Action: RuleMute, Pattern: "x"}, "unknown kind"},
+		{Rule{Kind: RuleSource, Action: RuleMute, Pattern: " "}, "pattern is required"},
+		{Rule{Kind: RuleRegex, Action: RuleMute, Pattern: "(unclosed"}, "missing closing"},
+		{Rule{Kind: RuleSemantic, Action: RuleMute, Pattern: "t"}, "embedding"},
+		{Rule{Kind: RuleSemantic, Action: RuleMute, Embedding: []float32{1}, Model: "m"}, "threshold"},
+	}
+	for _, tt := range tests {
+		if _, err := st.AddRule(tt.rule); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("AddRule(%+v) error = %v, want %q", tt.rule, err, tt.want)
+		}
+	}
+	if rules, _ := st.ListRules(); len(rules) != 0 {
+		t.Errorf("invalid rules were stored: %+v", rules)
+	}
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// migrateMutedSources creates the muted_sources table if it doesn't exist.
+func (s *Store) migrateMutedSources() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS muted_sources (
+			source_name TEXT PRIMARY KEY,
+			muted_at DATETIME NOT NULL
+		)
+	`)
+	return err
+}
+
+// MuteSource mutes a source by name: it is no longer fetched and its
+// items are hidden. Idempotent.
+// Thread-safe: acquires write lock.
+func (s *Store) MuteSource(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO muted_sources (source_name, muted_at) VALUES (?, ?)
+	`, name, time.Now())
+	if err != nil {
+		return fmt.Errorf("mute source %s: %w", name, err)
+	}
+	return nil
+}
+
+// UnmuteSource removes a mute. Returns false if the source was not muted.
+// Thread-safe: acquires write lock.
+func (s *Store) UnmuteSource(name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.Exec("DELETE FROM muted_sources WHERE source_name = ?", name)
+	if err != nil {
+		return false, fmt.Errorf("unmute source %s: %w", name, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ListMutedSources returns muted source names in alphabetical order.
+// Thread-safe: acquires read lock.
+func (s *Store) ListMutedSources() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query("SELECT source_name FROM muted_sources ORDER BY source_name")
+	if err != nil {
+		return nil, fmt.Errorf("list muted sources: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// CountItemsNeedingEmbeddingExcept is CountItemsNeedingEmbedding ignoring
+// items from the given sources.
+// Thread-safe: acquires read lock.
+func (s *Store) CountItemsNeedingEmbeddingExcept(model string, excludeSources []string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	where, args := excludeSourcesClause(excludeSources)
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM items i WHERE "+embeddingMissing+where, append([]any{model}, args...)...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count items needing embedding: %w", err)
+	}
+	return count, nil
+}
+
+// GetItemsNeedingEmbeddingExcept is GetItemsNeedingEmbedding ignoring
+// items from the given sources.
+// Thread-safe: acquires read lock.
+func (s *Store) GetItemsNeedingEmbeddingExcept(model string, limit int, excludeSources []string) ([]Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	where, args := excludeSourcesClause(excludeSources)
+	query := `
+		SELECT i.id, i.source_type, i.source_name, i.title, i.summary, i.url, i.author,
+			i.published_at, i.fetched_at, i.read, i.saved, i.provider
+		FROM items i` + embedReadyJoin + embedReadyWhere + where + embedPriorityOrder + `
+		LIMIT ?
+	`
+	now := time.Now()
+	args = append([]any{model, now}, args...)
+	return s.queryItems(query, append(args, now.Add(-recentEmbedWindow), limit)...)
+}
+
+// excludeSourcesClause builds an " AND source_name NOT IN (...)" clause.
+func excludeSourcesClause(sources []string) (string, []any) {
+	if len(sources) == 0 {
+		return "", nil
+	}
+	args := make([]any, len(sources))
+	for i, name := range sources {
+		args[i] = name
+	}
+	return " AND i.source_name NOT IN (?" + repeatString(",?", len(sources)-1) + ")", args
+}
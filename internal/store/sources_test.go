@@ -0,0 +1,83 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMutedSources(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	if err := st.MuteSource("Noisy"); err != nil {
+		t.Fatalf("MuteSource: %v", err)
+	}
+	if err := st.MuteSource("Noisy"); err != nil {
+		t.Fatalf("MuteSource should be idempotent: %v", err)
+	}
+	if err := st.MuteSource("Another"); err != nil {
+		t.Fatalf("MuteSource: %v", err)
+	}
+
+	muted, err := st.ListMutedSources()
+	if err != nil {
+		t.Fatalf("ListMutedSources: %v", err)
+	}
+	if len(muted) != 2 || muted[0] != "Another" || muted[1] != "Noisy" {
+		t.Errorf("unexpected muted list: %v", muted)
+	}
+
+	ok, err := st.UnmuteSource("Noisy")
+	if err != nil || !ok {
+		t.Fatalf("UnmuteSource: ok=%v err=%v", ok, err)
+	}
+	ok, err = st.UnmuteSource("Noisy")
+	if err != nil || ok {
+		t.Errorf("second UnmuteSource: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestGetItemsNeedingEmbeddingExcept(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	items := []Item{
+		{ID: "keep1", SourceType: "rss", SourceName: "Keep", Title: "A", URL: "https://example.com/1", Published: now, Fetched: now},
+		{ID: "drop1", SourceType: "rss", SourceName: "Drop", Title: "B", URL: "https://example.com/2", Published: now, Fetched: now},
+		{ID: "keep2", SourceType: "rss", SourceName: "Keep", Title: "C", URL: "https://example.com/3", Published: now, Fetched: now},
+	}
+	if _, err := st.SaveItems(items); err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+
+	got, err := st.GetItemsNeedingEmbeddingExcept("", 10, []string{"Drop"})
+	if err != nil {
+		t.Fatalf("GetItemsNeedingEmbeddingExcept: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(got))
+	}
+	for _, item := range got {
+		if item.SourceName == "Drop" {
+			t.Errorf("excluded source returned: %s", item.ID)
+		}
+	}
+
+	n, err := st.CountItemsNeedingEmbeddingExcept("", []string{"Drop"})
+	if err != nil || n != 2 {
+		t.Errorf("CountItemsNeedingEmbeddingExcept = %d, %v; want 2", n, err)
+	}
+
+	// No exclusions behaves like the unfiltered query.
+	all, err := st.GetItemsNeedingEmbeddingExcept("", 10, nil)
+	if err != nil || len(all) != 3 {
+		t.Errorf("expected 3 items with no exclusions, got %d (%v)", len(all), err)
+	}
+}
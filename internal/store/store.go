@@ -33,6 +33,7 @@ type Item struct {
 	Fetched    time.Time
 	Read       bool
 	Saved      bool
+	Provider   string // ingestion provider that produced the item ("clarion", ...)
 }
 
 // Open creates a new Store with the given database path.
@@ -98,6 +99,71 @@ func Open(dbPath string) (*Store, error) {
 		return nil, fmt.Errorf("migrate embeddings: %w", err)
 	}
 
+	if err := s.migrateProvider(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate provider: %w", err)
+	}
+
+	if err := s.migrateFeeds(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate feeds: %w", err)
+	}
+
+	if err := s.migrateMutedSources(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate muted sources: %w", err)
+	}
+
+	if err := s.migrateEmbedJobs(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate embed jobs: %w", err)
+	}
+
+	if err := s.migrateUsage(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate usage: %w", err)
+	}
+
+	if err := s.migrateEmbedCache(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate embed cache: %w", err)
+	}
+
+	if err := s.migrateEmbeddingModel(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate embedding model: %w", err)
+	}
+
+	if err := s.migrateItemEmbeddings(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate item embeddings: %w", err)
+	}
+
+	if err := s.migrateChunks(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate chunks: %w", err)
+	}
+
+	if err := s.migrateRerankCache(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate rerank cache: %w", err)
+	}
+
+	if err := s.migrateRules(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate rules: %w", err)
+	}
+
+	if err := s.migrateThreads(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate threads: %w", err)
+	}
+
+	if err := s.migrateInterest(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate interest: %w", err)
+	}
+
 	return s, nil
 }
 
@@ -272,7 +338,7 @@ func (s *Store) searchFTSRaw(query string, limit int) ([]Item, error) {
 	// Column weights: title=10, summary=5, source_name=1, author=3.
 	rows, err := s.db.Query(`
 		SELECT i.id, i.source_type, i.source_name, i.title, i.summary,
-			   i.url, i.author, i.published_at, i.fetched_at, i.read, i.saved
+			   i.url, i.author, i.published_at, i.fetched_at, i.read, i.saved, i.provider
 		FROM items_fts
 		JOIN items i ON i.rowid = items_fts.rowid
 		WHERE items_fts MATCH ?
@@ -291,7 +357,7 @@ func (s *Store) searchFTSRaw(query string, limit int) ([]Item, error) {
 		if err := rows.Scan(
 			&item.ID, &item.SourceType, &item.SourceName, &item.Title,
 			&item.Summary, &item.URL, &item.Author, &item.Published,
-			&item.Fetched, &read, &saved,
+			&item.Fetched, &read, &saved, &item.Provider,
 		); err != nil {
 			return nil, fmt.Errorf("scan FTS result: %w", err)
 		}
@@ -326,8 +392,8 @@ func (s *Store) SaveItems(items []Item) (int, error) {
 	stmt, err := s.db.Prepare(`
 		INSERT OR IGNORE INTO items (
 			id, source_type, source_name, title, summary, url, author,
-			published_at, fetched_at, read, saved
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			published_at, fetched_at, read, saved, provider
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return 0, fmt.Errorf("prepare insert: %w", err)
@@ -348,6 +414,7 @@ func (s *Store) SaveItems(items []Item) (int, error) {
 			item.Fetched,
 			boolToInt(item.Read),
 			boolToInt(item.Saved),
+			item.Provider,
 		)
 		if err != nil {
 			return newCount, err
@@ -379,7 +446,7 @@ func (s *Store) GetItems(limit int, includeRead bool) ([]Item, error) {
 	if includeRead {
 		query = `
 			SELECT id, source_type, source_name, title, summary, url, author,
-				published_at, fetched_at, read, saved
+				published_at, fetched_at, read, saved, provider
 			FROM items
 			ORDER BY published_at DESC
 			LIMIT ?
@@ -388,7 +455,7 @@ func (s *Store) GetItems(limit int, includeRead bool) ([]Item, error) {
 	} else {
 		query = `
 			SELECT id, source_type, source_name, title, summary, url, author,
-				published_at, fetched_at, read, saved
+				published_at, fetched_at, read, saved, provider
 			FROM items
 			WHERE read = 0
 			ORDER BY published_at DESC
@@ -408,7 +475,7 @@ func (s *Store) GetItemsSince(since time.Time) ([]Item, error) {
 
 	query := `
 		SELECT id, source_type, source_name, title, summary, url, author,
-			published_at, fetched_at, read, saved
+			published_at, fetched_at, read, saved, provider
 		FROM items
 		WHERE published_at > ?
 		ORDER BY published_at DESC
@@ -417,7 +484,8 @@ func (s *Store) GetItemsSince(since time.Time) ([]Item, error) {
 	return s.queryItems(query, since)
 }
 
-// MarkRead marks an item as read.
+// MarkRead marks an item as read and records a read signal for the
+// interest profile.
 // Thread-safe: acquires write lock.
 func (s *Store) MarkRead(id string) error {
 	s.mu.Lock()
@@ -427,10 +495,11 @@ func (s *Store) MarkRead(id string) error {
 	if err != nil {
 		return fmt.Errorf("mark read %s: %w", id, err)
 	}
-	return nil
+	return s.recordSignal(id, SignalRead, time.Now())
 }
 
-// MarkSaved toggles the saved state of an item.
+// MarkSaved toggles the saved state of an item, recording or forgetting
+// its saved signal for the interest profile.
 // Thread-safe: acquires write lock.
 func (s *Store) MarkSaved(id string, saved bool) error {
 	s.mu.Lock()
@@ -440,6 +509,12 @@ func (s *Store) MarkSaved(id string, saved bool) error {
 	if err != nil {
 		return fmt.Errorf("mark saved %s: %w", id, err)
 	}
+	if saved {
+		return s.recordSignal(id, SignalSaved, time.Now())
+	}
+	if _, err := s.db.Exec("DELETE FROM interest_signals WHERE item_id = ? AND kind = ?", id, SignalSaved); err != nil {
+		return fmt.Errorf("forget saved signal for %s: %w", id, err)
+	}
 	return nil
 }
 
@@ -468,6 +543,7 @@ func (s *Store) queryItems(query string, args ...any) ([]Item, error) {
 			&item.Fetched,
 			&readInt,
 			&savedInt,
+			&item.Provider,
 		)
 		if err != nil {
 			return nil, err
@@ -493,6 +569,8 @@ func boolToInt(b bool) int {
 }
 
 // migrateEmbeddings adds the embedding column and index if they don't exist.
+// Vectors now live in item_embeddings; the column only holds those stored
+// before it existed, until migrateItemEmbeddings moves them.
 func (s *Store) migrateEmbeddings() error {
 	// Check if column exists using pragma_table_info
 	var count int
@@ -519,74 +597,121 @@ func (s *Store) migrateEmbeddings() error {
 	return err
 }
 
-// SaveEmbedding stores an embedding for an item.
+// migrateProvider adds the provider column if it doesn't exist.
+// Items stored before multi-provider ingestion keep an empty provider.
+func (s *Store) migrateProvider() error {
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM pragma_table_info('items')
+		WHERE name = 'provider'
+	`).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		_, err = s.db.Exec(`ALTER TABLE items ADD COLUMN provider TEXT NOT NULL DEFAULT ''`)
+		if err != nil {
+			return fmt.Errorf("add provider column: %w", err)
+		}
+	}
+	return nil
+}
+
+// SaveEmbedding stores an embedding for an item without recording its
+// model. Prefer SaveModelEmbedding.
 // Thread-safe: acquires write lock.
 func (s *Store) SaveEmbedding(id string, embedding []float32) error {
+	return s.SaveModelEmbedding(id, "", embedding)
+}
+
+// SaveModelEmbedding stores an embedding for an item along with the model
+// that produced it, replacing only that model's vector, so it is only ever
+// compared with vectors from the same model (see GetModelEmbeddings).
+// Thread-safe: acquires write lock.
+func (s *Store) SaveModelEmbedding(id, model string, embedding []float32) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	data := encodeEmbedding(embedding)
-	_, err := s.db.Exec("UPDATE items SET embedding = ? WHERE id = ?", data, id)
+	_, err := s.db.Exec(`
+		INSERT INTO item_embeddings (item_id, embedding_model, embedding, updated_at)
+		SELECT id, ?, ?, ? FROM items WHERE id = ?
+		ON CONFLICT(item_id, embedding_model) DO UPDATE SET
+			embedding = excluded.embedding,
+			updated_at = excluded.updated_at
+	`, model, data, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("save embedding for %s: %w", id, err)
 	}
+	// Success closes any retry record.
+	if _, err := s.db.Exec("DELETE FROM embed_jobs WHERE item_id = ?", id); err != nil {
+		return fmt.Errorf("save embedding for %s: %w", id, err)
+	}
 	return nil
 }
 
-// CountItemsNeedingEmbedding returns the number of items with NULL embedding.
+// CountItemsNeedingEmbedding returns the number of items with no vector
+// from model.
 // Thread-safe: acquires read lock.
-func (s *Store) CountItemsNeedingEmbedding() (int, error) {
+func (s *Store) CountItemsNeedingEmbedding(model string) (int, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	var count int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM items WHERE embedding IS NULL").Scan(&count)
+	err := s.db.QueryRow("SELECT COUNT(*) FROM items i WHERE "+embeddingMissing, model).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("count items needing embedding: %w", err)
 	}
 	return count, nil
 }
 
-// GetItemsNeedingEmbedding returns items with NULL embedding.
-// Returns oldest items first (by fetched_at) up to limit.
+// GetItemsNeedingEmbedding returns items with no vector from model that
+// are due: dead-lettered items and items backing off after a failure are
+// skipped.
+// Returns up to limit items in priority order: items bumped by the TUI
+// (visible, then search pool), then recent unread, then backfill, newest
+// first within each tier.
 // Thread-safe: acquires read lock.
-func (s *Store) GetItemsNeedingEmbedding(limit int) ([]Item, error) {
+func (s *Store) GetItemsNeedingEmbedding(model string, limit int) ([]Item, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	query := `
-		SELECT id, source_type, source_name, title, summary, url, author,
-			published_at, fetched_at, read, saved
-		FROM items
-		WHERE embedding IS NULL
-		ORDER BY fetched_at ASC
+		SELECT i.id, i.source_type, i.source_name, i.title, i.summary, i.url, i.author,
+			i.published_at, i.fetched_at, i.read, i.saved, i.provider
+		FROM items i` + embedReadyJoin + embedReadyWhere + embedPriorityOrder + `
 		LIMIT ?
 	`
 
-	return s.queryItems(query, limit)
+	now := time.Now()
+	return s.queryItems(query, model, now, now.Add(-recentEmbedWindow), limit)
 }
 
-// GetEmbedding returns the embedding for an item, or nil if not set.
+// GetEmbedding returns the item's most recently saved embedding from any
+// model, or nil if it has none.
 // Thread-safe: acquires read lock.
 func (s *Store) GetEmbedding(id string) ([]float32, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	var data []byte
-	err := s.db.QueryRow("SELECT embedding FROM items WHERE id = ?", id).Scan(&data)
+	err := s.db.QueryRow(`
+		SELECT embedding FROM item_embeddings WHERE item_id = ?
+		ORDER BY updated_at DESC LIMIT 1
+	`, id).Scan(&data)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	if data == nil {
-		return nil, nil
-	}
 	return decodeEmbedding(data), nil
 }
 
-// GetItemsWithEmbeddings returns embeddings for given item IDs.
+// GetItemsWithEmbeddings returns embeddings for given item IDs: each
+// item's most recently saved vector, whatever its model. Prefer
+// GetModelEmbeddings.
 // Thread-safe: acquires read lock.
 func (s *Store) GetItemsWithEmbeddings(ids []string) (map[string][]float32, error) {
 	s.mu.RLock()
@@ -598,8 +723,8 @@ func (s *Store) GetItemsWithEmbeddings(ids []string) (map[string][]float32, erro
 
 	result := make(map[string][]float32)
 
-	// Build query with placeholders
-	query := "SELECT id, embedding FROM items WHERE id IN (?" + repeatString(",?", len(ids)-1) + ") AND embedding IS NOT NULL"
+	// Build query with placeholders; later rows overwrite earlier ones.
+	query := "SELECT item_id, embedding FROM item_embeddings WHERE item_id IN (?" + repeatString(",?", len(ids)-1) + ") ORDER BY updated_at"
 
 	args := make([]any, len(ids))
 	for i, id := range ids {
@@ -618,9 +743,7 @@ func (s *Store) GetItemsWithEmbeddings(ids []string) (map[string][]float32, erro
 		if err := rows.Scan(&id, &data); err != nil {
 			return nil, err
 		}
-		if data != nil {
-			result[id] = decodeEmbedding(data)
-		}
+		result[id] = decodeEmbedding(data)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -660,17 +783,24 @@ func repeatString(s string, n int) string {
 	return strings.Repeat(s, n)
 }
 
-// ClearAllEmbeddings sets all embeddings to NULL.
-// Returns the number of items that had their embeddings cleared.
+// ClearAllEmbeddings deletes every model's embeddings and all chunks.
+// Returns the number of vectors deleted.
 // Thread-safe: acquires write lock.
 func (s *Store) ClearAllEmbeddings() (int64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.db.Exec("UPDATE items SET embedding = NULL WHERE embedding IS NOT NULL")
+	result, err := s.db.Exec("DELETE FROM item_embeddings")
 	if err != nil {
 		return 0, fmt.Errorf("clear embeddings: %w", err)
 	}
+	// Failures against the old backend say nothing about the new one.
+	if _, err := s.db.Exec("DELETE FROM embed_jobs"); err != nil {
+		return 0, fmt.Errorf("clear embed jobs: %w", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM item_chunks"); err != nil {
+		return 0, fmt.Errorf("clear chunks: %w", err)
+	}
 	return result.RowsAffected()
 }
 
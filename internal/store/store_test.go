@@ -617,7 +617,7 @@ func TestGetItemsNeedingEmbedding(t *testing.T) {
 	}
 
 	// All items need embedding initially
-	needing, err := st.GetItemsNeedingEmbedding(10)
+	needing, err := st.GetItemsNeedingEmbedding("", 10)
 	if err != nil {
 		t.Fatalf("GetItemsNeedingEmbedding failed: %v", err)
 	}
@@ -625,9 +625,9 @@ func TestGetItemsNeedingEmbedding(t *testing.T) {
 		t.Errorf("expected 3 items needing embedding, got %d", len(needing))
 	}
 
-	// Oldest should be first (by fetched_at)
-	if needing[0].ID != "item3" {
-		t.Errorf("expected item3 first (oldest), got %s", needing[0].ID)
+	// Newest should be first (all three are recent unread news)
+	if needing[0].ID != "item1" {
+		t.Errorf("expected item1 first (newest), got %s", needing[0].ID)
 	}
 
 	// Add embedding to item2
@@ -637,7 +637,7 @@ func TestGetItemsNeedingEmbedding(t *testing.T) {
 	}
 
 	// Now only 2 items need embedding
-	needing, err = st.GetItemsNeedingEmbedding(10)
+	needing, err = st.GetItemsNeedingEmbedding("", 10)
 	if err != nil {
 		t.Fatalf("GetItemsNeedingEmbedding failed: %v", err)
 	}
@@ -646,7 +646,7 @@ func TestGetItemsNeedingEmbedding(t *testing.T) {
 	}
 
 	// Test limit
-	needing, err = st.GetItemsNeedingEmbedding(1)
+	needing, err = st.GetItemsNeedingEmbedding("", 1)
 	if err != nil {
 		t.Fatalf("GetItemsNeedingEmbedding with limit failed: %v", err)
 	}
@@ -926,3 +926,39 @@ func TestMigrationIdempotent(t *testing.T) {
 		t.Errorf("expected index to exist once, count = %d", count)
 	}
 }
+
+func TestProviderRoundTrip(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	// Open already ran the migration; a second run must be a no-op.
+	if err := st.migrateProvider(); err != nil {
+		t.Fatalf("second migrateProvider failed: %v", err)
+	}
+
+	now := time.Now()
+	items := []Item{
+		{ID: "p1", SourceType: "rss", SourceName: "Feed", Title: "Tagged", URL: "https://example.com/p1", Published: now, Fetched: now, Provider: "feeds"},
+		{ID: "p2", SourceType: "rss", SourceName: "Feed", Title: "Untagged", URL: "https://example.com/p2", Published: now.Add(-time.Minute), Fetched: now},
+	}
+	if _, err := st.SaveItems(items); err != nil {
+		t.Fatalf("SaveItems failed: %v", err)
+	}
+
+	got, err := st.GetItems(10, true)
+	if err != nil {
+		t.Fatalf("GetItems failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(got))
+	}
+	if got[0].Provider != "feeds" {
+		t.Errorf("expected provider 'feeds', got %q", got[0].Provider)
+	}
+	if got[1].Provider != "" {
+		t.Errorf("expected empty provider, got %q", got[1].Provider)
+	}
+}
@@ -0,0 +1,273 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Thread is a developing story: items linked across time by the thread
+// linker (see internal/thread) as they are embedded.
+type Thread struct {
+	ID    int64
+	Title string // the first report's title
+	Model string // embedding model of Centroid; threads only link vectors from it
+
+	// Centroid is the mean vector of the linked items; Terms and Entities
+	// count the title words and names they share. Set by ActiveThreads
+	// only.
+	Centroid []float32
+	Terms    map[string]int
+	Entities map[string]int
+
+	Items  int       // linked items
+	First  time.Time // earliest report
+	Latest time.Time // latest report
+}
+
+// migrateThreads creates the threads and thread_items tables if they
+// don't exist. An item belongs to at most one thread.
+func (s *Store) migrateThreads() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS threads (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			title TEXT NOT NULL,
+			model TEXT NOT NULL,
+			centroid BLOB,
+			terms TEXT NOT NULL DEFAULT '{}',
+			entities TEXT NOT NULL DEFAULT '{}',
+			item_count INTEGER NOT NULL DEFAULT 0,
+			first_published DATETIME NOT NULL,
+			last_published DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_threads_active ON threads(model, last_published);
+		CREATE TABLE IF NOT EXISTS thread_items (
+			item_id TEXT PRIMARY KEY,
+			thread_id INTEGER NOT NULL,
+			similarity REAL NOT NULL DEFAULT 0,
+			linked_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_thread_items_thread ON thread_items(thread_id);
+	`)
+	return err
+}
+
+// LinkThreadItem saves t, creating it if its ID is 0, and links itemID
+// to it with the similarity that placed it there. Returns t with its ID
+// set and its item count taken from its links, and true. Returns false
+// and saves nothing if itemID is already in a thread: t was computed
+// from a snapshot another linker has since moved past.
+// Thread-safe: acquires write lock.
+func (s *Store) LinkThreadItem(t Thread, itemID string, similarity float32) (Thread, bool, error) {
+	terms, err := json.Marshal(t.Terms)
+	if err != nil {
+		return Thread{}, false, fmt.Errorf("link %s to thread: %w", itemID, err)
+	}
+	entities, err := json.Marshal(t.Entities)
+	if err != nil {
+		return Thread{}, false, fmt.Errorf("link %s to thread: %w", itemID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Thread{}, false, fmt.Errorf("link %s to thread: %w", itemID, err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if t.ID == 0 {
+		result, err := tx.Exec(`
+			INSERT INTO threads (title, model, centroid, terms, entities, item_count, first_published, last_published, updated_at)
+			VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?)
+		`, t.Title, t.Model, encodeEmbedding(t.Centroid), string(terms), string(entities), t.First, t.Latest, now)
+		if err != nil {
+			return Thread{}, false, fmt.Errorf("link %s to thread: %w", itemID, err)
+		}
+		if t.ID, err = result.LastInsertId(); err != nil {
+			return Thread{}, false, fmt.Errorf("link %s to thread: %w", itemID, err)
+		}
+	}
+
+	// Link first: an item already in a thread rolls the whole change back.
+	result, err := tx.Exec(`
+		INSERT INTO thread_items (item_id, thread_id, similarity, linked_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(item_id) DO NOTHING
+	`, itemID, t.ID, similarity, now)
+	if err != nil {
+		return Thread{}, false, fmt.Errorf("link %s to thread %d: %w", itemID, t.ID, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return Thread{}, false, fmt.Errorf("link %s to thread %d: %w", itemID, t.ID, err)
+	}
+	if n == 0 {
+		return t, false, nil
+	}
+
+	err = tx.QueryRow(`
+		UPDATE threads SET title = ?, centroid = ?, terms = ?, entities = ?,
+			item_count = (SELECT COUNT(*) FROM thread_items WHERE thread_id = ?),
+			first_published = ?, last_published = ?, updated_at = ?
+		WHERE id = ?
+		RETURNING item_count
+	`, t.Title, encodeEmbedding(t.Centroid), string(terms), string(entities), t.ID, t.First, t.Latest, now, t.ID).Scan(&t.Items)
+	if err != nil {
+		return Thread{}, false, fmt.Errorf("link %s to thread %d: %w", itemID, t.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return Thread{}, false, fmt.Errorf("link %s to thread %d: %w", itemID, t.ID, err)
+	}
+	return t, true, nil
+}
+
+// ActiveThreads returns the threads of model with a report after since,
+// with their centroids and counts, most recent first.
+// Thread-safe: acquires read lock.
+func (s *Store) ActiveThreads(model string, since time.Time) ([]Thread, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT id, title, model, centroid, terms, entities, item_count, first_published, last_published
+		FROM threads
+		WHERE model = ? AND last_published > ?
+		ORDER BY last_published DESC
+	`, model, since)
+	if err != nil {
+		return nil, fmt.Errorf("active threads: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []Thread
+	for rows.Next() {
+		var t Thread
+		var centroid []byte
+		var terms, entities string
+		if err := rows.Scan(&t.ID, &t.Title, &t.Model, &centroid, &terms, &entities, &t.Items, &t.First, &t.Latest); err != nil {
+			return nil, fmt.Errorf("active threads: %w", err)
+		}
+		t.Centroid = decodeEmbedding(centroid)
+		if err := json.Unmarshal([]byte(terms), &t.Terms); err != nil {
+			return nil, fmt.Errorf("thread %d terms: %w", t.ID, err)
+		}
+		if err := json.Unmarshal([]byte(entities), &t.Entities); err != nil {
+			return nil, fmt.Errorf("thread %d entities: %w", t.ID, err)
+		}
+		threads = append(threads, t)
+	}
+	return threads, rows.Err()
+}
+
+// GetItemsNeedingThread returns up to limit items with a vector from
+// model that aren't in a thread yet, oldest first, so threads grow in
+// the order the story was reported.
+// Thread-safe: acquires read lock.
+func (s *Store) GetItemsNeedingThread(model string, limit int) ([]Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items, err := s.queryItems(`
+		SELECT i.id, i.source_type, i.source_name, i.title, i.summary, i.url, i.author,
+			i.published_at, i.fetched_at, i.read, i.saved, i.provider
+		FROM items i
+		LEFT JOIN thread_items t ON t.item_id = i.id
+		JOIN item_embeddings e ON e.item_id = i.id AND e.embedding_model = ?
+		WHERE t.item_id IS NULL
+		ORDER BY i.published_at ASC
+		LIMIT ?
+	`, model, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get items needing thread: %w", err)
+	}
+	return items, nil
+}
+
+// ItemThread returns the thread itemID belongs to, without its centroid,
+// terms or entities, and the thread's items oldest first. Returns nil
+// and no items if itemID isn't in a thread.
+// Thread-safe: acquires read lock.
+func (s *Store) ItemThread(itemID string) (*Thread, []Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var t Thread
+	err := s.db.QueryRow(`
+		SELECT t.id, t.title, t.model, t.item_count, t.first_published, t.last_published
+		FROM thread_items ti
+		JOIN threads t ON t.id = ti.thread_id
+		WHERE ti.item_id = ?
+	`, itemID).Scan(&t.ID, &t.Title, &t.Model, &t.Items, &t.First, &t.Latest)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("thread of %s: %w", itemID, err)
+	}
+
+	items, err := s.queryItems(`
+		SELECT i.id, i.source_type, i.source_name, i.title, i.summary, i.url, i.author,
+			i.published_at, i.fetched_at, i.read, i.saved, i.provider
+		FROM thread_items ti
+		JOIN items i ON i.id = ti.item_id
+		WHERE ti.thread_id = ?
+		ORDER BY i.published_at ASC
+	`, t.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("thread %d items: %w", t.ID, err)
+	}
+	return &t, items, nil
+}
+
+// ThreadItemsSince returns the threads with a report published after
+// since, without their centroids, terms or entities, and those reports
+// by thread, oldest first. Used to measure how fast stories are being taken up.
+// Thread-safe: acquires read lock.
+func (s *Store) ThreadItemsSince(since time.Time) ([]Thread, map[int64][]Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT t.id, t.title, t.model, t.item_count, t.first_published, t.last_published,
+			i.id, i.source_type, i.source_name, i.title, i.summary, i.url, i.author,
+			i.published_at, i.fetched_at, i.read, i.saved, i.provider
+		FROM thread_items ti
+		JOIN items i ON i.id = ti.item_id
+		JOIN threads t ON t.id = ti.thread_id
+		WHERE i.published_at > ?
+		ORDER BY i.published_at ASC
+	`, since)
+	if err != nil {
+		return nil, nil, fmt.Errorf("thread items since %s: %w", since.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	var threads []Thread
+	items := make(map[int64][]Item)
+	for rows.Next() {
+		var t Thread
+		var item Item
+		var readInt, savedInt int
+		err := rows.Scan(&t.ID, &t.Title, &t.Model, &t.Items, &t.First, &t.Latest,
+			&item.ID, &item.SourceType, &item.SourceName, &item.Title, &item.Summary, &item.URL, &item.Author,
+			&item.Published, &item.Fetched, &readInt, &savedInt, &item.Provider)
+		if err != nil {
+			return nil, nil, fmt.Errorf("thread items since: %w", err)
+		}
+		item.Read = readInt != 0
+		item.Saved = savedInt != 0
+		if _, ok := items[t.ID]; !ok {
+			threads = append(threads, t)
+		}
+		items[t.ID] = append(items[t.ID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("thread items since: %w", err)
+	}
+	return threads, items, nil
+}
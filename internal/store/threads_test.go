@@ -0,0 +1,101 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThreads(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now().Truncate(time.Second)
+	items := []Item{
+		{ID: "a", SourceName: "Wire", Title: "Quake hits coast", URL: "https://a", Published: now.Add(-3 * time.Hour)},
+		{ID: "b", SourceName: "Blog", Title: "Quake aftershocks", URL: "https://b", Published: now.Add(-time.Hour)},
+		{ID: "c", SourceName: "Wire", Title: "Cup final", URL: "https://c", Published: now.Add(-2 * time.Hour)},
+		{ID: "d", SourceName: "Wire", Title: "Not embedded", URL: "https://d", Published: now},
+	}
+	if _, err := st.SaveItems(items); err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := st.SaveModelEmbedding(id, "m", []float32{1, 0}); err != nil {
+			t.Fatalf("SaveModelEmbedding: %v", err)
+		}
+	}
+
+	pending, err := st.GetItemsNeedingThread("m", 10)
+	if err != nil {
+		t.Fatalf("GetItemsNeedingThread: %v", err)
+	}
+	if len(pending) != 3 || pending[0].ID != "a" || pending[2].ID != "b" {
+		t.Fatalf("pending = %v, want a, c, b oldest first", pending)
+	}
+
+	quake := Thread{
+		Title: "Quake hits coast", Model: "m", Centroid: []float32{1, 0},
+		Terms: map[string]int{"quake": 1}, Entities: map[string]int{},
+		Items: 1, First: items[0].Published, Latest: items[0].Published,
+	}
+	var linked bool
+	if quake, linked, err = st.LinkThreadItem(quake, "a", 1); err != nil || !linked || quake.ID == 0 {
+		t.Fatalf("LinkThreadItem(new): id %d, linked %v, err %v", quake.ID, linked, err)
+	}
+	quake.Latest, quake.Terms["quake"] = items[1].Published, 2
+	if quake, linked, err = st.LinkThreadItem(quake, "b", 0.9); err != nil || !linked || quake.Items != 2 {
+		t.Fatalf("LinkThreadItem: items %d, linked %v, err %v", quake.Items, linked, err)
+	}
+
+	// A linker working from a stale snapshot can't link b again, into
+	// the same thread or a new one.
+	if _, linked, err := st.LinkThreadItem(quake, "b", 0.9); err != nil || linked {
+		t.Errorf("relinking b: linked %v, err %v", linked, err)
+	}
+	if _, linked, err := st.LinkThreadItem(Thread{Title: "Dup", Model: "m", Centroid: []float32{1, 0}, First: now, Latest: now}, "b", 1); err != nil || linked {
+		t.Errorf("linking b into a new thread: linked %v, err %v", linked, err)
+	}
+
+	active, err := st.ActiveThreads("m", now.Add(-2*time.Hour))
+	if err != nil {
+		t.Fatalf("ActiveThreads: %v", err)
+	}
+	if len(active) != 1 || active[0].Items != 2 || active[0].Terms["quake"] != 2 || len(active[0].Centroid) != 2 {
+		t.Fatalf("active = %+v", active)
+	}
+	if other, _ := st.ActiveThreads("other", time.Time{}); len(other) != 0 {
+		t.Errorf("threads of another model: %+v", other)
+	}
+	if stale, _ := st.ActiveThreads("m", now); len(stale) != 0 {
+		t.Errorf("thread without a report since now is active: %+v", stale)
+	}
+
+	thread, members, err := st.ItemThread("b")
+	if err != nil || thread == nil {
+		t.Fatalf("ItemThread: %v, %v", thread, err)
+	}
+	if thread.ID != quake.ID || thread.Title != "Quake hits coast" || !thread.First.Equal(items[0].Published) {
+		t.Errorf("thread = %+v", thread)
+	}
+	if len(members) != 2 || members[0].ID != "a" || members[1].ID != "b" {
+		t.Errorf("members = %v, want a, b", members)
+	}
+	if thread, _, err := st.ItemThread("c"); thread != nil || err != nil {
+		t.Errorf("unthreaded item: %v, %v", thread, err)
+	}
+
+	if pending, _ := st.GetItemsNeedingThread("m", 10); len(pending) != 1 || pending[0].ID != "c" {
+		t.Errorf("pending after linking = %v, want c", pending)
+	}
+
+	threads, byThread, err := st.ThreadItemsSince(now.Add(-2 * time.Hour))
+	if err != nil {
+		t.Fatalf("ThreadItemsSince: %v", err)
+	}
+	if len(threads) != 1 || threads[0].ID != quake.ID || len(byThread[quake.ID]) != 1 || byThread[quake.ID][0].ID != "b" {
+		t.Errorf("ThreadItemsSince = %+v, %v; want b's thread with only b", threads, byThread)
+	}
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+// migrateUsage creates the usage table if it doesn't exist.
+func (s *Store) migrateUsage() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS usage (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			at DATETIME NOT NULL,
+			backend TEXT NOT NULL,
+			model TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			purpose TEXT NOT NULL,
+			requests INTEGER NOT NULL DEFAULT 1,
+			tokens INTEGER NOT NULL DEFAULT 0,
+			chars INTEGER NOT NULL DEFAULT 0,
+			latency_ms INTEGER NOT NULL DEFAULT 0,
+			failed INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS idx_usage_at ON usage(at);
+	`)
+	return err
+}
+
+// RecordUsage stores one backend call. Implements usage.Recorder.
+// Thread-safe: acquires write lock.
+func (s *Store) RecordUsage(r usage.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO usage (at, backend, model, kind, purpose, requests, tokens, chars, latency_ms, failed)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, r.Time, r.Backend, r.Model, string(r.Kind), string(r.Purpose), r.Requests, r.Tokens, r.Chars,
+		r.Latency.Milliseconds(), boolToInt(r.Failed))
+	if err != nil {
+		return fmt.Errorf("record usage: %w", err)
+	}
+	return nil
+}
+
+// UsageTotals sums all usage recorded at or after since.
+// Thread-safe: acquires read lock.
+func (s *Store) UsageTotals(since time.Time) (usage.Totals, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var t usage.Totals
+	var latencyMS int64
+	err := s.db.QueryRow(`
+		SELECT COALESCE(SUM(requests), 0), COALESCE(SUM(tokens), 0), COALESCE(SUM(chars), 0),
+			COALESCE(SUM(failed), 0), COALESCE(SUM(latency_ms), 0)
+		FROM usage WHERE at >= ?
+	`, since).Scan(&t.Requests, &t.Tokens, &t.Chars, &t.Failures, &latencyMS)
+	if err != nil {
+		return usage.Totals{}, fmt.Errorf("usage totals: %w", err)
+	}
+	t.Latency = time.Duration(latencyMS) * time.Millisecond
+	return t, nil
+}
+
+// UsageReport returns usage since the given time grouped by backend,
+// model, kind and purpose, heaviest token users first.
+// Thread-safe: acquires read lock.
+func (s *Store) UsageReport(since time.Time) ([]usage.Row, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT backend, model, kind, purpose,
+			SUM(requests), SUM(tokens), SUM(chars), SUM(failed), SUM(latency_ms)
+		FROM usage WHERE at >= ?
+		GROUP BY backend, model, kind, purpose
+		ORDER BY SUM(tokens) DESC, SUM(requests) DESC
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("usage report: %w", err)
+	}
+	defer rows.Close()
+
+	var report []usage.Row
+	for rows.Next() {
+		var r usage.Row
+		var kind, purpose string
+		var latencyMS int64
+		if err := rows.Scan(&r.Backend, &r.Model, &kind, &purpose,
+			&r.Requests, &r.Tokens, &r.Chars, &r.Failures, &latencyMS); err != nil {
+			return nil, err
+		}
+		r.Kind = usage.Kind(kind)
+		r.Purpose = usage.Purpose(purpose)
+		r.Latency = time.Duration(latencyMS) * time.Millisecond
+		report = append(report, r)
+	}
+	return report, rows.Err()
+}
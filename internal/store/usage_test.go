@@ -0,0 +1,54 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/usage"
+)
+
+func TestUsageTotalsAndReport(t *testing.T) {
+	st, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	records := []usage.Record{
+		{Time: now.Add(-48 * time.Hour), Backend: "jina", Model: "v3", Kind: usage.KindEmbed, Purpose: usage.PurposeBackground, Requests: 1, Tokens: 1000},
+		{Time: now, Backend: "jina", Model: "v3", Kind: usage.KindEmbed, Purpose: usage.PurposeBackground, Requests: 1, Tokens: 300, Chars: 1200, Latency: 200 * time.Millisecond},
+		{Time: now, Backend: "jina", Model: "v3", Kind: usage.KindEmbed, Purpose: usage.PurposeBackground, Requests: 1, Tokens: 200, Chars: 800, Latency: 100 * time.Millisecond, Failed: true},
+		{Time: now, Backend: "jina", Model: "reranker", Kind: usage.KindRerank, Purpose: usage.PurposeInteractive, Requests: 1, Tokens: 50},
+	}
+	for _, r := range records {
+		if err := st.RecordUsage(r); err != nil {
+			t.Fatalf("RecordUsage: %v", err)
+		}
+	}
+
+	totals, err := st.UsageTotals(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("UsageTotals: %v", err)
+	}
+	if totals.Requests != 3 || totals.Tokens != 550 || totals.Chars != 2000 || totals.Failures != 1 {
+		t.Errorf("unexpected totals: %+v", totals)
+	}
+	if totals.Latency != 300*time.Millisecond {
+		t.Errorf("expected 300ms summed latency, got %v", totals.Latency)
+	}
+
+	report, err := st.UsageReport(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("UsageReport: %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("expected 2 report rows, got %d: %+v", len(report), report)
+	}
+	if report[0].Model != "v3" || report[0].Tokens != 500 || report[0].Requests != 2 {
+		t.Errorf("expected heaviest model first, got %+v", report[0])
+	}
+	if report[1].Kind != usage.KindRerank || report[1].Purpose != usage.PurposeInteractive {
+		t.Errorf("unexpected second row: %+v", report[1])
+	}
+}
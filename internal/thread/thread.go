@@ -0,0 +1,287 @@
+// Package thread follows developing stories across days: as items are
+// embedded, each is linked to the active story thread it's most similar
+// to, or starts one. Similarity is the cosine to the thread's centroid,
+// supported by shared title words and named entities.
+package thread
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abelbrown/observer/internal/embed"
+	"github.com/abelbrown/observer/internal/store"
+)
+
+const (
+	// LinkSimilarity is the cosine to a thread's centroid at which an
+	// item joins it on its vector alone.
+	LinkSimilarity = 0.82
+	// SupportedSimilarity is the lower cosine at which an item joins a
+	// thread whose title words or named entities it shares.
+	SupportedSimilarity = 0.70
+	// ActiveWindow is how long after its latest report a thread still
+	// takes new items.
+	ActiveWindow = 7 * 24 * time.Hour
+	// BatchSize is how many items LinkPending links per call.
+	BatchSize = 200
+)
+
+const (
+	supportOverlap = 0.5 // share of an item's words or entities the thread must have
+	overlapWeight  = 0.1 // how much each overlap adds when ranking candidate threads
+	maxTerms       = 40  // title words and entities kept per thread, most frequent
+)
+
+// LinkPending links up to limit items embedded by model that aren't in
+// a thread yet, oldest first, and returns how many it linked.
+func LinkPending(ctx context.Context, st *store.Store, model string, limit int) (int, error) {
+	items, err := st.GetItemsNeedingThread(model, limit)
+	if err != nil || len(items) == 0 {
+		return 0, err
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	embeddings, err := st.GetModelEmbeddings(ids, model)
+	if err != nil {
+		return 0, err
+	}
+	threads, err := st.ActiveThreads(model, items[0].Published.Add(-ActiveWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		emb := embeddings[item.ID]
+		if len(emb) == 0 {
+			continue
+		}
+		k, sim := Match(threads, item, emb)
+		var t store.Thread
+		if k < 0 {
+			t, sim = New(item, model, emb), 1
+		} else {
+			t = clone(threads[k])
+			Add(&t, item, emb)
+		}
+		t, ok, err := st.LinkThreadItem(t, item.ID, sim)
+		if err != nil {
+			return linked, err
+		}
+		if !ok {
+			continue // linked meanwhile; leave the snapshot as it was
+		}
+		if k < 0 {
+			threads = append(threads, t)
+		} else {
+			threads[k] = t
+		}
+		linked++
+	}
+	return linked, nil
+}
+
+// Match returns the index of the thread item belongs to, with the cosine
+// between emb and its centroid, or -1 if it starts a new story. Among
+// the threads it qualifies for, the one with the highest cosine plus
+// word and entity overlap wins.
+func Match(threads []store.Thread, item store.Item, emb []float32) (int, float32) {
+	terms, entities := features(item)
+	best, bestScore, bestSim := -1, float32(0), float32(0)
+	for i, t := range threads {
+		if !active(t, item.Published) || len(t.Centroid) != len(emb) {
+			continue
+		}
+		sim := embed.CosineSimilarity(emb, t.Centroid)
+		if sim < SupportedSimilarity {
+			continue
+		}
+		termOverlap := overlap(terms, t.Terms)
+		entityOverlap := overlap(entities, t.Entities)
+		if sim < LinkSimilarity && termOverlap < supportOverlap && entityOverlap < supportOverlap {
+			continue
+		}
+		score := sim + overlapWeight*(termOverlap+entityOverlap)
+		if best < 0 || score > bestScore {
+			best, bestScore, bestSim = i, score, sim
+		}
+	}
+	return best, bestSim
+}
+
+// New starts a thread with item as its first report.
+func New(item store.Item, model string, emb []float32) store.Thread {
+	t := store.Thread{
+		Title:    item.Title,
+		Model:    model,
+		Centroid: make([]float32, len(emb)),
+		Terms:    make(map[string]int),
+		Entities: make(map[string]int),
+		First:    item.Published,
+		Latest:   item.Published,
+	}
+	Add(&t, item, emb)
+	return t
+}
+
+// Add folds item into t: the centroid moves to the mean of its items'
+// vectors, and the item's words, entities and publish time are counted.
+// A report earlier than the first gives the thread its title.
+func Add(t *store.Thread, item store.Item, emb []float32) {
+	n := float32(t.Items)
+	for i, v := range emb {
+		t.Centroid[i] = (t.Centroid[i]*n + v) / (n + 1)
+	}
+	t.Items++
+
+	terms, entities := features(item)
+	if t.Terms == nil {
+		t.Terms = make(map[string]int)
+	}
+	if t.Entities == nil {
+		t.Entities = make(map[string]int)
+	}
+	for _, w := range terms {
+		t.Terms[w]++
+	}
+	for _, e := range entities {
+		t.Entities[e]++
+	}
+	trim(t.Terms)
+	trim(t.Entities)
+
+	if item.Published.Before(t.First) {
+		t.First, t.Title = item.Published, item.Title
+	}
+	if item.Published.After(t.Latest) {
+		t.Latest = item.Published
+	}
+}
+
+// clone returns a copy of t that Add can change without touching t.
+func clone(t store.Thread) store.Thread {
+	t.Centroid = slices.Clone(t.Centroid)
+	t.Terms, t.Entities = maps.Clone(t.Terms), maps.Clone(t.Entities)
+	return t
+}
+
+// active reports whether t takes an item published at published: within
+// ActiveWindow of its reports.
+func active(t store.Thread, published time.Time) bool {
+	return published.After(t.First.Add(-ActiveWindow)) && published.Before(t.Latest.Add(ActiveWindow))
+}
+
+// overlap returns the share of words found in counts.
+func overlap(words []string, counts map[string]int) float32 {
+	if len(words) == 0 {
+		return 0
+	}
+	n := 0
+	for _, w := range words {
+		if counts[w] > 0 {
+			n++
+		}
+	}
+	return float32(n) / float32(len(words))
+}
+
+// trim keeps the maxTerms most frequent entries of counts.
+func trim(counts map[string]int) {
+	if len(counts) <= maxTerms {
+		return
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys[maxTerms:] {
+		delete(counts, k)
+	}
+}
+
+// features returns item's distinct title words and the named entities in
+// its title and summary, lowercased. Entities are capitalized words that
+// don't start a sentence, and all-caps acronyms anywhere: "Fed", "NASA".
+func features(item store.Item) (terms, entities []string) {
+	seen := make(map[string]bool)
+	for _, w := range words(item.Title) {
+		w = strings.ToLower(w)
+		if len(w) > 2 && !stopwords[w] && !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+
+	seen = make(map[string]bool)
+	for _, text := range []string{item.Title, item.Summary} {
+		start := true
+		for _, field := range strings.Fields(text) {
+			w := strings.TrimFunc(field, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			if w != "" && (!start || isAcronym(w)) && isCapitalized(w) {
+				if e := strings.ToLower(w); !stopwords[e] && !seen[e] {
+					seen[e] = true
+					entities = append(entities, e)
+				}
+			}
+			start = strings.ContainsAny(field[len(field)-1:], ".!?:")
+		}
+	}
+	return terms, entities
+}
+
+// words splits s on anything but letters and digits.
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func isCapitalized(w string) bool {
+	for _, r := range w {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+func isAcronym(w string) bool {
+	if len(w) < 2 {
+		return false
+	}
+	for _, r := range w {
+		if !unicode.IsUpper(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// stopwords carry no story.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "from": true, "has": true,
+	"have": true, "he": true, "her": true, "his": true, "how": true, "i": true,
+	"in": true, "into": true, "is": true, "it": true, "its": true, "new": true,
+	"not": true, "of": true, "on": true, "or": true, "over": true, "she": true,
+	"says": true, "that": true, "the": true, "their": true, "they": true,
+	"this": true, "to": true, "up": true, "was": true, "we": true, "were": true,
+	"what": true, "when": true, "who": true, "why": true, "will": true,
+	"with": true, "after": true, "about": true, "you": true,
+}
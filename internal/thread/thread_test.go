@@ -0,0 +1,113 @@
+package thread
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+func TestMatch(t *testing.T) {
+	now := time.Now()
+	quake := New(store.Item{Title: "Earthquake strikes Chile coast", Published: now.Add(-24 * time.Hour)}, "m", []float32{1, 0, 0})
+	cup := New(store.Item{Title: "Argentina wins Cup final", Published: now.Add(-time.Hour)}, "m", []float32{0, 1, 0})
+	threads := []store.Thread{quake, cup}
+
+	tests := []struct {
+		name string
+		item store.Item
+		emb  []float32
+		want int
+	}{
+		{"close vector", store.Item{Title: "Rescue efforts continue", Published: now}, []float32{0.95, 0.1, 0.1}, 0},
+		{"weaker vector, shared words", store.Item{Title: "Chile earthquake toll rises", Published: now}, []float32{0.75, 0.5, 0.3}, 0},
+		{"weaker vector alone", store.Item{Title: "Markets steady", Published: now}, []float32{0.75, 0.5, 0.3}, -1},
+		{"unrelated", store.Item{Title: "Chile earthquake toll rises", Published: now}, []float32{0, 0, 1}, -1},
+		{"story gone quiet", store.Item{Title: "Earthquake strikes Chile coast", Published: now.Add(9 * 24 * time.Hour)}, []float32{1, 0, 0}, -1},
+		{"other model's space", store.Item{Title: "Rescue efforts continue", Published: now}, []float32{1, 0}, -1},
+	}
+	for _, tt := range tests {
+		if got, _ := Match(threads, tt.item, tt.emb); got != tt.want {
+			t.Errorf("%s: Match = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	now := time.Now()
+	th := New(store.Item{Title: "Quake aftershocks", Published: now}, "m", []float32{1, 0})
+	Add(&th, store.Item{Title: "Quake hits coast", Published: now.Add(-time.Hour)}, []float32{0, 1})
+
+	if th.Items != 2 || th.Centroid[0] != 0.5 || th.Centroid[1] != 0.5 {
+		t.Errorf("centroid %v after %d items, want the mean", th.Centroid, th.Items)
+	}
+	if th.Title != "Quake hits coast" || !th.First.Equal(now.Add(-time.Hour)) || !th.Latest.Equal(now) {
+		t.Errorf("an earlier report should give the title: %q %v–%v", th.Title, th.First, th.Latest)
+	}
+	if th.Terms["quake"] != 2 || th.Terms["coast"] != 1 {
+		t.Errorf("terms = %v", th.Terms)
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	terms, entities := features(store.Item{
+		Title:   "The Fed holds rates as NASA delays launch",
+		Summary: "Officials in Washington said. Markets rallied on the Fed decision.",
+	})
+	if want := []string{"fed", "holds", "rates", "nasa", "delays", "launch"}; !reflect.DeepEqual(terms, want) {
+		t.Errorf("terms = %v, want %v", terms, want)
+	}
+	// "The", "Officials" and "Markets" start sentences.
+	if want := []string{"fed", "nasa", "washington"}; !reflect.DeepEqual(entities, want) {
+		t.Errorf("entities = %v, want %v", entities, want)
+	}
+}
+
+func TestLinkPending(t *testing.T) {
+	st, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	items := []store.Item{
+		{ID: "a", Title: "Quake hits Chile", URL: "https://a", Published: now.Add(-48 * time.Hour)},
+		{ID: "b", Title: "Chile quake toll rises", URL: "https://b", Published: now.Add(-24 * time.Hour)},
+		{ID: "c", Title: "Cup final tonight", URL: "https://c", Published: now.Add(-2 * time.Hour)},
+		{ID: "d", Title: "Chile rebuilds after quake", URL: "https://d", Published: now},
+	}
+	vectors := map[string][]float32{"a": {1, 0}, "b": {0.9, 0.1}, "c": {0, 1}, "d": {0.95, 0.05}}
+	if _, err := st.SaveItems(items); err != nil {
+		t.Fatalf("SaveItems: %v", err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := st.SaveModelEmbedding(id, "m", vectors[id]); err != nil {
+			t.Fatalf("SaveModelEmbedding: %v", err)
+		}
+	}
+
+	if n, err := LinkPending(context.Background(), st, "m", BatchSize); err != nil || n != 3 {
+		t.Fatalf("LinkPending = %d, %v; want 3", n, err)
+	}
+	// d is linked incrementally once embedded.
+	if err := st.SaveModelEmbedding("d", "m", vectors["d"]); err != nil {
+		t.Fatalf("SaveModelEmbedding: %v", err)
+	}
+	if n, err := LinkPending(context.Background(), st, "m", BatchSize); err != nil || n != 1 {
+		t.Fatalf("LinkPending = %d, %v; want 1", n, err)
+	}
+
+	thread, members, err := st.ItemThread("d")
+	if err != nil || thread == nil {
+		t.Fatalf("ItemThread: %v, %v", thread, err)
+	}
+	if thread.Title != "Quake hits Chile" || thread.Items != 3 || len(members) != 3 || members[0].ID != "a" || members[2].ID != "d" {
+		t.Errorf("thread %+v, members %v", thread, members)
+	}
+	if cup, _, _ := st.ItemThread("c"); cup == nil || cup.ID == thread.ID || cup.Items != 1 {
+		t.Errorf("unrelated story should have its own thread: %+v", cup)
+	}
+}
@@ -0,0 +1,95 @@
+package thread
+
+import (
+	"sort"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+// Trend is a story thread many sources took up at once.
+type Trend struct {
+	Thread  store.Thread
+	Window  time.Duration
+	Reports []store.Item // every report since the baseline began, oldest first
+	Recent  int          // reports in the window
+	Sources []string     // distinct sources reporting in the window, in order
+
+	// Baseline is the reports the thread usually gets in a window: its
+	// reports over the baseline before it, scaled to the window's length.
+	// Velocity is the window's sources over that, smoothed by adding one
+	// to both so a thread with next to no history doesn't look infinitely
+	// fast: (sources+1)/(Baseline+1).
+	Baseline float64
+	Velocity float64
+}
+
+// Detector flags bursts: threads covered by at least MinSources sources
+// within Window, at Burst times their usual rate over the Baseline
+// before it. Counting sources rather than reports keeps one prolific
+// feed from making a story trend.
+type Detector struct {
+	Window     time.Duration
+	Baseline   time.Duration
+	MinSources int
+	Burst      float64
+}
+
+// DefaultDetector looks for stories three sources took up in the last
+// hour, three times faster than over the day before.
+var DefaultDetector = Detector{
+	Window:     time.Hour,
+	Baseline:   24 * time.Hour,
+	MinSources: 3,
+	Burst:      3,
+}
+
+// Since returns the earliest publish time Detect looks at: reports before
+// it are irrelevant at now.
+func (d Detector) Since(now time.Time) time.Time {
+	return now.Add(-d.Window - d.Baseline)
+}
+
+// Detect returns the trending threads at now, fastest first, given the
+// threads' reports since d.Since(now) (see store.ThreadItemsSince).
+func (d Detector) Detect(threads []store.Thread, reports map[int64][]store.Item, now time.Time) []Trend {
+	since, windowStart := d.Since(now), now.Add(-d.Window)
+	perWindow := float64(d.Window) / float64(d.Baseline)
+
+	var trends []Trend
+	for _, t := range threads {
+		tr := Trend{Thread: t, Window: d.Window}
+		before := 0
+		seen := make(map[string]bool)
+		for _, item := range reports[t.ID] {
+			if item.Published.Before(since) || item.Published.After(now) {
+				continue
+			}
+			tr.Reports = append(tr.Reports, item)
+			if item.Published.Before(windowStart) {
+				before++
+				continue
+			}
+			tr.Recent++
+			if !seen[item.SourceName] {
+				seen[item.SourceName] = true
+				tr.Sources = append(tr.Sources, item.SourceName)
+			}
+		}
+		if len(tr.Sources) < d.MinSources {
+			continue
+		}
+		tr.Baseline = float64(before) * perWindow
+		tr.Velocity = float64(len(tr.Sources)+1) / (tr.Baseline + 1)
+		if tr.Velocity >= d.Burst {
+			trends = append(trends, tr)
+		}
+	}
+	sort.SliceStable(trends, func(i, j int) bool {
+		if trends[i].Velocity != trends[j].Velocity {
+			return trends[i].Velocity > trends[j].Velocity
+		}
+		return trends[i].Recent > trends[j].Recent
+	})
+	return trends
+}
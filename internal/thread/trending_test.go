@@ -0,0 +1,85 @@
+package thread
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+)
+
+func TestDetect(t *testing.T) {
+	now := time.Now()
+	report := func(source string, ago time.Duration) store.Item {
+		return store.Item{ID: source + ago.String(), SourceName: source, Published: now.Add(-ago)}
+	}
+	threads := []store.Thread{{ID: 1, Title: "Breaking: dam fails"}, {ID: 2, Title: "Budget talks"}, {ID: 3, Title: "One feed"}, {ID: 4, Title: "Quake"}}
+	reports := map[int64][]store.Item{
+		// Four sources in the last hour, nothing before: a burst.
+		1: {report("AP", 50*time.Minute), report("BBC", 40*time.Minute), report("CNN", 20*time.Minute), report("NPR", 5*time.Minute)},
+		// Covered all day by the same few sources: no burst.
+		2: func() []store.Item {
+			var items []store.Item
+			for h := 24; h > 0; h-- {
+				for _, s := range []string{"AP", "BBC", "CNN"} {
+					items = append(items, report(s, time.Duration(h)*time.Hour-time.Minute))
+				}
+			}
+			return items
+		}(),
+		// Many reports from one source aren't independent coverage.
+		3: {report("Blog", 50*time.Minute), report("Blog", 30*time.Minute), report("Blog", 10*time.Minute), report("Blog", 5*time.Minute)},
+		// Three sources in the hour after a quiet day: a smaller burst.
+		4: {report("AP", 20*time.Hour), report("AP", 30*time.Minute), report("BBC", 20*time.Minute), report("CNN", 10*time.Minute)},
+	}
+
+	trends := DefaultDetector.Detect(threads, reports, now)
+	if len(trends) != 2 || trends[0].Thread.ID != 1 || trends[1].Thread.ID != 4 {
+		t.Fatalf("trends = %+v, want threads 1 and 4", trends)
+	}
+	if tr := trends[0]; tr.Recent != 4 || len(tr.Sources) != 4 || tr.Baseline != 0 || tr.Velocity != 5 {
+		t.Errorf("trend 1 = recent %d, sources %v, baseline %v, velocity %v", tr.Recent, tr.Sources, tr.Baseline, tr.Velocity)
+	}
+	if tr := trends[1]; len(tr.Reports) != 4 || tr.Recent != 3 {
+		t.Errorf("trend 4 = %d reports, %d recent", len(tr.Reports), tr.Recent)
+	}
+
+	// A wider window takes in the budget talks' whole day: still steady.
+	wide := DefaultDetector
+	wide.Window = 6 * time.Hour
+	for _, tr := range wide.Detect(threads, reports, now) {
+		if tr.Thread.ID == 2 || tr.Thread.ID == 3 {
+			t.Errorf("thread %d trending over 6h", tr.Thread.ID)
+		}
+	}
+}
+
+func TestDetectSteadyThread(t *testing.T) {
+	now := time.Now()
+	threads := []store.Thread{{ID: 1, Title: "Budget talks"}}
+
+	for _, window := range []time.Duration{time.Hour, 3 * time.Hour} {
+		d := DefaultDetector
+		d.Window = window
+		// Three sources an hour, every hour, for as long as d looks.
+		var reports []store.Item
+		for ago := 5 * time.Minute; ago < window+d.Baseline; ago += 20 * time.Minute {
+			source := []string{"AP", "BBC", "CNN"}[int(ago/(20*time.Minute))%3]
+			reports = append(reports, store.Item{ID: ago.String(), SourceName: source, Published: now.Add(-ago)})
+		}
+		byThread := map[int64][]store.Item{1: reports}
+
+		if trends := d.Detect(threads, byThread, now); len(trends) != 0 {
+			t.Errorf("%v window: steady thread trending at %.2f× (baseline %.2f)", window, trends[0].Velocity, trends[0].Baseline)
+		}
+
+		// With no bar to clear, the velocity shows how far from a burst it is.
+		d.Burst = 0
+		trends := d.Detect(threads, byThread, now)
+		if len(trends) != 1 {
+			t.Fatalf("%v window: %d trends with no burst required", window, len(trends))
+		}
+		if tr := trends[0]; tr.Baseline != float64(tr.Recent) || tr.Velocity > 1 {
+			t.Errorf("%v window: baseline %.2f for %d recent reports, velocity %.2f", window, tr.Baseline, tr.Recent, tr.Velocity)
+		}
+	}
+}
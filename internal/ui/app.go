@@ -4,12 +4,14 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/interest"
 	"github.com/abelbrown/observer/internal/otel"
 	"github.com/abelbrown/observer/internal/store"
 	"github.com/abelbrown/observer/internal/ui/media"
@@ -37,6 +39,7 @@ const (
 	ModeHistory                // browsing search history (future)
 	ModeArticle                // reading full article (future)
 	ModeMedia                  // "Engineered" cyber-noir view
+	ModeRule                   // composing a rule for the selected item
 )
 
 // App is the root Bubble Tea model.
@@ -51,17 +54,89 @@ type App struct {
 	scoreEntry      func(ctx context.Context, query string, doc string, itemID string, queryID string) tea.Cmd // Ollama per-entry path (not wired in production; Jina batch path used instead)
 	batchRerank     func(ctx context.Context, query string, docs []string, queryID string) tea.Cmd             // Jina batch rerank — single API call for all docs
 	searchFTS       func(query string, limit int) ([]store.Item, error)                                        // FTS5 instant search
-
-	items       []store.Item
-	embeddings  map[string][]float32 // item ID -> embedding
-	cursor      int
-	err         error
-	width       int
-	height      int
-	ready       bool
-	loading     bool
-	statusText  string    // activity status for status bar; empty = no activity
-	searchStart time.Time // when current search was initiated
+	muteSource      func(source string) tea.Cmd                                                                // persist a source mute
+	prioritizeEmbed func(priority int, ids []string) tea.Cmd                                                   // bump embedding priority for items lacking vectors
+
+	// batch rerank over item fields and vectors (local reranker); preferred over batchRerank
+	rerankItems func(ctx context.Context, query string, queryEmb []float32, items []store.Item, embs [][]float32, queryID string) tea.Cmd
+
+	// rerank score cache (nil: disabled)
+	cachedRerankScores func(query string, items []store.Item) map[string]float32
+	saveRerankScores   func(query, reranker string, items []store.Item, scores []float32) tea.Cmd
+
+	// Relevance cut-off ("h" in results): results scored below
+	// minRelevance are moved from items to belowCut. Relevance is the
+	// rerank score, else the calibrated cosine similarity.
+	calibrateCosine func(model string, similarity float32) (float32, bool)
+	rerankRelevance map[string]float32 // item ID -> rerank score, current query
+	cosineRelevance map[string]float32 // item ID -> calibrated cosine, current query
+	minRelevance    float32
+	belowCut        []store.Item
+
+	// Rules: "u" composes one from the selected item (ModeRule), "U"
+	// opens the panel listing them.
+	loadRules    func() tea.Cmd
+	addRule      func(rule store.Rule) tea.Cmd
+	deleteRule   func(id int64) tea.Cmd
+	ruleInput    textinput.Model
+	ruleDraft    store.Rule
+	ruleItem     store.Item // the item the draft was started from
+	rules        []store.Rule
+	ruleCursor   int
+	rulesVisible bool
+
+	// What the filter pipeline dropped from the feed, and why; "H"
+	// shows it dimmed in place.
+	hidden     []filter.Decision
+	showHidden bool
+
+	// Near-duplicates semantic dedup folded into each kept item; "e"
+	// expands them below it.
+	similar  map[string][]filter.Similar
+	expanded map[string]bool
+
+	// Trending stories: their newest report is listed under Rising, every
+	// report badged.
+	rising      map[string]bool
+	trendBadges map[string]string
+
+	// Story thread of the selected item; "T" opens the panel.
+	loadThread    func(itemID string) tea.Cmd
+	thread        *store.Thread
+	threadItems   []store.Item
+	threadItemID  string // the item the panel was opened on
+	threadLoaded  bool
+	threadScroll  int
+	threadVisible bool
+
+	// Interest profile learned from reads, saves, more-like-this seeds and
+	// exclusions ("-"); "I" opens the panel listing every item it learned
+	// from, and interestOrder orders each time band by it.
+	loadInterest    func() tea.Cmd
+	recordSignal    func(itemID, kind string) tea.Cmd
+	forgetSignal    func(itemID, kind string) tea.Cmd
+	resetInterest   func() tea.Cmd
+	markSaved       func(id string, saved bool) tea.Cmd
+	interest        interest.Profile
+	interestLoaded  bool
+	interestScores  map[string]float64 // item ID -> interest score, current feed
+	interestOrder   bool
+	interestCursor  int
+	interestConfirm bool // "R" pressed once; pressing it again resets
+	interestVisible bool
+
+	items          []store.Item
+	embeddings     map[string][]float32     // item ID -> embedding
+	embeddingModel string                   // model of the vectors in embeddings
+	chunks         map[string][]store.Chunk // item ID -> passage vectors of long items
+	cursor         int
+	err            error
+	width          int
+	height         int
+	ready          bool
+	loading        bool
+	statusText     string    // activity status for status bar; empty = no activity
+	searchStart    time.Time // when current search was initiated
 
 	// Media View (ModeMedia)
 	mediaView media.MainModel
@@ -78,18 +153,28 @@ type App struct {
 	mltSeedTitle string // cached seed title for render
 
 	// Full-history search: save/restore chronological view
-	savedItems      []store.Item         // chronological items saved before search
-	savedEmbeddings map[string][]float32 // embeddings saved before search
+	savedItems          []store.Item         // chronological items saved before search
+	savedEmbeddings     map[string][]float32 // embeddings saved before search
+	savedEmbeddingModel string
+	savedChunks         map[string][]store.Chunk
+	savedSimilar        map[string][]filter.Similar // the feed's clusters; search has its own
+	savedExpanded       map[string]bool
 
 	// Search pool loading
 	searchPoolPending bool                 // true while loading search pool from DB
 	poolItems         []store.Item         // buffered pool; merged into items when embedding arrives
 	poolEmbeddings    map[string][]float32 // buffered pool embeddings
+	poolModel         string               // model of poolEmbeddings
+	poolChunks        map[string][]store.Chunk
+	poolSimilar       map[string][]filter.Similar
 
 	// Query state
-	queryEmbedding    []float32 // current query's embedding
-	embeddingPending  bool      // true while waiting for query embedding
-	lastEmbeddedQuery string    // the query that was last embedded
+	queryEmbedding    []float32         // current query's embedding
+	queryModel        string            // model that produced queryEmbedding
+	queryChunks       [][]float32       // extra query vectors: the MLT seed's passages
+	passages          map[string]string // item ID -> passage that best matched the query
+	embeddingPending  bool              // true while waiting for query embedding
+	lastEmbeddedQuery string            // the query that was last embedded
 
 	// Search correlation
 	queryID string // current search correlation ID; empty when no search active
@@ -101,12 +186,16 @@ type App struct {
 	// Rerank policy
 	autoReranks bool
 
+	// AI backends in use (status bar, debug overlay)
+	backend BackendStatus
+
 	// Rerank progress (package-manager style)
 	rerankPending  bool         // true during reranking
 	rerankEntries  []store.Item // entries being reranked
 	rerankScores   []float32    // scores per entry
 	rerankProgress int          // entries scored so far
 	rerankQuery    string       // the query that started the current rerank
+	rerankSent     []int        // rerankEntries positions sent to the batch reranker (the rest were cached)
 
 	// UI components
 	spinner spinner.Model
@@ -124,6 +213,9 @@ type App struct {
 
 	// Animation
 	shimmerOffset int
+
+	// Embedding priority: first item ID of the window last bumped
+	bumpedWindow string
 }
 
 // ObsConfig groups observability dependencies to prevent AppConfig god-object growth.
@@ -143,10 +235,56 @@ type AppConfig struct {
 	ScoreEntry      func(ctx context.Context, query string, doc string, itemID string, queryID string) tea.Cmd
 	BatchRerank     func(ctx context.Context, query string, docs []string, queryID string) tea.Cmd
 	SearchFTS       func(query string, limit int) ([]store.Item, error)
+	MuteSource      func(source string) tea.Cmd
 	Embeddings      map[string][]float32
 	Obs             ObsConfig
 	AutoReranks     bool
 	Features        Features
+	Backend         BackendStatus
+
+	// PrioritizeEmbedding asks the embedding worker to embed ids next.
+	// priority is store.EmbedPriorityVisible or store.EmbedPrioritySearch.
+	PrioritizeEmbedding func(priority int, ids []string) tea.Cmd
+
+	// RerankItems is BatchRerank for rerankers that score item fields
+	// and embeddings; preferred over BatchRerank when set. queryEmb and
+	// embs are nil when the query and items were embedded by different
+	// models; embs[i] is nil for items without a vector.
+	RerankItems func(ctx context.Context, query string, queryEmb []float32, items []store.Item, embs [][]float32, queryID string) tea.Cmd
+
+	// CachedRerankScores returns cached scores by item ID for query from
+	// the reranker that would serve the next call. Cached items are not
+	// sent to the reranker. Nil disables the cache.
+	CachedRerankScores func(query string, items []store.Item) map[string]float32
+	// SaveRerankScores caches scores[i] for items[i], as scored by reranker.
+	SaveRerankScores func(query, reranker string, items []store.Item, scores []float32) tea.Cmd
+
+	// CalibrateCosine maps a cosine similarity between vectors from model
+	// to a probability of relevance, comparable with calibrated rerank
+	// scores; ok is false when model has no calibration. Nil: cosine-only
+	// results are never hidden by the relevance cut-off.
+	CalibrateCosine func(model string, similarity float32) (p float32, ok bool)
+
+	// LoadRules, AddRule and DeleteRule manage mute/boost/allow rules;
+	// they return RulesLoaded, RuleAdded and RuleDeleted. Nil LoadRules
+	// disables the rules panel, nil AddRule the composer.
+	LoadRules  func() tea.Cmd
+	AddRule    func(rule store.Rule) tea.Cmd
+	DeleteRule func(id int64) tea.Cmd
+
+	// LoadThread loads the story thread an item belongs to and returns
+	// ThreadLoaded. Nil disables the thread panel.
+	LoadThread func(itemID string) tea.Cmd
+
+	// LoadInterest loads the interest profile and returns InterestLoaded.
+	// RecordSignal, ForgetSignal, ResetInterest and MarkSaved change it
+	// and return InterestChanged. Nil LoadInterest disables the panel,
+	// nil RecordSignal "less like this".
+	LoadInterest  func() tea.Cmd
+	RecordSignal  func(itemID, kind string) tea.Cmd
+	ForgetSignal  func(itemID, kind string) tea.Cmd
+	ResetInterest func() tea.Cmd
+	MarkSaved     func(id string, saved bool) tea.Cmd
 }
 
 // NewApp creates a new App with the given command functions.
@@ -174,6 +312,10 @@ func newApp(cfg AppConfig) App {
 	s.Spinner.FPS = 100 * time.Millisecond
 	s.Style = lipgloss.NewStyle().Foreground(colorSpinner)
 
+	ri := textinput.New()
+	ri.CharLimit = 200
+	ri.Width = 50
+
 	embeddings := cfg.Embeddings
 	if embeddings == nil {
 		embeddings = make(map[string][]float32)
@@ -193,7 +335,10 @@ func newApp(cfg AppConfig) App {
 		embedQuery:      cfg.EmbedQuery,
 		scoreEntry:      cfg.ScoreEntry,
 		batchRerank:     cfg.BatchRerank,
+		rerankItems:     cfg.RerankItems,
 		searchFTS:       cfg.SearchFTS,
+		muteSource:      cfg.MuteSource,
+		prioritizeEmbed: cfg.PrioritizeEmbedding,
 		cursor:          0,
 		filterInput:     ti,
 		embeddings:      embeddings,
@@ -203,10 +348,29 @@ func newApp(cfg AppConfig) App {
 		mode:            ModeList,
 		searchCtx:       context.Background(),
 		autoReranks:     cfg.AutoReranks,
+		backend:         cfg.Backend,
 		features:        cfg.Features,
 		width:           80,
 		height:          24,
 		ready:           true,
+
+		cachedRerankScores: cfg.CachedRerankScores,
+		saveRerankScores:   cfg.SaveRerankScores,
+
+		calibrateCosine: cfg.CalibrateCosine,
+
+		loadRules:  cfg.LoadRules,
+		addRule:    cfg.AddRule,
+		deleteRule: cfg.DeleteRule,
+		ruleInput:  ri,
+
+		loadThread: cfg.LoadThread,
+
+		loadInterest:  cfg.LoadInterest,
+		recordSignal:  cfg.RecordSignal,
+		forgetSignal:  cfg.ForgetSignal,
+		resetInterest: cfg.ResetInterest,
+		markSaved:     cfg.MarkSaved,
 	}
 }
 
@@ -354,11 +518,23 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
+		a.interestScores = msg.Interest
+		if a.interestOrder {
+			msg.Items = byInterestWithinBands(msg.Items, msg.Interest)
+		}
+		msg.Items = boostWithinBands(msg.Items, msg.Boosted)
+		msg.Items, a.rising = withRising(msg.Items, msg.Trends)
+		a.trendBadges = trendBadges(msg.Trends)
+		a.hidden = msg.Hidden
+
 		// If search is active, update savedItems instead of live view
 		if a.savedItems != nil {
-			a.savedItems = msg.Items
+			a.savedSimilar = msg.Similar
+			a.savedItems = expandClusters(msg.Items, msg.Similar, a.savedExpanded)
 			if msg.Embeddings != nil {
 				a.savedEmbeddings = msg.Embeddings
+				a.savedEmbeddingModel = msg.EmbeddingModel
+				a.savedChunks = msg.Chunks
 			}
 			// Still chain Stage 2 if needed
 			if !a.fullLoaded && a.loadItems != nil {
@@ -368,6 +544,9 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
+		a.similar = msg.Similar
+		msg.Items = expandClusters(msg.Items, a.similar, a.expanded)
+
 		// Cursor stability: record current item ID
 		cursorID := ""
 		if a.cursor < len(a.items) && len(a.items) > 0 {
@@ -378,6 +557,8 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.err = nil
 		if msg.Embeddings != nil {
 			a.embeddings = msg.Embeddings
+			a.embeddingModel = msg.EmbeddingModel
+			a.chunks = msg.Chunks
 		}
 
 		// Restore cursor by ID (not index)
@@ -396,12 +577,15 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.rerankItemsByEmbedding()
 		}
 
+		a.bumpedWindow = ""
+		bump := a.bumpVisible()
+
 		// Chain Stage 2: load full corpus after first paint
 		if !a.fullLoaded && a.loadItems != nil {
 			a.fullLoaded = true
-			return a, a.loadItems()
+			return a, tea.Batch(a.loadItems(), bump)
 		}
-		return a, nil
+		return a, bump
 
 	case QueryEmbedded:
 		if !a.embeddingPending {
@@ -418,19 +602,28 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.err = msg.Err
 			a.poolItems = nil
 			a.poolEmbeddings = nil
+			a.poolChunks = nil
+			a.poolSimilar = nil
 			a.statusText = fmt.Sprintf("Search failed: %v", msg.Err)
 			return a, nil
 		}
 		if msg.Query == a.activeQuery {
 			a.queryEmbedding = msg.Embedding
+			a.queryModel = msg.Model
+			a.queryChunks = nil
 			a.logger.Emit(otel.Event{Kind: otel.KindQueryEmbed, Level: otel.LevelInfo, Comp: "ui", Dur: time.Since(a.searchStart), Dims: len(msg.Embedding), Query: msg.Query})
 			a.lastEmbeddedQuery = msg.Query
 			// Merge buffered pool if it arrived while we were waiting for embedding
 			if a.poolItems != nil {
 				a.items = a.poolItems
 				a.embeddings = a.poolEmbeddings
+				a.embeddingModel = a.poolModel
+				a.chunks = a.poolChunks
+				a.similar = a.poolSimilar
 				a.poolItems = nil
 				a.poolEmbeddings = nil
+				a.poolChunks = nil
+				a.poolSimilar = nil
 			}
 			// Always apply fast cosine reranking for immediate feedback
 			a.rerankItemsByEmbedding()
@@ -474,34 +667,49 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.statusText = ""
 		}
 		a.logger.Emit(otel.Event{Kind: otel.KindSearchPool, Level: otel.LevelInfo, Comp: "ui", Dur: time.Since(a.searchStart), Count: len(msg.Items), Query: a.activeQuery, Extra: map[string]any{"embeddings": len(msg.Embeddings)}})
+		// Pool items without vectors are invisible to semantic ranking;
+		// ask the worker to embed them ahead of the backfill.
+		bump := a.bumpEmbedding(store.EmbedPrioritySearch, msg.Items, msg.Embeddings)
 		// If query embedding already arrived, merge pool into live view and rank.
 		// Otherwise, buffer pool — keep showing FTS results until embedding arrives.
 		if len(a.queryEmbedding) > 0 {
 			a.items = msg.Items
 			a.embeddings = msg.Embeddings
+			a.embeddingModel = msg.EmbeddingModel
+			a.chunks = msg.Chunks
+			a.similar = msg.Similar
 			a.poolItems = nil
 			a.poolEmbeddings = nil
+			a.poolChunks = nil
+			a.poolSimilar = nil
 			a.rerankItemsByEmbedding()
 			if a.mltSeedID != "" {
 				a.excludeItem(a.mltSeedID)
 			}
 			if a.autoReranks && a.rerankerAvailable() {
-				return a.startReranking(a.activeQuery)
+				m, cmd := a.startReranking(a.activeQuery)
+				return m, tea.Batch(cmd, bump)
 			}
 			a.statusText = a.cosineCompleteHint()
 		} else if a.embeddingPending {
 			// Embedding in flight — buffer pool until it arrives
 			a.poolItems = msg.Items
 			a.poolEmbeddings = msg.Embeddings
+			a.poolModel = msg.EmbeddingModel
+			a.poolChunks = msg.Chunks
+			a.poolSimilar = msg.Similar
 			a.statusText = a.searchStage()
 		} else {
 			// No embedding coming (no AI backend or embed already failed).
 			// Show pool as-is — FTS results are already visible, this is the full corpus.
 			a.items = msg.Items
 			a.embeddings = msg.Embeddings
+			a.embeddingModel = msg.EmbeddingModel
+			a.chunks = msg.Chunks
+			a.similar = msg.Similar
 			a.statusText = ""
 		}
-		return a, nil
+		return a, bump
 
 	case EntryReranked:
 		return a.handleEntryReranked(msg)
@@ -532,20 +740,35 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		a.statusText = ""
 		a.logger.Emit(otel.Event{Kind: otel.KindSearchComplete, Level: otel.LevelInfo, Comp: "ui", Dur: time.Since(a.searchStart), Query: a.activeQuery})
+		var save tea.Cmd
 		if len(msg.Scores) > 0 {
+			sent := make([]store.Item, 0, len(msg.Scores))
+			scores := make([]float32, 0, len(msg.Scores))
 			for i, score := range msg.Scores {
-				if i < len(a.rerankScores) {
-					a.rerankScores[i] = score
+				j := i
+				if a.rerankSent != nil {
+					if i >= len(a.rerankSent) {
+						break
+					}
+					j = a.rerankSent[i]
+				}
+				if j < len(a.rerankScores) {
+					a.rerankScores[j] = score
+					sent = append(sent, a.rerankEntries[j])
+					scores = append(scores, score)
 				}
 			}
 			a.rerankProgress = len(a.rerankEntries)
 			a.applyScoresAsOrder()
+			if a.saveRerankScores != nil && msg.Reranker != "" {
+				save = a.saveRerankScores(a.rerankQuery, msg.Reranker, sent, scores)
+			}
 		}
 		// D9: clear rerank state after success
 		a.rerankEntries = nil
 		a.rerankScores = nil
 		a.rerankProgress = 0
-		return a, nil
+		return a, save
 
 	case ItemMarkedRead:
 		for i := range a.items {
@@ -565,6 +788,96 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 
+	case SourceMuted:
+		if msg.Err != nil {
+			a.err = msg.Err
+		}
+		return a, nil
+
+	case RulesLoaded:
+		if msg.Err != nil {
+			a.err = msg.Err
+			return a, nil
+		}
+		a.rules = msg.Rules
+		if a.ruleCursor >= len(a.rules) {
+			a.ruleCursor = max(len(a.rules)-1, 0)
+		}
+		return a, nil
+
+	case RuleAdded, RuleDeleted:
+		var err error
+		switch msg := msg.(type) {
+		case RuleAdded:
+			err = msg.Err
+		case RuleDeleted:
+			err = msg.Err
+		}
+		if err != nil {
+			a.err = err
+			return a, nil
+		}
+		// Re-filter the feed with the new rules, and refresh the counts.
+		var cmds []tea.Cmd
+		if a.loadItems != nil {
+			a.loading = true
+			cmds = append(cmds, a.loadItems())
+		}
+		if a.rulesVisible && a.loadRules != nil {
+			cmds = append(cmds, a.loadRules())
+		}
+		return a, tea.Batch(cmds...)
+
+	case ThreadLoaded:
+		if msg.ItemID != a.threadItemID {
+			return a, nil
+		}
+		if msg.Err != nil {
+			a.err = msg.Err
+			a.threadVisible = false
+			return a, nil
+		}
+		a.thread, a.threadItems, a.threadLoaded = msg.Thread, msg.Items, true
+		// Open the timeline a couple of reports above the selected one.
+		for i, item := range msg.Items {
+			if item.ID == a.threadItemID {
+				a.threadScroll = max(i-2, 0)
+			}
+		}
+		return a, nil
+
+	case InterestLoaded:
+		if msg.Err != nil {
+			a.err = msg.Err
+			a.interestVisible = false
+			return a, nil
+		}
+		a.interest, a.interestLoaded = msg.Profile, true
+		if a.interestCursor >= len(msg.Profile.Contributions) {
+			a.interestCursor = max(len(msg.Profile.Contributions)-1, 0)
+		}
+		return a, nil
+
+	case InterestChanged:
+		if msg.Err != nil {
+			a.err = msg.Err
+			return a, nil
+		}
+		// Changes made from the panel: refresh it, and the feed if it's
+		// ordered by the profile.
+		if !a.interestVisible {
+			return a, nil
+		}
+		var cmds []tea.Cmd
+		if a.loadInterest != nil {
+			cmds = append(cmds, a.loadInterest())
+		}
+		if a.interestOrder && a.loadItems != nil {
+			a.loading = true
+			cmds = append(cmds, a.loadItems())
+		}
+		return a, tea.Batch(cmds...)
+
 	case FetchComplete:
 		a.loading = false
 		if msg.Err != nil {
@@ -582,6 +895,10 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, a.loadItems()
 		}
 		return a, nil
+
+	case BackendChanged:
+		a.backend = msg.Status
+		return a, nil
 	}
 
 	return a, nil
@@ -610,7 +927,16 @@ func (a App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 	}
-	if a.mode != ModeSearch {
+	if a.rulesVisible {
+		return a.handleRulesPanelKeys(msg)
+	}
+	if a.threadVisible {
+		return a.handleThreadKeys(msg)
+	}
+	if a.interestVisible {
+		return a.handleInterestKeys(msg)
+	}
+	if a.mode != ModeSearch && a.mode != ModeRule {
 		switch msg.String() {
 		case "q":
 			a.cancelSearch()
@@ -630,6 +956,8 @@ func (a App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a.handleHistoryKeys(msg)
 	case ModeArticle:
 		return a.handleArticleKeys(msg)
+	case ModeRule:
+		return a.handleRuleKeys(msg)
 	default:
 		return a.handleListKeys(msg)
 	}
@@ -694,6 +1022,25 @@ func (a App) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "t":
 		a.alignedList = !a.alignedList
 		return a, nil
+	case "S":
+		return a.handleMuteSource()
+	case "u":
+		return a.openRuleComposer()
+	case "U":
+		return a.toggleRulesPanel()
+	case "T":
+		return a.openThread()
+	case "I":
+		return a.toggleInterestPanel()
+	case "s":
+		return a.toggleSaved()
+	case "-":
+		return a.lessLikeThis()
+	case "H":
+		a.showHidden = !a.showHidden
+		return a, nil
+	case "e":
+		return a.toggleCluster()
 	case "ctrl+r":
 		if a.features.SearchHistory {
 			return a.openHistory()
@@ -769,6 +1116,8 @@ func (a App) handleResultsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if !a.autoReranks && !a.rerankPending && a.activeQuery != "" && a.rerankerAvailable() {
 			return a.startReranking(a.activeQuery)
 		}
+	case "h":
+		return a.cycleRelevanceCut()
 	case "x":
 		if a.features.ScoreColumn {
 			return a, nil
@@ -776,6 +1125,22 @@ func (a App) handleResultsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "t":
 		a.alignedList = !a.alignedList
 		return a, nil
+	case "S":
+		return a.handleMuteSource()
+	case "u":
+		return a.openRuleComposer()
+	case "U":
+		return a.toggleRulesPanel()
+	case "T":
+		return a.openThread()
+	case "I":
+		return a.toggleInterestPanel()
+	case "s":
+		return a.toggleSaved()
+	case "-":
+		return a.lessLikeThis()
+	case "e":
+		return a.toggleCluster()
 	case "ctrl+r":
 		if a.features.SearchHistory {
 			return a.openHistory()
@@ -839,6 +1204,11 @@ func (a *App) ensureSnapshot() {
 	for k, v := range a.embeddings {
 		a.savedEmbeddings[k] = v
 	}
+	a.savedEmbeddingModel = a.embeddingModel
+	a.savedChunks = a.chunks
+	// Results expand their own clusters.
+	a.savedSimilar, a.savedExpanded = a.similar, a.expanded
+	a.expanded = nil
 }
 
 // submitSearch submits the current search query.
@@ -875,6 +1245,7 @@ func (a App) submitSearch() (tea.Model, tea.Cmd) {
 	// Always clear items on search submit — never show stale feed as "results".
 	a.items = nil
 	a.cursor = 0
+	a.resetRelevance()
 	if a.features.FTS5 && a.searchFTS != nil {
 		ftsItems, err := a.searchFTS(query, 50)
 		if err != nil {
@@ -942,6 +1313,8 @@ func (a *App) cancelSearch() {
 	a.searchPoolPending = false
 	a.poolItems = nil
 	a.poolEmbeddings = nil
+	a.poolChunks = nil
+	a.poolSimilar = nil
 	a.rerankEntries = nil
 	a.rerankScores = nil
 	a.rerankProgress = 0
@@ -956,7 +1329,7 @@ func (a *App) newSearchContext() context.Context {
 }
 
 func (a App) rerankerAvailable() bool {
-	return a.batchRerank != nil || a.scoreEntry != nil
+	return a.rerankItems != nil || a.batchRerank != nil || a.scoreEntry != nil
 }
 
 // searchStage returns a human-readable string for the current search pipeline stage.
@@ -1016,9 +1389,15 @@ func (a App) handleMoreLikeThis() (tea.Model, tea.Cmd) {
 	a.filterInput.SetValue("")
 	a.filterInput.Blur()
 	a.queryEmbedding = seedEmb
+	a.queryModel = a.embeddingModel
+	a.queryChunks = nil
+	for _, c := range a.chunks[seed.ID] {
+		a.queryChunks = append(a.queryChunks, c.Embedding)
+	}
 	a.embeddingPending = false
 	a.searchStart = time.Now()
 	a.queryID = newQueryID()
+	a.resetRelevance()
 
 	a.logger.Emit(otel.Event{
 		Kind:    otel.KindSearchStart,
@@ -1040,9 +1419,15 @@ func (a App) handleMoreLikeThis() (tea.Model, tea.Cmd) {
 	a.statusText = a.searchStage()
 	cmds = append(cmds, a.spinner.Tick)
 
+	var record tea.Cmd
+	if a.recordSignal != nil {
+		record = a.recordSignal(seed.ID, store.SignalMoreLikeThis)
+	}
 	if !a.searchPoolPending {
-		return a.startMLTReranking(seed)
+		m, cmd := a.startMLTReranking(seed)
+		return m, tea.Batch(cmd, record)
 	}
+	cmds = append(cmds, record)
 
 	return a, tea.Batch(cmds...)
 }
@@ -1080,6 +1465,9 @@ func (a App) clearSearch() (tea.Model, tea.Cmd) {
 	a.filterInput.SetValue("")
 	a.filterInput.Blur()
 	a.queryEmbedding = nil
+	a.queryModel = ""
+	a.queryChunks = nil
+	a.passages = nil
 	a.lastEmbeddedQuery = ""
 	a.activeQuery = ""
 	a.mltSeedID = ""
@@ -1088,6 +1476,7 @@ func (a App) clearSearch() (tea.Model, tea.Cmd) {
 	a.rerankEntries = nil
 	a.rerankScores = nil
 	a.rerankProgress = 0
+	a.resetRelevance()
 	a.statusText = ""
 	a.searchStart = time.Time{}
 	a.queryID = ""
@@ -1096,8 +1485,13 @@ func (a App) clearSearch() (tea.Model, tea.Cmd) {
 	if a.savedItems != nil {
 		a.items = a.savedItems
 		a.embeddings = a.savedEmbeddings
+		a.embeddingModel = a.savedEmbeddingModel
+		a.chunks = a.savedChunks
+		a.similar, a.expanded = a.savedSimilar, a.savedExpanded
 		a.savedItems = nil
 		a.savedEmbeddings = nil
+		a.savedChunks = nil
+		a.savedSimilar, a.savedExpanded = nil, nil
 	} else {
 		a.sortByFetchTime()
 	}
@@ -1112,7 +1506,7 @@ func (a App) startReranking(query string) (tea.Model, tea.Cmd) {
 	}
 
 	// Need either batch or per-entry scoring
-	if a.batchRerank == nil && a.scoreEntry == nil {
+	if !a.rerankerAvailable() {
 		a.statusText = ""
 		return a, nil
 	}
@@ -1127,28 +1521,76 @@ func (a App) startReranking(query string) (tea.Model, tea.Cmd) {
 
 	a.rerankPending = true
 	a.rerankQuery = query
-	a.statusText = a.searchStage()
-	a.logger.Emit(otel.Event{Kind: otel.KindCrossEncoder, Level: otel.LevelInfo, Comp: "ui", Count: topN, Query: query, Extra: map[string]any{"batch": a.batchRerank != nil}})
 	a.rerankEntries = make([]store.Item, topN)
 	copy(a.rerankEntries, a.items[:topN])
 	a.rerankScores = make([]float32, topN)
 	a.rerankProgress = 0
 
+	// Cached scores apply now; only the rest go to the reranker.
+	a.rerankSent = nil
+	if a.cachedRerankScores != nil {
+		cached := a.cachedRerankScores(query, a.rerankEntries)
+		if len(cached) > 0 {
+			a.rerankSent = []int{}
+			for i, item := range a.rerankEntries {
+				if score, ok := cached[item.ID]; ok {
+					a.rerankScores[i] = score
+					a.rerankProgress++
+				} else {
+					a.rerankSent = append(a.rerankSent, i)
+				}
+			}
+		}
+	}
+	pending := a.rerankEntries
+	if a.rerankSent != nil {
+		pending = make([]store.Item, len(a.rerankSent))
+		for i, j := range a.rerankSent {
+			pending[i] = a.rerankEntries[j]
+		}
+	}
+	a.logger.Emit(otel.Event{Kind: otel.KindCrossEncoder, Level: otel.LevelInfo, Comp: "ui", Count: topN, Query: query, Extra: map[string]any{"batch": a.rerankItems != nil || a.batchRerank != nil, "cached": topN - len(pending)}})
+
+	if len(pending) == 0 {
+		a.rerankPending = false
+		a.statusText = ""
+		a.logger.Emit(otel.Event{Kind: otel.KindSearchComplete, Level: otel.LevelInfo, Comp: "ui", Dur: time.Since(a.searchStart), Query: query, Count: topN})
+		a.applyScoresAsOrder()
+		a.rerankEntries = nil
+		a.rerankScores = nil
+		a.rerankProgress = 0
+		return a, nil
+	}
+	a.statusText = a.searchStage()
+
+	// Item path: one call with fields and vectors (local reranker)
+	if a.rerankItems != nil {
+		var queryEmb []float32
+		var embs [][]float32
+		if len(a.queryEmbedding) > 0 && a.queryModel == a.embeddingModel {
+			queryEmb = a.queryEmbedding
+			embs = make([][]float32, len(pending))
+			for i, item := range pending {
+				embs[i] = a.embeddings[item.ID]
+			}
+		}
+		return a, tea.Batch(a.spinner.Tick, a.rerankItems(a.searchCtx, query, queryEmb, pending, embs, a.queryID))
+	}
+
 	// Batch path: single API call (Jina)
 	if a.batchRerank != nil {
-		docs := make([]string, topN)
-		for i := 0; i < topN; i++ {
-			docs[i] = entryText(a.rerankEntries[i])
+		docs := make([]string, len(pending))
+		for i, item := range pending {
+			docs[i] = entryText(item)
 		}
 		return a, tea.Batch(a.spinner.Tick, a.batchRerank(a.searchCtx, query, docs, a.queryID))
 	}
 
 	// Per-entry path: fire ALL entries in parallel (Ollama)
-	cmds := make([]tea.Cmd, topN+1)
+	cmds := make([]tea.Cmd, len(pending)+1)
 	cmds[0] = a.spinner.Tick
-	for i := 0; i < topN; i++ {
-		doc := entryText(a.rerankEntries[i])
-		cmds[i+1] = a.scoreEntry(a.searchCtx, query, doc, a.rerankEntries[i].ID, a.queryID)
+	for i, item := range pending {
+		cmds[i+1] = a.scoreEntry(a.searchCtx, query, entryText(item), item.ID, a.queryID)
 	}
 	return a, tea.Batch(cmds...)
 }
@@ -1170,9 +1612,13 @@ func (a App) handleEntryReranked(msg EntryReranked) (tea.Model, tea.Cmd) {
 	}
 
 	// Store score by ID lookup
+	var save tea.Cmd
 	if msg.Err == nil {
 		if idx := a.rerankIndexForID(msg.ItemID); idx >= 0 && idx < len(a.rerankScores) {
 			a.rerankScores[idx] = msg.Score
+			if a.saveRerankScores != nil && msg.Reranker != "" {
+				save = a.saveRerankScores(a.rerankQuery, msg.Reranker, []store.Item{a.rerankEntries[idx]}, []float32{msg.Score})
+			}
 		}
 	}
 	a.rerankProgress++
@@ -1187,11 +1633,11 @@ func (a App) handleEntryReranked(msg EntryReranked) (tea.Model, tea.Cmd) {
 		a.rerankEntries = nil
 		a.rerankScores = nil
 		a.rerankProgress = 0
-		return a, nil
+		return a, save
 	}
 
 	// No chaining — all entries fired in parallel, just wait for more results
-	return a, nil
+	return a, save
 }
 
 // applyScoresAsOrder sorts reranked entries by score and applies the order.
@@ -1220,6 +1666,14 @@ func (a *App) applyScoresAsOrder() {
 	}
 
 	a.applyRerankOrder(order)
+
+	if a.rerankRelevance == nil {
+		a.rerankRelevance = make(map[string]float32, len(a.rerankEntries))
+	}
+	for i, item := range a.rerankEntries {
+		a.rerankRelevance[item.ID] = a.rerankScores[i]
+	}
+	a.applyRelevanceCut()
 }
 
 func (a *App) rerankIndexForID(id string) int {
@@ -1242,12 +1696,47 @@ func (a App) handleEnter() (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// handleMuteSource mutes the source of the item under the cursor.
+// Its items disappear immediately; the mute is persisted asynchronously
+// and stops the source being fetched from the next cycle on.
+func (a App) handleMuteSource() (tea.Model, tea.Cmd) {
+	if a.muteSource == nil || len(a.items) == 0 || a.cursor >= len(a.items) {
+		return a, nil
+	}
+	source := a.items[a.cursor].SourceName
+
+	a.items = withoutSource(a.items, source)
+	a.belowCut = withoutSource(a.belowCut, source)
+	if a.savedItems != nil {
+		a.savedItems = withoutSource(a.savedItems, source)
+	}
+	if a.cursor >= len(a.items) {
+		a.cursor = len(a.items) - 1
+	}
+	if a.cursor < 0 {
+		a.cursor = 0
+	}
+	return a, a.muteSource(source)
+}
+
+// withoutSource returns a copy of items without those from source.
+func withoutSource(items []store.Item, source string) []store.Item {
+	result := make([]store.Item, 0, len(items))
+	for _, item := range items {
+		if item.SourceName != source {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // handleUp moves cursor up.
 func (a App) handleUp() (tea.Model, tea.Cmd) {
 	if a.cursor > 0 {
 		a.cursor--
 	}
-	return a, nil
+	cmd := a.bumpVisible()
+	return a, cmd
 }
 
 // handleDown moves cursor down.
@@ -1255,13 +1744,15 @@ func (a App) handleDown() (tea.Model, tea.Cmd) {
 	if a.cursor < len(a.items)-1 {
 		a.cursor++
 	}
-	return a, nil
+	cmd := a.bumpVisible()
+	return a, cmd
 }
 
 // handleHome moves cursor to start.
 func (a App) handleHome() (tea.Model, tea.Cmd) {
 	a.cursor = 0
-	return a, nil
+	cmd := a.bumpVisible()
+	return a, cmd
 }
 
 // handleEnd moves cursor to end.
@@ -1269,16 +1760,193 @@ func (a App) handleEnd() (tea.Model, tea.Cmd) {
 	if len(a.items) > 0 {
 		a.cursor = len(a.items) - 1
 	}
-	return a, nil
+	cmd := a.bumpVisible()
+	return a, cmd
+}
+
+// bumpVisible asks for the on-screen items lacking vectors to be embedded
+// first. Only fires when the visible window has moved since the last bump,
+// so scrolling within a screen costs nothing.
+func (a *App) bumpVisible() tea.Cmd {
+	if a.prioritizeEmbed == nil || len(a.items) == 0 {
+		return nil
+	}
+	height := a.height - 2 // status bar + stream's own reserve
+	if height < 1 {
+		height = 1
+	}
+	start := scrollOffset(a.items, a.cursor, height, streamDecor{rising: a.rising}.bands(!a.hasQuery()))
+	end := start + height
+	if end > len(a.items) {
+		end = len(a.items)
+	}
+	if a.items[start].ID == a.bumpedWindow {
+		return nil
+	}
+	a.bumpedWindow = a.items[start].ID
+	return a.bumpEmbedding(store.EmbedPriorityVisible, a.items[start:end], a.embeddings)
+}
+
+// bumpEmbedding requests priority for the items that have no embedding.
+// Returns nil when nothing is missing or no callback is wired.
+func (a App) bumpEmbedding(priority int, items []store.Item, embeddings map[string][]float32) tea.Cmd {
+	if a.prioritizeEmbed == nil {
+		return nil
+	}
+	var missing []string
+	for _, item := range items {
+		if _, ok := embeddings[item.ID]; !ok {
+			missing = append(missing, item.ID)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return a.prioritizeEmbed(priority, missing)
 }
 
 // rerankItemsByEmbedding reranks items in place by cosine similarity to the query embedding.
+// Long items score their best-matching passage (max-sim over chunks); the
+// passage is kept in a.passages to show why the item matched.
+// Does nothing if the query and item vectors come from different models
+// (a fallback switch between loading them): their similarities would be noise.
 func (a *App) rerankItemsByEmbedding() {
 	if len(a.queryEmbedding) == 0 || len(a.items) == 0 {
 		return
 	}
-	a.items = filter.RerankByQuery(a.items, a.embeddings, a.queryEmbedding)
+	if a.queryModel != a.embeddingModel {
+		a.logger.Emit(otel.Event{Kind: otel.KindCosineRerank, Level: otel.LevelWarn, Comp: "ui", QueryID: a.queryID, Msg: "skipped: query and items embedded by different models", Extra: map[string]any{"query_model": a.queryModel, "item_model": a.embeddingModel}})
+		return
+	}
+	queries := append([][]float32{a.queryEmbedding}, a.queryChunks...)
+	a.items = filter.RerankByQuery(a.items, a.embeddings, a.chunks, queries...)
+	a.passages = filter.MatchingPassages(a.items, a.chunks, queries...)
 	a.cursor = 0
+
+	if a.calibrateCosine != nil {
+		if a.cosineRelevance == nil {
+			a.cosineRelevance = make(map[string]float32, len(a.items))
+		}
+		for _, item := range a.items {
+			emb, chunks := a.embeddings[item.ID], a.chunks[item.ID]
+			if len(emb) == 0 && len(chunks) == 0 {
+				continue
+			}
+			sim, _ := filter.MaxSim(queries, emb, chunks)
+			if p, ok := a.calibrateCosine(a.queryModel, sim); ok {
+				a.cosineRelevance[item.ID] = p
+			}
+		}
+	}
+	a.applyRelevanceCut()
+}
+
+// relevanceCuts are the cut-offs "h" cycles through in results mode.
+var relevanceCuts = []float32{0, 0.25, 0.5, 0.75}
+
+// cycleRelevanceCut raises the relevance cut-off to the next step, and
+// from the highest back to showing every result. The cut-off is kept
+// for later searches.
+func (a App) cycleRelevanceCut() (tea.Model, tea.Cmd) {
+	next := relevanceCuts[0]
+	for _, c := range relevanceCuts {
+		if c > a.minRelevance {
+			next = c
+			break
+		}
+	}
+	a.minRelevance = next
+	a.applyRelevanceCut()
+	return a, nil
+}
+
+// resetRelevance forgets the current results' relevance and hidden items.
+func (a *App) resetRelevance() {
+	a.rerankRelevance = nil
+	a.cosineRelevance = nil
+	a.belowCut = nil
+}
+
+// relevanceOf returns the relevance of a result: its rerank score, else
+// its calibrated cosine similarity. ok is false if it has neither.
+func (a App) relevanceOf(id string) (float32, bool) {
+	if r, ok := a.rerankRelevance[id]; ok {
+		return r, true
+	}
+	r, ok := a.cosineRelevance[id]
+	return r, ok
+}
+
+// belowRelevance reports whether the result with id is hidden by the
+// cut-off. Results without a relevance are always shown.
+func (a App) belowRelevance(id string) bool {
+	r, ok := a.relevanceOf(id)
+	return ok && a.minRelevance > 0 && r < a.minRelevance
+}
+
+// applyRelevanceCut moves results below the cut-off from items to
+// belowCut, and hidden ones back into place when it is lowered or they
+// were rescored. The selected result keeps the cursor if still shown.
+func (a *App) applyRelevanceCut() {
+	if a.minRelevance == 0 && len(a.belowCut) == 0 {
+		return
+	}
+	selected := ""
+	if a.cursor < len(a.items) {
+		selected = a.items[a.cursor].ID
+	}
+
+	shown := make(map[string]bool, len(a.items))
+	hidden := make(map[string]bool)
+	var kept, cut []store.Item
+	for _, item := range a.items {
+		if a.belowRelevance(item.ID) {
+			if !hidden[item.ID] {
+				hidden[item.ID] = true
+				cut = append(cut, item)
+			}
+			continue
+		}
+		shown[item.ID] = true
+		kept = append(kept, item)
+	}
+	a.items = kept
+	for _, item := range a.belowCut {
+		switch {
+		case shown[item.ID] || hidden[item.ID]:
+			// Reloaded into items (search pool) and handled above.
+		case a.belowRelevance(item.ID):
+			hidden[item.ID] = true
+			cut = append(cut, item)
+		default:
+			shown[item.ID] = true
+			a.insertByRelevance(item)
+		}
+	}
+	a.belowCut = cut
+	a.restoreCursor(selected)
+}
+
+// insertByRelevance inserts item after the last result at least as
+// relevant, so results ordered by relevance stay in order.
+func (a *App) insertByRelevance(item store.Item) {
+	r, _ := a.relevanceOf(item.ID)
+	pos := 0
+	for i, other := range a.items {
+		if o, ok := a.relevanceOf(other.ID); ok && o >= r {
+			pos = i + 1
+		}
+	}
+	a.items = slices.Insert(a.items, pos, item)
+}
+
+// resultsLabel appends the relevance cut-off, when set, to the results
+// bar label.
+func (a App) resultsLabel(label string) string {
+	if a.minRelevance == 0 {
+		return label
+	}
+	return fmt.Sprintf("%s  [relevance ≥ %.2f: %d hidden]", label, a.minRelevance, len(a.belowCut))
 }
 
 // applyRerankOrder applies the cross-encoder reranking order to items.
@@ -1353,20 +2021,48 @@ func (a App) View() string {
 	// Debug overlay: full takeover
 	if a.debugVisible && a.ring != nil {
 		contentHeight := a.height - 2 // -1 status bar, -1 newline separator
-		overlay := debugOverlay(a.ring, a.width, contentHeight)
+		overlay := debugOverlay(a.ring, a.backend, a.width, contentHeight)
 		statusBar := debugStatusBar(a.width)
 		return overlay + "\n" + statusBar
 	}
+	if a.rulesVisible {
+		return rulesOverlay(a.rules, a.ruleCursor, a.width, a.height-2) + "\n" + rulesStatusBar(a.width)
+	}
+	if a.interestVisible {
+		return interestOverlay(a.interest, a.interestLoaded, a.interestOrder, a.interestConfirm, a.interestCursor, a.width, a.height-2) + "\n" + interestStatusBar(a.width)
+	}
+	if a.threadVisible {
+		return threadOverlay(a.thread, a.threadItems, a.threadLoaded, a.threadItemID, a.threadScroll, a.width, a.height-2) + "\n" + threadStatusBar(a.width)
+	}
 
 	contentHeight := a.height - 1
 	if a.err != nil {
 		contentHeight--
 	}
-	if a.mode == ModeSearch || (a.hasQuery() && a.statusText == "") {
+	showHidden := a.showHidden && !a.hasQuery() && a.mode == ModeList
+	if a.mode == ModeSearch || a.mode == ModeRule || (a.hasQuery() && a.statusText == "") || showHidden {
 		contentHeight--
 	}
 
-	stream := RenderStream(a.items, a.cursor, a.width, contentHeight, !a.hasQuery(), a.alignedList, a.shimmerOffset)
+	matchLine := ""
+	if passage := a.selectedPassage(); passage != "" {
+		if matchLine = RenderMatchLine(passage, a.width); matchLine != "" {
+			contentHeight--
+		}
+	}
+
+	var stream, hiddenBar string
+	if a.hasQuery() {
+		stream = renderStream(a.items, streamDecor{notes: a.itemNotes()}, a.cursor, a.width, contentHeight, false, a.alignedList, a.shimmerOffset)
+	} else {
+		items, cursor := a.items, a.cursor
+		decor := streamDecor{notes: a.itemNotes(), badges: a.trendBadges, rising: a.rising}
+		if showHidden {
+			items, decor.hidden, cursor = withHidden(items, a.hidden, cursor)
+			hiddenBar = renderHiddenBar(len(decor.hidden), a.width)
+		}
+		stream = renderStream(items, decor, cursor, a.width, contentHeight, true, a.alignedList, a.shimmerOffset)
+	}
 
 	errorBar := ""
 	if a.err != nil {
@@ -1377,10 +2073,14 @@ func (a App) View() string {
 	searchBar := ""
 	if a.mode == ModeSearch {
 		searchBar = a.renderSearchInput()
+	} else if a.mode == ModeRule {
+		searchBar = a.renderRuleInput()
 	} else if a.mltSeedID != "" && a.statusText == "" {
-		searchBar = RenderFilterBarWithStatus(fmt.Sprintf("Similar to: %s", truncateRunes(a.mltSeedTitle, 40)), len(a.items), len(a.items), a.width, "")
+		searchBar = RenderFilterBarWithStatus(a.resultsLabel(fmt.Sprintf("Similar to: %s", truncateRunes(a.mltSeedTitle, 40))), len(a.items), len(a.items)+len(a.belowCut), a.width, "")
 	} else if a.hasQuery() && a.statusText == "" {
-		searchBar = RenderFilterBarWithStatus(a.activeQuery, len(a.items), len(a.items), a.width, "")
+		searchBar = RenderFilterBarWithStatus(a.resultsLabel(a.activeQuery), len(a.items), len(a.items)+len(a.belowCut), a.width, "")
+	} else if showHidden {
+		searchBar = hiddenBar
 	}
 
 	// Status bar
@@ -1389,6 +2089,7 @@ func (a App) View() string {
 		statusBar = RenderStatusBarWithFilter(
 			a.cursor, len(a.items), len(a.items), a.width, a.loading,
 			a.statusText, a.searchPoolPending, a.embeddingPending, a.rerankPending,
+			backendLabel(a.backend),
 		)
 	} else if a.statusText != "" {
 		elapsed := ""
@@ -1398,10 +2099,19 @@ func (a App) View() string {
 		status := fmt.Sprintf("  %s %s%s", a.spinner.View(), a.statusText, elapsed)
 		statusBar = StatusBar.Width(a.width).Render(status)
 	} else {
-		statusBar = RenderStatusBar(a.cursor, len(a.items), a.width, a.loading)
+		statusBar = RenderStatusBar(a.cursor, len(a.items), a.width, a.loading, backendLabel(a.backend))
 	}
 
-	return stream + errorBar + searchBar + statusBar
+	return stream + matchLine + errorBar + searchBar + statusBar
+}
+
+// selectedPassage returns the passage of the selected search result that
+// best matched the query, or "" outside search or for short items.
+func (a App) selectedPassage() string {
+	if !a.hasQuery() || a.cursor >= len(a.items) {
+		return ""
+	}
+	return a.passages[a.items[a.cursor].ID]
 }
 
 // renderSearchInput renders the search input bar.
@@ -1504,8 +2214,17 @@ func (a App) traceMsg(msg tea.Msg) {
 	case ItemMarkedRead:
 		typeName = "ItemMarkedRead"
 		e.Source = m.ID
+	case SourceMuted:
+		typeName = "SourceMuted"
+		e.Source = m.Source
+		if m.Err != nil {
+			e.Err = m.Err.Error()
+		}
 	case RefreshTick:
 		typeName = "RefreshTick"
+	case BackendChanged:
+		typeName = "BackendChanged"
+		e.Source = m.Status.Embedder
 	default:
 		// %T gives the type name without allocating the full value string.
 		// NEVER use %+v here — it triggers full reflection and can allocate
@@ -1523,7 +2242,7 @@ func (a App) convertToHeadlines() []media.Headline {
 	for i, item := range a.items {
 		// Calculate base scores if available, else defaults
 		sem := 0.5
-		if emb, ok := a.embeddings[item.ID]; ok && len(a.queryEmbedding) > 0 {
+		if emb, ok := a.embeddings[item.ID]; ok && len(a.queryEmbedding) > 0 && a.queryModel == a.embeddingModel {
 			sem = float64(filter.CosineSimilarity(a.queryEmbedding, emb))
 		}
 
@@ -194,6 +194,102 @@ func TestAppMarkReadEmpty(t *testing.T) {
 	}
 }
 
+func TestAppMuteSource(t *testing.T) {
+	var muted string
+	app := NewAppWithConfig(AppConfig{
+		MuteSource: func(source string) tea.Cmd {
+			muted = source
+			return func() tea.Msg { return SourceMuted{Source: source} }
+		},
+	})
+	app.items = []store.Item{
+		{ID: "1", Title: "Keep 1", SourceName: "Good"},
+		{ID: "2", Title: "Drop 1", SourceName: "Noisy"},
+		{ID: "3", Title: "Keep 2", SourceName: "Good"},
+		{ID: "4", Title: "Drop 2", SourceName: "Noisy"},
+	}
+	app.cursor = 3
+
+	model, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	app = model.(App)
+
+	if muted != "Noisy" {
+		t.Errorf("expected MuteSource called with 'Noisy', got %q", muted)
+	}
+	if cmd == nil {
+		t.Error("S should return a command")
+	}
+	if len(app.items) != 2 {
+		t.Fatalf("expected 2 items after mute, got %d", len(app.items))
+	}
+	for _, item := range app.items {
+		if item.SourceName == "Noisy" {
+			t.Errorf("muted source item %s still visible", item.ID)
+		}
+	}
+	if app.cursor != 1 {
+		t.Errorf("cursor should clamp to last item, got %d", app.cursor)
+	}
+}
+
+func TestAppMuteSourceNotWired(t *testing.T) {
+	mock := &mockCmd{}
+	app := NewApp(mock.loadItems, mock.markRead, mock.triggerFetch)
+	app.items = []store.Item{{ID: "1", SourceName: "Any"}}
+
+	model, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if cmd != nil {
+		t.Error("S should be a no-op without MuteSource")
+	}
+	if len(model.(App).items) != 1 {
+		t.Error("items should be unchanged without MuteSource")
+	}
+}
+
+func TestAppPrioritizesEmbedding(t *testing.T) {
+	type bump struct {
+		priority int
+		ids      []string
+	}
+	var bumps []bump
+	app := NewAppWithConfig(AppConfig{
+		PrioritizeEmbedding: func(priority int, ids []string) tea.Cmd {
+			bumps = append(bumps, bump{priority, ids})
+			return func() tea.Msg { return nil }
+		},
+	})
+	app.fullLoaded = true
+
+	items := []store.Item{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	model, cmd := app.Update(ItemsLoaded{Items: items, Embeddings: map[string][]float32{"a": {1}}})
+	app = model.(App)
+	if cmd == nil || len(bumps) != 1 {
+		t.Fatalf("expected one bump on load, got %d", len(bumps))
+	}
+	if bumps[0].priority != store.EmbedPriorityVisible || strings.Join(bumps[0].ids, ",") != "b,c" {
+		t.Errorf("unexpected visible bump %+v", bumps[0])
+	}
+
+	// Moving within the same screen doesn't re-bump.
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	app = model.(App)
+	if len(bumps) != 1 {
+		t.Errorf("scrolling within the window should not bump, got %d bumps", len(bumps))
+	}
+
+	// Search pool items lacking vectors are bumped at search priority.
+	app.activeQuery = "q"
+	app.searchPoolPending = true
+	pool := []store.Item{{ID: "a"}, {ID: "x"}, {ID: "y"}}
+	app.Update(SearchPoolLoaded{Items: pool, Embeddings: map[string][]float32{"y": {1}}})
+	if len(bumps) != 2 {
+		t.Fatalf("expected pool bump, got %d bumps", len(bumps))
+	}
+	if bumps[1].priority != store.EmbedPrioritySearch || strings.Join(bumps[1].ids, ",") != "a,x" {
+		t.Errorf("unexpected pool bump %+v", bumps[1])
+	}
+}
+
 func TestAppRefresh(t *testing.T) {
 	mock := &mockCmd{}
 	app := NewApp(mock.loadItems, mock.markRead, mock.triggerFetch)
@@ -965,6 +1061,49 @@ func TestAppBatchRerankPath(t *testing.T) {
 	}
 }
 
+func TestAppRerankItemsPath(t *testing.T) {
+	var gotItems []store.Item
+	var gotQueryEmb []float32
+	var gotEmbs [][]float32
+	app := NewAppWithConfig(AppConfig{
+		BatchRerank: func(ctx context.Context, query string, docs []string, queryID string) tea.Cmd {
+			t.Error("BatchRerank should not be called when RerankItems is set")
+			return nil
+		},
+		RerankItems: func(ctx context.Context, query string, queryEmb []float32, items []store.Item, embs [][]float32, queryID string) tea.Cmd {
+			gotItems, gotQueryEmb, gotEmbs = items, queryEmb, embs
+			return nil
+		},
+		AutoReranks: true,
+	})
+	app.items = []store.Item{{ID: "1", Title: "Item 1"}, {ID: "2", Title: "Item 2"}}
+	app.embeddings = map[string][]float32{"1": {1, 0}}
+	app.embeddingModel = "m"
+	app.activeQuery = "test"
+	app.mode = ModeResults
+	app.lastEmbeddedQuery = "test"
+	app.embeddingPending = true
+
+	model, _ := app.Update(QueryEmbedded{Query: "test", Embedding: []float32{0, 1}, Model: "m"})
+	if !model.(App).rerankPending {
+		t.Fatal("Should be rerank pending after startReranking")
+	}
+	if len(gotItems) != 2 || gotItems[0].ID != "1" {
+		t.Fatalf("RerankItems got items %v", gotItems)
+	}
+	if len(gotQueryEmb) != 2 || len(gotEmbs) != 2 || len(gotEmbs[0]) != 2 || gotEmbs[1] != nil {
+		t.Errorf("RerankItems got query %v, embeddings %v; want the vectors by position", gotQueryEmb, gotEmbs)
+	}
+
+	// Vectors from different models are not passed on.
+	app.embeddingPending = true
+	gotEmbs = nil
+	app.Update(QueryEmbedded{Query: "test", Embedding: []float32{0, 1}, Model: "other"})
+	if gotQueryEmb != nil || gotEmbs != nil {
+		t.Errorf("RerankItems got query %v, embeddings %v across models; want nil", gotQueryEmb, gotEmbs)
+	}
+}
+
 func TestAppRerankCompleteAppliesScores(t *testing.T) {
 	app := NewAppWithConfig(AppConfig{
 		BatchRerank: func(ctx context.Context, query string, docs []string, queryID string) tea.Cmd {
@@ -1044,6 +1183,149 @@ func TestAppRerankCompleteStaleQuery(t *testing.T) {
 	}
 }
 
+func TestAppRerankCache(t *testing.T) {
+	var sent []string
+	var saved map[string]float32
+	var savedBy string
+	app := NewAppWithConfig(AppConfig{
+		BatchRerank: func(ctx context.Context, query string, docs []string, queryID string) tea.Cmd {
+			sent = docs
+			return nil
+		},
+		CachedRerankScores: func(query string, items []store.Item) map[string]float32 {
+			return map[string]float32{"1": 0.2, "3": 0.9}
+		},
+		SaveRerankScores: func(query, reranker string, items []store.Item, scores []float32) tea.Cmd {
+			savedBy, saved = reranker, make(map[string]float32)
+			for i, item := range items {
+				saved[item.ID] = scores[i]
+			}
+			return nil
+		},
+		AutoReranks: true,
+	})
+	app.items = []store.Item{{ID: "1", Title: "Item 1"}, {ID: "2", Title: "Item 2"}, {ID: "3", Title: "Item 3"}}
+	app.activeQuery = "test"
+	app.mode = ModeResults
+
+	model, _ := app.startReranking("test")
+	updated := model.(App)
+	if len(sent) != 1 || sent[0] != "Item 2" {
+		t.Fatalf("sent %q to the reranker, want only the uncached Item 2", sent)
+	}
+
+	// The reranker's score for Item 2 merges with the cached ones.
+	model, _ = updated.Update(RerankComplete{Query: "test", Scores: []float32{0.5}, Reranker: "jina/v3"})
+	updated = model.(App)
+	if got := []string{updated.items[0].ID, updated.items[1].ID, updated.items[2].ID}; got[0] != "3" || got[1] != "2" || got[2] != "1" {
+		t.Errorf("order = %v, want [3 2 1]", got)
+	}
+	if savedBy != "jina/v3" || len(saved) != 1 || saved["2"] != 0.5 {
+		t.Errorf("saved %v by %q, want only Item 2 by jina/v3", saved, savedBy)
+	}
+
+	// Fully cached: applied at once, nothing sent.
+	sent = nil
+	app.items = []store.Item{{ID: "1", Title: "Item 1"}, {ID: "3", Title: "Item 3"}}
+	model, _ = app.startReranking("test")
+	updated = model.(App)
+	if sent != nil || updated.rerankPending {
+		t.Errorf("fully cached rerank sent %q (pending %v)", sent, updated.rerankPending)
+	}
+	if updated.items[0].ID != "3" {
+		t.Errorf("cached order not applied: first = %s", updated.items[0].ID)
+	}
+}
+
+func TestAppRelevanceCut(t *testing.T) {
+	app := NewAppWithConfig(AppConfig{
+		BatchRerank: func(ctx context.Context, query string, docs []string, queryID string) tea.Cmd {
+			return nil
+		},
+		AutoReranks: true,
+	})
+	app.items = []store.Item{{ID: "1", Title: "Item 1"}, {ID: "2", Title: "Item 2"}, {ID: "3", Title: "Item 3"}, {ID: "4", Title: "Item 4"}}
+	app.activeQuery = "test"
+	app.mode = ModeResults
+
+	model, _ := app.startReranking("test")
+	model, _ = model.(App).Update(RerankComplete{Query: "test", Scores: []float32{0.9, 0.3, 0.6, 0.1}})
+	ids := func(m tea.Model) string {
+		var out []string
+		for _, item := range m.(App).items {
+			out = append(out, item.ID)
+		}
+		return strings.Join(out, " ")
+	}
+	press := func(m tea.Model) tea.Model {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+		return m
+	}
+
+	for _, want := range []string{"1 3 2", "1 3", "1", "1 3 2 4"} {
+		model = press(model)
+		if got := ids(model); got != want {
+			t.Errorf("cut-off %.2f shows %q, want %q", model.(App).minRelevance, got, want)
+		}
+	}
+
+	model = press(press(model))
+	updated := model.(App)
+	if len(updated.belowCut) != 2 {
+		t.Fatalf("belowCut = %d items, want 2", len(updated.belowCut))
+	}
+	if view := updated.View(); !strings.Contains(view, "2 hidden") || !strings.Contains(view, "2/4") {
+		t.Errorf("results bar should show the cut-off and hidden count:\n%s", view)
+	}
+
+	// A new search keeps the cut-off but not the old results' hidden items.
+	model, _ = updated.clearSearch()
+	updated = model.(App)
+	if updated.belowCut != nil || updated.rerankRelevance != nil || updated.minRelevance != 0.5 {
+		t.Errorf("after clearSearch: belowCut %v, relevance %v, cut-off %v", updated.belowCut, updated.rerankRelevance, updated.minRelevance)
+	}
+}
+
+func TestAppRelevanceCutCalibratedCosine(t *testing.T) {
+	app := NewAppWithConfig(AppConfig{
+		CalibrateCosine: func(model string, similarity float32) (float32, bool) {
+			return similarity, model == "m"
+		},
+	})
+	app.items = []store.Item{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}, {ID: "c", Title: "No vector"}}
+	app.embeddings = map[string][]float32{"a": {1, 0}, "b": {0.2, 1}}
+	app.embeddingModel, app.queryModel = "m", "m"
+	app.queryEmbedding = []float32{1, 0}
+	app.activeQuery = "test"
+	app.mode = ModeResults
+	app.minRelevance = 0.5
+
+	app.rerankItemsByEmbedding()
+	if len(app.items) != 2 || app.items[0].ID != "a" || app.items[1].ID != "c" {
+		t.Errorf("items = %v, want a and the unscored c", idsOfItems(app.items))
+	}
+	if len(app.belowCut) != 1 || app.belowCut[0].ID != "b" {
+		t.Errorf("belowCut = %v, want b", idsOfItems(app.belowCut))
+	}
+
+	// An uncalibrated model hides nothing.
+	app.resetRelevance()
+	app.items = []store.Item{{ID: "a"}, {ID: "b"}}
+	app.embeddingModel, app.queryModel = "other", "other"
+	app.rerankItemsByEmbedding()
+	if len(app.items) != 2 || len(app.belowCut) != 0 {
+		t.Errorf("uncalibrated model hid %v", idsOfItems(app.belowCut))
+	}
+}
+
+func idsOfItems(items []store.Item) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
 func TestAppBatchRerankView(t *testing.T) {
 	app := NewAppWithConfig(AppConfig{
 		BatchRerank: func(ctx context.Context, query string, docs []string, queryID string) tea.Cmd {
@@ -2090,3 +2372,77 @@ func TestSearchFlow_FTSFirst(t *testing.T) {
 		t.Error("poolItems buffer should be cleared after merge")
 	}
 }
+
+func TestCosineRerankSkippedAcrossModels(t *testing.T) {
+	app := NewApp(nil, nil, nil)
+	app.items = []store.Item{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}}
+	app.embeddings = map[string][]float32{"a": {0, 1}, "b": {1, 0}}
+	app.embeddingModel = "jina/jina-embeddings-v3"
+	app.queryEmbedding = []float32{1, 0}
+
+	app.queryModel = "local/hash-ngram-v1"
+	app.rerankItemsByEmbedding()
+	if app.items[0].ID != "a" {
+		t.Error("vectors from different models should not be compared")
+	}
+
+	app.queryModel = app.embeddingModel
+	app.rerankItemsByEmbedding()
+	if app.items[0].ID != "b" {
+		t.Error("expected cosine rerank when the models match")
+	}
+}
+
+func TestBackendChangedUpdatesStatus(t *testing.T) {
+	app := NewApp(nil, nil, nil)
+	model, _ := app.Update(BackendChanged{Status: BackendStatus{Embedder: "local/hash-ngram-v1", Degraded: true}})
+	updated := model.(App)
+	if !updated.backend.Degraded || updated.backend.Embedder != "local/hash-ngram-v1" {
+		t.Errorf("backend status not stored: %+v", updated.backend)
+	}
+}
+
+func TestSearchShowsMatchingPassage(t *testing.T) {
+	app := NewApp(nil, nil, nil)
+	app.ready = true
+	app.width = 100
+	app.height = 20
+	app.items = []store.Item{{ID: "short", Title: "Short item", Published: time.Now()}, {ID: "long", Title: "Long item", Published: time.Now()}}
+	app.embeddings = map[string][]float32{"short": {0.6, 0.8}, "long": {0, 1}}
+	app.chunks = map[string][]store.Chunk{"long": {
+		{Index: 0, Text: "opening paragraph", Embedding: []float32{0, 1}},
+		{Index: 1, Text: "the passage about the query", Embedding: []float32{1, 0}},
+	}}
+	app.activeQuery = "query"
+	app.queryEmbedding = []float32{1, 0}
+
+	app.rerankItemsByEmbedding()
+	if app.items[0].ID != "long" {
+		t.Fatalf("expected the long item's matching passage to rank it first, got %s", app.items[0].ID)
+	}
+	if view := app.View(); !strings.Contains(view, "the passage about the query") {
+		t.Errorf("view should show why the selected result matched, got:\n%s", view)
+	}
+
+	model, _ := app.clearSearch()
+	if cleared := model.(App); cleared.passages != nil || strings.Contains(cleared.View(), "↳") {
+		t.Error("passages should be cleared with the search")
+	}
+}
+
+func TestMoreLikeThisUsesSeedPassages(t *testing.T) {
+	app := NewApp(nil, nil, nil)
+	app.items = []store.Item{{ID: "seed", Title: "Seed"}, {ID: "a", Title: "A"}, {ID: "b", Title: "B"}}
+	app.embeddings = map[string][]float32{"seed": {1, 0}, "a": {0.9, 0.1}, "b": {0, 1}}
+	app.chunks = map[string][]store.Chunk{"seed": {{Index: 0, Text: "aside", Embedding: []float32{0, 1}}}}
+
+	model, _ := app.handleMoreLikeThis()
+	updated := model.(App)
+	if len(updated.queryChunks) != 1 {
+		t.Fatalf("expected the seed's passage vectors as extra queries, got %d", len(updated.queryChunks))
+	}
+	// b matches the seed's passage exactly, which beats a's near match on the document.
+	if updated.items[0].ID != "b" {
+		t.Errorf("expected max-sim over the seed's passages to rank b first, got %s", updated.items[0].ID)
+	}
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clusterSources is how many sources the collapsed note names.
+const clusterSources = 3
+
+// expandClusters returns items with the near-duplicates of each expanded
+// item listed right after it.
+func expandClusters(items []store.Item, similar map[string][]filter.Similar, expanded map[string]bool) []store.Item {
+	if len(expanded) == 0 || len(similar) == 0 {
+		return items
+	}
+	out := make([]store.Item, 0, len(items))
+	for _, item := range items {
+		out = append(out, item)
+		if expanded[item.ID] {
+			for _, s := range similar[item.ID] {
+				out = append(out, s.Item)
+			}
+		}
+	}
+	return out
+}
+
+// clusterOf returns the kept item whose cluster id belongs to: id itself
+// if it has near-duplicates, or the expanded item it's listed under.
+func (a App) clusterOf(id string) string {
+	if len(a.similar[id]) > 0 {
+		return id
+	}
+	for head := range a.expanded {
+		for _, s := range a.similar[head] {
+			if s.Item.ID == id {
+				return head
+			}
+		}
+	}
+	return ""
+}
+
+// toggleCluster expands the selected item's near-duplicates below it, or
+// collapses the cluster the selection is in, back onto its kept item.
+func (a App) toggleCluster() (tea.Model, tea.Cmd) {
+	if a.cursor >= len(a.items) {
+		return a, nil
+	}
+	head := a.clusterOf(a.items[a.cursor].ID)
+	if head == "" {
+		return a, nil
+	}
+	if a.expanded == nil {
+		a.expanded = make(map[string]bool)
+	}
+
+	if a.expanded[head] {
+		delete(a.expanded, head)
+		members := make(map[string]bool, len(a.similar[head]))
+		for _, s := range a.similar[head] {
+			members[s.Item.ID] = true
+		}
+		items := make([]store.Item, 0, len(a.items))
+		for _, item := range a.items {
+			if !members[item.ID] {
+				items = append(items, item)
+			}
+		}
+		a.items = items
+	} else {
+		a.expanded[head] = true
+		a.items = expandClusters(a.items, map[string][]filter.Similar{head: a.similar[head]}, a.expanded)
+	}
+	a.restoreCursor(head)
+	return a, nil
+}
+
+// clusterNotes returns the note after each clustered item's title:
+// "+4 similar from Reuters, AP, BBC" on a collapsed kept item, the count
+// on an expanded one and the similarity on each item listed under it.
+func (a App) clusterNotes() map[string]string {
+	if len(a.similar) == 0 {
+		return nil
+	}
+	notes := make(map[string]string)
+	for _, item := range a.items {
+		similar := a.similar[item.ID]
+		if len(similar) == 0 {
+			continue
+		}
+		if !a.expanded[item.ID] {
+			notes[item.ID] = clusterNote(similar)
+			continue
+		}
+		notes[item.ID] = fmt.Sprintf("▾ %d similar", len(similar))
+		for _, s := range similar {
+			notes[s.Item.ID] = fmt.Sprintf("≈ %.2f", s.Similarity)
+		}
+	}
+	return notes
+}
+
+// clusterNote summarizes a collapsed cluster: how many items, and from
+// which sources.
+func clusterNote(similar []filter.Similar) string {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, s := range similar {
+		if !seen[s.Item.SourceName] {
+			seen[s.Item.SourceName] = true
+			sources = append(sources, s.Item.SourceName)
+		}
+	}
+	from := strings.Join(sources[:min(len(sources), clusterSources)], ", ")
+	if len(sources) > clusterSources {
+		from += ", …"
+	}
+	return fmt.Sprintf("+%d similar from %s", len(similar), from)
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAppClusters(t *testing.T) {
+	now := time.Now()
+	loaded := ItemsLoaded{
+		Items: []store.Item{
+			{ID: "1", Title: "Rates held", SourceName: "Reuters", Published: now.Add(-time.Minute)},
+			{ID: "4", Title: "Cup final", SourceName: "Sport", Published: now.Add(-5 * time.Minute)},
+		},
+		Similar: map[string][]filter.Similar{"1": {
+			{Item: store.Item{ID: "2", Title: "Bank keeps rates", SourceName: "AP"}, Similarity: 0.91},
+			{Item: store.Item{ID: "3", Title: "Rates unchanged", SourceName: "BBC"}, Similarity: 0.88},
+			{Item: store.Item{ID: "5", Title: "Rates on hold", SourceName: "AP"}, Similarity: 0.87},
+		}},
+	}
+	app := NewAppWithConfig(AppConfig{})
+	model, _ := app.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	app = model.(App)
+	model, _ = app.Update(loaded)
+	app = model.(App)
+	app.loading = false
+
+	view := app.View()
+	if !strings.Contains(view, "+3 similar from AP, BBC") || strings.Contains(view, "Bank keeps rates") {
+		t.Fatalf("collapsed cluster not shown as a note:\n%s", view)
+	}
+
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	app = model.(App)
+	if got := len(app.items); got != 5 || app.items[1].ID != "2" || app.items[4].ID != "4" {
+		t.Fatalf("e should list the cluster under its item, got %d items", got)
+	}
+	view = app.View()
+	for _, want := range []string{"▾ 3 similar", "Bank keeps rates", "≈ 0.91"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expanded view missing %q:\n%s", want, view)
+		}
+	}
+
+	// A reload keeps the cluster open.
+	model, _ = app.Update(loaded)
+	app = model.(App)
+	if len(app.items) != 5 {
+		t.Errorf("reload collapsed the cluster: %d items", len(app.items))
+	}
+
+	// e on a member collapses the cluster back onto its item.
+	app.cursor = 2
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	app = model.(App)
+	if len(app.items) != 2 || app.cursor != 0 {
+		t.Errorf("collapse: %d items, cursor %d", len(app.items), app.cursor)
+	}
+
+	// Items without near-duplicates don't expand.
+	app.cursor = 1
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	app = model.(App)
+	if len(app.items) != 2 {
+		t.Errorf("e on an unclustered item changed the list: %d items", len(app.items))
+	}
+}
+
+func TestAppSearchClusters(t *testing.T) {
+	now := time.Now()
+	app := NewAppWithConfig(AppConfig{})
+	model, _ := app.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	app = model.(App)
+	model, _ = app.Update(ItemsLoaded{
+		Items: []store.Item{{ID: "1", Title: "Rates held", SourceName: "Reuters", Published: now}},
+		Similar: map[string][]filter.Similar{"1": {
+			{Item: store.Item{ID: "2", Title: "Bank keeps rates", SourceName: "AP"}, Similarity: 0.91},
+			{Item: store.Item{ID: "3", Title: "Rates unchanged", SourceName: "BBC"}, Similarity: 0.88},
+		}},
+	})
+	app = model.(App)
+	app.loading = false
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	app = model.(App)
+
+	for _, msg := range []tea.Msg{
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("rates")},
+		tea.KeyMsg{Type: tea.KeyEnter},
+	} {
+		model, _ = app.Update(msg)
+		app = model.(App)
+	}
+	app.searchPoolPending = true
+	model, _ = app.Update(SearchPoolLoaded{
+		Items: []store.Item{
+			{ID: "1", Title: "Rates held", SourceName: "Reuters", Published: now},
+			{ID: "9", Title: "Rate history", SourceName: "Wire", Published: now.Add(-48 * time.Hour)},
+		},
+		Similar: map[string][]filter.Similar{"9": {
+			{Item: store.Item{ID: "8", Title: "A history of rates", SourceName: "Blog"}, Similarity: 0.9},
+		}},
+	})
+	app = model.(App)
+
+	// Results use the pool's clusters, collapsed, not the feed's.
+	view := app.View()
+	if !strings.Contains(view, "+1 similar from Blog") || strings.Contains(view, "Bank keeps rates") || len(app.items) != 2 {
+		t.Fatalf("search results should fold the pool's near-duplicates:\n%s", view)
+	}
+	app.cursor = 1
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	app = model.(App)
+	if len(app.items) != 3 || app.items[2].ID != "8" {
+		t.Fatalf("e in results should expand the cluster, got %d items", len(app.items))
+	}
+
+	// Esc restores the feed with its own cluster still expanded.
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app = model.(App)
+	if len(app.items) != 3 || app.items[1].ID != "2" || !strings.Contains(app.View(), "▾ 2 similar") {
+		t.Errorf("feed clusters not restored after search: %v", app.items)
+	}
+}
+
+func TestClusterNote(t *testing.T) {
+	var similar []filter.Similar
+	for _, source := range []string{"AP", "BBC", "AP", "CNN", "NPR"} {
+		similar = append(similar, filter.Similar{Item: store.Item{SourceName: source}})
+	}
+	if got := clusterNote(similar); got != "+5 similar from AP, BBC, CNN, …" {
+		t.Errorf("clusterNote = %q", got)
+	}
+}
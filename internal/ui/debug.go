@@ -13,9 +13,10 @@ import (
 // Must be updated if DebugPanel style changes.
 const debugPanelChrome = 4
 
-// debugOverlay renders the debug panel showing pipeline stats and recent events.
+// debugOverlay renders the debug panel showing pipeline stats, the AI
+// backends in use and recent events.
 // Pure function with no side effects. Returns empty string if ring is nil.
-func debugOverlay(ring *otel.RingBuffer, width, height int) string {
+func debugOverlay(ring *otel.RingBuffer, backend BackendStatus, width, height int) string {
 	if ring == nil {
 		return ""
 	}
@@ -37,6 +38,13 @@ func debugOverlay(ring *otel.RingBuffer, width, height int) string {
 	lines = append(lines, fmt.Sprintf("  Buffer:     %d / %d events", ring.Len(), ring.Cap()))
 	lines = append(lines, "")
 
+	// --- Backends section ---
+	if backend.Embedder != "" {
+		lines = append(lines, DebugHeaderStyle.Render("Backends"))
+		lines = append(lines, debugBackendLines(backend)...)
+		lines = append(lines, "")
+	}
+
 	// --- Recent events section ---
 	lines = append(lines, DebugHeaderStyle.Render("Recent Events"))
 	for _, e := range recent {
@@ -81,6 +89,38 @@ func debugOverlay(ring *otel.RingBuffer, width, height int) string {
 	return DebugPanel.Width(panelWidth).Render(content)
 }
 
+// debugBackendLines lists the active embedder and reranker, then every
+// embedding backend in the fallback chain with its health.
+func debugBackendLines(b BackendStatus) []string {
+	embedder := b.Embedder
+	if b.Degraded {
+		embedder += "  (fallback active)"
+	}
+	reranker := b.Reranker
+	if reranker == "" {
+		reranker = "none (cosine only)"
+	}
+	lines := []string{
+		"  Embedder:   " + embedder,
+		"  Reranker:   " + reranker,
+	}
+	for i, h := range b.Chain {
+		mark := "ok"
+		if !h.Healthy {
+			mark = "down"
+		}
+		if h.Active {
+			mark += ", active"
+		}
+		line := fmt.Sprintf("  %d. %-28s %s", i+1, truncateRunes(h.Name, 28), mark)
+		if h.Err != "" {
+			line += "  ERR:" + truncateRunes(h.Err, 30)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 // formatAge formats a duration as a compact human string.
 // Handles negative durations from clock skew by clamping to "0ms".
 func formatAge(d time.Duration) string {
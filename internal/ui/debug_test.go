@@ -10,7 +10,7 @@ import (
 )
 
 func TestDebugOverlayNilRing(t *testing.T) {
-	result := debugOverlay(nil, 80, 24)
+	result := debugOverlay(nil, BackendStatus{}, 80, 24)
 	if result != "" {
 		t.Errorf("debugOverlay(nil) should return empty string, got %q", result)
 	}
@@ -24,7 +24,7 @@ func TestDebugOverlayRendersStats(t *testing.T) {
 	ring.Push(otel.Event{Kind: otel.KindSearchStart, Time: time.Now()})
 	ring.Push(otel.Event{Kind: otel.KindSearchComplete, Time: time.Now()})
 
-	result := debugOverlay(ring, 80, 40)
+	result := debugOverlay(ring, BackendStatus{}, 80, 40)
 
 	if !strings.Contains(result, "Pipeline Stats") {
 		t.Error("overlay should contain 'Pipeline Stats' header")
@@ -46,7 +46,7 @@ func TestDebugOverlayRecentEvents(t *testing.T) {
 	ring.Push(otel.Event{Kind: otel.KindFetchError, Time: time.Now(), Err: "timeout"})
 	ring.Push(otel.Event{Kind: otel.KindSearchStart, Time: time.Now(), QueryID: "abcdef1234567890"})
 
-	result := debugOverlay(ring, 80, 40)
+	result := debugOverlay(ring, BackendStatus{}, 80, 40)
 
 	if !strings.Contains(result, "Recent Events") {
 		t.Error("overlay should contain 'Recent Events' header")
@@ -70,7 +70,7 @@ func TestDebugOverlayTruncation(t *testing.T) {
 	}
 
 	// Very small height should still render without panic
-	result := debugOverlay(ring, 80, 10)
+	result := debugOverlay(ring, BackendStatus{}, 80, 10)
 	if result == "" {
 		t.Error("overlay should still render with small height")
 	}
@@ -145,3 +145,22 @@ func TestFormatAgeNegative(t *testing.T) {
 		t.Errorf("formatAge(-5s) = %q, want \"0ms\"", got)
 	}
 }
+
+func TestDebugOverlayRendersBackendChain(t *testing.T) {
+	ring := otel.NewRingBuffer(64)
+	b := BackendStatus{
+		Embedder: "local/hash-ngram-v1",
+		Degraded: true,
+		Chain: []BackendHealth{
+			{Name: "jina/jina-embeddings-v3", Err: "jina returned status 503"},
+			{Name: "local/hash-ngram-v1", Healthy: true, Active: true},
+		},
+	}
+	result := debugOverlay(ring, b, 100, 60)
+
+	for _, want := range []string{"Backends", "fallback active", "cosine only", "down  ERR:jina returned", "ok, active"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("overlay should contain %q, got:\n%s", want, result)
+		}
+	}
+}
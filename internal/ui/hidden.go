@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// withHidden merges the items the filters dropped into the feed by
+// publish time, returning the merged list, each dropped item's reason
+// by ID, and cursor moved to the same visible item. Dropped items older
+// than the whole feed (max_age's, mostly) aren't inline and are left out,
+// as are those already listed (near-duplicates of an expanded item).
+func withHidden(items []store.Item, hidden []filter.Decision, cursor int) ([]store.Item, map[string]string, int) {
+	if len(hidden) == 0 || len(items) == 0 {
+		return items, nil, cursor
+	}
+	listed := make(map[string]bool, len(items))
+	for _, item := range items {
+		listed[item.ID] = true
+	}
+	dropped := make([]filter.Decision, 0, len(hidden))
+	for _, d := range hidden {
+		if !listed[d.Item.ID] {
+			dropped = append(dropped, d)
+		}
+	}
+	sort.SliceStable(dropped, func(i, j int) bool {
+		return dropped[i].Item.Published.After(dropped[j].Item.Published)
+	})
+
+	merged := make([]store.Item, 0, len(items)+len(dropped))
+	reasons := make(map[string]string)
+	mergedCursor := 0
+	next := 0
+	for i, item := range items {
+		for ; next < len(dropped) && dropped[next].Item.Published.After(item.Published); next++ {
+			d := dropped[next]
+			merged = append(merged, d.Item)
+			reasons[d.Item.ID] = d.Stage + ": " + d.Reason
+		}
+		if i == cursor {
+			mergedCursor = len(merged)
+		}
+		merged = append(merged, item)
+	}
+	return merged, reasons, mergedCursor
+}
+
+// renderHiddenLine renders a dropped item dimmed, with why.
+func renderHiddenLine(item store.Item, reason string, width int) string {
+	line := fmt.Sprintf("%s · %s — %s", item.SourceName, item.Title, reason)
+	return HiddenItem.Render(truncateRunes(line, width-2))
+}
+
+// renderHiddenBar renders the bar shown while dropped items are.
+func renderHiddenBar(inline, width int) string {
+	prompt := FilterBarPrompt.Render("Hidden:")
+	text := FilterBarText.Render(fmt.Sprintf(" %d items dropped by filters, dimmed with why", inline))
+	hints := FilterBarCount.Render("H:hide")
+	content := prompt + text
+	padding := width - lipgloss.Width(content) - lipgloss.Width(hints) - 2
+	if padding < 1 {
+		padding = 1
+	}
+	return FilterBar.Width(width).Render(content + strings.Repeat(" ", padding) + hints)
+}
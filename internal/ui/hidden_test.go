@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAppShowHidden(t *testing.T) {
+	now := time.Now()
+	app := NewAppWithConfig(AppConfig{})
+	model, _ := app.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	app = model.(App)
+	model, _ = app.Update(ItemsLoaded{
+		Items: []store.Item{
+			{ID: "1", Title: "Rates held", SourceName: "Wire", Published: now.Add(-time.Minute)},
+			{ID: "3", Title: "Cup final", SourceName: "Sport", Published: now.Add(-3 * time.Minute)},
+		},
+		Hidden: []filter.Decision{
+			{Item: store.Item{ID: "2", Title: "Bank keeps rates", SourceName: "Blog", Published: now.Add(-2 * time.Minute)}, Stage: "semantic_dedup", Reason: `near-duplicate of "Rates held" (0.91)`, DuplicateOf: "1"},
+			{Item: store.Item{ID: "9", Title: "Last week", SourceName: "Wire", Published: now.Add(-200 * time.Hour)}, Stage: "max_age", Reason: "older than 24h"},
+		},
+	})
+	app = model.(App)
+	app.loading = false
+	app.cursor = 1
+
+	if strings.Contains(app.View(), "Bank keeps rates") {
+		t.Fatal("dropped items shown before H")
+	}
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	app = model.(App)
+	view := app.View()
+	for _, want := range []string{"Bank keeps rates", `semantic_dedup: near-duplicate of "Rates held" (0.91)`, "1 items dropped by filters"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "Last week") {
+		t.Error("items older than the feed should not be shown inline")
+	}
+	if i, j := strings.Index(view, "Rates held"), strings.Index(view, "Bank keeps rates"); i > j {
+		t.Error("dropped item should sit between the visible ones by publish time")
+	}
+
+	// Navigation still moves over the feed's own items.
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	app = model.(App)
+	if app.cursor != 0 {
+		t.Errorf("cursor = %d, want 0", app.cursor)
+	}
+
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	app = model.(App)
+	if strings.Contains(app.View(), "Bank keeps rates") {
+		t.Error("H again should hide the dropped items")
+	}
+}
+
+func TestWithHidden(t *testing.T) {
+	now := time.Now()
+	items := []store.Item{
+		{ID: "a", Published: now.Add(-1 * time.Minute)},
+		{ID: "b", Published: now.Add(-5 * time.Minute)},
+	}
+	hidden := []filter.Decision{
+		{Item: store.Item{ID: "y", Published: now.Add(-3 * time.Minute)}, Stage: "dedup", Reason: "r"},
+		{Item: store.Item{ID: "x", Published: now}, Stage: "rules", Reason: "r"},
+	}
+	merged, reasons, cursor := withHidden(items, hidden, 1)
+	ids := make([]string, len(merged))
+	for i, item := range merged {
+		ids[i] = item.ID
+	}
+	if strings.Join(ids, ",") != "x,a,y,b" {
+		t.Errorf("merged %v, want x,a,y,b", ids)
+	}
+	if cursor != 3 || len(reasons) != 2 || reasons["x"] != "rules: r" {
+		t.Errorf("cursor %d, reasons %v", cursor, reasons)
+	}
+}
@@ -0,0 +1,264 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abelbrown/observer/internal/interest"
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// interestHeaderLines is how many lines of the interest panel come
+// before the contributing items: title, summary, order, blank, columns.
+const interestHeaderLines = 5
+
+// signalKinds is the order signal counts are listed in.
+var signalKinds = []string{store.SignalRead, store.SignalSaved, store.SignalMoreLikeThis, store.SignalExcluded}
+
+// signalLabel is how a signal kind reads in the interest panel.
+func signalLabel(kind string) string {
+	if kind == store.SignalMoreLikeThis {
+		return "more like"
+	}
+	return kind
+}
+
+// toggleSaved saves or unsaves the item under the cursor; saving counts
+// towards the interest profile.
+func (a App) toggleSaved() (tea.Model, tea.Cmd) {
+	if a.markSaved == nil || a.cursor >= len(a.items) {
+		return a, nil
+	}
+	item := &a.items[a.cursor]
+	item.Saved = !item.Saved
+	return a, a.markSaved(item.ID, item.Saved)
+}
+
+// lessLikeThis dismisses the item under the cursor: it leaves the feed
+// at once and pulls the profile's negative centroid towards it.
+func (a App) lessLikeThis() (tea.Model, tea.Cmd) {
+	if a.recordSignal == nil || a.cursor >= len(a.items) {
+		return a, nil
+	}
+	id := a.items[a.cursor].ID
+	a.items = withoutItem(a.items, id)
+	a.belowCut = withoutItem(a.belowCut, id)
+	if a.savedItems != nil {
+		a.savedItems = withoutItem(a.savedItems, id)
+	}
+	if a.cursor >= len(a.items) {
+		a.cursor = len(a.items) - 1
+	}
+	if a.cursor < 0 {
+		a.cursor = 0
+	}
+	return a, a.recordSignal(id, store.SignalExcluded)
+}
+
+// withoutItem returns a copy of items without id.
+func withoutItem(items []store.Item, id string) []store.Item {
+	result := make([]store.Item, 0, len(items))
+	for _, item := range items {
+		if item.ID != id {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// itemNotes returns the notes shown after titles: cluster notes, with
+// saved items starred.
+func (a App) itemNotes() map[string]string {
+	notes := a.clusterNotes()
+	for _, item := range a.items {
+		if !item.Saved {
+			continue
+		}
+		if notes == nil {
+			notes = make(map[string]string)
+		}
+		if note := notes[item.ID]; note != "" {
+			notes[item.ID] = "★ " + note
+		} else {
+			notes[item.ID] = "★ saved"
+		}
+	}
+	return notes
+}
+
+// toggleInterestPanel opens the interest panel, loading the profile, or
+// closes it.
+func (a App) toggleInterestPanel() (tea.Model, tea.Cmd) {
+	if a.loadInterest == nil {
+		return a, nil
+	}
+	a.interestVisible = !a.interestVisible
+	if !a.interestVisible {
+		return a, nil
+	}
+	a.interestCursor, a.interestConfirm, a.interestLoaded = 0, false, false
+	return a, a.loadInterest()
+}
+
+func (a App) handleInterestKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	confirm := a.interestConfirm
+	a.interestConfirm = false
+	contributions := a.interest.Contributions
+
+	switch key {
+	case "esc", "I", "q":
+		a.interestVisible = false
+	case "j", "down":
+		if a.interestCursor < len(contributions)-1 {
+			a.interestCursor++
+		}
+	case "k", "up":
+		if a.interestCursor > 0 {
+			a.interestCursor--
+		}
+	case "d", "x", "delete":
+		if a.forgetSignal != nil && a.interestCursor < len(contributions) {
+			c := contributions[a.interestCursor]
+			return a, a.forgetSignal(c.Item.ID, c.Kind)
+		}
+	case "o":
+		a.interestOrder = !a.interestOrder
+		if a.loadItems != nil {
+			a.loading = true
+			return a, a.loadItems()
+		}
+	case "R":
+		if a.resetInterest == nil || len(contributions) == 0 {
+			return a, nil
+		}
+		if !confirm {
+			a.interestConfirm = true
+			return a, nil
+		}
+		return a, a.resetInterest()
+	}
+	return a, nil
+}
+
+// byInterestWithinBands orders each time band by interest score, best
+// first; items without a score follow in their original order. items
+// are newest first.
+func byInterestWithinBands(items []store.Item, scores map[string]float64) []store.Item {
+	if len(scores) == 0 {
+		return items
+	}
+	out := append([]store.Item(nil), items...)
+	for start := 0; start < len(out); {
+		band := TimeBand(out[start].Published)
+		end := start + 1
+		for end < len(out) && TimeBand(out[end].Published) == band {
+			end++
+		}
+		group := out[start:end]
+		sort.SliceStable(group, func(i, j int) bool {
+			si, iok := scores[group[i].ID]
+			sj, jok := scores[group[j].ID]
+			if iok != jok {
+				return iok
+			}
+			return si > sj
+		})
+		start = end
+	}
+	return out
+}
+
+// interestOverlay renders the interest panel: what the profile was
+// learned from, how the feed uses it, and every contributing item with
+// its decayed weight.
+func interestOverlay(p interest.Profile, loaded, order, confirm bool, cursor, width, height int) string {
+	panelWidth := 100
+	if panelWidth > width-4 {
+		panelWidth = width - 4
+	}
+	if panelWidth < 20 {
+		panelWidth = 20
+	}
+	textWidth := panelWidth - 6 // border and padding
+
+	lines := []string{DebugHeaderStyle.Render("Interest profile")}
+	if !loaded {
+		lines = append(lines, "", "  Loading…")
+		return DebugPanel.Width(panelWidth).Render(strings.Join(lines, "\n"))
+	}
+
+	counts := p.Counts()
+	var parts []string
+	for _, kind := range signalKinds {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], signalLabel(kind)))
+		}
+	}
+	summary := "Learned on this machine from nothing yet"
+	if len(parts) > 0 {
+		summary = "Learned on this machine from " + strings.Join(parts, ", ")
+	}
+	summary += fmt.Sprintf(" · half-life %s", threadSpan(interest.HalfLife))
+	if p.Model != "" {
+		summary += " · " + p.Model
+	}
+	lines = append(lines, truncateRunes(summary, textWidth))
+
+	switch {
+	case confirm:
+		lines = append(lines, fmt.Sprintf("Press R again to forget all %d signals.", len(p.Contributions)))
+	case order:
+		lines = append(lines, "Feed: each time band ordered by interest (o: newest first)")
+	default:
+		lines = append(lines, "Feed: newest first (o: order each time band by interest)")
+	}
+	lines = append(lines, "")
+
+	if len(p.Contributions) == 0 {
+		lines = append(lines,
+			"  Nothing yet. Opening (Enter), saving (s), more like this (m) and",
+			"  less like this (-) teach it; every item it learns from is listed here.")
+		return DebugPanel.Width(panelWidth).Render(strings.Join(lines, "\n"))
+	}
+
+	lines = append(lines, fmt.Sprintf("  %7s  %-9s %4s  %-14s %s", "WEIGHT", "SIGNAL", "AGO", "SOURCE", "ITEM"))
+	now := time.Now()
+	var rows []string
+	for i, c := range p.Contributions {
+		marker := "  "
+		if i == cursor {
+			marker = "▸ "
+		}
+		weight := fmt.Sprintf("%+7.2f", c.Weight)
+		title := c.Item.Title
+		if !c.Embedded {
+			weight = fmt.Sprintf("%7s", "—")
+			title += " (not embedded yet)"
+		}
+		row := fmt.Sprintf("%s%s  %-9s %4s  %-14s %s", marker, weight, signalLabel(c.Kind),
+			threadSpan(now.Sub(c.At)), truncateRunes(c.Item.SourceName, 14), title)
+		rows = append(rows, truncateRunes(row, textWidth))
+	}
+
+	// Keep the selected item in view.
+	room := max(height-debugPanelChrome-interestHeaderLines, 1)
+	start := min(max(cursor-room+1, 0), max(len(rows)-room, 0))
+	lines = append(lines, rows[start:min(start+room, len(rows))]...)
+	return DebugPanel.Width(panelWidth).Render(strings.Join(lines, "\n"))
+}
+
+// interestStatusBar renders the status bar for the interest panel.
+func interestStatusBar(width int) string {
+	keys := []string{
+		StatusBarKey.Render("j/k") + StatusBarText.Render(":nav"),
+		StatusBarKey.Render("d") + StatusBarText.Render(":forget"),
+		StatusBarKey.Render("o") + StatusBarText.Render(":order"),
+		StatusBarKey.Render("R") + StatusBarText.Render(":reset"),
+		StatusBarKey.Render("I") + StatusBarText.Render(":close"),
+	}
+	return StatusBar.Width(width).Render("  [INTEREST]  " + strings.Join(keys, " "))
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/interest"
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func keyRune(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestAppInterestPanel(t *testing.T) {
+	now := time.Now()
+	var forgot, reset, reloaded int
+	var forgotten string
+	app := NewAppWithConfig(AppConfig{
+		LoadItems: func() tea.Cmd {
+			reloaded++
+			return func() tea.Msg { return nil }
+		},
+		LoadInterest: func() tea.Cmd { return func() tea.Msg { return nil } },
+		ForgetSignal: func(itemID, kind string) tea.Cmd {
+			forgot++
+			forgotten = itemID + "/" + kind
+			return func() tea.Msg { return InterestChanged{} }
+		},
+		ResetInterest: func() tea.Cmd {
+			reset++
+			return func() tea.Msg { return InterestChanged{} }
+		},
+	})
+	model, _ := app.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	app = model.(App)
+
+	model, cmd := app.Update(keyRune("I"))
+	app = model.(App)
+	if !app.interestVisible || cmd == nil {
+		t.Fatal("I should open the interest panel and load the profile")
+	}
+	if !strings.Contains(app.View(), "Loading") {
+		t.Error("panel should show loading until the profile arrives")
+	}
+
+	model, _ = app.Update(InterestLoaded{Profile: interest.Profile{
+		Model: "m",
+		Contributions: []interest.Contribution{
+			{Signal: store.Signal{Item: store.Item{ID: "a", Title: "Fusion milestone", SourceName: "Wire"}, Kind: store.SignalSaved, At: now.Add(-2 * time.Hour)}, Weight: 2.99, Embedded: true},
+			{Signal: store.Signal{Item: store.Item{ID: "b", Title: "Celebrity gossip", SourceName: "Blog"}, Kind: store.SignalExcluded, At: now}, Weight: -1, Embedded: true},
+			{Signal: store.Signal{Item: store.Item{ID: "c", Title: "Fresh story", SourceName: "Wire"}, Kind: store.SignalRead, At: now}},
+		},
+	}})
+	app = model.(App)
+	view := app.View()
+	for _, want := range []string{"Interest profile", "1 read, 1 saved, 1 excluded", "half-life 14d", "+2.99", "-1.00", "Celebrity gossip", "Fresh story (not embedded yet)", "Feed: newest first"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("interest view missing %q:\n%s", want, view)
+		}
+	}
+
+	// d forgets the selected item's signal.
+	model, _ = app.Update(keyRune("j"))
+	app = model.(App)
+	model, cmd = app.Update(keyRune("d"))
+	app = model.(App)
+	if forgot != 1 || forgotten != "b/excluded" {
+		t.Fatalf("d forgot %q (%d calls), want b/excluded", forgotten, forgot)
+	}
+	model, _ = app.Update(cmd())
+	app = model.(App)
+
+	// o orders the feed by interest, reloading it; later changes reload it too.
+	model, _ = app.Update(keyRune("o"))
+	app = model.(App)
+	if !app.interestOrder || reloaded != 1 || !strings.Contains(app.View(), "ordered by interest") {
+		t.Errorf("o should order the feed by interest and reload it (reloads: %d)", reloaded)
+	}
+
+	// Reset needs R twice.
+	model, _ = app.Update(keyRune("R"))
+	app = model.(App)
+	if reset != 0 || !strings.Contains(app.View(), "Press R again to forget all 3 signals") {
+		t.Fatalf("first R should ask for confirmation (resets: %d)", reset)
+	}
+	model, cmd = app.Update(keyRune("R"))
+	app = model.(App)
+	if reset != 1 {
+		t.Fatal("second R should reset the profile")
+	}
+	model, _ = app.Update(cmd())
+	app = model.(App)
+	if reloaded != 2 {
+		t.Errorf("reset should reload the interest-ordered feed (reloads: %d)", reloaded)
+	}
+
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app = model.(App)
+	if app.interestVisible {
+		t.Error("Esc should close the interest panel")
+	}
+}
+
+func TestAppInterestSignals(t *testing.T) {
+	now := time.Now()
+	var recorded []string
+	var saved []bool
+	app := NewAppWithConfig(AppConfig{
+		RecordSignal: func(itemID, kind string) tea.Cmd {
+			recorded = append(recorded, itemID+"/"+kind)
+			return func() tea.Msg { return InterestChanged{} }
+		},
+		MarkSaved: func(id string, s bool) tea.Cmd {
+			saved = append(saved, s)
+			return func() tea.Msg { return InterestChanged{} }
+		},
+		Features: Features{MLT: true},
+	})
+	model, _ := app.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	app = model.(App)
+	model, _ = app.Update(ItemsLoaded{
+		Items: []store.Item{
+			{ID: "a", Title: "Gossip", SourceName: "Blog", Published: now},
+			{ID: "b", Title: "Fusion milestone", SourceName: "Wire", Published: now.Add(-time.Minute)},
+			{ID: "c", Title: "Reactor design", SourceName: "Lab", Published: now.Add(-2 * time.Minute)},
+		},
+		Embeddings:     map[string][]float32{"b": {1, 0}, "c": {0.9, 0.1}},
+		EmbeddingModel: "m",
+	})
+	app = model.(App)
+	app.loading = false
+
+	model, _ = app.Update(keyRune("-"))
+	app = model.(App)
+	if len(app.items) != 2 || app.items[0].ID != "b" {
+		t.Fatalf("- should drop the item from the feed, got %v", app.items)
+	}
+
+	model, _ = app.Update(keyRune("s"))
+	app = model.(App)
+	if len(saved) != 1 || !saved[0] || !app.items[0].Saved || !strings.Contains(app.View(), "★ saved") {
+		t.Errorf("s should save and star the item (saved: %v)", saved)
+	}
+
+	model, _ = app.Update(keyRune("m"))
+	app = model.(App)
+	if strings.Join(recorded, ",") != "a/excluded,b/mlt" {
+		t.Errorf("recorded %v, want a/excluded then b/mlt", recorded)
+	}
+}
+
+func TestByInterestWithinBands(t *testing.T) {
+	now := time.Now()
+	items := []store.Item{
+		{ID: "a", Published: now},
+		{ID: "b", Published: now.Add(-time.Minute)},
+		{ID: "c", Published: now.Add(-2 * time.Minute)},
+		{ID: "old", Published: now.Add(-72 * time.Hour)},
+		{ID: "older", Published: now.Add(-73 * time.Hour)},
+	}
+	scores := map[string]float64{"b": 0.1, "c": 0.4, "older": -0.2, "old": -0.5}
+	var ids []string
+	for _, item := range byInterestWithinBands(items, scores) {
+		ids = append(ids, item.ID)
+	}
+	if strings.Join(ids, ",") != "c,b,a,older,old" {
+		t.Errorf("byInterestWithinBands = %v, want c,b,a,older,old", ids)
+	}
+	if items[0].ID != "a" {
+		t.Error("byInterestWithinBands reordered its input")
+	}
+}
@@ -1,13 +1,25 @@
 // Package ui provides the Bubble Tea TUI for Observer.
 package ui
 
-import "github.com/abelbrown/observer/internal/store"
+import (
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/interest"
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/thread"
+)
 
 // ItemsLoaded is sent when items are fetched from the store.
 type ItemsLoaded struct {
-	Items      []store.Item
-	Embeddings map[string][]float32
-	Err        error
+	Items          []store.Item
+	Embeddings     map[string][]float32
+	EmbeddingModel string                      // model of every vector in Embeddings
+	Chunks         map[string][]store.Chunk    // passage vectors of long items, same model
+	Boosted        map[string]bool             // items a boost rule matched, listed first in their time band
+	Hidden         []filter.Decision           // items the filter pipeline dropped, and why
+	Similar        map[string][]filter.Similar // kept item ID -> near-duplicates folded into it
+	Trends         []thread.Trend              // stories many sources just took up, fastest first
+	Interest       map[string]float64          // item ID -> interest score (interest.Profile.Score)
+	Err            error
 }
 
 // ItemMarkedRead is sent when an item is marked as read.
@@ -15,6 +27,52 @@ type ItemMarkedRead struct {
 	ID string
 }
 
+// SourceMuted is sent when a source mute has been persisted.
+type SourceMuted struct {
+	Source string
+	Err    error
+}
+
+// RulesLoaded carries the feed rules with today's hidden counts.
+type RulesLoaded struct {
+	Rules []store.Rule
+	Err   error
+}
+
+// RuleAdded is sent when a rule has been persisted.
+type RuleAdded struct {
+	Rule store.Rule
+	Err  error
+}
+
+// RuleDeleted is sent when a rule has been removed.
+type RuleDeleted struct {
+	ID  int64
+	Err error
+}
+
+// ThreadLoaded carries the story thread of ItemID, nil if it has none,
+// with the thread's items oldest first.
+type ThreadLoaded struct {
+	ItemID string
+	Thread *store.Thread
+	Items  []store.Item
+	Err    error
+}
+
+// InterestLoaded carries the interest profile for the feed's embedding
+// model.
+type InterestLoaded struct {
+	Profile interest.Profile
+	Err     error
+}
+
+// InterestChanged is sent when an interest signal has been recorded or
+// forgotten, an item saved or unsaved, or the profile reset.
+type InterestChanged struct {
+	Err error
+}
+
 // FetchComplete is sent when background fetch finishes.
 type FetchComplete struct {
 	Source   string
@@ -32,6 +90,7 @@ type ShimmerTick struct{}
 type QueryEmbedded struct {
 	Query     string
 	Embedding []float32
+	Model     string // model that produced Embedding
 	QueryID   string // search correlation ID
 	Err       error
 }
@@ -39,25 +98,52 @@ type QueryEmbedded struct {
 // EntryReranked is sent when a single entry has been scored by the cross-encoder.
 // Used for package-manager style progress feedback.
 type EntryReranked struct {
-	ItemID  string  // Item ID for lookup in rerankEntries
-	Score   float32 // Relevance score in [0, 1]
-	QueryID string  // search correlation ID
-	Err     error
+	ItemID   string  // Item ID for lookup in rerankEntries
+	Score    float32 // Relevance score in [0, 1]
+	QueryID  string  // search correlation ID
+	Reranker string  // name of the reranker that produced Score
+	Err      error
 }
 
 // RerankComplete is sent when batch reranking finishes (Jina API path).
 // Contains all scores at once, unlike EntryReranked which arrives one at a time.
 type RerankComplete struct {
-	Query   string    // query that was reranked (for stale-check)
-	Scores  []float32 // score per entry, indexed by rerankEntries position
-	QueryID string    // search correlation ID
-	Err     error
+	Query    string    // query that was reranked (for stale-check)
+	Scores   []float32 // score per document sent, in the order sent
+	QueryID  string    // search correlation ID
+	Reranker string    // name of the reranker that produced Scores
+	Err      error
 }
 
 // SearchPoolLoaded is sent when the full item pool for search is ready.
 type SearchPoolLoaded struct {
-	Items      []store.Item
-	Embeddings map[string][]float32
-	QueryID    string // search correlation ID
-	Err        error
+	Items          []store.Item
+	Embeddings     map[string][]float32
+	EmbeddingModel string                      // model of every vector in Embeddings
+	Chunks         map[string][]store.Chunk    // passage vectors of long items, same model
+	Similar        map[string][]filter.Similar // kept item ID -> near-duplicates folded into it
+	QueryID        string                      // search correlation ID
+	Err            error
+}
+
+// BackendStatus describes the AI backends in use, for the status bar and
+// debug overlay.
+type BackendStatus struct {
+	Embedder string          // model embedding search queries, e.g. "jina/jina-embeddings-v3"
+	Reranker string          // active reranker; "" for none
+	Degraded bool            // a fallback is serving instead of a preferred backend
+	Chain    []BackendHealth // embedding backends, most preferred first
+}
+
+// BackendHealth is one embedding backend in the fallback chain.
+type BackendHealth struct {
+	Name    string
+	Healthy bool
+	Active  bool   // served the most recent call
+	Err     string // last failure, if any
+}
+
+// BackendChanged is sent when a fallback chain switches backends.
+type BackendChanged struct {
+	Status BackendStatus
 }
@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abelbrown/observer/internal/filter"
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// semanticRuleThreshold is the cosine similarity to the example above
+// which a "similar" rule matches; above the dedup threshold's neighbours,
+// below near-duplicates.
+const semanticRuleThreshold = 0.8
+
+// ruleKinds is the order Tab cycles kinds in the rule composer.
+var ruleKinds = []string{store.RuleSource, store.RuleAuthor, store.RuleKeyword, store.RuleRegex, store.RuleSemantic}
+
+// ruleActions is the order ↑/↓ cycle actions in the rule composer.
+var ruleActions = []string{store.RuleMute, store.RuleBoost, store.RuleAllow}
+
+// ruleKindLabel is how a rule kind reads in the composer and panel.
+func ruleKindLabel(kind string) string {
+	if kind == store.RuleSemantic {
+		return "similar"
+	}
+	return kind
+}
+
+// openRuleComposer starts a rule for the item under the cursor: mute its
+// source, until Tab and ↑/↓ pick another kind and action.
+func (a App) openRuleComposer() (tea.Model, tea.Cmd) {
+	if a.addRule == nil || len(a.items) == 0 || a.cursor >= len(a.items) {
+		return a, nil
+	}
+	a.ruleItem = a.items[a.cursor]
+	a.ruleDraft = store.Rule{Action: store.RuleMute}
+	a.setRuleKind(store.RuleSource)
+	a.pushMode(ModeRule)
+	return a, a.ruleInput.Focus()
+}
+
+// ruleKindAvailable reports whether the selected item can seed a rule of
+// kind: author rules need an author, similar rules its vector.
+func (a App) ruleKindAvailable(kind string) bool {
+	switch kind {
+	case store.RuleAuthor:
+		return strings.TrimSpace(a.ruleItem.Author) != ""
+	case store.RuleSemantic:
+		return a.embeddingModel != "" && len(a.embeddings[a.ruleItem.ID]) > 0
+	}
+	return true
+}
+
+// setRuleKind switches the draft to kind, prefilling the pattern from
+// the selected item.
+func (a *App) setRuleKind(kind string) {
+	a.ruleDraft.Kind = kind
+	a.ruleInput.Placeholder = ""
+	switch kind {
+	case store.RuleSource:
+		a.ruleInput.SetValue(a.ruleItem.SourceName)
+	case store.RuleAuthor:
+		a.ruleInput.SetValue(strings.TrimSpace(a.ruleItem.Author))
+	case store.RuleKeyword:
+		a.ruleInput.SetValue("")
+		a.ruleInput.Placeholder = "word or phrase"
+	case store.RuleRegex:
+		a.ruleInput.SetValue("")
+		a.ruleInput.Placeholder = "regular expression (title and summary)"
+	case store.RuleSemantic:
+		a.ruleInput.SetValue(a.ruleItem.Title)
+	}
+	a.ruleInput.CursorEnd()
+}
+
+// cycleRuleKind moves the draft to the next (step 1) or previous (-1)
+// kind the selected item supports.
+func (a *App) cycleRuleKind(step int) {
+	i := indexOf(ruleKinds, a.ruleDraft.Kind)
+	for range ruleKinds {
+		i = (i + step + len(ruleKinds)) % len(ruleKinds)
+		if a.ruleKindAvailable(ruleKinds[i]) {
+			a.setRuleKind(ruleKinds[i])
+			return
+		}
+	}
+}
+
+func indexOf(values []string, v string) int {
+	for i, x := range values {
+		if x == v {
+			return i
+		}
+	}
+	return 0
+}
+
+func (a App) handleRuleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		a.ruleInput.Blur()
+		a.popMode(ModeList)
+		return a, nil
+	case tea.KeyEnter:
+		return a.saveRule()
+	case tea.KeyTab:
+		a.cycleRuleKind(1)
+		return a, nil
+	case tea.KeyShiftTab:
+		a.cycleRuleKind(-1)
+		return a, nil
+	case tea.KeyUp, tea.KeyDown:
+		step := 1
+		if msg.Type == tea.KeyUp {
+			step = -1
+		}
+		i := indexOf(ruleActions, a.ruleDraft.Action)
+		a.ruleDraft.Action = ruleActions[(i+step+len(ruleActions))%len(ruleActions)]
+		return a, nil
+	}
+	if a.ruleDraft.Kind == store.RuleSemantic {
+		return a, nil // the example is the item; its title is only a label
+	}
+	var cmd tea.Cmd
+	a.ruleInput, cmd = a.ruleInput.Update(msg)
+	return a, cmd
+}
+
+// saveRule persists the draft. Items a mute rule matches disappear
+// immediately, as with "S"; the reload after RuleAdded settles the rest
+// (boosts, allows overriding other mutes).
+func (a App) saveRule() (tea.Model, tea.Cmd) {
+	rule := a.ruleDraft
+	rule.Pattern = strings.TrimSpace(a.ruleInput.Value())
+	if rule.Kind == store.RuleSemantic {
+		rule.ExampleID = a.ruleItem.ID
+		rule.Embedding = a.embeddings[a.ruleItem.ID]
+		rule.Model = a.embeddingModel
+		rule.Threshold = semanticRuleThreshold
+	}
+	if err := rule.Validate(); err != nil {
+		a.err = err
+		return a, nil
+	}
+	a.ruleInput.Blur()
+	a.popMode(ModeList)
+
+	if rule.Action == store.RuleMute {
+		if rs, err := filter.NewRuleSet([]store.Rule{rule}, a.embeddingModel); err == nil {
+			a.items, _ = rs.Apply(a.items, a.embeddings)
+			a.belowCut, _ = rs.Apply(a.belowCut, a.embeddings)
+			if a.savedItems != nil {
+				a.savedItems, _ = rs.Apply(a.savedItems, a.savedEmbeddings)
+			}
+			if a.cursor >= len(a.items) {
+				a.cursor = len(a.items) - 1
+			}
+			if a.cursor < 0 {
+				a.cursor = 0
+			}
+		}
+	}
+	return a, a.addRule(rule)
+}
+
+// renderRuleInput renders the rule composer bar.
+func (a App) renderRuleInput() string {
+	prompt := FilterBarPrompt.Render(fmt.Sprintf("%s %s:", a.ruleDraft.Action, ruleKindLabel(a.ruleDraft.Kind)))
+	hints := FilterBarCount.Render("Tab:kind ↑↓:action Enter:save Esc:cancel")
+	text := a.ruleInput.View()
+	if a.ruleDraft.Kind == store.RuleSemantic {
+		text = FilterBarText.Render(truncateRunes(a.ruleInput.Value(), 50))
+	}
+	content := prompt + " " + text
+	padding := a.width - lipgloss.Width(content) - lipgloss.Width(hints) - 2
+	if padding < 1 {
+		padding = 1
+	}
+	return FilterBar.Width(a.width).Render(content + strings.Repeat(" ", padding) + hints)
+}
+
+// toggleRulesPanel opens the rules panel, loading the rules with their
+// counts, or closes it.
+func (a App) toggleRulesPanel() (tea.Model, tea.Cmd) {
+	if a.loadRules == nil {
+		return a, nil
+	}
+	a.rulesVisible = !a.rulesVisible
+	if !a.rulesVisible {
+		return a, nil
+	}
+	a.ruleCursor = 0
+	return a, a.loadRules()
+}
+
+func (a App) handleRulesPanelKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "U", "q":
+		a.rulesVisible = false
+	case "j", "down":
+		if a.ruleCursor < len(a.rules)-1 {
+			a.ruleCursor++
+		}
+	case "k", "up":
+		if a.ruleCursor > 0 {
+			a.ruleCursor--
+		}
+	case "d", "x", "delete":
+		if a.deleteRule != nil && a.ruleCursor < len(a.rules) {
+			return a, a.deleteRule(a.rules[a.ruleCursor].ID)
+		}
+	}
+	return a, nil
+}
+
+// rulesOverlay renders the rules panel: every rule with what it matches
+// and how many items it hid today.
+func rulesOverlay(rules []store.Rule, cursor, width, height int) string {
+	lines := []string{DebugHeaderStyle.Render("Rules"), ""}
+	if len(rules) == 0 {
+		lines = append(lines, "  No rules. Press u on an item to mute, boost or allow")
+		lines = append(lines, "  its source, author, a keyword, a regex or similar stories.")
+	}
+	for i, r := range rules {
+		pattern := r.Pattern
+		switch r.Kind {
+		case store.RuleSemantic:
+			pattern = fmt.Sprintf("%q ≥%.2f", truncateRunes(pattern, 24), r.Threshold)
+		case store.RuleKeyword:
+			pattern = fmt.Sprintf("%q", pattern)
+		case store.RuleRegex:
+			pattern = "/" + pattern + "/"
+		}
+		hidden := ""
+		if r.Action == store.RuleMute {
+			hidden = fmt.Sprintf("%d hidden today", r.HiddenToday)
+		}
+		marker := "  "
+		if i == cursor {
+			marker = "▸ "
+		}
+		lines = append(lines, fmt.Sprintf("%s%-6s %-8s %-34s %s", marker, r.Action, ruleKindLabel(r.Kind), truncateRunes(pattern, 34), hidden))
+	}
+
+	maxHeight := height - debugPanelChrome
+	if maxHeight < 1 {
+		maxHeight = 1
+	}
+	if len(lines) > maxHeight {
+		// Keep the selected rule in view.
+		start := cursor + 2 - maxHeight + 1
+		if start < 0 {
+			start = 0
+		}
+		lines = lines[start : start+maxHeight]
+	}
+
+	panelWidth := 76
+	if panelWidth > width-4 {
+		panelWidth = width - 4
+	}
+	if panelWidth < 20 {
+		panelWidth = 20
+	}
+	return DebugPanel.Width(panelWidth).Render(strings.Join(lines, "\n"))
+}
+
+// rulesStatusBar renders the status bar for the rules panel.
+func rulesStatusBar(width int) string {
+	keys := []string{
+		StatusBarKey.Render("j/k") + StatusBarText.Render(":nav"),
+		StatusBarKey.Render("d") + StatusBarText.Render(":delete"),
+		StatusBarKey.Render("U") + StatusBarText.Render(":close"),
+	}
+	return StatusBar.Width(width).Render("  [RULES]  " + strings.Join(keys, " "))
+}
+
+// boostWithinBands moves items a boost rule matched ahead of the others
+// in their time band, keeping each group's order. items are newest first.
+func boostWithinBands(items []store.Item, boosted map[string]bool) []store.Item {
+	if len(boosted) == 0 {
+		return items
+	}
+	out := make([]store.Item, 0, len(items))
+	for start := 0; start < len(items); {
+		band := TimeBand(items[start].Published)
+		end := start + 1
+		for end < len(items) && TimeBand(items[end].Published) == band {
+			end++
+		}
+		for _, item := range items[start:end] {
+			if boosted[item.ID] {
+				out = append(out, item)
+			}
+		}
+		for _, item := range items[start:end] {
+			if !boosted[item.ID] {
+				out = append(out, item)
+			}
+		}
+		start = end
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAppRuleComposer(t *testing.T) {
+	var added []store.Rule
+	app := NewAppWithConfig(AppConfig{
+		AddRule: func(rule store.Rule) tea.Cmd {
+			added = append(added, rule)
+			return func() tea.Msg { return RuleAdded{Rule: rule} }
+		},
+	})
+	app.items = []store.Item{
+		{ID: "1", Title: "Crypto exchange fails", SourceName: "Wire"},
+		{ID: "2", Title: "Rates held", SourceName: "Wire", Author: "Jane Doe"},
+		{ID: "3", Title: "Crypto winter deepens", SourceName: "Blog"},
+	}
+	app.embeddings = map[string][]float32{"2": {1, 0}}
+	app.embeddingModel = "m"
+	app.cursor = 1
+
+	model, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	app = model.(App)
+	if app.mode != ModeRule || app.ruleDraft.Kind != store.RuleSource || app.ruleInput.Value() != "Wire" {
+		t.Fatalf("u should start a source mute prefilled from the item: mode %v, draft %+v, input %q", app.mode, app.ruleDraft, app.ruleInput.Value())
+	}
+
+	// Tab: author (the item has one), then keyword; ↓: boost.
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	app = model.(App)
+	if app.ruleDraft.Kind != store.RuleAuthor || app.ruleInput.Value() != "Jane Doe" {
+		t.Errorf("Tab: draft %+v, input %q", app.ruleDraft, app.ruleInput.Value())
+	}
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	app = model.(App)
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	app = model.(App)
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	app = model.(App)
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyUp})
+	app = model.(App)
+	if app.ruleDraft.Kind != store.RuleKeyword || app.ruleDraft.Action != store.RuleBoost {
+		t.Errorf("draft %+v, want a keyword boost", app.ruleDraft)
+	}
+	if !strings.Contains(app.View(), "boost keyword:") {
+		t.Errorf("composer bar missing from view:\n%s", app.View())
+	}
+
+	// Switch back to mute, type a keyword and save.
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyUp})
+	app = model.(App)
+	for _, r := range "crypto" {
+		model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		app = model.(App)
+	}
+	model, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app = model.(App)
+	if len(added) != 1 || added[0].Kind != store.RuleKeyword || added[0].Action != store.RuleMute || added[0].Pattern != "crypto" {
+		t.Fatalf("AddRule called with %+v", added)
+	}
+	if cmd == nil || app.mode != ModeList {
+		t.Errorf("Enter should save and close the composer: mode %v", app.mode)
+	}
+	if len(app.items) != 1 || app.items[0].ID != "2" {
+		t.Errorf("muted items should disappear at once, got %v", app.items)
+	}
+}
+
+func TestAppRuleComposerSemanticAndInvalid(t *testing.T) {
+	var added store.Rule
+	app := NewAppWithConfig(AppConfig{
+		AddRule: func(rule store.Rule) tea.Cmd {
+			added = rule
+			return nil
+		},
+	})
+	app.items = []store.Item{{ID: "1", Title: "Transfer rumours", SourceName: "Sport"}}
+	app.embeddings = map[string][]float32{"1": {0.6, 0.8}}
+	app.embeddingModel = "m"
+
+	model, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	app = model.(App)
+	// No author: Shift+Tab from source wraps to "similar".
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	app = model.(App)
+	if app.ruleDraft.Kind != store.RuleSemantic {
+		t.Fatalf("Shift+Tab: kind %q, want semantic", app.ruleDraft.Kind)
+	}
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app = model.(App)
+	if added.ExampleID != "1" || added.Model != "m" || len(added.Embedding) != 2 || added.Threshold != semanticRuleThreshold {
+		t.Errorf("semantic rule %+v", added)
+	}
+
+	if len(app.items) != 0 {
+		t.Errorf("the example should be muted with its neighbours, got %v", app.items)
+	}
+
+	// An invalid regex is reported and the composer stays open.
+	app.items = []store.Item{{ID: "2", Title: "Cup final", SourceName: "Sport"}}
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	app = model.(App)
+	app.ruleDraft.Kind = store.RuleRegex
+	app.ruleInput.SetValue("(")
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app = model.(App)
+	if app.err == nil || app.mode != ModeRule {
+		t.Errorf("invalid regex: err %v, mode %v", app.err, app.mode)
+	}
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app = model.(App)
+	if app.mode != ModeList {
+		t.Errorf("Esc should cancel the composer, mode %v", app.mode)
+	}
+}
+
+func TestAppRulesPanel(t *testing.T) {
+	var deleted int64
+	reloads := 0
+	app := NewAppWithConfig(AppConfig{
+		LoadRules: func() tea.Cmd {
+			return func() tea.Msg { return RulesLoaded{} }
+		},
+		DeleteRule: func(id int64) tea.Cmd {
+			deleted = id
+			return func() tea.Msg { return RuleDeleted{ID: id} }
+		},
+		LoadItems: func() tea.Cmd {
+			reloads++
+			return func() tea.Msg { return nil }
+		},
+	})
+
+	model, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("U")})
+	app = model.(App)
+	if !app.rulesVisible || cmd == nil {
+		t.Fatal("U should open the panel and load the rules")
+	}
+	model, _ = app.Update(RulesLoaded{Rules: []store.Rule{
+		{ID: 3, Kind: store.RuleSource, Action: store.RuleMute, Pattern: "Tabloid", HiddenToday: 12},
+		{ID: 7, Kind: store.RuleSemantic, Action: store.RuleBoost, Pattern: "Webb finds water", Threshold: 0.8},
+	}})
+	app = model.(App)
+
+	view := app.View()
+	for _, want := range []string{"Tabloid", "12 hidden today", "similar", `"Webb finds water" ≥0.80`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel missing %q:\n%s", want, view)
+		}
+	}
+
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	app = model.(App)
+	model, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	app = model.(App)
+	if deleted != 7 || cmd == nil {
+		t.Fatalf("d deleted rule %d, want 7", deleted)
+	}
+	model, _ = app.Update(cmd())
+	app = model.(App)
+	if reloads != 1 {
+		t.Errorf("deleting a rule should reload the feed, got %d loads", reloads)
+	}
+
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app = model.(App)
+	if app.rulesVisible {
+		t.Error("Esc should close the panel")
+	}
+}
+
+func TestBoostWithinBands(t *testing.T) {
+	now := time.Now()
+	items := []store.Item{
+		{ID: "1", Published: now.Add(-5 * time.Minute)},
+		{ID: "2", Published: now.Add(-10 * time.Minute)},
+		{ID: "3", Published: now.Add(-2 * time.Hour)},
+		{ID: "4", Published: now.Add(-3 * time.Hour)},
+		{ID: "5", Published: now.Add(-4 * time.Hour)},
+	}
+	got := boostWithinBands(items, map[string]bool{"2": true, "5": true})
+	ids := make([]string, len(got))
+	for i, item := range got {
+		ids[i] = item.ID
+	}
+	if strings.Join(ids, ",") != "2,1,5,3,4" {
+		t.Errorf("order %v, want boosted first within each band: 2,1,5,3,4", ids)
+	}
+}
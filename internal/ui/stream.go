@@ -31,6 +31,36 @@ func TimeBand(published time.Time) string {
 // When showBands is false (e.g. during search results), time band headers are suppressed.
 // Returns the rendered string for display.
 func RenderStream(items []store.Item, cursor int, width, height int, showBands bool, aligned bool, shimmerOffset int) string {
+	return renderStream(items, streamDecor{}, cursor, width, height, showBands, aligned, shimmerOffset)
+}
+
+// streamDecor is what the feed adds to RenderStream's lines.
+type streamDecor struct {
+	hidden map[string]string // item ID -> why the filters dropped it; rendered dimmed
+	notes  map[string]string // item ID -> note after the title ("+4 similar from ...")
+	badges map[string]string // item ID -> trending badge after the title ("▲ 4 sources/1h")
+	rising map[string]bool   // items listed under the Rising band, above the time bands
+}
+
+// bandFunc names the band an item is listed under.
+type bandFunc func(item store.Item) string
+
+// bands returns how items are banded with decor: by TimeBand, except
+// rising items. Nil (no bands) when show is false.
+func (d streamDecor) bands(show bool) bandFunc {
+	if !show {
+		return nil
+	}
+	return func(item store.Item) string {
+		if d.rising[item.ID] {
+			return risingBand
+		}
+		return TimeBand(item.Published)
+	}
+}
+
+// renderStream is RenderStream with decor.
+func renderStream(items []store.Item, decor streamDecor, cursor int, width, height int, showBands bool, aligned bool, shimmerOffset int) string {
 	if len(items) == 0 {
 		return HelpStyle.Render("No items to display. Press 'r' to refresh.")
 	}
@@ -46,7 +76,8 @@ func RenderStream(items []store.Item, cursor int, width, height int, showBands b
 	}
 
 	// Calculate scroll offset to keep cursor visible, accounting for band headers.
-	scrollOffset := calcScrollOffset(items, cursor, availableHeight, showBands)
+	bands := decor.bands(showBands)
+	offset := scrollOffset(items, cursor, availableHeight, bands)
 
 	for i, item := range items {
 		if renderedLines >= availableHeight {
@@ -55,11 +86,11 @@ func RenderStream(items []store.Item, cursor int, width, height int, showBands b
 
 		// Track band state for all items (including skipped) so headers
 		// render correctly when we reach the visible region.
-		if showBands {
-			band := TimeBand(item.Published)
+		if bands != nil {
+			band := bands(item)
 			if band != currentBand {
 				currentBand = band
-				if i >= scrollOffset && renderedLines < availableHeight {
+				if i >= offset && renderedLines < availableHeight {
 					header := TimeBandHeader.Render(band)
 					b.WriteString(header)
 					b.WriteString("\n")
@@ -68,7 +99,7 @@ func RenderStream(items []store.Item, cursor int, width, height int, showBands b
 			}
 		}
 
-		if i < scrollOffset {
+		if i < offset {
 			continue
 		}
 
@@ -76,7 +107,12 @@ func RenderStream(items []store.Item, cursor int, width, height int, showBands b
 			break
 		}
 
-		line := renderItemLine(item, i == cursor, width, aligned, shimmerOffset)
+		line := ""
+		if reason, ok := decor.hidden[item.ID]; ok {
+			line = renderHiddenLine(item, reason, width)
+		} else {
+			line = renderItemLine(item, decor.badges[item.ID], decor.notes[item.ID], i == cursor, width, aligned, shimmerOffset)
+		}
 		b.WriteString(line)
 		b.WriteString("\n")
 		renderedLines++
@@ -90,6 +126,12 @@ func RenderStream(items []store.Item, cursor int, width, height int, showBands b
 // availableHeight. Without bands this is a simple subtraction; with bands
 // we iterate to account for header lines that consume viewport space.
 func calcScrollOffset(items []store.Item, cursor, availableHeight int, showBands bool) int {
+	return scrollOffset(items, cursor, availableHeight, streamDecor{}.bands(showBands))
+}
+
+// scrollOffset is calcScrollOffset for items listed under bands (nil:
+// no band headers).
+func scrollOffset(items []store.Item, cursor, availableHeight int, bands bandFunc) int {
 	if len(items) == 0 || cursor < 0 {
 		return 0
 	}
@@ -97,7 +139,7 @@ func calcScrollOffset(items []store.Item, cursor, availableHeight int, showBands
 		cursor = len(items) - 1
 	}
 
-	if !showBands {
+	if bands == nil {
 		if cursor >= availableHeight {
 			return cursor - availableHeight + 1
 		}
@@ -112,7 +154,7 @@ func calcScrollOffset(items []store.Item, cursor, availableHeight int, showBands
 	}
 
 	for offset <= cursor {
-		lines := visibleLineCount(items, offset, cursor, showBands)
+		lines := lineCount(items, offset, cursor, bands)
 		if lines <= availableHeight {
 			return offset
 		}
@@ -125,15 +167,21 @@ func calcScrollOffset(items []store.Item, cursor, availableHeight int, showBands
 // visibleLineCount counts how many rendered lines items[from..to] would
 // produce, including any band headers that appear within that range.
 func visibleLineCount(items []store.Item, from, to int, showBands bool) int {
+	return lineCount(items, from, to, streamDecor{}.bands(showBands))
+}
+
+// lineCount is visibleLineCount for items listed under bands (nil: no
+// band headers).
+func lineCount(items []store.Item, from, to int, bands bandFunc) int {
 	lines := 0
 	currentBand := ""
 	// Initialize band from predecessor so we know if items[from] starts a new band.
-	if from > 0 {
-		currentBand = TimeBand(items[from-1].Published)
+	if from > 0 && bands != nil {
+		currentBand = bands(items[from-1])
 	}
 	for i := from; i <= to && i < len(items); i++ {
-		if showBands {
-			band := TimeBand(items[i].Published)
+		if bands != nil {
+			band := bands(items[i])
 			if band != currentBand {
 				currentBand = band
 				lines++
@@ -144,8 +192,9 @@ func visibleLineCount(items []store.Item, from, to int, showBands bool) int {
 	return lines
 }
 
-// renderItemLine renders a single item line.
-func renderItemLine(item store.Item, selected bool, width int, aligned bool, shimmerOffset int) string {
+// renderItemLine renders a single item line, with the trend badge and
+// note (if any) after the title, which is shortened to fit them.
+func renderItemLine(item store.Item, trend, note string, selected bool, width int, aligned bool, shimmerOffset int) string {
 	// Build the source badge
 	badge := SourceBadge.Render(item.SourceName)
 	badgeWidth := lipgloss.Width(badge)
@@ -157,6 +206,15 @@ func renderItemLine(item store.Item, selected bool, width int, aligned bool, shi
 		titleWidth = 20
 	}
 
+	for _, extra := range []string{trend, note} {
+		if extra != "" {
+			titleWidth -= utf8.RuneCountInString(extra) + 2
+		}
+	}
+	if titleWidth < 10 {
+		titleWidth = 10
+	}
+
 	// Truncate title if needed (use rune count, not byte count for Unicode support)
 	title := item.Title
 	if utf8.RuneCountInString(title) > titleWidth {
@@ -181,6 +239,14 @@ func renderItemLine(item store.Item, selected bool, width int, aligned bool, shi
 
 	// Compose the line
 	styledTitle := titleStyle.Render(title)
+	if trend != "" {
+		styledTitle += TrendBadge.Render(" " + trend)
+		title += "  " + trend
+	}
+	if note != "" {
+		styledTitle += ClusterNote.Render(" " + note) // after the title's padding
+		title += "  " + note
+	}
 
 	if !aligned {
 		line := fmt.Sprintf("%s %s", badge, styledTitle)
@@ -389,14 +455,16 @@ func sourcePaletteColor(name string) lipgloss.Color {
 }
 
 // RenderStatusBar renders the bottom status bar with key hints and item count.
-func RenderStatusBar(cursor, total int, width int, loading bool) string {
-	// Left side: position info or loading indicator
+// backend is the rendered backendLabel (may be empty).
+func RenderStatusBar(cursor, total int, width int, loading bool, backend string) string {
+	// Left side: position info or loading indicator, then the backend
 	var position string
 	if loading {
 		position = " Loading... "
 	} else {
 		position = fmt.Sprintf(" %d/%d ", cursor+1, total)
 	}
+	position += backend
 
 	// Right side: key hints
 	keys := []string{
@@ -406,6 +474,8 @@ func RenderStatusBar(cursor, total int, width int, loading bool) string {
 		StatusBarKey.Render("r") + StatusBarText.Render(":refresh"),
 		StatusBarKey.Render("f") + StatusBarText.Render(":fetch"),
 		StatusBarKey.Render("t") + StatusBarText.Render(":layout"),
+		StatusBarKey.Render("S") + StatusBarText.Render(":mute"),
+		StatusBarKey.Render("u") + StatusBarText.Render(":rule"),
 		StatusBarKey.Render("?") + StatusBarText.Render(":debug"),
 		StatusBarKey.Render("q") + StatusBarText.Render(":quit"),
 	}
@@ -424,8 +494,9 @@ func RenderStatusBar(cursor, total int, width int, loading bool) string {
 }
 
 // RenderStatusBarWithFilter renders the status bar when filter is active.
-func RenderStatusBarWithFilter(cursor, filtered, total int, width int, loading bool, statusText string, poolPending, embedPending, rerankPending bool) string {
-	// Left side: position info or status text
+// backend is as for RenderStatusBar.
+func RenderStatusBarWithFilter(cursor, filtered, total int, width int, loading bool, statusText string, poolPending, embedPending, rerankPending bool, backend string) string {
+	// Left side: position info or status text, then the backend
 	var leftSide string
 	if statusText != "" {
 		leftSide = " " + statusText + " "
@@ -434,6 +505,7 @@ func RenderStatusBarWithFilter(cursor, filtered, total int, width int, loading b
 	} else {
 		leftSide = fmt.Sprintf(" %d/%d ", cursor+1, filtered)
 	}
+	leftSide += backend
 
 	// Pipeline strip
 	var strip string
@@ -482,6 +554,35 @@ func RenderStatusBarWithFilter(cursor, filtered, total int, width int, loading b
 	return StatusBar.Width(width).Render(bar)
 }
 
+// backendLabel renders the active embedding backend for the status bar:
+// its short name ("jina", "ollama", "local"), flagged when a fallback is
+// serving instead of the preferred backend. Empty if unknown.
+func backendLabel(b BackendStatus) string {
+	if b.Embedder == "" {
+		return ""
+	}
+	name := b.Embedder
+	if i := strings.Index(name, "/"); i > 0 {
+		name = name[:i]
+	}
+	if b.Degraded {
+		return StatusBarWarn.Render("⚠ "+name+" (fallback)") + " "
+	}
+	return StatusBarText.Render(name) + " "
+}
+
+// RenderMatchLine renders the "why this matched" line shown under search
+// results: the passage of the selected item that best matched the query,
+// cut to one line.
+func RenderMatchLine(passage string, width int) string {
+	prefix := "  ↳ "
+	avail := width - lipgloss.Width(prefix) - 2
+	if avail < 10 {
+		return ""
+	}
+	return MatchPassage.Render(prefix+"“"+truncateRunes(passage, avail)+"”") + "\n"
+}
+
 // RenderFilterBarWithStatus renders the filter input bar with a custom status indicator.
 // status can be empty (no indicator), "embedding", "reranking", etc.
 func RenderFilterBarWithStatus(filterText string, filtered, total int, width int, status string) string {
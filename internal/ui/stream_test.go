@@ -228,3 +228,15 @@ func TestVisibleLineCount(t *testing.T) {
 		t.Errorf("visibleLineCount(3,10,false) = %d, want 8", got)
 	}
 }
+
+func TestBackendLabel(t *testing.T) {
+	if got := backendLabel(BackendStatus{}); got != "" {
+		t.Errorf("backendLabel(empty) = %q, want empty", got)
+	}
+	if got := backendLabel(BackendStatus{Embedder: "jina/jina-embeddings-v3"}); !strings.Contains(got, "jina") || strings.Contains(got, "fallback") {
+		t.Errorf("backendLabel(primary) = %q", got)
+	}
+	if got := backendLabel(BackendStatus{Embedder: "ollama/mxbai-embed-large", Degraded: true}); !strings.Contains(got, "ollama (fallback)") {
+		t.Errorf("backendLabel(degraded) = %q", got)
+	}
+}
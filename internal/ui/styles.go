@@ -28,6 +28,21 @@ var ReadItem = lipgloss.NewStyle().
 	Foreground(colorSecondary).
 	Padding(0, 1)
 
+// HiddenItem style for items the feed's filters dropped, shown with "H".
+var HiddenItem = lipgloss.NewStyle().
+	Foreground(colorMuted).
+	Padding(0, 1)
+
+// TrendBadge style for the "▲ 4 sources/1h" badge on trending stories.
+var TrendBadge = lipgloss.NewStyle().
+	Foreground(colorHighlight).
+	Bold(true)
+
+// ClusterNote style for the "+4 similar from ..." note after a title.
+var ClusterNote = lipgloss.NewStyle().
+	Foreground(colorSecondary).
+	Italic(true)
+
 // MetaItem style for secondary metadata lines.
 var MetaItem = lipgloss.NewStyle().
 	Foreground(colorSecondary).
@@ -69,6 +84,15 @@ var StatusBarKey = lipgloss.NewStyle().
 var StatusBarText = lipgloss.NewStyle().
 	Foreground(colorSecondary)
 
+// StatusBarWarn style for degraded-state notes in the status bar.
+var StatusBarWarn = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("214"))
+
+// MatchPassage style for the "why this matched" line under search results.
+var MatchPassage = lipgloss.NewStyle().
+	Foreground(colorSecondary).
+	Italic(true)
+
 // ErrorStyle for displaying errors.
 var ErrorStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.Color("196")).
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// threadHeaderLines is how many lines of the thread panel come before
+// the timeline: title, summary, sources, first and latest, blank.
+const threadHeaderLines = 7
+
+// openThread opens the thread panel for the item under the cursor and
+// loads its story thread.
+func (a App) openThread() (tea.Model, tea.Cmd) {
+	if a.loadThread == nil || a.cursor >= len(a.items) {
+		return a, nil
+	}
+	a.threadVisible = true
+	a.threadItemID = a.items[a.cursor].ID
+	a.thread, a.threadItems, a.threadLoaded = nil, nil, false
+	a.threadScroll = 0
+	return a, a.loadThread(a.threadItemID)
+}
+
+func (a App) handleThreadKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "T", "q":
+		a.threadVisible = false
+	case "j", "down":
+		if a.threadScroll < len(a.threadItems)-1 {
+			a.threadScroll++
+		}
+	case "k", "up":
+		if a.threadScroll > 0 {
+			a.threadScroll--
+		}
+	case "g", "home":
+		a.threadScroll = 0
+	case "G", "end":
+		a.threadScroll = max(len(a.threadItems)-1, 0)
+	}
+	return a, nil
+}
+
+// threadOverlay renders a story thread: its sources, first and latest
+// reports and the timeline of every report, the item the panel was
+// opened on marked.
+func threadOverlay(t *store.Thread, items []store.Item, loaded bool, selectedID string, scroll, width, height int) string {
+	panelWidth := 100
+	if panelWidth > width-4 {
+		panelWidth = width - 4
+	}
+	if panelWidth < 20 {
+		panelWidth = 20
+	}
+	textWidth := panelWidth - 6 // border and padding
+
+	var lines []string
+	switch {
+	case !loaded:
+		lines = []string{DebugHeaderStyle.Render("Thread"), "", "  Loading…"}
+	case t == nil || len(items) == 0:
+		lines = []string{
+			DebugHeaderStyle.Render("Thread"), "",
+			"  Not in a story thread yet: items join one once they're embedded.",
+		}
+	default:
+		first, latest := items[0], items[len(items)-1]
+		lines = []string{
+			DebugHeaderStyle.Render(truncateRunes(t.Title, textWidth)),
+			fmt.Sprintf("%d reports from %s over %s", len(items), pluralSources(items), threadSpan(latest.Published.Sub(first.Published))),
+			truncateRunes("Sources: "+threadSources(items), textWidth),
+			truncateRunes(fmt.Sprintf("First:   %s · %s · %s", threadTime(first.Published), first.SourceName, first.Title), textWidth),
+			truncateRunes(fmt.Sprintf("Latest:  %s · %s · %s", threadTime(latest.Published), latest.SourceName, latest.Title), textWidth),
+			"",
+			"Timeline",
+		}
+		var timeline []string
+		for _, item := range items {
+			marker := "  "
+			if item.ID == selectedID {
+				marker = "▸ "
+			}
+			line := fmt.Sprintf("%s%s  %-14s %s", marker, threadTime(item.Published), truncateRunes(item.SourceName, 14), item.Title)
+			timeline = append(timeline, truncateRunes(line, textWidth))
+		}
+
+		room := max(height-debugPanelChrome-threadHeaderLines, 1)
+		scroll = min(scroll, max(len(timeline)-room, 0))
+		lines = append(lines, timeline[scroll:min(scroll+room, len(timeline))]...)
+	}
+	return DebugPanel.Width(panelWidth).Render(strings.Join(lines, "\n"))
+}
+
+// threadStatusBar renders the status bar for the thread panel.
+func threadStatusBar(width int) string {
+	keys := []string{
+		StatusBarKey.Render("j/k") + StatusBarText.Render(":scroll"),
+		StatusBarKey.Render("T") + StatusBarText.Render(":close"),
+	}
+	return StatusBar.Width(width).Render("  [THREAD]  " + strings.Join(keys, " "))
+}
+
+// threadSources lists a thread's sources, most reports first:
+// "Reuters ×4, AP ×2, BBC".
+func threadSources(items []store.Item) string {
+	counts := make(map[string]int)
+	var sources []string
+	for _, item := range items {
+		if counts[item.SourceName] == 0 {
+			sources = append(sources, item.SourceName)
+		}
+		counts[item.SourceName]++
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return counts[sources[i]] > counts[sources[j]]
+	})
+	parts := make([]string, len(sources))
+	for i, s := range sources {
+		parts[i] = s
+		if counts[s] > 1 {
+			parts[i] += fmt.Sprintf(" ×%d", counts[s])
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pluralSources returns "1 source" or "N sources".
+func pluralSources(items []store.Item) string {
+	seen := make(map[string]bool)
+	for _, item := range items {
+		seen[item.SourceName] = true
+	}
+	if len(seen) == 1 {
+		return "1 source"
+	}
+	return fmt.Sprintf("%d sources", len(seen))
+}
+
+// threadTime formats a report's local publish time: "Mon 02 Jan 15:04".
+func threadTime(t time.Time) string {
+	return t.Local().Format("Mon 02 Jan 15:04")
+}
+
+// threadSpan formats how long a story has run: "40m", "19h", "3d".
+func threadSpan(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
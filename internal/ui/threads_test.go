@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAppThread(t *testing.T) {
+	now := time.Now()
+	var requested string
+	app := NewAppWithConfig(AppConfig{
+		LoadThread: func(itemID string) tea.Cmd {
+			requested = itemID
+			return func() tea.Msg { return nil }
+		},
+	})
+	model, _ := app.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	app = model.(App)
+	model, _ = app.Update(ItemsLoaded{Items: []store.Item{
+		{ID: "c", Title: "Chile rebuilds after quake", SourceName: "AP", Published: now},
+		{ID: "x", Title: "Cup final", SourceName: "Sport", Published: now.Add(-time.Hour)},
+	}})
+	app = model.(App)
+	app.loading = false
+
+	model, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	app = model.(App)
+	if !app.threadVisible || cmd == nil || requested != "c" {
+		t.Fatalf("T should open the panel and load the thread of c (loaded %q)", requested)
+	}
+	if !strings.Contains(app.View(), "Loading") {
+		t.Error("panel should show loading until the thread arrives")
+	}
+
+	model, _ = app.Update(ThreadLoaded{
+		ItemID: "c",
+		Thread: &store.Thread{ID: 1, Title: "Quake hits Chile"},
+		Items: []store.Item{
+			{ID: "a", Title: "Quake hits Chile", SourceName: "Reuters", Published: now.Add(-50 * time.Hour)},
+			{ID: "b", Title: "Chile quake toll rises", SourceName: "Reuters", Published: now.Add(-24 * time.Hour)},
+			{ID: "c", Title: "Chile rebuilds after quake", SourceName: "AP", Published: now},
+		},
+	})
+	app = model.(App)
+	view := app.View()
+	for _, want := range []string{"Quake hits Chile", "3 reports from 2 sources over 2d", "Sources: Reuters ×2, AP", "First:", "Latest:", "▸ "} {
+		if !strings.Contains(view, want) {
+			t.Errorf("thread view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Keys go to the panel while it's open.
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	app = model.(App)
+	if app.cursor != 0 {
+		t.Errorf("j moved the feed cursor under the panel")
+	}
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app = model.(App)
+	if app.threadVisible {
+		t.Error("Esc should close the thread panel")
+	}
+
+	// An item not linked yet says so.
+	app.cursor = 1
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	app = model.(App)
+	model, _ = app.Update(ThreadLoaded{ItemID: "x"})
+	app = model.(App)
+	if !strings.Contains(app.View(), "Not in a story thread yet") {
+		t.Errorf("unthreaded item:\n%s", app.View())
+	}
+}
+
+func TestThreadSources(t *testing.T) {
+	items := []store.Item{{SourceName: "AP"}, {SourceName: "BBC"}, {SourceName: "BBC"}, {SourceName: "CNN"}}
+	if got := threadSources(items); got != "BBC ×2, AP, CNN" {
+		t.Errorf("threadSources = %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/thread"
+)
+
+// risingBand is the band trending stories are listed under, above the
+// time bands.
+const risingBand = "Rising"
+
+// withRising moves the newest listed report of each trending story to
+// the top of items, fastest first, and returns their IDs. The story's
+// other reports stay in their time bands.
+func withRising(items []store.Item, trends []thread.Trend) ([]store.Item, map[string]bool) {
+	if len(trends) == 0 {
+		return items, nil
+	}
+	listed := make(map[string]int, len(items))
+	for i, item := range items {
+		listed[item.ID] = i
+	}
+	rising := make(map[string]bool)
+	var top []store.Item
+	for _, tr := range trends {
+		for i := len(tr.Reports) - 1; i >= 0; i-- {
+			if k, ok := listed[tr.Reports[i].ID]; ok && !rising[items[k].ID] {
+				rising[items[k].ID] = true
+				top = append(top, items[k])
+				break
+			}
+		}
+	}
+	if len(top) == 0 {
+		return items, nil
+	}
+	out := append(make([]store.Item, 0, len(items)), top...)
+	for _, item := range items {
+		if !rising[item.ID] {
+			out = append(out, item)
+		}
+	}
+	return out, rising
+}
+
+// trendBadges returns the badge shown on every report of a trending
+// story: "▲ 4 sources/1h".
+func trendBadges(trends []thread.Trend) map[string]string {
+	if len(trends) == 0 {
+		return nil
+	}
+	badges := make(map[string]string)
+	for _, tr := range trends {
+		badge := fmt.Sprintf("▲ %d sources/%s", len(tr.Sources), threadSpan(tr.Window))
+		for _, item := range tr.Reports {
+			badges[item.ID] = badge
+		}
+	}
+	return badges
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelbrown/observer/internal/store"
+	"github.com/abelbrown/observer/internal/thread"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAppRising(t *testing.T) {
+	now := time.Now()
+	items := []store.Item{
+		{ID: "1", Title: "Cup final", SourceName: "Sport", Published: now.Add(-time.Minute)},
+		{ID: "2", Title: "Dam fails upriver", SourceName: "CNN", Published: now.Add(-5 * time.Minute)},
+		{ID: "3", Title: "Rates held", SourceName: "Wire", Published: now.Add(-10 * time.Minute)},
+		{ID: "4", Title: "Dam breach floods town", SourceName: "AP", Published: now.Add(-40 * time.Minute)},
+	}
+	trend := thread.Trend{
+		Thread:  store.Thread{ID: 7, Title: "Dam breach floods town"},
+		Window:  time.Hour,
+		Reports: []store.Item{items[3], {ID: "x", SourceName: "BBC"}, items[1]},
+		Recent:  3,
+		Sources: []string{"AP", "BBC", "CNN"},
+	}
+
+	app := NewAppWithConfig(AppConfig{})
+	model, _ := app.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	app = model.(App)
+	model, _ = app.Update(ItemsLoaded{Items: items, Trends: []thread.Trend{trend}})
+	app = model.(App)
+	app.loading = false
+
+	if app.items[0].ID != "2" || len(app.items) != 4 {
+		t.Fatalf("the story's newest report should lead the feed, got %s first", app.items[0].ID)
+	}
+	view := app.View()
+	rising, justNow := strings.Index(view, "Rising"), strings.Index(view, "Just Now")
+	if rising < 0 || justNow < rising {
+		t.Errorf("Rising band should come before the time bands:\n%s", view)
+	}
+	if n := strings.Count(view, "▲ 3 sources/1h"); n != 2 {
+		t.Errorf("badge shown on %d reports, want 2:\n%s", n, view)
+	}
+}
+
+func TestWithRising(t *testing.T) {
+	items := []store.Item{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	trends := []thread.Trend{
+		{Reports: []store.Item{{ID: "d"}, {ID: "c"}}}, // newest listed report: c
+		{Reports: []store.Item{{ID: "gone"}}},         // nothing listed
+		{Reports: []store.Item{{ID: "b"}}},
+	}
+	got, rising := withRising(items, trends)
+	var ids []string
+	for _, item := range got {
+		ids = append(ids, item.ID)
+	}
+	if strings.Join(ids, ",") != "c,b,a,d" || len(rising) != 2 || !rising["c"] {
+		t.Errorf("withRising = %v, %v; want c,b,a,d", ids, rising)
+	}
+}
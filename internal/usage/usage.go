@@ -0,0 +1,158 @@
+// Package usage accounts for AI backend calls — tokens, characters,
+// requests and latency — and checks them against spending budgets.
+//
+// Backends report each call through a Meter; the store persists records
+// (see store.RecordUsage). Budgets only gate background work: interactive
+// search is never refused.
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Kind is the type of backend call.
+type Kind string
+
+const (
+	KindEmbed  Kind = "embed"
+	KindRerank Kind = "rerank"
+)
+
+// Purpose says why a call was made.
+type Purpose string
+
+const (
+	PurposeInteractive Purpose = "interactive" // user-initiated search (default)
+	PurposeBackground  Purpose = "background"  // embedding worker
+	PurposeBackfill    Purpose = "backfill"    // obs backfill
+)
+
+type purposeKey struct{}
+
+// WithPurpose tags ctx so calls made with it are attributed to p.
+func WithPurpose(ctx context.Context, p Purpose) context.Context {
+	return context.WithValue(ctx, purposeKey{}, p)
+}
+
+// PurposeFrom returns the purpose tagged on ctx, or PurposeInteractive.
+func PurposeFrom(ctx context.Context) Purpose {
+	if p, ok := ctx.Value(purposeKey{}).(Purpose); ok {
+		return p
+	}
+	return PurposeInteractive
+}
+
+// Record is one backend call.
+type Record struct {
+	Time     time.Time
+	Backend  string // "jina", "ollama"
+	Model    string
+	Kind     Kind
+	Purpose  Purpose
+	Requests int
+	Tokens   int // as reported by the backend; 0 if unknown
+	Chars    int // characters sent
+	Latency  time.Duration
+	Failed   bool
+}
+
+// Recorder persists usage records. *store.Store implements it.
+type Recorder interface {
+	RecordUsage(r Record) error
+}
+
+// Totals aggregates records.
+type Totals struct {
+	Requests int64
+	Tokens   int64
+	Chars    int64
+	Failures int64
+	Latency  time.Duration // summed; divide by Requests for the mean
+}
+
+// Row is one line of a usage report.
+type Row struct {
+	Backend string
+	Model   string
+	Kind    Kind
+	Purpose Purpose
+	Totals
+}
+
+// Meter records calls for one backend model. A nil *Meter is a no-op, so
+// backends can call it unconditionally.
+type Meter struct {
+	rec     Recorder
+	backend string
+	model   string
+	kind    Kind
+}
+
+// NewMeter creates a Meter. Returns nil if rec is nil.
+func NewMeter(rec Recorder, backend, model string, kind Kind) *Meter {
+	if rec == nil {
+		return nil
+	}
+	return &Meter{rec: rec, backend: backend, model: model, kind: kind}
+}
+
+// Observe records one call that started at start. Recording errors are
+// dropped: accounting must never fail the call it measures.
+func (m *Meter) Observe(ctx context.Context, start time.Time, tokens, chars int, err error) {
+	if m == nil {
+		return
+	}
+	_ = m.rec.RecordUsage(Record{
+		Time:     start,
+		Backend:  m.backend,
+		Model:    m.model,
+		Kind:     m.kind,
+		Purpose:  PurposeFrom(ctx),
+		Requests: 1,
+		Tokens:   tokens,
+		Chars:    chars,
+		Latency:  time.Since(start),
+		Failed:   err != nil,
+	})
+}
+
+// ErrBudgetExceeded is returned by Budget.Check when a cap is reached.
+var ErrBudgetExceeded = errors.New("usage budget exceeded")
+
+// Budget caps token spend per calendar day and month. Zero means no cap.
+type Budget struct {
+	DailyTokens   int64
+	MonthlyTokens int64
+}
+
+// Enabled reports whether any cap is set.
+func (b Budget) Enabled() bool {
+	return b.DailyTokens > 0 || b.MonthlyTokens > 0
+}
+
+// Check returns an error wrapping ErrBudgetExceeded if today's or this
+// month's totals have reached a cap.
+func (b Budget) Check(day, month Totals) error {
+	if b.DailyTokens > 0 && day.Tokens >= b.DailyTokens {
+		return fmt.Errorf("%w: %d of %d daily tokens used", ErrBudgetExceeded, day.Tokens, b.DailyTokens)
+	}
+	if b.MonthlyTokens > 0 && month.Tokens >= b.MonthlyTokens {
+		return fmt.Errorf("%w: %d of %d monthly tokens used", ErrBudgetExceeded, month.Tokens, b.MonthlyTokens)
+	}
+	return nil
+}
+
+// StartOfDay returns local midnight of t's day.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfMonth returns local midnight of the first of t's month.
+func StartOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
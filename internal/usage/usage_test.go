@@ -0,0 +1,61 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type recorderFunc func(Record) error
+
+func (f recorderFunc) RecordUsage(r Record) error { return f(r) }
+
+func TestBudgetCheck(t *testing.T) {
+	b := Budget{DailyTokens: 100, MonthlyTokens: 1000}
+
+	if err := b.Check(Totals{Tokens: 99}, Totals{Tokens: 999}); err != nil {
+		t.Errorf("expected under budget, got %v", err)
+	}
+	if err := b.Check(Totals{Tokens: 100}, Totals{Tokens: 100}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected daily cap exceeded, got %v", err)
+	}
+	if err := b.Check(Totals{}, Totals{Tokens: 1000}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected monthly cap exceeded, got %v", err)
+	}
+	if (Budget{}).Enabled() {
+		t.Error("zero budget should be disabled")
+	}
+	if err := (Budget{}).Check(Totals{Tokens: 1 << 40}, Totals{Tokens: 1 << 40}); err != nil {
+		t.Errorf("zero budget should never be exceeded, got %v", err)
+	}
+}
+
+func TestMeterObserve(t *testing.T) {
+	var nilMeter *Meter
+	nilMeter.Observe(context.Background(), time.Now(), 1, 1, nil) // must not panic
+
+	if NewMeter(nil, "jina", "m", KindEmbed) != nil {
+		t.Error("NewMeter with nil recorder should return nil")
+	}
+
+	var got []Record
+	m := NewMeter(recorderFunc(func(r Record) error {
+		got = append(got, r)
+		return errors.New("disk full") // dropped
+	}), "jina", "m", KindEmbed)
+
+	ctx := WithPurpose(context.Background(), PurposeBackfill)
+	m.Observe(ctx, time.Now(), 42, 100, nil)
+	m.Observe(context.Background(), time.Now(), 0, 10, errors.New("boom"))
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(got))
+	}
+	if got[0].Purpose != PurposeBackfill || got[0].Tokens != 42 || got[0].Chars != 100 || got[0].Failed {
+		t.Errorf("unexpected first record: %+v", got[0])
+	}
+	if got[1].Purpose != PurposeInteractive || !got[1].Failed || got[1].Requests != 1 {
+		t.Errorf("unexpected second record: %+v", got[1])
+	}
+}